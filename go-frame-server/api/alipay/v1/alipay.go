@@ -57,16 +57,18 @@ type RefundRes struct {
 
 // 创建基于账单的支付订单
 type CreateBillingPayReq struct {
-	g.Meta    `path:"/alipay/create-billing-pay" method:"post" tags:"支付宝支付" summary:"根据账单创建支付订单"`
-	BillingId int64  `json:"billing_id" v:"required#账单ID不能为空"`
-	PayType   string `json:"pay_type" v:"required|in:web,wap,app#支付类型不能为空|支付类型错误"` // web:电脑网站, wap:手机网站, app:APP
-	Remark    string `json:"remark"`
+	g.Meta     `path:"/alipay/create-billing-pay" method:"post" tags:"支付宝支付" summary:"根据账单创建支付订单"`
+	BillingId  int64  `json:"billing_id" v:"required#账单ID不能为空"`
+	PayType    string `json:"pay_type" v:"required|in:web,wap,app#支付类型不能为空|支付类型错误"` // web:电脑网站, wap:手机网站, app:APP
+	Remark     string `json:"remark"`
+	CouponCode string `json:"coupon_code" v:"max-length:32#优惠码错误"` // 优惠码
 }
 
 type CreateBillingPayRes struct {
-	OrderString string `json:"order_string"` // 支付宝订单信息(HTML或URL)
-	OutTradeNo  string `json:"out_trade_no"` // 商户订单号
-	TotalAmount string `json:"total_amount"` // 支付金额
+	OrderString    string `json:"order_string"`    // 支付宝订单信息(HTML或URL)
+	OutTradeNo     string `json:"out_trade_no"`    // 商户订单号
+	TotalAmount    string `json:"total_amount"`    // 支付金额
+	DiscountAmount string `json:"discount_amount"` // 优惠码减免的金额
 }
 
 // 创建订阅套餐支付订单
@@ -75,10 +77,12 @@ type CreateSubscriptionPayReq struct {
 	UserPlan    string `json:"user_plan" v:"required|in:professional,team#套餐类型不能为空|套餐类型错误"`
 	MemberCount int    `json:"member_count" v:"required-if:UserPlan,team|min:3|max:100#团队版需要指定人数|团队人数不能少于3人|团队人数不能超过100人"`
 	PayType     string `json:"pay_type" v:"required|in:web,wap,app#支付类型不能为空|支付类型错误"` // web:电脑网站, wap:手机网站, app:APP
+	CouponCode  string `json:"coupon_code" v:"max-length:32#优惠码错误"`                  // 优惠码
 }
 
 type CreateSubscriptionPayRes struct {
-	OrderString string `json:"order_string"` // 支付宝订单信息(HTML或URL)
-	OutTradeNo  string `json:"out_trade_no"` // 商户订单号
-	TotalAmount string `json:"total_amount"` // 支付金额
+	OrderString    string `json:"order_string"`    // 支付宝订单信息(HTML或URL)
+	OutTradeNo     string `json:"out_trade_no"`    // 商户订单号
+	TotalAmount    string `json:"total_amount"`    // 支付金额
+	DiscountAmount string `json:"discount_amount"` // 优惠码减免的金额
 }
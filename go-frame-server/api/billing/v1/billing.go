@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/logic/billing"
+)
+
+type PreviewBillReq struct {
+	g.Meta `path:"/billing/preview" method:"get" tags:"账单" sm:"按当前用量预估本账期的账单"`
+}
+
+type PreviewBillRes struct {
+	*billing.Bill
+}
+
+type RunBillingReq struct {
+	g.Meta `path:"/admin/billing/runs" method:"post" tags:"账单" sm:"为已结束的账期出账, 可重复执行"`
+	Period string `json:"period" v:"required|date-format:Y-m#请选择账期|账期格式错误" dc:"账期, 格式 YYYY-MM"`
+}
+
+type RunBillingRes struct {
+	*billing.RunResult
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/logic/invoices"
+)
+
+type RequestInvoiceReq struct {
+	g.Meta    `path:"/billing/records/{billingId}/invoice" method:"post" tags:"账单" sm:"为已支付的账单申请发票"`
+	BillingId int64  `json:"billingId" in:"path" v:"required|min:1#请选择账单|账单ID错误" dc:"账单ID"`
+	Title     string `json:"title" v:"required|max-length:200#请填写发票抬头|发票抬头不能超过200个字符" dc:"发票抬头"`
+	TaxId     string `json:"taxId" v:"required|regex:^[0-9A-Za-z]{15,20}$#请填写税号|税号格式错误" dc:"纳税人识别号"`
+}
+
+type RequestInvoiceRes struct {
+	*invoices.Invoice
+}
+
+type GetInvoiceReq struct {
+	g.Meta    `path:"/billing/records/{billingId}/invoice" method:"get" tags:"账单" sm:"获取账单的发票信息和下载地址"`
+	BillingId int64 `json:"billingId" in:"path" v:"required|min:1#请选择账单|账单ID错误" dc:"账单ID"`
+}
+
+type GetInvoiceRes struct {
+	*invoices.Invoice
+}
+
+type ReissueInvoiceReq struct {
+	g.Meta    `path:"/admin/billing/records/{billingId}/invoice" method:"put" tags:"账单" sm:"重新开具发票"`
+	BillingId int64  `json:"billingId" in:"path" v:"required|min:1#请选择账单|账单ID错误" dc:"账单ID"`
+	Title     string `json:"title" v:"max-length:200#发票抬头不能超过200个字符" dc:"发票抬头, 为空时沿用原抬头"`
+	TaxId     string `json:"taxId" v:"regex:^$|^[0-9A-Za-z]{15,20}$#税号格式错误" dc:"纳税人识别号, 为空时沿用原税号"`
+}
+
+type ReissueInvoiceRes struct {
+	*invoices.Invoice
+}
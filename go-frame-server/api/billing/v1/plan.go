@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/model/entity"
+)
+
+type PreviewPlanChangeReq struct {
+	g.Meta      `path:"/billing/plan-changes/preview" method:"get" tags:"账单" sm:"预览变更套餐的差价和生效时间"`
+	UserPlan    string `json:"userPlan" v:"required|in:free,professional,team#请选择套餐|套餐类型错误" dc:"目标套餐"`
+	MemberCount int    `json:"memberCount" dc:"团队人数, 仅团队版需要"`
+}
+
+type PreviewPlanChangeRes struct {
+	*billing.PlanChangeQuote
+}
+
+type ChangePlanReq struct {
+	g.Meta      `path:"/billing/plan-changes" method:"post" tags:"账单" sm:"变更套餐: 升级支付差价后立即生效, 降级在账期结束时生效"`
+	UserPlan    string `json:"userPlan" v:"required|in:free,professional,team#请选择套餐|套餐类型错误" dc:"目标套餐"`
+	MemberCount int    `json:"memberCount" dc:"团队人数, 仅团队版需要"`
+	PayType     string `json:"pay_type" v:"in:web,wap,app#支付类型错误" d:"web" dc:"支付类型: web-电脑网站, wap-手机网站, app-APP"`
+	Provider    string `json:"provider" v:"in:alipay,wechat,mock" dc:"支付渠道, 为空时使用默认渠道"`
+}
+
+type ChangePlanRes struct {
+	Change      *entity.SubscriptionChanges `json:"change" dc:"套餐变更"`
+	Quote       *billing.PlanChangeQuote    `json:"quote" dc:"差价和生效时间"`
+	OrderString string                      `json:"order_string" dc:"支付订单信息(HTML或URL), 无需支付时为空"`
+	OutTradeNo  string                      `json:"out_trade_no" dc:"商户订单号, 无需支付时为空"`
+	TotalAmount string                      `json:"total_amount" dc:"支付金额"`
+}
+
+type CancelPlanChangeReq struct {
+	g.Meta   `path:"/billing/plan-changes/{changeId}" method:"delete" tags:"账单" sm:"取消待支付或待生效的套餐变更"`
+	ChangeId int `json:"changeId" in:"path" v:"required|min:1#请选择套餐变更|套餐变更ID错误" dc:"套餐变更ID"`
+}
+
+type CancelPlanChangeRes struct{}
+
+type ListPlanChangesReq struct {
+	g.Meta `path:"/billing/plan-changes" method:"get" tags:"账单" sm:"获取套餐变更记录"`
+	Page   int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListPlanChangesRes struct {
+	List  []*entity.SubscriptionChanges `json:"list" dc:"套餐变更列表"`
+	Total int                           `json:"total" dc:"总数"`
+}
@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/logic/traffic"
+	"kgplatform-backend/internal/logic/usage"
+)
+
+type GetTrafficUsageReq struct {
+	g.Meta      `path:"/billing/usage/traffic" method:"get" tags:"账单" sm:"按流量类型获取流量用量的时间序列和各接口的流量"`
+	Granularity string      `json:"granularity" d:"day" v:"in:hour,day#时间粒度错误" dc:"时间粒度, hour-按小时, day-按天"`
+	StartDate   *gtime.Time `json:"startDate" dc:"开始日期, 按小时默认为结束日期当天, 按天默认为结束日期前6天"`
+	EndDate     *gtime.Time `json:"endDate" dc:"结束日期, 默认为今天"`
+	TrafficType string      `json:"trafficType" v:"in:graph_query,api_read,file_transfer,export#流量类型错误" dc:"流量类型, graph_query-图谱查询, api_read-项目数据读取, file_transfer-文件下载, export-导出, 为空时返回所有类型"`
+	MemberId    int64       `json:"memberId" dc:"团队成员的用户ID, 仅团队所有者和管理员可指定, 为空时查看整个团队"`
+}
+
+type GetTrafficUsageRes struct {
+	*traffic.Usage
+}
+
+type GetUsageHistoryReq struct {
+	g.Meta       `path:"/billing/usage/history" method:"get" tags:"账单" sm:"获取字数、存储、算力和流量的每日用量"`
+	ResourceType string      `json:"resourceType" v:"in:words,storage,traffic,cu#资源类型错误" dc:"资源类型, words-字数, storage-存储, traffic-流量, cu-算力, 为空时返回所有资源"`
+	StartDate    *gtime.Time `json:"startDate" dc:"开始日期, 默认为结束日期前29天"`
+	EndDate      *gtime.Time `json:"endDate" dc:"结束日期, 默认为今天"`
+	MemberId     int64       `json:"memberId" dc:"团队成员的用户ID, 仅团队所有者和管理员可指定, 为空时查看整个团队"`
+}
+
+type GetUsageHistoryRes struct {
+	*usage.History
+}
+
+type GetUsageBreakdownReq struct {
+	g.Meta       `path:"/billing/usage/breakdown" method:"get" tags:"账单" sm:"按项目、模型或团队成员汇总一种资源的用量"`
+	ResourceType string      `json:"resourceType" v:"required|in:words,storage,traffic,cu#请选择资源类型|资源类型错误" dc:"资源类型, words-字数, storage-存储, traffic-流量, cu-算力"`
+	Dimension    string      `json:"dimension" d:"project" v:"in:project,model,member#分析维度错误" dc:"维度, project-项目, model-模型, member-团队成员"`
+	StartDate    *gtime.Time `json:"startDate" dc:"开始日期, 默认为结束日期前29天"`
+	EndDate      *gtime.Time `json:"endDate" dc:"结束日期, 默认为今天"`
+	MemberId     int64       `json:"memberId" dc:"团队成员的用户ID, 仅团队所有者和管理员可指定, 为空时查看整个团队"`
+}
+
+type GetUsageBreakdownRes struct {
+	*usage.Breakdown
+}
+
+type ExportUsageReq struct {
+	g.Meta       `path:"/billing/usage/export" method:"get" tags:"账单" sm:"导出用量明细CSV, 最多导出92天"`
+	ResourceType string      `json:"resourceType" v:"in:words,storage,traffic,cu#资源类型错误" dc:"资源类型, 为空时导出所有资源"`
+	StartDate    *gtime.Time `json:"startDate" dc:"开始日期, 默认为结束日期前29天"`
+	EndDate      *gtime.Time `json:"endDate" dc:"结束日期, 默认为今天"`
+	MemberId     int64       `json:"memberId" dc:"团队成员的用户ID, 仅团队所有者和管理员可指定, 为空时导出整个团队"`
+}
+
+type ExportUsageRes struct{}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/logic/coupons"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// 下单前校验优惠码并计算优惠金额, 不占用使用次数
+type CheckCouponReq struct {
+	g.Meta       `path:"/coupons/check" method:"post" tags:"优惠券" summary:"校验优惠码"`
+	Code         string `json:"code" v:"required|max-length:32#请输入优惠码|优惠码错误" dc:"优惠码"`
+	OrderType    string `json:"orderType" v:"required|in:subscription,billing,project#请选择订单类型|订单类型错误" dc:"订单类型: subscription-订阅, billing-账单, project-项目"`
+	UserPlan     string `json:"userPlan" v:"required-if:OrderType,subscription|in:professional,team#请选择套餐|套餐类型错误" dc:"订阅的套餐"`
+	MemberCount  int    `json:"memberCount" dc:"团队版人数"`
+	BillingId    int64  `json:"billingId" v:"required-if:OrderType,billing#请选择账单" dc:"账单ID"`
+	ProjectId    int    `json:"projectId" v:"required-if:OrderType,project#请选择项目" dc:"项目ID"`
+	PurchaseType string `json:"purchaseType" v:"in:read,buy" d:"buy" dc:"项目购买类型: read-阅读权限, buy-购买"`
+}
+
+type CheckCouponRes struct {
+	*coupons.Quote
+}
+
+// 优惠券的可修改字段
+type CouponFields struct {
+	Name              string      `json:"name" v:"required|max-length:100#请输入优惠券名称|优惠券名称不能超过100个字符" dc:"优惠券名称"`
+	DiscountType      string      `json:"discountType" v:"required|in:percent,fixed#请选择优惠类型|优惠类型错误" dc:"优惠类型: percent-按比例, fixed-固定金额"`
+	PercentOff        int         `json:"percentOff" v:"required-if:DiscountType,percent|between:0,100#请输入优惠比例|优惠比例必须在1到100之间" dc:"按比例优惠的百分比, 20 表示减免20%"`
+	AmountOffCent     int64       `json:"amountOffCent" v:"required-if:DiscountType,fixed|min:0#请输入减免金额|减免金额不能小于0" dc:"固定减免金额（分）"`
+	MaxDiscountCent   int64       `json:"maxDiscountCent" v:"min:0#最高减免金额不能小于0" dc:"按比例优惠的最高减免金额（分）, 0 表示不限"`
+	MinAmountCent     int64       `json:"minAmountCent" v:"min:0#订单金额门槛不能小于0" dc:"订单金额门槛（分）, 0 表示不限"`
+	OrderTypes        []string    `json:"orderTypes" v:"foreach|in:subscription,billing,project#订单类型错误" dc:"适用的订单类型, 为空表示不限"`
+	Plans             []string    `json:"plans" dc:"适用的套餐, 为空表示不限"`
+	ProjectIds        []int       `json:"projectIds" dc:"适用的项目, 为空表示不限"`
+	FirstPurchaseOnly bool        `json:"firstPurchaseOnly" dc:"是否仅限首次购买"`
+	TotalLimit        int         `json:"totalLimit" v:"min:0#总使用次数不能小于0" dc:"总使用次数上限, 0 表示不限"`
+	PerUserLimit      int         `json:"perUserLimit" d:"1" v:"min:0#每人使用次数不能小于0" dc:"每个用户的使用次数上限, 0 表示不限"`
+	StartsAt          *gtime.Time `json:"startsAt" dc:"生效时间, 为空表示立即生效"`
+	ExpiresAt         *gtime.Time `json:"expiresAt" dc:"过期时间, 为空表示长期有效"`
+	Status            string      `json:"status" v:"in:active,disabled#状态错误" d:"active" dc:"状态: active-启用, disabled-停用"`
+}
+
+type ListCouponReq struct {
+	g.Meta  `path:"/admin/coupons" method:"get" tags:"优惠券" summary:"获取优惠券列表"`
+	Keyword string `json:"keyword" dc:"按优惠码或名称搜索"`
+	Status  string `json:"status" v:"in:active,disabled#状态错误" dc:"状态"`
+	Page    int    `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size    int    `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListCouponRes struct {
+	Total int               `json:"total" dc:"总记录数"`
+	List  []*entity.Coupons `json:"list"`
+}
+
+type GetCouponReq struct {
+	g.Meta   `path:"/admin/coupons/{couponId}" method:"get" tags:"优惠券" summary:"获取优惠券"`
+	CouponId int `path:"couponId" v:"required|min:1#请选择优惠券"`
+}
+
+type GetCouponRes struct {
+	*entity.Coupons
+}
+
+type CreateCouponReq struct {
+	g.Meta `path:"/admin/coupons" method:"post" tags:"优惠券" summary:"创建优惠券"`
+	Code   string `json:"code" v:"required|regex:^[0-9A-Za-z_-]{4,32}$#请输入优惠码|优惠码为4到32位字母、数字、下划线或中划线" dc:"优惠码, 不区分大小写"`
+	CouponFields
+}
+
+type CreateCouponRes struct {
+	CouponId int `json:"couponId"`
+}
+
+type UpdateCouponReq struct {
+	g.Meta   `path:"/admin/coupons/{couponId}" method:"put" tags:"优惠券" summary:"修改优惠券, 优惠码不能修改"`
+	CouponId int `path:"couponId" v:"required|min:1#请选择优惠券"`
+	CouponFields
+}
+
+type UpdateCouponRes struct{}
+
+type DeleteCouponReq struct {
+	g.Meta   `path:"/admin/coupons/{couponId}" method:"delete" tags:"优惠券" summary:"删除未使用过的优惠券"`
+	CouponId int `path:"couponId" v:"required|min:1#请选择优惠券"`
+}
+
+type DeleteCouponRes struct{}
+
+type ListRedemptionReq struct {
+	g.Meta   `path:"/admin/coupons/{couponId}/redemptions" method:"get" tags:"优惠券" summary:"获取优惠券使用记录"`
+	CouponId int `path:"couponId" v:"required|min:1#请选择优惠券"`
+	Page     int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size     int `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListRedemptionRes struct {
+	Total int                         `json:"total" dc:"总记录数"`
+	List  []*entity.CouponRedemptions `json:"list"`
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/model/entity"
+)
+
+type ListCronJobReq struct {
+	g.Meta `path:"/admin/cron/list" method:"get" tags:"定时任务管理" sm:"获取定时任务列表及最近一次运行结果"`
+}
+
+type CronJobItem struct {
+	Name        string              `json:"name" dc:"任务名称"`
+	Description string              `json:"description" dc:"任务说明"`
+	Pattern     string              `json:"pattern" dc:"cron表达式"`
+	Running     bool                `json:"running" dc:"是否正在运行"`
+	LastRun     *entity.CronJobRuns `json:"lastRun" dc:"最近一次运行记录"`
+}
+
+type ListCronJobRes struct {
+	List []*CronJobItem `json:"list"`
+}
+
+type TriggerCronJobReq struct {
+	g.Meta `path:"/admin/cron/trigger/{name}" method:"post" tags:"定时任务管理" sm:"手动触发定时任务"`
+	Name   string `path:"name" v:"required#请选择定时任务"`
+}
+
+type TriggerCronJobRes struct {
+	RunId int `json:"runId" dc:"运行记录ID"`
+}
+
+type ListCronJobRunReq struct {
+	g.Meta `path:"/admin/cron/runs/{name}" method:"get" tags:"定时任务管理" sm:"获取定时任务运行记录"`
+	Name   string `path:"name" v:"required#请选择定时任务"`
+	Page   int    `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int    `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListCronJobRunRes struct {
+	Total int                   `json:"total" dc:"总记录数"`
+	List  []*entity.CronJobRuns `json:"list"`
+}
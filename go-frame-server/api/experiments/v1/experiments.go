@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/logic/experiments"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// ExperimentRunItem 参与对比的模型/提示词组合
+type ExperimentRunItem struct {
+	ModelId int    `json:"modelId" v:"required#请选择模型"`
+	Prompt  string `json:"prompt" dc:"提示词, 为空时使用项目抽取配置中的提示词"`
+
+	// 引用提示词库中的版本, 设置后忽略 Prompt
+	PromptVersionId *int `json:"promptVersionId"`
+}
+
+type CreateExperimentReq struct {
+	g.Meta         `path:"/experiment/create" method:"post" tags:"模型对比实验" sm:"创建模型对比实验"`
+	ProjectId      int                 `json:"projectId" v:"required#请选择项目"`
+	PipelineId     int                 `json:"pipelineId" v:"required#请选择管道"`
+	MaterialIDList []int               `json:"materialIdList" v:"required#请选择素材"`
+	SampleSize     int                 `json:"sampleSize" v:"min:0" dc:"抽样素材数量, 0表示使用配置的最大抽样数量"`
+	Runs           []ExperimentRunItem `json:"runs" v:"required|min-length:2#请选择对比的模型|至少选择两组模型或提示词"`
+}
+
+type CreateExperimentRes struct {
+	ExperimentId int    `json:"experimentId"`
+	TaskId       int    `json:"taskId"`
+	Status       string `json:"status" dc:"实验状态, pending, processing, completed, failed"`
+}
+
+type GetExperimentReq struct {
+	g.Meta `path:"/experiment/get/{id}" method:"get" tags:"模型对比实验" sm:"获取实验对比报告"`
+	Id     int `path:"id" v:"required#请选择实验"`
+}
+
+type GetExperimentRes struct {
+	Experiment *entity.ExtractExperiments      `json:"experiment"`
+	Runs       []*entity.ExtractExperimentRuns `json:"runs"`
+	Report     *experiments.Report             `json:"report" dc:"对比报告, 实验完成后生成"`
+}
+
+type ListExperimentReq struct {
+	g.Meta    `path:"/experiment/list" method:"get" tags:"模型对比实验" sm:"获取项目的实验列表"`
+	ProjectId int `json:"projectId" v:"required#请选择项目"`
+}
+
+type ListExperimentRes struct {
+	List []*entity.ExtractExperiments `json:"list"`
+}
+
+type PromoteExperimentRunReq struct {
+	g.Meta       `path:"/experiment/promote" method:"post" tags:"模型对比实验" sm:"将某次运行结果应用到项目"`
+	ExperimentId int `json:"experimentId" v:"required#请选择实验"`
+	RunId        int `json:"runId" v:"required#请选择运行结果"`
+}
+
+type PromoteExperimentRunRes struct {
+}
@@ -11,4 +11,6 @@ type GetGraphRes struct {
 	Id    int                 `json:"id"`
 	Nodes []map[string]string `json:"nodes"`
 	Edges []map[string]string `json:"edges"`
+	// Preview 未购买时只返回部分节点
+	Preview bool `json:"preview"`
 }
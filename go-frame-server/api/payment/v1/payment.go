@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/model/entity"
+)
+
+type ListPaymentProviderReq struct {
+	g.Meta `path:"/payment/providers" method:"get" tags:"支付" summary:"获取可用的支付渠道"`
+}
+
+type ListPaymentProviderRes struct {
+	Providers []string `json:"providers" dc:"可用的支付渠道: alipay-支付宝, wechat-微信支付, mock-模拟支付"`
+	Default   string   `json:"default" dc:"默认支付渠道"`
+}
+
+// 支付结果通知, 各渠道的通知格式不同, 由渠道自行解析
+type PaymentNotifyReq struct {
+	g.Meta   `path:"/payment/notify/{provider}" method:"post" tags:"支付" summary:"支付结果通知"`
+	Provider string `path:"provider" v:"required|in:alipay,wechat,mock#支付渠道不能为空|支付渠道错误"`
+}
+
+type PaymentNotifyRes struct{}
+
+// 模拟支付, 仅在启用 payment.mock 时可用
+type MockPayReq struct {
+	g.Meta     `path:"/payment/mock/pay/{outTradeNo}" method:"post" tags:"支付" summary:"模拟支付并发送支付通知"`
+	OutTradeNo string `path:"outTradeNo" v:"required#订单号不能为空"`
+	Status     string `json:"status" d:"paid" v:"in:paid,closed#模拟结果错误" dc:"模拟结果: paid-支付成功, closed-关闭订单"`
+	Repeat     int    `json:"repeat" d:"1" v:"min:1|max:10#重复次数不能小于1|重复次数不能超过10" dc:"重复发送通知的次数"`
+}
+
+type MockPayRes struct {
+	NotifyId      string `json:"notifyId" dc:"通知ID"`
+	TransactionId string `json:"transactionId" dc:"模拟的渠道交易号"`
+	Status        string `json:"status" dc:"订单状态"`
+}
+
+type ListReconcileReportReq struct {
+	g.Meta `path:"/admin/payment/reconcile-reports" method:"get" tags:"支付" summary:"获取支付对账报告列表"`
+	Page   int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListReconcileReportRes struct {
+	Total int                               `json:"total" dc:"总记录数"`
+	List  []*entity.PaymentReconcileReports `json:"list"`
+}
+
+type GetReconcileReportReq struct {
+	g.Meta   `path:"/admin/payment/reconcile-reports/{reportId}" method:"get" tags:"支付" summary:"获取支付对账报告及差异明细"`
+	ReportId int `path:"reportId" v:"required|min:1#请选择对账报告"`
+}
+
+type GetReconcileReportRes struct {
+	Report *entity.PaymentReconcileReports `json:"report"`
+	Items  []*entity.PaymentReconcileItems `json:"items" dc:"差异明细"`
+}
+
+// 重新生成指定日期的对账报告, 每日报告由定时任务自动生成
+type CreateReconcileReportReq struct {
+	g.Meta `path:"/admin/payment/reconcile-reports" method:"post" tags:"支付" summary:"生成支付对账报告"`
+	Date   string `json:"date" v:"required|date-format:Y-m-d#请选择对账日期|对账日期格式错误" dc:"对账日期, 格式 YYYY-MM-DD"`
+}
+
+type CreateReconcileReportRes struct {
+	Report *entity.PaymentReconcileReports `json:"report"`
+}
+
+// 管理员为已支付的订单申请退款, 全额退款后收回订单对应的权益
+type RefundOrderReq struct {
+	g.Meta     `path:"/admin/payment/orders/{outTradeNo}/refund" method:"post" tags:"支付" summary:"申请订单退款"`
+	OutTradeNo string `path:"outTradeNo" v:"required#订单号不能为空"`
+	RefundCent int64  `json:"refundCent" v:"min:0#退款金额不能为负数" dc:"退款金额(分), 为 0 时全额退款"`
+	Reason     string `json:"reason" v:"max-length:100#退款原因不能超过100个字符" dc:"退款原因"`
+}
+
+type RefundOrderRes struct {
+	Order        *entity.PaymentOrders `json:"order"`
+	OutRefundNo  string                `json:"outRefundNo" dc:"退款单号"`
+	RefundId     string                `json:"refundId" dc:"渠道退款单号"`
+	RefundStatus string                `json:"refundStatus" dc:"退款状态: processing-处理中, success-成功, failed-失败"`
+}
@@ -42,9 +42,21 @@ type IProjectsV1 interface {
 	// 获取项目收藏数
 	GetProjectFavorCount(ctx context.Context, req *v1.GetProjectFavorCountReq) (res *v1.GetProjectFavorCountRes, err error)
 
+	// 获取项目浏览量
+	GetProjectViewCount(ctx context.Context, req *v1.GetProjectViewCountReq) (res *v1.GetProjectViewCountRes, err error)
+
+	// 获取项目每日浏览量趋势
+	GetProjectViewTrend(ctx context.Context, req *v1.GetProjectViewTrendReq) (res *v1.GetProjectViewTrendRes, err error)
+
 	// 下载抽取示例文件
 	DownloadExtractExample(ctx context.Context, req *v1.DownloadExtractExampleReq) (res *v1.DownloadExtractExampleRes, err error)
 
 	// 获取三元组来源信息
 	GetTripleSourceInfo(ctx context.Context, req *v1.GetTripleSourceInfoReq) (res *v1.GetTripleSourceInfoRes, err error)
+
+	// 获取当前用户对项目的访问权限
+	GetProjectAccess(ctx context.Context, req *v1.GetProjectAccessReq) (res *v1.GetProjectAccessRes, err error)
+
+	// 克隆项目
+	ForkProject(ctx context.Context, req *v1.ForkProjectReq) (res *v1.ForkProjectRes, err error)
 }
@@ -5,6 +5,7 @@ import (
 	"kgplatform-backend/internal/logic/materials"
 	"kgplatform-backend/internal/logic/projects"
 	"kgplatform-backend/internal/logic/tasks"
+	"kgplatform-backend/internal/logic/views"
 	"kgplatform-backend/internal/model/entity"
 	"kgplatform-backend/internal/neo4j"
 	"kgplatform-backend/internal/utils"
@@ -53,6 +54,14 @@ type GetProjectRes struct {
 
 	// ExtractConfig 抽取配置
 	ExtractConfig *py_service.ExtractConfig `json:"extractConfig" dc:"抽取配置"`
+
+	// ForkedFrom 克隆来源项目ID, 非克隆项目为0
+	ForkedFrom int `json:"forkedFrom" dc:"克隆来源项目ID"`
+	// ForkedFromUserId 克隆来源项目的所有者
+	ForkedFromUserId int `json:"forkedFromUserId" dc:"克隆来源项目的所有者"`
+
+	// AccessLevel 当前用户的访问级别
+	AccessLevel string `json:"accessLevel" dc:"访问级别, preview-预览, read-阅读, buy-已购买, owner-所有者"`
 }
 
 type UpdateProjectReq struct {
@@ -160,6 +169,7 @@ type PublishProjectReq struct {
 	BuyPriceCent     string    `json:"buyPriceCent" dc:"购买价格(元)"`
 	Description      *string   `json:"description" dc:"详细描述"`
 	Tags             *[]string `json:"tags" dc:"关键词数组"`
+	Forkable         *bool     `json:"forkable" dc:"是否允许未购买的用户克隆"`
 }
 
 type PublishProjectRes struct {
@@ -190,13 +200,19 @@ type PurchaseProjectReq struct {
 	ProjectId   int    `json:"projectId" v:"required|min:1" dc:"项目ID"`
 	ProjectName string `json:"projectName" v:"required|length:1,200" dc:"项目名称"`
 	PayType     string `json:"pay_type" v:"required|in:web,wap,app" dc:"支付类型: web-电脑网站, wap-手机网站, app-APP"`
+	// PurchaseType 购买类型, 默认购买项目
+	PurchaseType string `json:"purchase_type" v:"in:read,buy" d:"buy" dc:"购买类型: read-阅读权限, buy-购买"`
+	Provider     string `json:"provider" v:"in:alipay,wechat,mock" dc:"支付渠道, 为空时使用默认渠道"`
+	CouponCode   string `json:"coupon_code" v:"max-length:32" dc:"优惠码"`
 }
 
 // 购买项目响应
 type PurchaseProjectRes struct {
-	OrderString string `json:"order_string" dc:"支付订单信息(HTML或URL)"`
-	OutTradeNo  string `json:"out_trade_no" dc:"商户订单号"`
-	TotalAmount string `json:"total_amount" dc:"支付金额"`
+	OrderString    string `json:"order_string" dc:"支付订单信息(HTML或URL)"`
+	OutTradeNo     string `json:"out_trade_no" dc:"商户订单号"`
+	TotalAmount    string `json:"total_amount" dc:"支付金额"`
+	Provider       string `json:"provider" dc:"支付渠道"`
+	DiscountAmount string `json:"discount_amount" dc:"优惠金额"`
 }
 
 // 查询项目购买状态请求
@@ -249,6 +265,40 @@ type GetProjectViewCountRes struct {
 	ViewCount int `json:"view_count" dc:"浏览量"`
 }
 
+type GetProjectViewTrendReq struct {
+	g.Meta    `path:"/view/project/:project_id/trend" method:"get" tags:"项目" summary:"获取项目每日浏览量趋势"`
+	ProjectId int `json:"project_id" v:"required" dc:"项目ID"`
+	Days      int `json:"days" d:"30" v:"min:1|max:365#天数不能小于1|天数不能大于365" dc:"统计最近多少天"`
+}
+
+type GetProjectViewTrendRes struct {
+	List []*views.TrendItem `json:"list" dc:"每日浏览量, 按日期升序"`
+}
+
+type GetProjectAccessReq struct {
+	g.Meta    `path:"projects/{projectId}/access" method:"get" sm:"获取当前用户对项目的访问权限" tags:"项目管理"`
+	ProjectId int `json:"projectId" v:"required|min:1" dc:"项目ID"`
+}
+
+type GetProjectAccessRes struct {
+	AccessLevel   string  `json:"accessLevel" dc:"访问级别, none-无权访问, preview-预览, read-阅读, buy-已购买, owner-所有者"`
+	CanRead       bool    `json:"canRead" dc:"是否可在线浏览和查询完整图谱"`
+	CanExport     bool    `json:"canExport" dc:"是否可导出和克隆"`
+	ReadPriceCent float64 `json:"readPriceCent" dc:"阅读价格"`
+	BuyPriceCent  float64 `json:"buyPriceCent" dc:"购买价格"`
+}
+
+type ForkProjectReq struct {
+	g.Meta      `path:"projects/{projectId}/fork" method:"post" sm:"克隆项目到我的工作台" tags:"项目管理"`
+	ProjectId   int    `json:"projectId" v:"required|min:1" dc:"来源项目ID"`
+	ProjectName string `json:"projectName" v:"max-length:255#项目名称不能超过255个字符" dc:"新项目名称, 为空时使用来源项目名称"`
+}
+
+type ForkProjectRes struct {
+	ProjectId  int `json:"projectId" dc:"新项目ID"`
+	PipelineId int `json:"pipelineId" dc:"新项目的工作流ID"`
+}
+
 type GetTripleSourceInfoReq struct {
 	g.Meta    `path:"projects/{projectId}/triplets/source" method:"post" sm:"获取三元组来源信息" tags:"项目管理"`
 	ProjectId int                `json:"projectId" v:"required" dc:"项目ID"`
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/logic/prompts"
+	"kgplatform-backend/internal/model/entity"
+)
+
+type CreatePromptReq struct {
+	g.Meta      `path:"/prompt/create" method:"post" tags:"提示词库" sm:"创建提示词"`
+	Name        string `json:"name" v:"required|max-length:255#请输入提示词名称|提示词名称不能超过255个字符"`
+	Description string `json:"description"`
+	Scope       string `json:"scope" d:"user" v:"in:user,team,public#无效的可见范围" dc:"可见范围, user-个人, team-团队, public-公开"`
+	TeamId      int64  `json:"teamId" dc:"所属团队, scope为team时必填"`
+	Content     string `json:"content" v:"required#请输入提示词内容"`
+	ChangeNote  string `json:"changeNote" v:"max-length:500#变更说明不能超过500个字符"`
+}
+
+type CreatePromptRes struct {
+	PromptId  int `json:"promptId"`
+	VersionId int `json:"versionId"`
+	Version   int `json:"version"`
+}
+
+type CreatePromptVersionReq struct {
+	g.Meta     `path:"/prompt/version/create" method:"post" tags:"提示词库" sm:"创建提示词新版本"`
+	PromptId   int    `json:"promptId" v:"required#请选择提示词"`
+	Content    string `json:"content" v:"required#请输入提示词内容"`
+	ChangeNote string `json:"changeNote" v:"max-length:500#变更说明不能超过500个字符"`
+}
+
+type CreatePromptVersionRes struct {
+	VersionId int `json:"versionId"`
+	Version   int `json:"version"`
+}
+
+type ListPromptReq struct {
+	g.Meta  `path:"/prompt/list" method:"get" tags:"提示词库" sm:"获取可见的提示词列表"`
+	Scope   string `json:"scope" v:"in:user,team,public#无效的可见范围" dc:"可见范围, 为空时返回全部可见提示词"`
+	TeamId  int64  `json:"teamId"`
+	Keyword string `json:"keyword"`
+	Page    int    `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size    int    `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListPromptRes struct {
+	Total int               `json:"total" dc:"总记录数"`
+	List  []*entity.Prompts `json:"list"`
+}
+
+type GetPromptReq struct {
+	g.Meta `path:"/prompt/get/{id}" method:"get" tags:"提示词库" sm:"获取提示词详情及版本历史"`
+	Id     int `path:"id" v:"required#请选择提示词"`
+}
+
+type GetPromptRes struct {
+	Prompt   *entity.Prompts        `json:"prompt"`
+	Versions []*prompts.VersionItem `json:"versions" dc:"版本列表, 按版本号倒序, 包含使用统计"`
+}
+
+type DiffPromptVersionReq struct {
+	g.Meta        `path:"/prompt/version/diff" method:"get" tags:"提示词库" sm:"对比两个提示词版本"`
+	FromVersionId int `json:"fromVersionId" v:"required#请选择对比的版本"`
+	ToVersionId   int `json:"toVersionId" v:"required#请选择对比的版本"`
+}
+
+type DiffPromptVersionRes struct {
+	From  *entity.PromptVersions `json:"from"`
+	To    *entity.PromptVersions `json:"to"`
+	Lines []prompts.DiffLine     `json:"lines" dc:"按行对比结果"`
+}
+
+type ListPromptVersionUsageReq struct {
+	g.Meta    `path:"/prompt/version/usage" method:"get" tags:"提示词库" sm:"获取提示词版本的使用记录"`
+	VersionId int `json:"versionId" v:"required#请选择版本"`
+	Page      int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size      int `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListPromptVersionUsageRes struct {
+	Stats *prompts.UsageStats    `json:"stats" dc:"该版本在所有抽取中的统计"`
+	Total int                    `json:"total" dc:"总记录数"`
+	List  []*entity.PromptUsages `json:"list" dc:"当前用户的使用记录"`
+}
@@ -0,0 +1,14 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// CallbackReq Python 服务任务状态回调, 请求头需携带 X-Timestamp 与 X-Signature
+type CallbackReq struct {
+	g.Meta `path:"/python/callback" method:"post" tags:"Python服务" summary:"Python任务状态回调"`
+}
+
+type CallbackRes struct {
+	Result string `json:"result"`
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/logic/revenue"
+	"kgplatform-backend/internal/model/entity"
+)
+
+type GetRevenueStatementReq struct {
+	g.Meta `path:"/revenue/statement" method:"get" tags:"创作者收入" sm:"获取当前用户的月度收入对账单"`
+	Period string `json:"period" v:"date-format:Y-m#结算月份格式错误" dc:"结算月份, 格式 YYYY-MM, 默认当月"`
+	Page   int    `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int    `json:"size" d:"20" v:"min:1|max:100#每页大小不能小于1|每页大小不能大于100" dc:"每页大小"`
+}
+
+type GetRevenueStatementRes struct {
+	Period  string                          `json:"period" dc:"结算月份"`
+	Summary *revenue.Summary                `json:"summary" dc:"当月收入汇总"`
+	Balance *revenue.Balance                `json:"balance" dc:"截至目前的余额"`
+	Total   int                             `json:"total" dc:"当月流水总数"`
+	List    []*entity.CreatorRevenueEntries `json:"list" dc:"当月收入流水"`
+}
+
+type ListCreatorBalanceReq struct {
+	g.Meta `path:"/admin/revenue/balances" method:"get" tags:"创作者收入" sm:"获取各创作者的余额"`
+	Page   int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int `json:"size" d:"20" v:"min:1|max:100#每页大小不能小于1|每页大小不能大于100" dc:"每页大小"`
+}
+
+type ListCreatorBalanceRes struct {
+	Total int                `json:"total" dc:"创作者总数"`
+	List  []*revenue.Balance `json:"list"`
+}
+
+type CreatePayoutBatchReq struct {
+	g.Meta `path:"/admin/revenue/payout-batches" method:"post" tags:"创作者收入" sm:"生成结算批次"`
+	Period string `json:"period" v:"required|date-format:Y-m#请选择结算月份|结算月份格式错误" dc:"结算截止月份, 格式 YYYY-MM"`
+}
+
+type CreatePayoutBatchRes struct {
+	Batch *entity.CreatorPayoutBatches `json:"batch"`
+}
+
+type ListPayoutBatchReq struct {
+	g.Meta `path:"/admin/revenue/payout-batches" method:"get" tags:"创作者收入" sm:"获取结算批次列表"`
+	Page   int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListPayoutBatchRes struct {
+	Total int                            `json:"total" dc:"总记录数"`
+	List  []*entity.CreatorPayoutBatches `json:"list"`
+}
+
+type MarkPayoutBatchPaidReq struct {
+	g.Meta  `path:"/admin/revenue/payout-batches/{batchId}/paid" method:"post" tags:"创作者收入" sm:"标记结算批次已打款"`
+	BatchId int `path:"batchId" v:"required|min:1#请选择结算批次"`
+}
+
+type MarkPayoutBatchPaidRes struct{}
+
+type ExportPayoutBatchReq struct {
+	g.Meta  `path:"/admin/revenue/payout-batches/{batchId}/export" method:"get" tags:"创作者收入" sm:"导出结算批次打款明细CSV"`
+	BatchId int `path:"batchId" v:"required|min:1#请选择结算批次"`
+}
+
+type ExportPayoutBatchRes struct{}
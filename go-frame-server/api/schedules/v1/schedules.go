@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/model/entity"
+)
+
+type CreateScheduleReq struct {
+	g.Meta          `path:"/schedule/create" method:"post" tags:"定时运行" sm:"创建工作流定时运行计划"`
+	PipelineId      int    `json:"pipelineId" v:"required#请选择工作流"`
+	CronExpr        string `json:"cronExpr" v:"required#请输入cron表达式" dc:"cron表达式, 如 0 0 2 * * * 表示每天凌晨2点"`
+	NotifyOnFailure bool   `json:"notifyOnFailure" dc:"运行失败时是否邮件通知"`
+}
+
+type CreateScheduleRes struct {
+	*entity.PipelineSchedules
+}
+
+type ListScheduleReq struct {
+	g.Meta     `path:"/schedule/list" method:"get" tags:"定时运行" sm:"获取定时运行计划列表"`
+	PipelineId int `json:"pipelineId" dc:"工作流ID, 为空时返回全部"`
+}
+
+type ListScheduleRes struct {
+	List []*entity.PipelineSchedules `json:"list"`
+}
+
+type PauseScheduleReq struct {
+	g.Meta `path:"/schedule/pause/{id}" method:"post" tags:"定时运行" sm:"暂停定时运行计划"`
+	Id     int `path:"id" v:"required#请选择运行计划"`
+}
+
+type PauseScheduleRes struct{}
+
+type ResumeScheduleReq struct {
+	g.Meta `path:"/schedule/resume/{id}" method:"post" tags:"定时运行" sm:"恢复定时运行计划"`
+	Id     int `path:"id" v:"required#请选择运行计划"`
+}
+
+type ResumeScheduleRes struct{}
+
+type DeleteScheduleReq struct {
+	g.Meta `path:"/schedule/delete/{id}" method:"delete" tags:"定时运行" sm:"删除定时运行计划"`
+	Id     int `path:"id" v:"required#请选择运行计划"`
+}
+
+type DeleteScheduleRes struct{}
+
+type ListScheduleRunReq struct {
+	g.Meta `path:"/schedule/runs/{id}" method:"get" tags:"定时运行" sm:"获取定时运行记录"`
+	Id     int `path:"id" v:"required#请选择运行计划"`
+	Page   int `json:"page" d:"1" v:"min:1#页码不能小于1" dc:"页码"`
+	Size   int `json:"size" d:"10" v:"min:1|max:50#每页大小不能小于1|每页大小不能大于50" dc:"每页大小"`
+}
+
+type ListScheduleRunRes struct {
+	Total int                            `json:"total" dc:"总记录数"`
+	List  []*entity.PipelineScheduleRuns `json:"list"`
+}
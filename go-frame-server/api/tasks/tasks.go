@@ -15,6 +15,7 @@ type ITasksV1 interface {
 	GetTask(ctx context.Context, req *v1.GetTaskReq) (res *v1.GetTaskRes, err error)
 	CreateOCRTask(ctx context.Context, req *v1.CreateOCRTaskReq) (res *v1.CreateOCRTaskRes, err error)
 	CreateExtractTask(ctx context.Context, req *v1.CreateExtractTaskReq) (res *v1.CreateExtractTaskRes, err error)
+	RetryFailedTask(ctx context.Context, req *v1.RetryFailedTaskReq) (res *v1.RetryFailedTaskRes, err error)
 	CreateGraphTask(ctx context.Context, req *v1.CreateGraphTaskReq) (res *v1.CreateGraphTaskRes, err error)
 	ListTask(ctx context.Context, req *v1.ListTaskReq) (res *v1.ListTaskRes, err error)
 }
@@ -34,6 +34,20 @@ type GetTaskRes struct {
 	CreateTime string `json:"createTime"`
 	StartTime  string `json:"startTime" dc:"任务开始处理的时间"`
 	FinishTime string `json:"finishTime"`
+
+	ErrorMessage string           `json:"errorMessage"`
+	PyTaskId     string           `json:"pyTaskId" dc:"抽取服务的任务ID"`
+	Results      []TaskFileResult `json:"results" dc:"各素材的抽取结果"`
+}
+
+// TaskFileResult 单个素材的抽取结果
+type TaskFileResult struct {
+	MaterialId   int               `json:"materialId"`
+	FileName     string            `json:"fileName"`
+	Status       string            `json:"status" dc:"success, failed"`
+	TriplesCount int               `json:"triplesCount"`
+	OutputFiles  map[string]string `json:"outputFiles" dc:"输出文件, 如 jsonl"`
+	Error        string            `json:"error" dc:"失败原因"`
 }
 
 type CreateOCRTaskReq struct {
@@ -54,9 +68,12 @@ type CreateExtractTaskReq struct {
 	ProjectId      int    `json:"projectId" v:"required#请选择项目"`
 	PipelineId     int    `json:"pipelineId" v:"required#请选择管道"`
 	Method         string `json:"method" v:"required#请选择抽取方式"`
-	Prompt         string `json:"prompt" v:"required#请输入提示词"`
+	Prompt         string `json:"prompt" v:"required-without:PromptVersionId#请输入提示词"`
 	ModelId        *int   `json:"modelId" v:"required#请选择大模型"`
 
+	// 引用提示词库中的版本, 设置后忽略 Prompt
+	PromptVersionId *int `json:"promptVersionId"`
+
 	// genprompt 接口返回的配置
 	SchemaURL              string   `json:"schemaUrl"`
 	SampleTextURL          string   `json:"sampleTextUrl"`
@@ -73,6 +90,18 @@ type CreateExtractTaskRes struct {
 	Status string `json:"status" dc:"任务状态, pending, processing, completed, failed"`
 }
 
+type RetryFailedTaskReq struct {
+	g.Meta `path:"/task/retry-failed/{id}" method:"post" tags:"任务" sm:"重试抽取失败的素材" dc:"创建只包含失败素材的子任务, 沿用原任务的抽取配置"`
+	Id     int `path:"id" v:"required#请选择任务"`
+}
+
+type RetryFailedTaskRes struct {
+	TaskId         int    `json:"id"`
+	ParentTaskId   int    `json:"parentTaskId"`
+	Status         string `json:"status" dc:"任务状态, pending, processing, completed, failed"`
+	MaterialIdList []int  `json:"materialIdList" dc:"重试的素材"`
+}
+
 type CreateGraphTaskReq struct {
 	g.Meta         `path:"/task/graph" method:"post" tags:"任务" sm:"创建图谱任务"`
 	MaterialIDList []int `json:"materialIdList" v:"required#请选择素材"`
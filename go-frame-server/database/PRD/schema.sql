@@ -23,6 +23,7 @@ CREATE TYPE payment_status_enum AS ENUM ('pending', 'paid', 'failed', 'refunded'
 CREATE TYPE billing_status_enum AS ENUM ('unpaid', 'paid');
 
 ALTER TYPE billing_type_enum ADD VALUE 'project';
+ALTER TYPE billing_status_enum ADD VALUE 'refunded';
 
 -- 创建用户表
 CREATE TABLE users
@@ -224,7 +225,12 @@ create table tasks
     finish_time      timestamp with time zone,
     material_id_list integer[],
     error_message    text,
-    project_id       integer
+    project_id       integer,
+    py_task_id       varchar(64),
+    results          jsonb,
+    parent_task_id   integer,
+    extract_config   jsonb,
+    schedule_id      integer
 );
 
 comment
@@ -237,11 +243,23 @@ comment
 on column tasks.material_id_list is '任务需处理的材料';
 comment
 on column tasks.error_message is '任务失败日志';
+comment
+on column tasks.py_task_id is 'Python服务任务ID';
+comment
+on column tasks.results is '各素材的抽取结果: material_id, status, triples_count, output_files, error';
+comment
+on column tasks.parent_task_id is '重试任务对应的原任务ID';
+comment
+on column tasks.extract_config is '创建任务时的抽取配置';
+comment
+on column tasks.schedule_id is '创建该任务的定时运行计划ID';
 
 alter table tasks
     owner to postgres;
 
 create index idx_tasks_pipeline_id on tasks (pipeline_id);
+create index idx_tasks_py_task_id on tasks (py_task_id);
+create index idx_tasks_parent_task_id on tasks (parent_task_id);
 
 -- 创建视图表（收藏功能）
 create table views
@@ -946,4 +964,912 @@ ON COLUMN traffic_logs.endpoint IS '请求端点';
 COMMENT
 ON COLUMN traffic_logs.ip_address IS 'IP地址';
 COMMENT
-ON COLUMN traffic_logs.created_at IS '创建时间';
\ No newline at end of file
+ON COLUMN traffic_logs.created_at IS '创建时间';
+
+-- 创建模型对比实验表
+CREATE TABLE extract_experiments
+(
+    id               SERIAL PRIMARY KEY,
+    user_id          INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    project_id       INTEGER NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    task_id          INTEGER,
+    material_id_list INTEGER[],
+    status           VARCHAR(50),
+    report           JSONB,
+    promoted_run_id  INTEGER,
+    error_message    TEXT,
+    finish_time      TIMESTAMP WITH TIME ZONE,
+    created_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+comment
+on table extract_experiments is '模型对比实验表';
+comment
+on column extract_experiments.task_id is '关联的experiment类型任务';
+comment
+on column extract_experiments.material_id_list is '实验抽样的素材';
+comment
+on column extract_experiments.status is '实验状态, pending-待处理, processing-处理中, completed-完成, failed-失败';
+comment
+on column extract_experiments.report is '对比报告';
+comment
+on column extract_experiments.promoted_run_id is '已应用到项目的运行ID';
+
+-- 创建模型对比实验运行表（每个模型/提示词组合一条）
+CREATE TABLE extract_experiment_runs
+(
+    id               SERIAL PRIMARY KEY,
+    experiment_id    INTEGER NOT NULL REFERENCES extract_experiments (id) ON DELETE CASCADE,
+    model_id         INTEGER NOT NULL,
+    prompt           TEXT,
+    py_task_id       VARCHAR(100),
+    status           VARCHAR(50),
+    triples_count    INTEGER        DEFAULT 0,
+    triple_url       TEXT,
+    words_used       INTEGER        DEFAULT 0,
+    cost_multiplier  NUMERIC(6, 2)  DEFAULT 1.00,
+    cost             NUMERIC(10, 2) DEFAULT 0.00,
+    conformance_rate NUMERIC(5, 4)  DEFAULT 0.0000,
+    error_message    TEXT,
+    finish_time      TIMESTAMP WITH TIME ZONE,
+    created_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+comment
+on table extract_experiment_runs is '模型对比实验运行表';
+comment
+on column extract_experiment_runs.py_task_id is 'Python服务任务ID';
+comment
+on column extract_experiment_runs.triple_url is '本次运行的三元组结果存储URL';
+comment
+on column extract_experiment_runs.words_used is '按成本系数折算后的消耗字数';
+comment
+on column extract_experiment_runs.cost is '按超额单价估算的费用';
+comment
+on column extract_experiment_runs.conformance_rate is '符合主体结构的三元组比例';
+
+CREATE INDEX idx_experiments_project_id ON extract_experiments (project_id);
+CREATE INDEX idx_experiment_runs_experiment_id ON extract_experiment_runs (experiment_id);
+
+-- 创建提示词库表
+CREATE TABLE prompts
+(
+    id             SERIAL PRIMARY KEY,
+    name           VARCHAR(255) NOT NULL,
+    description    TEXT,
+    scope          VARCHAR(20)  NOT NULL    DEFAULT 'user',
+    user_id        INTEGER      NOT NULL,
+    team_id        BIGINT,
+    latest_version INTEGER      NOT NULL    DEFAULT 0,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+comment
+on table prompts is '提示词库表';
+comment
+on column prompts.scope is '可见范围, user-个人, team-团队, public-公开';
+comment
+on column prompts.user_id is '创建者';
+comment
+on column prompts.team_id is '所属团队, scope为team时有效';
+comment
+on column prompts.latest_version is '最新版本号';
+
+-- 创建提示词版本表
+CREATE TABLE prompt_versions
+(
+    id          SERIAL PRIMARY KEY,
+    prompt_id   INTEGER NOT NULL REFERENCES prompts (id) ON DELETE CASCADE,
+    version     INTEGER NOT NULL,
+    content     TEXT    NOT NULL,
+    change_note VARCHAR(500),
+    created_by  INTEGER,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (prompt_id, version)
+);
+
+comment
+on table prompt_versions is '提示词版本表, 版本创建后不可修改';
+comment
+on column prompt_versions.change_note is '版本变更说明';
+
+-- 创建提示词使用记录表（关联使用该版本的抽取任务/实验运行）
+CREATE TABLE prompt_usages
+(
+    id                SERIAL PRIMARY KEY,
+    prompt_version_id INTEGER NOT NULL REFERENCES prompt_versions (id) ON DELETE CASCADE,
+    user_id           INTEGER,
+    project_id        INTEGER,
+    task_id           INTEGER,
+    experiment_run_id INTEGER,
+    status            VARCHAR(50),
+    triples_count     INTEGER                  DEFAULT 0,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+comment
+on table prompt_usages is '提示词使用记录表';
+comment
+on column prompt_usages.task_id is '使用该版本的抽取任务';
+comment
+on column prompt_usages.experiment_run_id is '使用该版本的实验运行';
+comment
+on column prompt_usages.status is '任务状态, pending-待处理, processing-处理中, completed-完成, failed-失败';
+comment
+on column prompt_usages.triples_count is '抽取得到的三元组数量';
+
+CREATE INDEX idx_prompts_user_id ON prompts (user_id);
+CREATE INDEX idx_prompts_team_id ON prompts (team_id);
+CREATE INDEX idx_prompt_usages_version_id ON prompt_usages (prompt_version_id);
+CREATE INDEX idx_prompt_usages_task_id ON prompt_usages (task_id);
+
+-- 创建 Python 任务回调事件表（用于回调去重）
+CREATE TABLE python_callback_events
+(
+    id         SERIAL PRIMARY KEY,
+    event_id   VARCHAR(100) NOT NULL UNIQUE,
+    py_task_id VARCHAR(100),
+    task_id    INTEGER,
+    status     VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+comment
+on table python_callback_events is 'Python任务回调事件表, 按event_id去重';
+comment
+on column python_callback_events.py_task_id is 'Python服务任务ID';
+comment
+on column python_callback_events.task_id is '对应的Go任务ID';
+comment
+on column python_callback_events.status is '回调中的Python任务状态';
+
+CREATE INDEX idx_python_callback_events_py_task_id ON python_callback_events (py_task_id);
+
+-- 创建工作流定时运行计划表
+create table pipeline_schedules
+(
+    id                serial primary key,
+    pipeline_id       integer                  not null references pipelines (id) on delete cascade,
+    project_id        integer                  not null,
+    user_id           integer                  not null,
+    cron_expr         varchar(100)             not null,
+    status            varchar(20)              not null default 'active',
+    notify_on_failure boolean                  not null default true,
+    last_material_id  integer                  not null default 0,
+    last_run_at       timestamp with time zone,
+    created_at        timestamp with time zone          default CURRENT_TIMESTAMP,
+    updated_at        timestamp with time zone          default CURRENT_TIMESTAMP
+);
+
+comment
+on table pipeline_schedules is '工作流定时运行计划';
+comment
+on column pipeline_schedules.user_id is '创建者';
+comment
+on column pipeline_schedules.cron_expr is 'cron表达式';
+comment
+on column pipeline_schedules.status is '状态, active-启用, paused-暂停';
+comment
+on column pipeline_schedules.notify_on_failure is '运行失败时是否邮件通知';
+comment
+on column pipeline_schedules.last_material_id is '已处理的最大素材ID, 下次运行处理之后新增的素材';
+comment
+on column pipeline_schedules.last_run_at is '最近一次运行时间';
+
+create index idx_pipeline_schedules_pipeline_id on pipeline_schedules (pipeline_id);
+
+-- 创建工作流定时运行记录表
+create table pipeline_schedule_runs
+(
+    id               serial primary key,
+    schedule_id      integer     not null references pipeline_schedules (id) on delete cascade,
+    status           varchar(20) not null,
+    material_id_list integer[],
+    ocr_task_id      integer,
+    extract_task_id  integer,
+    graph_task_id    integer,
+    error_message    text,
+    start_time       timestamp with time zone,
+    finish_time      timestamp with time zone,
+    created_at       timestamp with time zone default CURRENT_TIMESTAMP
+);
+
+comment
+on table pipeline_schedule_runs is '工作流定时运行记录';
+comment
+on column pipeline_schedule_runs.status is '运行状态, running-运行中, completed-完成, failed-失败, skipped-无新增素材';
+comment
+on column pipeline_schedule_runs.material_id_list is '本次处理的素材';
+comment
+on column pipeline_schedule_runs.error_message is '失败原因';
+
+create index idx_pipeline_schedule_runs_schedule_id on pipeline_schedule_runs (schedule_id);
+
+-- 创建定时任务运行记录表
+create table cron_job_runs
+(
+    id            serial primary key,
+    job_name      varchar(100) not null,
+    trigger_type  varchar(20)  not null default 'schedule',
+    triggered_by  integer,
+    instance      varchar(255),
+    status        varchar(20)  not null,
+    error_message text,
+    start_time    timestamp with time zone,
+    finish_time   timestamp with time zone,
+    duration_ms   bigint,
+    created_at    timestamp with time zone default current_timestamp
+);
+
+comment
+on table cron_job_runs is '定时任务运行记录';
+comment
+on column cron_job_runs.job_name is '定时任务名称';
+comment
+on column cron_job_runs.trigger_type is '触发方式, schedule-定时触发, manual-手动触发';
+comment
+on column cron_job_runs.triggered_by is '手动触发的用户ID';
+comment
+on column cron_job_runs.instance is '执行任务的实例';
+comment
+on column cron_job_runs.status is '运行结果, running-运行中, success-成功, failed-失败';
+comment
+on column cron_job_runs.error_message is '失败原因';
+comment
+on column cron_job_runs.duration_ms is '运行耗时（毫秒）';
+
+create index idx_cron_job_runs_job_name on cron_job_runs (job_name, id desc);
+
+-- 创建项目浏览事件表
+create table project_view_events
+(
+    id         serial primary key,
+    event_id   varchar(64) not null unique,
+    project_id integer     not null,
+    user_id    integer     not null default 0,
+    viewer     varchar(100) not null,
+    ip         varchar(64),
+    viewed_at  timestamp with time zone not null,
+    created_at timestamp with time zone default current_timestamp
+);
+
+comment
+on table project_view_events is '项目浏览事件表, 访客去重后的浏览明细';
+comment
+on column project_view_events.event_id is '浏览事件ID, 用于批量写入去重';
+comment
+on column project_view_events.user_id is '浏览用户ID, 未登录时为0';
+comment
+on column project_view_events.viewer is '去重用的访客标识, 登录用户为u:用户ID, 未登录为ip:IP地址';
+comment
+on column project_view_events.viewed_at is '浏览时间';
+
+create index idx_project_view_events_project_id on project_view_events (project_id, viewed_at);
+
+-- 创建项目每日浏览量汇总表
+create table project_view_daily
+(
+    id             serial primary key,
+    project_id     integer not null,
+    view_date      date    not null,
+    views          integer not null default 0,
+    unique_viewers integer not null default 0,
+    updated_at     timestamp with time zone default current_timestamp,
+    unique (project_id, view_date)
+);
+
+comment
+on table project_view_daily is '项目每日浏览量汇总表';
+comment
+on column project_view_daily.view_date is '统计日期';
+comment
+on column project_view_daily.views is '浏览量';
+comment
+on column project_view_daily.unique_viewers is '独立访客数';
+
+-- 区分项目的阅读权限与购买
+alter table user_project_purchases
+    add column purchase_type varchar(20) not null default 'buy';
+
+comment
+on column user_project_purchases.purchase_type is '购买类型, read-阅读权限, buy-购买';
+
+alter table user_project_purchases
+    drop constraint uk_user_project;
+alter table user_project_purchases
+    add constraint uk_user_project_type unique (user_id, project_id, purchase_type);
+
+-- 项目克隆
+alter table projects
+    add column forkable boolean not null default false;
+alter table projects
+    add column forked_from integer references projects (id) on delete set null;
+alter table projects
+    add column forked_from_user_id integer;
+
+comment
+on column projects.forkable is '是否允许未购买的用户克隆';
+comment
+on column projects.forked_from is '克隆来源项目ID';
+comment
+on column projects.forked_from_user_id is '克隆来源项目的所有者, 来源项目删除后仍保留署名';
+
+create index idx_projects_forked_from on projects (forked_from);
+
+-- 记录购买时的平台抽成比例, 收入流水按购买时的比例记账
+alter table user_project_purchases
+    add column commission_rate numeric(5, 4);
+
+comment
+on column user_project_purchases.commission_rate is '购买时的平台抽成比例, 为空时按当前配置';
+
+-- 创建创作者收入流水表
+create table creator_revenue_entries
+(
+    id                serial primary key,
+    creator_id        integer        not null,
+    project_id        integer        not null,
+    purchase_id       integer        not null,
+    payment_id        integer        not null,
+    buyer_id          integer        not null,
+    entry_type        varchar(20)    not null,
+    purchase_type     varchar(20)    not null,
+    gross_amount      numeric(10, 2) not null,
+    commission_rate   numeric(5, 4)  not null,
+    commission_amount numeric(10, 2) not null,
+    net_amount        numeric(10, 2) not null,
+    period            varchar(7)     not null,
+    occurred_at       timestamp with time zone not null,
+    payout_batch_id   integer,
+    created_at        timestamp with time zone default current_timestamp,
+    unique (payment_id, entry_type)
+);
+
+comment
+on table creator_revenue_entries is '创作者收入流水表, 每笔项目销售和退款各一条';
+comment
+on column creator_revenue_entries.creator_id is '创作者(项目所有者)ID';
+comment
+on column creator_revenue_entries.purchase_id is '项目购买记录ID';
+comment
+on column creator_revenue_entries.payment_id is '支付记录ID, 退款后再次购买会复用购买记录, 流水按每次支付记账';
+comment
+on column creator_revenue_entries.buyer_id is '购买用户ID';
+comment
+on column creator_revenue_entries.entry_type is '类型, sale-销售, refund-退款';
+comment
+on column creator_revenue_entries.purchase_type is '购买类型, read-阅读权限, buy-购买';
+comment
+on column creator_revenue_entries.gross_amount is '订单金额, 退款为负数';
+comment
+on column creator_revenue_entries.commission_rate is '平台抽成比例';
+comment
+on column creator_revenue_entries.commission_amount is '平台抽成金额, 退款为负数';
+comment
+on column creator_revenue_entries.net_amount is '创作者收入, 退款为负数';
+comment
+on column creator_revenue_entries.period is '结算月份, 格式 YYYY-MM';
+comment
+on column creator_revenue_entries.occurred_at is '购买或退款时间';
+comment
+on column creator_revenue_entries.payout_batch_id is '结算批次ID, 未结算为空';
+
+create index idx_creator_revenue_entries_creator on creator_revenue_entries (creator_id, period);
+create index idx_creator_revenue_entries_unsettled on creator_revenue_entries (creator_id) where payout_batch_id is null;
+
+-- 创建创作者结算批次表
+create table creator_payout_batches
+(
+    id            serial primary key,
+    period        varchar(7)     not null,
+    status        varchar(20)    not null default 'pending',
+    creator_count integer        not null default 0,
+    total_amount  numeric(12, 2) not null default 0,
+    created_by    integer        not null,
+    paid_at       timestamp with time zone,
+    created_at    timestamp with time zone default current_timestamp,
+    updated_at    timestamp with time zone default current_timestamp
+);
+
+comment
+on table creator_payout_batches is '创作者结算批次表';
+comment
+on column creator_payout_batches.period is '结算截止月份, 格式 YYYY-MM';
+comment
+on column creator_payout_batches.status is '状态, pending-待打款, paid-已打款';
+comment
+on column creator_payout_batches.creator_count is '创作者数量';
+comment
+on column creator_payout_batches.total_amount is '打款总金额';
+comment
+on column creator_payout_batches.created_by is '创建批次的管理员ID';
+comment
+on column creator_payout_batches.paid_at is '打款时间';
+
+-- 创建创作者结算明细表
+create table creator_payout_items
+(
+    id          serial primary key,
+    batch_id    integer        not null references creator_payout_batches (id) on delete cascade,
+    creator_id  integer        not null,
+    entry_count integer        not null default 0,
+    amount      numeric(10, 2) not null,
+    created_at  timestamp with time zone default current_timestamp,
+    unique (batch_id, creator_id)
+);
+
+comment
+on table creator_payout_items is '创作者结算明细表, 每个批次中每位创作者一条';
+comment
+on column creator_payout_items.batch_id is '结算批次ID';
+comment
+on column creator_payout_items.entry_count is '结算的收入记录数';
+comment
+on column creator_payout_items.amount is '打款金额';
+
+-- 创建支付订单表
+create table payment_orders
+(
+    id             serial primary key,
+    out_trade_no   varchar(64)  not null unique,
+    provider       varchar(20)  not null,
+    user_id        integer      not null,
+    order_type     varchar(20)  not null,
+    biz_id         integer      not null default 0,
+    biz_data       text,
+    subject        varchar(200) not null,
+    amount_cent    bigint       not null check (amount_cent > 0),
+    status         varchar(20)  not null default 'created',
+    transaction_id varchar(100),
+    billing_id     integer,
+    payment_id     integer,
+    expire_at      timestamp with time zone,
+    paid_at        timestamp with time zone,
+    closed_at      timestamp with time zone,
+    refunded_at    timestamp with time zone,
+    created_at     timestamp with time zone default current_timestamp,
+    updated_at     timestamp with time zone default current_timestamp
+);
+
+comment
+on table payment_orders is '支付订单表, 状态只能按 created -> paid -> refunded 或 created -> closed 流转';
+comment
+on column payment_orders.out_trade_no is '商户订单号';
+comment
+on column payment_orders.provider is '支付渠道, alipay-支付宝, wechat-微信支付, mock-模拟支付';
+comment
+on column payment_orders.order_type is '订单类型, project-购买项目, subscription-订阅套餐, billing-支付账单';
+comment
+on column payment_orders.biz_id is '业务ID, 购买项目时为项目ID, 支付账单时为账单ID';
+comment
+on column payment_orders.biz_data is '业务参数JSON, 如购买类型、套餐和人数';
+comment
+on column payment_orders.amount_cent is '订单金额（分）';
+comment
+on column payment_orders.status is '订单状态, created-待支付, paid-已支付, refunded-已退款, closed-已关闭';
+comment
+on column payment_orders.transaction_id is '支付渠道交易号';
+comment
+on column payment_orders.billing_id is '支付成功后关联的账单ID';
+comment
+on column payment_orders.payment_id is '支付成功后关联的支付记录ID';
+comment
+on column payment_orders.expire_at is '订单过期时间';
+
+create index idx_payment_orders_user_id on payment_orders (user_id, id desc);
+create index idx_payment_orders_status on payment_orders (status, created_at);
+create unique index uk_payment_orders_transaction on payment_orders (provider, transaction_id) where transaction_id is not null;
+
+-- 创建支付订单状态变更记录表
+create table payment_order_events
+(
+    id          serial primary key,
+    order_id    integer     not null references payment_orders (id) on delete cascade,
+    event_key   varchar(200) not null,
+    source      varchar(20) not null,
+    from_status varchar(20) not null,
+    to_status   varchar(20) not null,
+    applied     boolean     not null default false,
+    raw         text,
+    created_at  timestamp with time zone default current_timestamp
+);
+
+comment
+on table payment_order_events is '支付订单状态变更记录表, 记录每次收到的通知和查询结果';
+comment
+on column payment_order_events.event_key is '去重键, 同一通知或同一交易状态只生效一次';
+comment
+on column payment_order_events.source is '来源, notify-支付通知, query-主动查询, expire-超时关闭, refund-申请退款, cancel-业务取消';
+comment
+on column payment_order_events.applied is '是否引起了状态变化';
+comment
+on column payment_order_events.raw is '渠道原始数据';
+
+create unique index uk_payment_order_events_key on payment_order_events (event_key) where applied;
+create index idx_payment_order_events_order_id on payment_order_events (order_id);
+
+-- 创建支付退款表
+create table payment_refunds
+(
+    id            serial primary key,
+    order_id      integer      not null references payment_orders (id) on delete cascade,
+    out_refund_no varchar(64)  not null unique,
+    refund_cent   bigint       not null,
+    reason        varchar(200) not null default '',
+    status        varchar(20)  not null default 'processing',
+    refund_id     varchar(64)  not null default '',
+    created_at    timestamp with time zone default current_timestamp,
+    updated_at    timestamp with time zone default current_timestamp
+);
+
+comment
+on table payment_refunds is '支付退款表, 每次退款申请一条记录, 累计退款金额不超过订单金额';
+comment
+on column payment_refunds.out_refund_no is '退款单号, 按订单顺序编号, 重试时沿用';
+comment
+on column payment_refunds.refund_cent is '退款金额(分)';
+comment
+on column payment_refunds.reason is '退款原因';
+comment
+on column payment_refunds.status is '退款状态, processing-处理中, success-成功, failed-失败';
+comment
+on column payment_refunds.refund_id is '渠道退款单号';
+
+create index idx_payment_refunds_order_id on payment_refunds (order_id);
+
+-- 创建支付对账报告表
+create table payment_reconcile_reports
+(
+    id                  serial primary key,
+    report_date         date        not null unique,
+    status              varchar(20) not null default 'matched',
+    payment_count       integer     not null default 0,
+    payment_amount_cent bigint      not null default 0,
+    mismatch_count      integer     not null default 0,
+    created_at          timestamp with time zone default current_timestamp,
+    updated_at          timestamp with time zone default current_timestamp
+);
+
+comment
+on table payment_reconcile_reports is '支付对账报告表, 每天一份, 重新生成时覆盖';
+comment
+on column payment_reconcile_reports.report_date is '对账日期';
+comment
+on column payment_reconcile_reports.status is '状态, matched-一致, mismatched-存在差异';
+comment
+on column payment_reconcile_reports.payment_count is '当日本地支付记录数';
+comment
+on column payment_reconcile_reports.payment_amount_cent is '当日本地支付金额（分）';
+comment
+on column payment_reconcile_reports.mismatch_count is '差异数量';
+
+-- 创建支付对账差异明细表
+create table payment_reconcile_items
+(
+    id                   serial primary key,
+    report_id            integer     not null references payment_reconcile_reports (id) on delete cascade,
+    provider             varchar(20) not null,
+    out_trade_no         varchar(64) not null,
+    payment_id           integer     not null default 0,
+    mismatch_type        varchar(20) not null,
+    local_status         varchar(20),
+    provider_status      varchar(20),
+    local_amount_cent    bigint      not null default 0,
+    provider_amount_cent bigint      not null default 0,
+    detail               varchar(500),
+    created_at           timestamp with time zone default current_timestamp
+);
+
+comment
+on table payment_reconcile_items is '支付对账差异明细表';
+comment
+on column payment_reconcile_items.provider is '支付渠道';
+comment
+on column payment_reconcile_items.out_trade_no is '商户订单号';
+comment
+on column payment_reconcile_items.payment_id is '本地支付记录ID, 本地没有支付记录时为0';
+comment
+on column payment_reconcile_items.mismatch_type is '差异类型, status-状态不一致, amount-金额不一致, transaction-交易号不一致, unpaid-渠道已支付但本地未支付, query_failed-查询渠道失败';
+comment
+on column payment_reconcile_items.local_status is '本地状态';
+comment
+on column payment_reconcile_items.provider_status is '渠道状态';
+comment
+on column payment_reconcile_items.local_amount_cent is '本地金额（分）';
+comment
+on column payment_reconcile_items.provider_amount_cent is '渠道金额（分）';
+comment
+on column payment_reconcile_items.detail is '差异说明';
+
+create index idx_payment_reconcile_items_report on payment_reconcile_items (report_id);
+
+-- 创建月度账单唯一索引, 同一账户同一账期只有一张月度账单, 重复出账时更新
+create unique index uk_billing_records_month on billing_records (user_id, coalesce(team_id, 0), billing_period) where billing_type = 'month';
+
+-- 创建优惠券表
+create table coupons
+(
+    id                  serial primary key,
+    code                varchar(32)  not null unique,
+    name                varchar(100) not null,
+    discount_type       varchar(20)  not null,
+    percent_off         integer      not null default 0,
+    amount_off_cent     bigint       not null default 0,
+    max_discount_cent   bigint       not null default 0,
+    min_amount_cent     bigint       not null default 0,
+    order_types         varchar(20)[],
+    plans               varchar(20)[],
+    project_ids         integer[],
+    first_purchase_only boolean      not null default false,
+    total_limit         integer      not null default 0,
+    per_user_limit      integer      not null default 1,
+    used_count          integer      not null default 0,
+    starts_at           timestamp with time zone,
+    expires_at          timestamp with time zone,
+    status              varchar(20)  not null default 'active',
+    created_by          integer      not null default 0,
+    created_at          timestamp with time zone default current_timestamp,
+    updated_at          timestamp with time zone default current_timestamp
+);
+
+comment
+on table coupons is '优惠券表, 用户下单时填写优惠码';
+comment
+on column coupons.code is '优惠码, 大写字母和数字';
+comment
+on column coupons.name is '优惠券名称';
+comment
+on column coupons.discount_type is '优惠类型, percent-按比例, fixed-固定金额';
+comment
+on column coupons.percent_off is '按比例优惠的百分比, 20 表示减免20%';
+comment
+on column coupons.amount_off_cent is '固定减免金额（分）';
+comment
+on column coupons.max_discount_cent is '按比例优惠的最高减免金额（分）, 0 表示不限';
+comment
+on column coupons.min_amount_cent is '订单金额门槛（分）, 0 表示不限';
+comment
+on column coupons.order_types is '适用的订单类型, subscription-订阅, billing-账单, project-项目, 为空表示不限';
+comment
+on column coupons.plans is '适用的套餐, 为空表示不限';
+comment
+on column coupons.project_ids is '适用的项目, 为空表示不限';
+comment
+on column coupons.first_purchase_only is '是否仅限首次购买';
+comment
+on column coupons.total_limit is '总使用次数上限, 0 表示不限';
+comment
+on column coupons.per_user_limit is '每个用户的使用次数上限, 0 表示不限';
+comment
+on column coupons.used_count is '已使用次数, 含待支付订单占用的次数';
+comment
+on column coupons.starts_at is '生效时间, 为空表示立即生效';
+comment
+on column coupons.expires_at is '过期时间, 为空表示长期有效';
+comment
+on column coupons.status is '状态, active-启用, disabled-停用';
+comment
+on column coupons.created_by is '创建人';
+
+-- 创建优惠券使用记录表
+create table coupon_redemptions
+(
+    id                   serial primary key,
+    coupon_id            integer     not null references coupons (id),
+    user_id              integer     not null,
+    order_type           varchar(20) not null,
+    out_trade_no         varchar(64) not null unique,
+    billing_id           integer     not null default 0,
+    original_amount_cent bigint      not null,
+    discount_cent        bigint      not null,
+    status               varchar(20) not null default 'pending',
+    redeemed_at          timestamp with time zone,
+    created_at           timestamp with time zone default current_timestamp,
+    updated_at           timestamp with time zone default current_timestamp
+);
+
+comment
+on table coupon_redemptions is '优惠券使用记录表, 下单时占用, 支付后核销, 订单关闭时释放';
+comment
+on column coupon_redemptions.out_trade_no is '商户订单号';
+comment
+on column coupon_redemptions.billing_id is '支付后生成或支付的账单ID';
+comment
+on column coupon_redemptions.original_amount_cent is '优惠前金额（分）';
+comment
+on column coupon_redemptions.discount_cent is '优惠金额（分）';
+comment
+on column coupon_redemptions.status is '状态, pending-待支付, redeemed-已核销, released-已释放';
+comment
+on column coupon_redemptions.redeemed_at is '核销时间';
+
+create index idx_coupon_redemptions_coupon_user on coupon_redemptions (coupon_id, user_id);
+
+-- 创建套餐变更表
+create table subscription_changes
+(
+    id                   serial primary key,
+    user_id              integer     not null,
+    team_id              integer     not null default 0,
+    change_type          varchar(20) not null,
+    from_plan            varchar(20) not null,
+    from_members         integer     not null default 1,
+    to_plan              varchar(20) not null,
+    to_members           integer     not null default 1,
+    prorated_amount_cent bigint      not null default 0,
+    status               varchar(20) not null,
+    effective_at         timestamp with time zone,
+    applied_at           timestamp with time zone,
+    created_at           timestamp with time zone default current_timestamp,
+    updated_at           timestamp with time zone default current_timestamp
+);
+
+comment
+on table subscription_changes is '套餐变更表, 升级补交差价后立即生效, 降级在账期结束时由月度出账生效';
+comment
+on column subscription_changes.user_id is '订阅所有者, 团队为团队所有者';
+comment
+on column subscription_changes.team_id is '变更涉及的团队, 个人套餐为0';
+comment
+on column subscription_changes.change_type is '变更类型, upgrade-升级, downgrade-降级';
+comment
+on column subscription_changes.from_plan is '变更前的套餐';
+comment
+on column subscription_changes.from_members is '变更前的人数, 个人套餐为1';
+comment
+on column subscription_changes.to_plan is '变更后的套餐';
+comment
+on column subscription_changes.to_members is '变更后的人数, 个人套餐为1';
+comment
+on column subscription_changes.prorated_amount_cent is '升级按账期剩余天数折算的差价（分）';
+comment
+on column subscription_changes.status is '状态, pending-待支付, scheduled-待生效, applied-已生效, cancelled-已取消, reverted-退款后已撤销';
+comment
+on column subscription_changes.effective_at is '生效时间, 降级为账期结束时间';
+comment
+on column subscription_changes.applied_at is '实际生效时间';
+
+create index idx_subscription_changes_user on subscription_changes (user_id, status);
+
+create table storage_objects
+(
+    id          serial primary key,
+    user_id     integer      not null,
+    team_id     integer      not null default 0,
+    object_name varchar(512) not null unique,
+    size_bytes  bigint       not null default 0,
+    source      varchar(20)  not null,
+    status      varchar(20)  not null default 'active',
+    created_at  timestamp with time zone default current_timestamp,
+    updated_at  timestamp with time zone default current_timestamp,
+    deleted_at  timestamp with time zone,
+    purged_at   timestamp with time zone
+);
+
+comment
+on table storage_objects is '存储对象表, 记录云存储中每个对象的归属和大小, 用于统计存储用量';
+comment
+on column storage_objects.user_id is '写入对象的用户';
+comment
+on column storage_objects.team_id is '对象计入的团队, 个人存储为0';
+comment
+on column storage_objects.object_name is '对象名';
+comment
+on column storage_objects.size_bytes is '对象大小（字节）';
+comment
+on column storage_objects.source is '来源, upload-用户上传, data-保存的数据, artifact-抽取等任务生成的文件, invoice-发票';
+comment
+on column storage_objects.status is '状态, pending-写入中(已预留配额, 超时未确认的由清理任务删除), active-已写入';
+comment
+on column storage_objects.deleted_at is '删除时间, 删除后不再计入存储用量';
+comment
+on column storage_objects.purged_at is '从云存储中删除对象的时间';
+
+create index idx_storage_objects_owner on storage_objects (team_id, user_id) where deleted_at is null;
+create index idx_storage_objects_purge on storage_objects (deleted_at) where purged_at is null;
+create index idx_storage_objects_pending on storage_objects (updated_at) where status = 'pending';
+
+create table traffic_hourly
+(
+    id           bigserial primary key,
+    stat_hour    timestamp with time zone not null,
+    user_id      bigint       not null,
+    team_id      bigint       not null default 0,
+    traffic_type varchar(50)  not null,
+    endpoint     varchar(200) not null default '',
+    requests     integer      not null default 0,
+    data_size    bigint       not null default 0,
+    updated_at   timestamp with time zone default current_timestamp,
+    unique (stat_hour, user_id, team_id, traffic_type, endpoint)
+);
+
+comment
+on table traffic_hourly is '流量每小时汇总表, 按用户、团队、流量类型和接口汇总 traffic_logs';
+comment
+on column traffic_hourly.stat_hour is '统计小时';
+comment
+on column traffic_hourly.team_id is '流量计入的团队, 个人流量为0';
+comment
+on column traffic_hourly.traffic_type is '流量类型, graph_query-图谱查询, api_read-项目数据读取, file_transfer-文件下载, export-导出';
+comment
+on column traffic_hourly.endpoint is '接口路由';
+comment
+on column traffic_hourly.requests is '请求数';
+comment
+on column traffic_hourly.data_size is '响应流量（字节）';
+
+create index idx_traffic_hourly_user on traffic_hourly (user_id, stat_hour);
+create index idx_traffic_hourly_team on traffic_hourly (team_id, stat_hour);
+create index idx_traffic_logs_created_at on traffic_logs (created_at);
+
+create table usage_events
+(
+    id              bigserial primary key,
+    user_id         bigint         not null,
+    team_id         bigint         not null default 0,
+    resource_type   varchar(20)    not null,
+    amount          numeric(20, 6) not null,
+    cost_multiplier numeric(10, 4) not null default 1,
+    task_id         integer        not null default 0,
+    project_id      integer        not null default 0,
+    model           varchar(100)   not null default '',
+    source          varchar(50)    not null default '',
+    created_at      timestamp with time zone default current_timestamp
+);
+
+comment
+on table usage_events is '用量事件表, 只追加不修改, 记录每次字数、存储、算力和流量的用量';
+comment
+on column usage_events.user_id is '产生用量的用户, 团队中为成员';
+comment
+on column usage_events.team_id is '用量计入的团队, 个人用量为0';
+comment
+on column usage_events.resource_type is '资源类型, words-字数, storage-存储(MB), traffic-流量(GB), cu-算力(CU)';
+comment
+on column usage_events.amount is '用量, 存储删除对象时为负数';
+comment
+on column usage_events.cost_multiplier is '成本系数, 字数为任务使用的AI模型的成本系数, 其他资源为1';
+comment
+on column usage_events.task_id is '产生用量的任务, 没有时为0';
+comment
+on column usage_events.project_id is '产生用量的项目, 没有时为0';
+comment
+on column usage_events.model is '使用的AI模型';
+comment
+on column usage_events.source is '来源, 如 extract-抽取, 存储对象的来源, 流量类型';
+
+create index idx_usage_events_owner on usage_events (team_id, user_id, created_at);
+create index idx_usage_events_created_at on usage_events (created_at);
+
+create table usage_daily
+(
+    id              bigserial primary key,
+    stat_date       date           not null,
+    user_id         bigint         not null,
+    team_id         bigint         not null default 0,
+    resource_type   varchar(20)    not null,
+    project_id      integer        not null default 0,
+    model           varchar(100)   not null default '',
+    amount          numeric(20, 6) not null default 0,
+    weighted_amount numeric(20, 6) not null default 0,
+    events          integer        not null default 0,
+    updated_at      timestamp with time zone default current_timestamp,
+    unique (stat_date, user_id, team_id, resource_type, project_id, model)
+);
+
+comment
+on table usage_daily is '用量每日汇总表, 按成员、团队、资源类型、项目和模型汇总 usage_events';
+comment
+on column usage_daily.stat_date is '统计日期';
+comment
+on column usage_daily.amount is '用量';
+comment
+on column usage_daily.weighted_amount is '按成本系数折算后的用量';
+comment
+on column usage_daily.events is '用量事件数';
+
+create index idx_usage_daily_owner on usage_daily (team_id, user_id, stat_date);
@@ -8,12 +8,13 @@ import (
 	"fmt"
 
 	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gcode"
-	"github.com/google/uuid"
 
 	"io"
 	"kgplatform-backend/internal/consts"
 	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/storage"
 	"kgplatform-backend/internal/logic/upload"
 	"kgplatform-backend/internal/model/entity"
 	"kgplatform-backend/internal/neo4j"
@@ -32,9 +33,10 @@ import (
 
 // PythonClient Python 服务客户端
 type PythonClient struct {
-	baseURL    string
+	pool       *WorkerPool
 	httpClient *http.Client
 	sseManager *SSEManager
+	options    ClientOptions
 }
 
 // SSEManager SSE 连接管理器
@@ -43,7 +45,7 @@ type SSEManager struct {
 	mutex       sync.RWMutex
 }
 
-// SSEConnection SSE 连接
+// SSEConnection SSE 连接, 断开重连时复用同一个对象
 type SSEConnection struct {
 	taskID     string
 	ctx        context.Context
@@ -64,6 +66,8 @@ type PythonCreateTaskRequest struct {
 	Model      string `json:"model,omitempty"`
 	APIKey     string `json:"api_key"`
 	BaseURL    string `json:"base_url,omitempty"`
+	// webhook 模式下任务状态变化时回调的地址
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 type File struct {
@@ -130,6 +134,9 @@ type PythonGenPromptResponse struct {
 var (
 	pythonClient *PythonClient
 	clientOnce   sync.Once
+
+	// 不设置整体超时, SSE 长连接需要一直保持; 普通请求的超时由 call 通过 context 控制
+	sharedHTTPClient = &http.Client{Timeout: 0}
 )
 
 // GetPythonClient 获取 Python 客户端单例
@@ -140,106 +147,195 @@ func GetPythonClient() *PythonClient {
 	return pythonClient
 }
 
-// NewPythonClient 创建新的 Python 客户端
+// NewPythonClient 创建新的 Python 客户端, 节点池与 HTTP 连接由所有客户端共用
 func NewPythonClient() *PythonClient {
-	// 从配置中获取 Python 服务地址
-	baseURL := g.Cfg().MustGet(context.Background(), "python.baseUrl", "http://localhost:8000").String()
+	ctx := context.Background()
+	options := loadClientOptions(ctx)
 
 	return &PythonClient{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 0,
-		},
+		pool:       getWorkerPool(ctx, options, sharedHTTPClient),
+		httpClient: sharedHTTPClient,
 		sseManager: &SSEManager{
 			connections: make(map[string]*SSEConnection),
 		},
+		options: options,
+	}
+}
+
+// NewCreateTaskRequest 根据模型配置构建 Python 抽取任务请求
+// API Key 与 BaseURL 从 python.providers.<provider> 配置中读取
+func NewCreateTaskRequest(ctx context.Context, model *entity.Models, prompt string, files []File) *PythonCreateTaskRequest {
+	provider := strings.ToLower(model.Provider)
+	return &PythonCreateTaskRequest{
+		Files:      files,
+		PromptText: prompt,
+		Provider:   provider,
+		Model:      model.ModelCode,
+		APIKey:     g.Cfg().MustGet(ctx, fmt.Sprintf("python.providers.%s.apiKey", provider)).String(),
+		BaseURL:    g.Cfg().MustGet(ctx, fmt.Sprintf("python.providers.%s.baseUrl", provider)).String(),
 	}
 }
 
 // CreateTask 创建 Python 三元组抽取任务
+// 创建任务不是幂等操作, 失败后不重试
 func (c *PythonClient) CreateTask(ctx context.Context, req *PythonCreateTaskRequest) (*PythonCreateTaskResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/tasks", c.baseURL)
-
-	jsonData, err := json.Marshal(req)
+	payload := *req
+	if c.options.CompletionMode == CompletionModeWebhook && payload.CallbackURL == "" {
+		payload.CallbackURL = c.options.CallbackURL
+	}
+	jsonData, err := json.Marshal(&payload)
 	if err != nil {
 		return nil, gerror.Newf("序列化请求失败: %v", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	worker, err := c.pool.reserve()
 	if err != nil {
-		return nil, gerror.Newf("创建HTTP请求失败: %v", err)
+		return nil, err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+	var response PythonCreateTaskResponse
+	err = c.call(ctx, worker, func(ctx context.Context, worker *Worker) error {
+		url := fmt.Sprintf("%s/api/v1/tasks", worker.BaseURL)
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return gerror.Newf("创建HTTP请求失败: %v", err)
+		}
+
+		httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, gerror.Newf("发送HTTP请求失败: %v", err)
-	}
-	defer resp.Body.Close()
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return gerror.Newf("发送HTTP请求失败: %v", err)
+		}
+		defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, gerror.Newf("Python服务返回错误: %d, %s", resp.StatusCode, string(body))
-	}
+		if resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			return &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+		}
 
-	var response PythonCreateTaskResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, gerror.Newf("解析响应失败: %v", err)
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return gerror.Newf("解析响应失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		c.pool.unreserve(worker)
+		return nil, err
 	}
 
-	g.Log().Infof(ctx, "Python任务创建成功: %s", response.TaskID)
+	c.pool.assign(ctx, response.TaskID, worker)
+	g.Log().Infof(ctx, "Python任务已分配到节点: %s, 任务ID: %s", worker.Name, response.TaskID)
 	return &response, nil
 }
 
-// GetTaskStatus 获取 Python 任务状态
+// GetTaskStatus 获取 Python 任务状态, 失败时按指数退避重试
 func (c *PythonClient) GetTaskStatus(ctx context.Context, taskID string) (*PythonTaskStatus, error) {
-	url := fmt.Sprintf("%s/api/v1/tasks/%s", c.baseURL, taskID)
+	var status PythonTaskStatus
+	err := c.callWithRetry(ctx, "获取Python任务状态", c.taskWorker(ctx, taskID), func(ctx context.Context, worker *Worker) error {
+		url := fmt.Sprintf("%s/api/v1/tasks/%s", worker.BaseURL, taskID)
+		httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return gerror.Newf("创建HTTP请求失败: %v", err)
+		}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, gerror.Newf("创建HTTP请求失败: %v", err)
-	}
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return gerror.Newf("发送HTTP请求失败: %v", err)
+		}
+		defer resp.Body.Close()
 
-	resp, err := c.httpClient.Do(httpReq)
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+			return gerror.Newf("解析响应失败: %v", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, gerror.Newf("发送HTTP请求失败: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, gerror.Newf("Python服务返回错误: %d, %s", resp.StatusCode, string(body))
+	if isTerminalStatus(status.Status) {
+		c.pool.release(taskID)
 	}
 
-	var status PythonTaskStatus
-	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
-		return nil, gerror.Newf("解析响应失败: %v", err)
+	return &status, nil
+}
+
+// taskWorker 固定返回任务所属节点的选择器
+func (c *PythonClient) taskWorker(ctx context.Context, taskID string) func() (*Worker, error) {
+	worker := c.pool.workerFor(ctx, taskID)
+	return func() (*Worker, error) {
+		return worker, nil
 	}
+}
 
-	return &status, nil
+// isTerminalStatus 任务是否已结束
+func isTerminalStatus(status string) bool {
+	return status == "completed" || status == "failed" || status == "cancelled"
+}
+
+// isTaskFinished Go 任务是否已结束, 取消的任务记为失败
+func isTaskFinished(status string) bool {
+	return status == consts.TaskStatusCompleted || status == consts.TaskStatusFailed
+}
+
+// WorkerStatus 获取所有 Python 节点的运行状态
+func (c *PythonClient) WorkerStatus() []WorkerStatus {
+	return c.pool.Status()
+}
+
+// WaitTask 轮询 Python 任务状态，直到任务完成、失败或被取消
+func (c *PythonClient) WaitTask(ctx context.Context, taskID string, interval time.Duration) (*PythonTaskStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.GetTaskStatus(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if isTerminalStatus(status.Status) {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, gerror.Newf("等待Python任务超时: %s", taskID)
+		case <-ticker.C:
+		}
+	}
 }
 
 // CancelTask 取消 Python 任务
 func (c *PythonClient) CancelTask(ctx context.Context, taskID string) error {
-	url := fmt.Sprintf("%s/api/v1/tasks/%s", c.baseURL, taskID)
+	err := c.call(ctx, c.pool.workerFor(ctx, taskID), func(ctx context.Context, worker *Worker) error {
+		url := fmt.Sprintf("%s/api/v1/tasks/%s", worker.BaseURL, taskID)
+		httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+		if err != nil {
+			return gerror.Newf("创建HTTP请求失败: %v", err)
+		}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
-	if err != nil {
-		return gerror.Newf("创建HTTP请求失败: %v", err)
-	}
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return gerror.Newf("发送HTTP请求失败: %v", err)
+		}
+		defer resp.Body.Close()
 
-	resp, err := c.httpClient.Do(httpReq)
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		return nil
+	})
 	if err != nil {
-		return gerror.Newf("发送HTTP请求失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return gerror.Newf("Python服务返回错误: %d, %s", resp.StatusCode, string(body))
+		return err
 	}
 
+	c.pool.release(taskID)
 	g.Log().Infof(ctx, "Python任务取消成功: %s", taskID)
 	return nil
 }
@@ -247,7 +343,20 @@ func (c *PythonClient) CancelTask(ctx context.Context, taskID string) error {
 // StartSSEConnection 启动 SSE 连接监听任务状态
 // pyTaskID: 调用 py 侧的 createTask 生成的任务ID
 // goTaskID: go 侧 Task 的 ID
+// 连接断开后按指数退避重连, 重连失败则改为轮询任务状态, 不设置整体超时
+// webhook 模式下不建立连接, 由 Python 服务回调 HandleCallback
+// 无法跟踪时返回错误并释放任务占用的节点名额, 由调用方将任务标记为失败
 func (c *PythonClient) StartSSEConnection(ctx context.Context, pyTaskID string, goTaskID int) error {
+	if err := bindPythonTask(ctx, pyTaskID, goTaskID); err != nil {
+		c.pool.release(pyTaskID)
+		return err
+	}
+	if c.options.CompletionMode == CompletionModeWebhook {
+		g.Log().Infof(ctx, "等待Python任务回调: %s", pyTaskID)
+		c.watchCallback(pyTaskID, goTaskID)
+		return nil
+	}
+
 	c.sseManager.mutex.Lock()
 	defer c.sseManager.mutex.Unlock()
 
@@ -256,72 +365,213 @@ func (c *PythonClient) StartSSEConnection(ctx context.Context, pyTaskID string,
 		return gerror.Newf("任务 %s 的SSE连接已存在", pyTaskID)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/tasks/%s/stream", c.baseURL, pyTaskID)
+	sseCtx, cancel := context.WithCancel(context.Background())
+	connection := &SSEConnection{
+		taskID:     pyTaskID,
+		ctx:        sseCtx,
+		cancel:     cancel,
+		statusChan: make(chan *PythonTaskStatus, 10),
+		errorChan:  make(chan error, 1),
+	}
+	if err := c.openSSEStream(connection); err != nil {
+		cancel()
+		c.pool.release(pyTaskID)
+		return err
+	}
+
+	c.sseManager.connections[pyTaskID] = connection
+
+	// 启动 SSE 读取协程
+	go func() {
+		defer c.closeSSEConnection(pyTaskID)
+		defer func() {
+			if r := recover(); r != nil {
+				g.Log().Errorf(context.Background(), "SSE连接处理异常: %v", r)
+			}
+		}()
+
+		if err := c.watchTask(connection, goTaskID); err != nil {
+			g.Log().Errorf(context.Background(), "SSE处理异常: %v", err)
+			c.pool.release(pyTaskID)
+			failWatchedTask(context.Background(), goTaskID, err)
+		}
+	}()
 
-	sseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	g.Log().Infof(ctx, "SSE连接已启动: %s", pyTaskID)
+	return nil
+}
 
-	httpReq, err := http.NewRequestWithContext(sseCtx, "GET", url, nil)
+// watchCallback webhook 模式下超过 python.webhook.timeout 仍未收到回调时主动查询一次任务状态
+// 任务已结束时按查询结果处理, 否则将任务标记为失败, 两种情况都会释放任务占用的节点名额
+func (c *PythonClient) watchCallback(pyTaskID string, goTaskID int) {
+	if c.options.CallbackTimeout <= 0 {
+		return
+	}
+	time.AfterFunc(c.options.CallbackTimeout, func() {
+		ctx := context.Background()
+		taskStatus, err := dao.Tasks.Ctx(ctx).Where("id", goTaskID).Value("status")
+		if err != nil {
+			g.Log().Errorf(ctx, "获取任务状态失败: %v, 任务ID: %d", err, goTaskID)
+		} else if isTaskFinished(taskStatus.String()) {
+			c.pool.release(pyTaskID)
+			return
+		}
+
+		status, err := c.GetTaskStatus(ctx, pyTaskID)
+		if err == nil && isTerminalStatus(status.Status) {
+			if err = processTaskStatus(goTaskID, status); err != nil {
+				g.Log().Errorf(ctx, "处理任务状态失败: %v, 任务ID: %d", err, goTaskID)
+			}
+			return
+		}
+		c.pool.release(pyTaskID)
+		failWatchedTask(ctx, goTaskID, gerror.Newf("等待Python任务回调超时: %s", pyTaskID))
+	})
+}
+
+// failWatchedTask 无法继续跟踪时将任务标记为失败, 已结束的任务不受影响
+func failWatchedTask(ctx context.Context, goTaskID int, cause error) {
+	result, err := dao.Tasks.Ctx(ctx).
+		Where("id", goTaskID).
+		WhereNotIn("status", g.Slice{consts.TaskStatusCompleted, consts.TaskStatusFailed}).
+		Update(g.Map{
+			"status":        consts.TaskStatusFailed,
+			"error_message": cause.Error(),
+			"updated_at":    gtime.Now(),
+		})
 	if err != nil {
-		cancel()
+		g.Log().Errorf(ctx, "更新任务状态失败: %v", err)
+		return
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		updatePromptUsage(ctx, goTaskID, consts.TaskStatusFailed)
+	}
+}
+
+// openSSEStream 建立(或重新建立) SSE 数据流
+// 超时只作用于建立连接, 连接建立后读取数据不受限制
+func (c *PythonClient) openSSEStream(conn *SSEConnection) error {
+	worker := c.pool.workerFor(conn.ctx, conn.taskID)
+	if err := worker.breaker.allow(); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/tasks/%s/stream", worker.BaseURL, conn.taskID)
+
+	reqCtx, reqCancel := context.WithCancel(conn.ctx)
+	httpReq, err := http.NewRequestWithContext(reqCtx, "GET", url, nil)
+	if err != nil {
+		reqCancel()
 		return gerror.Newf("创建SSE请求失败: %v", err)
 	}
 
 	httpReq.Header.Set("Accept", "text/event-stream")
 	httpReq.Header.Set("Cache-Control", "no-cache")
 
+	timer := time.AfterFunc(c.options.Timeout, reqCancel)
 	resp, err := c.httpClient.Do(httpReq)
+	timer.Stop()
 	if err != nil {
-		cancel()
-		return gerror.Newf("发送SSE请求失败: %v", err)
+		reqCancel()
+		err = gerror.Newf("发送SSE请求失败: %v", err)
+		if conn.ctx.Err() != nil {
+			worker.breaker.release()
+		} else {
+			worker.breaker.record(err)
+		}
+		return err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		cancel()
+		reqCancel()
 		resp.Body.Close()
-		return gerror.Newf("SSE连接失败: %d", resp.StatusCode)
+		err = &statusError{StatusCode: resp.StatusCode, Body: "SSE连接失败"}
+		worker.breaker.record(err)
+		return err
 	}
+	worker.breaker.record(nil)
 
-	connection := &SSEConnection{
-		taskID:     pyTaskID,
-		ctx:        sseCtx,
-		cancel:     cancel,
-		response:   resp,
-		reader:     bufio.NewScanner(resp.Body),
-		statusChan: make(chan *PythonTaskStatus, 10),
-		errorChan:  make(chan error, 1),
+	reader := bufio.NewScanner(resp.Body)
+	// 完成事件包含所有文件的结果, 放宽单行长度限制
+	reader.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	conn.mutex.Lock()
+	if conn.response != nil {
+		conn.response.Body.Close()
 	}
+	conn.response = resp
+	conn.reader = reader
+	conn.mutex.Unlock()
+	return nil
+}
 
-	c.sseManager.connections[pyTaskID] = connection
+// watchTask 监听任务状态直到任务结束
+func (c *PythonClient) watchTask(conn *SSEConnection, goTaskID int) error {
+	attempt := 0
+	for {
+		finished, err := c.handleSSEConnection(conn, goTaskID)
+		if err != nil || finished {
+			return err
+		}
 
-	// 启动 SSE 读取协程
-	go func() {
-		err = c.handleSSEConnection(connection, goTaskID)
-		if err != nil {
-			g.Log().Errorf(ctx, "SSE处理异常: %v", err)
-			_, err = dao.Tasks.Ctx(ctx).Where("id", goTaskID).Update(g.Map{
-				"status":        consts.TaskStatusFailed,
-				"error_message": err.Error(),
-				"updated_at":    gtime.Now(),
-			})
-			c.closeSSEConnection(pyTaskID)
+		// 连接断开但任务未结束, 尝试重连
+		reconnected := false
+		for !reconnected && attempt < c.options.SSEReconnect {
+			wait := c.backoff(attempt)
+			attempt++
+			g.Log().Warningf(context.Background(), "SSE连接断开, %v后进行第%d次重连: %s", wait, attempt, conn.taskID)
+			select {
+			case <-conn.ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+			if err = c.openSSEStream(conn); err != nil {
+				g.Log().Warningf(context.Background(), "SSE重连失败: %v", err)
+				continue
+			}
+			reconnected = true
 		}
-	}()
+		if !reconnected {
+			break
+		}
+	}
 
-	g.Log().Infof(ctx, "SSE连接已启动: %s", pyTaskID)
-	return nil
+	g.Log().Warningf(context.Background(), "SSE重连失败, 改为轮询任务状态: %s", conn.taskID)
+	return c.pollTask(conn, goTaskID)
 }
 
-// handleSSEConnection 处理 SSE 连接
-func (c *PythonClient) handleSSEConnection(conn *SSEConnection, goTaskID int) error {
-	defer func() {
-		c.closeSSEConnection(conn.taskID)
-		if r := recover(); r != nil {
-			g.Log().Errorf(context.Background(), "SSE连接处理异常: %v", r)
+// pollTask 轮询任务状态直到任务结束, Python 服务暂时不可用时继续等待
+func (c *PythonClient) pollTask(conn *SSEConnection, goTaskID int) error {
+	for {
+		status, err := c.WaitTask(conn.ctx, conn.taskID, c.options.PollInterval)
+		if err == nil {
+			return processTaskStatus(goTaskID, status)
 		}
-	}()
+		if conn.ctx.Err() != nil {
+			return nil
+		}
+		if !isRetryable(err) && gerror.Code(err) != gcode.CodeServerBusy {
+			return err
+		}
+
+		g.Log().Warningf(context.Background(), "轮询任务状态失败: %v, 任务ID: %s", err, conn.taskID)
+		select {
+		case <-conn.ctx.Done():
+			return nil
+		case <-time.After(c.options.PollInterval):
+		}
+	}
+}
 
-	for conn.reader.Scan() {
-		line := strings.TrimSpace(conn.reader.Text())
+// handleSSEConnection 读取 SSE 数据直到连接断开
+// 返回任务是否已结束
+func (c *PythonClient) handleSSEConnection(conn *SSEConnection, goTaskID int) (bool, error) {
+	conn.mutex.RLock()
+	reader := conn.reader
+	conn.mutex.RUnlock()
+
+	for reader.Scan() {
+		line := strings.TrimSpace(reader.Text())
 
 		// 跳过空行和非数据行
 		if line == "" || !strings.HasPrefix(line, "data: ") {
@@ -338,7 +588,7 @@ func (c *PythonClient) handleSSEConnection(conn *SSEConnection, goTaskID int) er
 		var status PythonTaskStatus
 		if err := json.Unmarshal([]byte(data), &status); err != nil {
 			g.Log().Errorf(context.Background(), "解析SSE数据失败: %v, data: %s", err, data)
-			return err
+			return false, err
 		}
 
 		// 处理心跳消息
@@ -351,26 +601,47 @@ func (c *PythonClient) handleSSEConnection(conn *SSEConnection, goTaskID int) er
 			continue
 		}
 
-		ctx := context.Background()
-		tx, err := g.DB().Begin(ctx)
-		if err != nil {
-			return err
+		if err := processTaskStatus(goTaskID, &status); err != nil {
+			return false, err
 		}
-		defer func() {
-			if err != nil {
-				tx.Rollback()
-			} else {
-				tx.Commit()
-			}
-		}()
 
+		// 如果任务完成，退出循环
+		if isTerminalStatus(status.Status) {
+			c.pool.release(conn.taskID)
+			g.Log().Infof(context.Background(), "Python任务完成: %s, 状态: %s", conn.taskID, status.Status)
+			return true, nil
+		}
+	}
+
+	// 检查扫描错误, 主动关闭的连接不记录
+	if err := reader.Err(); err != nil && conn.ctx.Err() == nil {
+		g.Log().Errorf(context.Background(), "SSE连接读取错误: %v", err)
+	}
+
+	return false, nil
+}
+
+// processTaskStatus 处理 Python 任务的状态变更：更新任务状态、素材抽取结果和项目进度
+// 任务已结束后不再处理, 同一结束状态通过 SSE、轮询或新的回调事件重复到达时不会重复扣减字数和保存三元组
+func processTaskStatus(goTaskID int, status *PythonTaskStatus) error {
+	ctx := context.Background()
+	var (
+		project  entity.Projects
+		finished bool
+	)
+	err := g.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 锁定任务, 同一任务的状态变更串行处理
 		var task entity.Tasks
-		err = dao.Tasks.Ctx(ctx).TX(tx).Where("id", goTaskID).Scan(&task)
+		err := dao.Tasks.Ctx(ctx).TX(tx).Where("id", goTaskID).LockUpdate().Scan(&task)
 		if err != nil {
 			return err
 		}
+		if isTaskFinished(task.Status) {
+			finished = true
+			g.Log().Infof(ctx, "任务已结束, 忽略状态变更: ID=%d, 当前状态=%s, 收到状态=%s", task.Id, task.Status, status.Status)
+			return nil
+		}
 
-		var project entity.Projects
 		err = dao.Projects.Ctx(ctx).Where("id", task.ProjectId).Scan(&project)
 		if err != nil {
 			g.Log().Errorf(ctx, "获取项目信息失败: %v", err)
@@ -378,47 +649,41 @@ func (c *PythonClient) handleSSEConnection(conn *SSEConnection, goTaskID int) er
 		}
 
 		// 更新 Go 任务状态
-		if err := c.updateGoTaskStatus(ctx, tx, task.Id, &status); err != nil {
+		if err := updateGoTaskStatus(ctx, tx, task.Id, status); err != nil {
 			g.Log().Errorf(ctx, "更新Go任务状态失败: %v", err)
 			return err
 		}
 
 		// 更新素材提取URL
 		if status.Status == "completed" {
-			if err := c.updateMaterialsExtractURL(ctx, tx, &task, &project, &status); err != nil {
+			if err := updateMaterialsExtractURL(ctx, tx, &task, &project, status); err != nil {
 				g.Log().Errorf(ctx, "更新素材提取URL失败: %v", err)
 				return err
 			}
 		}
 
-		// 更新任务进度
-		if project.ProjectProgress < 3 {
-			_, err = dao.Projects.Ctx(ctx).Where("id", project.Id).Update(g.Map{
-				"project_progress": 3,
-			})
-			if err != nil {
-				g.Log().Errorf(ctx, "更新项目进度失败: %v", err)
-				return gerror.Newf("更新项目进度失败: %v", err)
-			}
-		}
-
-		// 如果任务完成，退出循环
-		if status.Status == "completed" || status.Status == "failed" || status.Status == "cancelled" {
-			g.Log().Infof(context.Background(), "Python任务完成: %s, 状态: %s", conn.taskID, status.Status)
-			break
-		}
+		// 保存各素材的抽取结果, 包含 Go 侧处理时的失败原因
+		return saveTaskResults(ctx, tx, task.Id, status)
+	})
+	if err != nil || finished {
+		return err
 	}
 
-	// 检查扫描错误
-	if err := conn.reader.Err(); err != nil {
-		g.Log().Errorf(context.Background(), "SSE连接读取错误: %v", err)
+	// 更新任务进度
+	if project.ProjectProgress < 3 {
+		_, err = dao.Projects.Ctx(ctx).Where("id", project.Id).Update(g.Map{
+			"project_progress": 3,
+		})
+		if err != nil {
+			g.Log().Errorf(ctx, "更新项目进度失败: %v", err)
+			return gerror.Newf("更新项目进度失败: %v", err)
+		}
 	}
-
 	return nil
 }
 
 // updateGoTaskStatus 更新 Go 任务状态
-func (c *PythonClient) updateGoTaskStatus(ctx context.Context, tx gdb.TX, goTaskID int, pythonStatus *PythonTaskStatus) error {
+func updateGoTaskStatus(ctx context.Context, tx gdb.TX, goTaskID int, pythonStatus *PythonTaskStatus) error {
 	var goStatus string
 	var errorMessage string
 	var finishTime *gtime.Time
@@ -464,10 +729,80 @@ func (c *PythonClient) updateGoTaskStatus(ctx context.Context, tx gdb.TX, goTask
 		return gerror.Newf("更新任务状态失败: %v", err)
 	}
 
+	// 同步提示词使用记录的状态
+	if finishTime != nil {
+		_, err = dao.PromptUsages.Ctx(ctx).TX(tx).Where("task_id", goTaskID).Update(g.Map{
+			"status":     goStatus,
+			"updated_at": gtime.Now(),
+		})
+		if err != nil {
+			g.Log().Errorf(ctx, "更新提示词使用记录失败: %v, 任务ID: %d", err, goTaskID)
+		}
+	}
+
 	g.Log().Infof(ctx, "Go Task状态已更新: ID=%d, Status=%s", goTaskID, goStatus)
 	return nil
 }
 
+// bindPythonTask 在 Go 任务上记录对应的 Python 任务ID
+func bindPythonTask(ctx context.Context, pyTaskID string, goTaskID int) error {
+	_, err := dao.Tasks.Ctx(ctx).Where("id", goTaskID).Update(g.Map{
+		"py_task_id": pyTaskID,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		return gerror.Newf("记录Python任务ID失败: %v", err)
+	}
+	return nil
+}
+
+// saveTaskResults 保存各素材的抽取状态、三元组数量、输出文件和失败原因
+func saveTaskResults(ctx context.Context, tx gdb.TX, goTaskID int, status *PythonTaskStatus) error {
+	if len(status.Result) == 0 {
+		return nil
+	}
+	_, err := dao.Tasks.Ctx(ctx).TX(tx).Where("id", goTaskID).Update(g.Map{
+		"results": gjson.New(status.Result),
+	})
+	if err != nil {
+		return gerror.Newf("保存任务结果失败: %v", err)
+	}
+	return nil
+}
+
+// FailedMaterials 任务中抽取失败或没有输出文件的素材
+func FailedMaterials(task *entity.Tasks) []int {
+	var files []TaskFile
+	if task.Results != nil {
+		_ = task.Results.Scan(&files)
+	}
+	succeeded := make(map[int]bool, len(files))
+	for _, file := range files {
+		if file.Status == "success" && file.OutputFiles["jsonl"] != "" {
+			succeeded[file.MaterialId] = true
+		}
+	}
+
+	failed := make([]int, 0)
+	for _, materialId := range task.MaterialIdList {
+		if !succeeded[materialId] {
+			failed = append(failed, materialId)
+		}
+	}
+	return failed
+}
+
+// markFileFailed 记录素材在 Go 侧处理失败的原因
+func markFileFailed(status *PythonTaskStatus, materialId int, message string) {
+	for i := range status.Result {
+		if status.Result[i].MaterialId == materialId {
+			status.Result[i].Status = "failed"
+			status.Result[i].Error = message
+			return
+		}
+	}
+}
+
 // closeSSEConnection 关闭 SSE 连接
 func (c *PythonClient) closeSSEConnection(taskID string) {
 	c.sseManager.mutex.Lock()
@@ -477,7 +812,9 @@ func (c *PythonClient) closeSSEConnection(taskID string) {
 		conn.mutex.Lock()
 		if !conn.closed {
 			conn.cancel()
-			conn.response.Body.Close()
+			if conn.response != nil {
+				conn.response.Body.Close()
+			}
 			close(conn.statusChan)
 			close(conn.errorChan)
 			conn.closed = true
@@ -496,41 +833,40 @@ func (c *PythonClient) StopSSEConnection(taskID string) {
 
 // StopAllSSEConnections 停止所有 SSE 连接
 func (c *PythonClient) StopAllSSEConnections() {
-	c.sseManager.mutex.Lock()
-	defer c.sseManager.mutex.Unlock()
-
+	// closeSSEConnection 内部会加锁, 先复制任务ID再逐个关闭
+	c.sseManager.mutex.RLock()
+	taskIDs := make([]string, 0, len(c.sseManager.connections))
 	for taskID := range c.sseManager.connections {
+		taskIDs = append(taskIDs, taskID)
+	}
+	c.sseManager.mutex.RUnlock()
+
+	for _, taskID := range taskIDs {
 		c.closeSSEConnection(taskID)
 	}
 
 	g.Log().Info(context.Background(), "所有SSE连接已关闭")
 }
 
-// HealthCheck 健康检查
+// HealthCheck 健康检查, 任一节点健康即视为可用
 func (c *PythonClient) HealthCheck(ctx context.Context) error {
-	url := fmt.Sprintf("%s/health", c.baseURL)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return gerror.Newf("创建健康检查请求失败: %v", err)
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return gerror.Newf("健康检查请求失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return gerror.Newf("Python服务不健康: %d", resp.StatusCode)
+	var lastErr error
+	for _, worker := range c.pool.workers {
+		selector := func() (*Worker, error) {
+			return worker, nil
+		}
+		lastErr = c.callWithRetry(ctx, "Python服务健康检查", selector, func(ctx context.Context, worker *Worker) error {
+			return checkWorkerHealth(ctx, c.httpClient, worker, c.options.Timeout)
+		})
+		if lastErr == nil {
+			return nil
+		}
 	}
-
-	return nil
+	return lastErr
 }
 
-func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX, task *entity.Tasks, project *entity.Projects, result *PythonTaskStatus) error {
+func updateMaterialsExtractURL(ctx context.Context, tx gdb.TX, task *entity.Tasks, project *entity.Projects, result *PythonTaskStatus) error {
 	var err error
-	uploadLogic := upload.NewUpload()
 	var materialList []entity.Materials
 	err = dao.Materials.Ctx(ctx).TX(tx).WhereIn("id", task.MaterialIdList).Scan(&materialList)
 	if err != nil {
@@ -556,21 +892,12 @@ func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX,
 	var subscription entity.UserSubscriptions
 	err = dao.UserSubscriptions.Ctx(ctx).TX(tx).
 		Where("user_id", userId).
+		LockUpdate().
 		Scan(&subscription)
 	if err != nil {
 		g.Log().Errorf(ctx, "获取用户订阅记录失败: %v, 用户ID: %d", err, userId)
 	}
 
-	// 定义套餐字数配额
-	free_quota := g.Cfg().MustGet(ctx, "plans.free.words_quota").Int()
-	professional_quota := g.Cfg().MustGet(ctx, "plans.professional.words_quota").Int()
-	team_quota := g.Cfg().MustGet(ctx, "plans.team.words_quota").Int()
-	plansQuota := map[string]int{
-		"free":         free_quota,
-		"professional": professional_quota,
-		"team":         team_quota,
-	}
-
 	// 计算当前任务的总字数（先获取所有文件的字数）
 	for _, material := range materialList {
 		extractResultPath, exists := extractMapping[material.Id]
@@ -588,24 +915,11 @@ func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX,
 		totalWords += taskWords
 	}
 
-	// 应用AI模型的成本系数
+	// 应用任务实际使用的AI模型的成本系数
 	var modelCostMultiplier float64 = 1.0
-	if subscription.SelectedAiModel != "" {
-		// 尝试从配置中获取模型的成本系数
-		modelName := strings.ToLower(subscription.SelectedAiModel)
-		// 首先检查中文模型
-		modelCostMultiplier = g.Cfg().MustGet(ctx, fmt.Sprintf("ai_models.chinese.%s.cost_multiplier", modelName)).Float64()
-
-		// 如果中文模型中没有找到，尝试检查英文模型
-		if modelCostMultiplier == 0 {
-			modelCostMultiplier = g.Cfg().MustGet(ctx, fmt.Sprintf("ai_models.english.%s.cost_multiplier", modelName)).Float64()
-		}
-
-		// 如果配置中仍未找到，使用默认值1.0
-		if modelCostMultiplier == 0 {
-			modelCostMultiplier = 1.0
-			g.Log().Warningf(ctx, "未找到模型 %s 的成本系数配置，使用默认值: %f", subscription.SelectedAiModel, modelCostMultiplier)
-		}
+	model := taskModel(ctx, tx, task, subscription.SelectedAiModel)
+	if model != "" {
+		modelCostMultiplier = GetModelCostMultiplier(ctx, model)
 	}
 	// 计算实际消耗的字数（原始字数 × 成本系数）
 	actualWordsUsed := int(float64(totalWords) * modelCostMultiplier)
@@ -615,29 +929,8 @@ func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX,
 		actualWordsUsed = 1
 	}
 
-	// 检查免费版用户是否超过配额，免费版不允许超额使用
-	if subscription.UserPlan == "free" {
-		quota := plansQuota[subscription.UserPlan]
-		currentUsage := int(subscription.WordsUsed) + actualWordsUsed
-		if currentUsage > quota {
-			// 使用独特的错误码1002表示免费版用户字数超出配额
-			return gerror.NewCodef(
-				gcode.New(1002, "FreePlanWordQuotaExceeded", "免费版字数超出配额"),
-				"免费版用户字数已超出配额，当前用量: %d, 配额: %d", currentUsage, quota,
-			)
-		}
-	} else {
-		quota := plansQuota[subscription.UserPlan]
-		currentUsage := int(subscription.WordsUsed) + actualWordsUsed
-		if currentUsage > quota {
-			// 使用独特的错误码1003表示付费版用户字数超出配额
-			return gerror.NewCodef(
-				gcode.New(1003, "PaidPlanWordQuotaExceeded", "付费版字数超出配额"),
-				"用户字数已超出配额，请注意用量", currentUsage, quota,
-			)
-		}
-
-		//TODO: 添加字数超额提醒（目前已使用特殊code来表示，0表示成功）
+	if err = checkWordsQuota(ctx, &subscription, actualWordsUsed); err != nil {
+		return err
 	}
 
 	// 更新每个素材的三元组URL
@@ -650,61 +943,18 @@ func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX,
 		text, err := utils.ExtractTextFromFile(ctx, extractResultPath)
 		if err != nil {
 			g.Log().Errorf(ctx, "提取文件文本失败: %v, 文件路径: %s", err, extractResultPath)
+			markFileFailed(result, material.Id, "读取抽取结果失败")
 			continue
 		}
 
 		// 解析三元组（包含溯源信息）
-		var materialTripleDataList []map[string]interface{}
-		err = json.Unmarshal([]byte(text), &materialTripleDataList)
+		materialTripleListProcessed, err := ParseTriples(text, &material)
 		if err != nil {
 			g.Log().Errorf(ctx, "三元组格式错误: %v, 文件路径: %s", err, extractResultPath)
+			markFileFailed(result, material.Id, "三元组格式错误")
 			continue
 		}
 
-		// 转换为SimpleTriple并添加溯源信息
-		materialTripleListProcessed := []neo4j.SimpleTriple{}
-
-		for _, tripleData := range materialTripleDataList {
-			triple := neo4j.SimpleTriple{}
-
-			// 解析标准字段（head, relationship, tail）
-			if headData, ok := tripleData["head"].(map[string]interface{}); ok {
-				triple.Head.Type = getStringValue(headData, "type")
-				triple.Head.Label = getStringValue(headData, "label")
-			}
-			if relData, ok := tripleData["relationship"].(map[string]interface{}); ok {
-				triple.Relationship.Type = getStringValue(relData, "type")
-				triple.Relationship.Label = getStringValue(relData, "label")
-			}
-			if tailData, ok := tripleData["tail"].(map[string]interface{}); ok {
-				triple.Tail.Type = getStringValue(tailData, "type")
-				triple.Tail.Label = getStringValue(tailData, "label")
-			}
-
-			// 添加溯源信息（如果存在_chunk_index和_source_text）
-			if chunkIndexVal, ok := tripleData["_chunk_index"].(float64); ok {
-				sourceText := ""
-				if textVal, ok := tripleData["_source_text"].(string); ok {
-					sourceText = textVal
-				}
-
-				// 获取素材名称（简化处理，使用URL的文件名部分）
-				materialName := material.Url
-				if lastSlash := strings.LastIndex(material.Url, "/"); lastSlash >= 0 {
-					materialName = material.Url[lastSlash+1:]
-				}
-
-				triple.SourceInfo = &neo4j.TripleSourceInfo{
-					MaterialId:   material.Id,
-					MaterialName: materialName,
-					ChunkIndex:   int(chunkIndexVal),
-					SourceText:   sourceText,
-				}
-			}
-
-			materialTripleListProcessed = append(materialTripleListProcessed, triple)
-		}
-
 		// 添加到项目三元组列表
 		projectTripleList = append(projectTripleList, materialTripleListProcessed...)
 
@@ -712,14 +962,19 @@ func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX,
 		uploadFileName := utils.RemoveExt(material.Url)
 		materialTripleContent, _ := json.Marshal(materialTripleListProcessed)
 
-		saveDataOutput, err := uploadLogic.SaveData(ctx, &upload.SaveDataInput{
+		saveDataOutput, err := storage.New().SaveData(ctx, &upload.SaveDataInput{
 			FileName: uploadFileName,
 			Content:  string(materialTripleContent),
 			DataType: "json",
 			UserId:   userId,
-		})
+		}, consts.StorageSourceArtifact)
 		if err != nil {
 			g.Log().Errorf(ctx, "保存数据失败: %v, 文件名: %s", err, material.Url)
+			message := "保存三元组失败"
+			if gerror.Code(err) == storage.CodeFreePlanStorageQuotaExceeded {
+				message = err.Error()
+			}
+			markFileFailed(result, material.Id, message)
 			continue
 		}
 
@@ -728,94 +983,75 @@ func (c *PythonClient) updateMaterialsExtractURL(ctx context.Context, tx gdb.TX,
 		})
 		if err != nil {
 			g.Log().Errorf(ctx, "更新素材三元组URL失败: %v, 素材ID: %d", err, material.Id)
+			markFileFailed(result, material.Id, "更新素材三元组失败")
 			continue
 		}
 	}
 
+	// 本次任务抽取的三元组数量, 合并前统计
+	extractedCount := len(projectTripleList)
+
+	// 重试任务和定时运行的增量任务只包含部分素材, 需要与项目已有的三元组合并
+	if task.ParentTaskId > 0 || task.ScheduleId > 0 {
+		projectTripleList, err = MergeProjectTriples(ctx, project, projectTripleList)
+		if err != nil {
+			return err
+		}
+	}
+
 	// 更新项目的三元组url
-	uuidStr := uuid.New().String()
-	timestamp := time.Now().Format("20060102150405")
-	uploadFileName := utils.RemoveExt("triples_project_" + strconv.Itoa(task.ProjectId) + "_" + timestamp + "_" + uuidStr[:8])
-
-	// 序列化包含SourceInfo的三元组列表
-	uploadContent, _ := json.Marshal(projectTripleList)
-	saveDataOutput, err := uploadLogic.SaveData(ctx, &upload.SaveDataInput{
-		FileName: uploadFileName,
-		Content:  string(uploadContent),
-		DataType: "json",
-		UserId:   userId,
-	})
-	if err != nil {
-		g.Log().Errorf(ctx, "保存数据失败: %v, 文件名: %s", err, uploadFileName)
+	if err = SaveProjectTriples(ctx, tx, task.ProjectId, userId, projectTripleList); err != nil {
 		return err
 	}
-	_, err = dao.Projects.Ctx(ctx).TX(tx).Where("id", task.ProjectId).Update(g.Map{
-		"triple_url": saveDataOutput.FileName,
-	})
 
-	// 按照三元组type分类存储
-	var tripeTypeMap = make(map[string][]neo4j.SimpleTriple)
-	for _, triple := range projectTripleList {
-		tripleType := fmt.Sprintf("%s-%s-%s", triple.Head.Type, triple.Relationship.Type, triple.Tail.Type)
-		tripeTypeMap[tripleType] = append(tripeTypeMap[tripleType], triple)
-	}
-	var tripleTypeFilenameMap = make(map[string]string)
-	for tripleType, triples := range tripeTypeMap {
-		uuidStr = uuid.New().String()
-		timestamp = time.Now().Format("20060102150405")
-		uploadFileName = utils.RemoveExt("triples_project_type" + tripleType + "_" + timestamp + "_" + uuidStr[:8])
-		uploadContent, _ = json.Marshal(triples)
-		saveMapOutput, err := uploadLogic.SaveData(ctx, &upload.SaveDataInput{
-			FileName: uploadFileName,
-			Content:  string(uploadContent),
-			DataType: "json",
-			UserId:   userId,
-		})
-		if err != nil {
-			g.Log().Errorf(ctx, "保存数据失败: %v, 文件名: %s", err, uploadFileName)
-			continue
-		}
-		tripleTypeFilenameMap[tripleType] = saveMapOutput.FileName
+	// 记录提示词版本本次抽取的三元组数量
+	_, err = dao.PromptUsages.Ctx(ctx).TX(tx).Where("task_id", task.Id).Update(g.Map{
+		"triples_count": extractedCount,
+		"updated_at":    gtime.Now(),
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "更新提示词使用记录失败: %v, 任务ID: %d", err, task.Id)
 	}
 
-	// 修改projects表
-	_, err = dao.Projects.Ctx(ctx).TX(tx).Where("id", task.ProjectId).Update(g.Map{
-		"triple_url":      saveDataOutput.FileName,
-		"triple_type_url": tripleTypeFilenameMap,
+	// 更新用户订阅表中的文字用量并记录字数用量事件
+	RecordWords(ctx, tx, &WordsUsage{
+		UserId:         userId,
+		Words:          totalWords,
+		CostMultiplier: modelCostMultiplier,
+		TaskId:         task.Id,
+		ProjectId:      task.ProjectId,
+		Model:          model,
+		Source:         consts.UsageSourceExtract,
 	})
 
-	// 更新用户订阅表中的文字用量
-	if totalWords > 0 && userId > 0 {
-		// 再次获取用户订阅记录（确保数据最新）
-		var subscriptionUpdate entity.UserSubscriptions
-		err := dao.UserSubscriptions.Ctx(ctx).TX(tx).
-			Where("user_id", userId).
-			Scan(&subscriptionUpdate)
-		if err != nil {
-			g.Log().Errorf(ctx, "获取用户订阅记录失败: %v, 用户ID: %d", err, userId)
-		}
+	return nil
+}
 
-		// 更新文字用量
-		newWordsUsed := int64(subscriptionUpdate.WordsUsed) + int64(totalWords)
-		_, err = dao.UserSubscriptions.Ctx(ctx).TX(tx).
-			Where("user_id", userId).
-			Update(g.Map{
-				"words_used": newWordsUsed,
-				"updated_at": gtime.Now(),
-			})
-		if err != nil {
-			g.Log().Errorf(ctx, "更新用户文字用量失败: %v, 用户ID: %d", err, userId)
-		} else {
-			g.Log().Infof(ctx, "用户文字用量已更新: 用户ID=%d, 新增字数=%d, 总字数=%d",
-				userId, totalWords, newWordsUsed)
+// taskModel 任务实际使用的模型编码, 早期任务未保存抽取配置时使用用户所选的模型
+func taskModel(ctx context.Context, tx gdb.TX, task *entity.Tasks, fallback string) string {
+	var config ExtractConfig
+	if task.ExtractConfig != nil {
+		if err := task.ExtractConfig.Scan(&config); err != nil {
+			g.Log().Errorf(ctx, "解析抽取配置失败: %v, 任务ID: %d", err, task.Id)
 		}
 	}
-
-	return nil
+	if config.ModelId == 0 {
+		return fallback
+	}
+	code, err := dao.Models.Ctx(ctx).TX(tx).Where("id", config.ModelId).Value("model_code")
+	if err != nil {
+		g.Log().Errorf(ctx, "获取任务模型失败: %v, 任务ID: %d", err, task.Id)
+		return fallback
+	}
+	if code.IsEmpty() {
+		return fallback
+	}
+	return code.String()
 }
 
 type ExtractConfig struct {
 	Prompt                 string   `json:"prompt"`
+	PromptVersionId        *int     `json:"promptVersionId"`
 	ModelId                int      `json:"modelId"`
 	Method                 string   `json:"method"`
 	SchemaURL              string   `json:"schemaUrl"`
@@ -852,38 +1088,43 @@ type ExtractConfig struct {
 //	return int(userIdFloat), nil
 //}
 
+// GenPrompt 生成抽取提示词, 失败时按指数退避重试
 func (c *PythonClient) GenPrompt(ctx context.Context, req *PythonGenPromptRequest) (*PythonGenPromptResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/genprompt", c.baseURL)
-	g.Log().Infof(ctx, "请求Python服务: %s", url)
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, gerror.Newf("序列化请求失败: %v", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, gerror.Newf("创建HTTP请求失败: %v", err)
-	}
+	var response PythonGenPromptResponse
+	err = c.callWithRetry(ctx, "生成Prompt", c.pool.pick, func(ctx context.Context, worker *Worker) error {
+		url := fmt.Sprintf("%s/api/v1/genprompt", worker.BaseURL)
+		g.Log().Infof(ctx, "请求Python服务: %s", url)
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			return gerror.Newf("创建HTTP请求失败: %v", err)
+		}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, gerror.Newf("发送HTTP请求失败: %v", err)
-	}
-	defer resp.Body.Close()
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return gerror.Newf("发送HTTP请求失败: %v", err)
+		}
+		defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		errorMsg := string(body)
-		// 解码Unicode转义字符
-		decodedErrorMsg := decodeUnicodeEscapes(errorMsg)
-		return nil, gerror.Newf("Python服务返回错误: %d, %s", resp.StatusCode, decodedErrorMsg)
-	}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			// 解码Unicode转义字符
+			return &statusError{StatusCode: resp.StatusCode, Body: decodeUnicodeEscapes(string(body))}
+		}
 
-	var response PythonGenPromptResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, gerror.Newf("解析响应失败: %v", err)
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			return gerror.Newf("解析响应失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	g.Log().Infof(ctx, "Prompt生成成功")
@@ -0,0 +1,61 @@
+package py_service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 抽取后端
+const (
+	ExtractorPython = "python" // 调用 Python 抽取服务
+	ExtractorNative = "native" // Go 侧直接调用 OpenAI 兼容的对话接口
+)
+
+// Extractor 三元组抽取后端
+// 请求与任务状态沿用 Python 服务的格式, 便于各后端共用任务完成后的处理逻辑
+type Extractor interface {
+	// CreateTask 提交抽取任务
+	CreateTask(ctx context.Context, req *PythonCreateTaskRequest) (*PythonCreateTaskResponse, error)
+	// GetTaskStatus 获取任务状态
+	GetTaskStatus(ctx context.Context, taskID string) (*PythonTaskStatus, error)
+	// WaitTask 等待任务结束
+	WaitTask(ctx context.Context, taskID string, interval time.Duration) (*PythonTaskStatus, error)
+	// CancelTask 取消任务
+	CancelTask(ctx context.Context, taskID string) error
+	// WatchTask 后台跟踪任务, 结束后更新 Go 任务、素材与项目的抽取结果
+	WatchTask(ctx context.Context, taskID string, goTaskID int) error
+}
+
+var (
+	_ Extractor = (*PythonClient)(nil)
+	_ Extractor = (*NativeExtractor)(nil)
+)
+
+var (
+	extractor     Extractor
+	extractorOnce sync.Once
+)
+
+// GetExtractor 按 extractor.backend 配置获取抽取后端单例, 默认使用 Python 服务
+func GetExtractor() Extractor {
+	extractorOnce.Do(func() {
+		ctx := context.Background()
+		backend := g.Cfg().MustGet(ctx, "extractor.backend", ExtractorPython).String()
+		switch backend {
+		case ExtractorNative:
+			extractor = NewNativeExtractor()
+		default:
+			extractor = GetPythonClient()
+		}
+		g.Log().Infof(ctx, "三元组抽取后端: %s", backend)
+	})
+	return extractor
+}
+
+// WatchTask 通过 SSE 或回调跟踪 Python 任务
+func (c *PythonClient) WatchTask(ctx context.Context, taskID string, goTaskID int) error {
+	return c.StartSSEConnection(ctx, taskID, goTaskID)
+}
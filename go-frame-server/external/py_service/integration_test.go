@@ -0,0 +1,450 @@
+//go:build integration
+
+// 抽取流程集成测试：使用 pyfake 替代 Python 服务，其余依赖(数据库、Redis、对象存储)读取现有配置。
+// 运行: go test -tags integration ./external/py_service/
+package py_service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	_ "github.com/gogf/gf/contrib/drivers/pgsql/v2"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gcfg"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/external/py_service"
+	"kgplatform-backend/external/py_service/pyfake"
+	"kgplatform-backend/internal/controller/python"
+	"kgplatform-backend/internal/controller/tasks"
+	"kgplatform-backend/internal/dao"
+	_ "kgplatform-backend/internal/logic"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/model/entity"
+	_ "kgplatform-backend/internal/packed"
+)
+
+const integrationSecret = "integration-secret"
+
+var (
+	fake       *pyfake.Server
+	server     *ghttp.Server
+	skipReason string
+	setupErr   error
+)
+
+func TestMain(m *testing.M) {
+	fake = pyfake.New()
+	skipReason, setupErr = setup(context.Background())
+
+	code := m.Run()
+	if server != nil {
+		py_service.GetPythonClient().StopAllSSEConnections()
+		_ = server.Shutdown()
+	}
+	fake.Close()
+	os.Exit(code)
+}
+
+// setup 在现有配置基础上指向 fake 并启动被测服务；依赖不可用时返回跳过原因
+func setup(ctx context.Context) (string, error) {
+	// 缩短各类间隔
+	data, err := g.Cfg().Data(ctx)
+	if err != nil {
+		return "", fmt.Errorf("读取配置失败: %v", err)
+	}
+	config := gjson.New(data)
+	_ = config.Set("python.baseUrl", fake.URL)
+	_ = config.Set("python.workers", nil)
+	_ = config.Set("python.completionMode", py_service.CompletionModeSSE)
+	_ = config.Set("python.retryInterval", 1)
+	_ = config.Set("python.maxRetryInterval", 1)
+	_ = config.Set("python.sse.pollInterval", 1)
+	_ = config.Set("python.healthCheckInterval", 0)
+	_ = config.Set("python.webhook.secret", integrationSecret)
+	_ = config.Set("plans.free.words_quota", 1<<30)
+	adapter, err := gcfg.NewAdapterContent(config.MustToJsonString())
+	if err != nil {
+		return "", fmt.Errorf("创建配置失败: %v", err)
+	}
+	g.Cfg().SetAdapter(adapter)
+
+	if err = g.DB().PingMaster(); err != nil {
+		return fmt.Sprintf("数据库不可用, 跳过集成测试: %v", err), nil
+	}
+
+	s := g.Server("py_service_integration")
+	s.SetPort(0)
+	s.SetDumpRouterMap(false)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware(func(r *ghttp.Request) {
+			r.SetCtxVar("userID", r.Header.Get("X-Test-User"))
+			r.Middleware.Next()
+		})
+		group.Middleware(ghttp.MiddlewareHandlerResponse)
+		group.Bind(tasks.NewV1())
+	})
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware(py_service.VerifyCallback)
+		group.Bind(python.NewV1())
+	})
+	if err = s.Start(); err != nil {
+		return "", fmt.Errorf("启动服务失败: %v", err)
+	}
+	server = s
+	return "", nil
+}
+
+// requireEnv 集成环境不可用时跳过或终止测试
+func requireEnv(t *testing.T) {
+	t.Helper()
+	if setupErr != nil {
+		t.Fatal(setupErr)
+	}
+	if skipReason != "" {
+		t.Skip(skipReason)
+	}
+}
+
+// fixture 测试用的用户、项目、管道、素材与模型
+type fixture struct {
+	userId      int
+	projectId   int
+	pipelineId  int
+	modelId     int
+	materialIds []int
+}
+
+func newFixture(t *testing.T, materials int) *fixture {
+	t.Helper()
+	requireEnv(t)
+	ctx := context.Background()
+	f := &fixture{}
+	suffix := gtime.TimestampNanoStr()
+
+	id, err := dao.Users.Ctx(ctx).Data(g.Map{
+		"username": "pyfake_" + suffix,
+		"password": "pyfake",
+	}).InsertAndGetId()
+	if err != nil {
+		t.Fatalf("创建用户失败: %v", err)
+	}
+	f.userId = int(id)
+
+	_, err = dao.UserSubscriptions.Ctx(ctx).Data(g.Map{
+		"user_id":          f.userId,
+		"quota_reset_date": gtime.Now().AddDate(0, 1, 0).Format("Y-m-d"),
+	}).Insert()
+	if err != nil {
+		t.Fatalf("创建订阅失败: %v", err)
+	}
+
+	// projects.id 没有自增序列
+	maxId, err := dao.Projects.Ctx(ctx).Max("id")
+	if err != nil {
+		t.Fatalf("获取项目ID失败: %v", err)
+	}
+	f.projectId = int(maxId) + 1
+	_, err = dao.Projects.Ctx(ctx).Data(g.Map{
+		"id":           f.projectId,
+		"user_id":      f.userId,
+		"project_name": "pyfake_" + suffix,
+	}).Insert()
+	if err != nil {
+		t.Fatalf("创建项目失败: %v", err)
+	}
+
+	id, err = dao.Pipelines.Ctx(ctx).Data(g.Map{
+		"project_id": f.projectId,
+		"start_step": "extract",
+	}).InsertAndGetId()
+	if err != nil {
+		t.Fatalf("创建管道失败: %v", err)
+	}
+	f.pipelineId = int(id)
+
+	for i := 0; i < materials; i++ {
+		id, err = dao.Materials.Ctx(ctx).Data(g.Map{
+			"project_id": f.projectId,
+			"url":        fmt.Sprintf("%s/materials/%d.txt", fake.URL, i),
+			"text_url":   fmt.Sprintf("%s/materials/%d.txt", fake.URL, i),
+		}).InsertAndGetId()
+		if err != nil {
+			t.Fatalf("创建素材失败: %v", err)
+		}
+		f.materialIds = append(f.materialIds, int(id))
+	}
+
+	id, err = dao.Models.Ctx(ctx).Data(g.Map{
+		"provider":   "openai",
+		"model_code": "fake-model",
+		"name":       "fake-model",
+	}).InsertAndGetId()
+	if err != nil {
+		t.Fatalf("创建模型失败: %v", err)
+	}
+	f.modelId = int(id)
+
+	t.Cleanup(func() {
+		taskIds, _ := dao.Tasks.Ctx(ctx).Where("project_id", f.projectId).Array("id")
+		if len(taskIds) > 0 {
+			_, _ = dao.PythonCallbackEvents.Ctx(ctx).WhereIn("task_id", taskIds).Delete()
+			_, _ = dao.PromptUsages.Ctx(ctx).WhereIn("task_id", taskIds).Delete()
+		}
+		// 抽取结果写入云存储, 删除对象和存储记录, 避免测试用户的文件残留在存储桶中
+		names, _ := dao.StorageObjects.Ctx(ctx).Where("user_id", f.userId).Array("object_name")
+		for _, name := range names {
+			if err := upload.NewUpload().RemoveObject(ctx, name.String()); err != nil {
+				t.Logf("删除测试对象失败: %v", err)
+			}
+		}
+		_, _ = dao.StorageObjects.Ctx(ctx).Where("user_id", f.userId).Delete()
+		_, _ = dao.UsageEvents.Ctx(ctx).Where("user_id", f.userId).Delete()
+		_, _ = dao.UsageDaily.Ctx(ctx).Where("user_id", f.userId).Delete()
+		_, _ = dao.Tasks.Ctx(ctx).Where("project_id", f.projectId).Delete()
+		_, _ = dao.Materials.Ctx(ctx).Where("project_id", f.projectId).Delete()
+		_, _ = dao.Pipelines.Ctx(ctx).Where("project_id", f.projectId).Delete()
+		_, _ = dao.Projects.Ctx(ctx).Where("id", f.projectId).Delete()
+		_, _ = dao.Models.Ctx(ctx).Where("id", f.modelId).Delete()
+		_, _ = dao.UserSubscriptions.Ctx(ctx).Where("user_id", f.userId).Delete()
+		_, _ = dao.Users.Ctx(ctx).Where("id", f.userId).Delete()
+	})
+	return f
+}
+
+// createExtractTask 通过 HTTP 接口创建抽取任务
+func (f *fixture) createExtractTask(t *testing.T) int {
+	t.Helper()
+	ctx := context.Background()
+	client := g.Client().
+		Prefix(fmt.Sprintf("http://127.0.0.1:%d", server.GetListenedPort())).
+		Header(map[string]string{"X-Test-User": fmt.Sprint(f.userId)})
+	content := client.PostContent(ctx, "/task/extract", g.Map{
+		"materialIdList": f.materialIds,
+		"projectId":      f.projectId,
+		"pipelineId":     f.pipelineId,
+		"method":         "llm",
+		"prompt":         "抽取人物与公司的任职关系",
+		"modelId":        f.modelId,
+	})
+	res := gjson.New(content)
+	if res.Get("code").Int() != 0 {
+		t.Fatalf("创建抽取任务失败: %s", content)
+	}
+	return res.Get("data.id").Int()
+}
+
+// waitTask 等待任务进入结束状态
+func waitTask(t *testing.T, taskId int, timeout time.Duration) *entity.Tasks {
+	t.Helper()
+	ctx := context.Background()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		var task *entity.Tasks
+		if err := dao.Tasks.Ctx(ctx).Where("id", taskId).Scan(&task); err != nil {
+			t.Fatalf("查询任务失败: %v", err)
+		}
+		if task != nil && (task.Status == "completed" || task.Status == "failed") {
+			return task
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	t.Fatalf("任务%d在%v内未结束", taskId, timeout)
+	return nil
+}
+
+func TestCreateExtractTask(t *testing.T) {
+	triples := []pyfake.Triple{
+		pyfake.TripleOf("人物", "张三", "任职于", "公司", "甲公司"),
+		pyfake.TripleOf("人物", "李四", "任职于", "公司", "乙公司"),
+	}
+
+	cases := []struct {
+		name       string
+		outcome    pyfake.Outcome
+		wantStatus string
+	}{
+		{"success", pyfake.Success(triples...), "completed"},
+		{"failure", pyfake.Failure("模型调用失败"), "failed"},
+		{"slow", pyfake.Slow(5, 300*time.Millisecond, triples...), "completed"},
+		{"disconnect", pyfake.Disconnect(2, triples...), "completed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFixture(t, 2)
+			fake.Script(tc.outcome)
+
+			taskId := f.createExtractTask(t)
+			task := waitTask(t, taskId, 30*time.Second)
+			if task.Status != tc.wantStatus {
+				t.Fatalf("任务状态为%s, 期望%s, 错误信息: %s", task.Status, tc.wantStatus, task.ErrorMessage)
+			}
+			if task.PyTaskId == "" {
+				t.Errorf("任务未记录py_task_id")
+			}
+			if tc.wantStatus == "completed" && len(task.Results.Array()) != len(f.materialIds) {
+				t.Errorf("任务结果数量为%d, 期望%d", len(task.Results.Array()), len(f.materialIds))
+			}
+
+			var materials []entity.Materials
+			if err := dao.Materials.Ctx(ctx).WhereIn("id", f.materialIds).Scan(&materials); err != nil {
+				t.Fatalf("查询素材失败: %v", err)
+			}
+			for _, material := range materials {
+				if tc.wantStatus == "completed" && material.TripleUrl == "" {
+					t.Errorf("素材%d未写入triple_url", material.Id)
+				}
+				if tc.wantStatus == "failed" && material.TripleUrl != "" {
+					t.Errorf("失败任务不应写入素材%d的triple_url", material.Id)
+				}
+			}
+
+			if tc.wantStatus == "completed" {
+				var project *entity.Projects
+				if err := dao.Projects.Ctx(ctx).Where("id", f.projectId).Scan(&project); err != nil {
+					t.Fatalf("查询项目失败: %v", err)
+				}
+				if project == nil || project.TripleUrl == "" {
+					t.Errorf("项目未写入triple_url")
+				}
+			}
+		})
+	}
+}
+
+func TestPythonClientAgainstFake(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+	client := py_service.NewPythonClient()
+
+	if err := client.HealthCheck(ctx); err != nil {
+		t.Fatalf("健康检查失败: %v", err)
+	}
+
+	prompt, err := client.GenPrompt(ctx, &py_service.PythonGenPromptRequest{SchemaURL: fake.URL + "/schema.json"})
+	if err != nil {
+		t.Fatalf("生成提示词失败: %v", err)
+	}
+	if prompt.Prompt == "" {
+		t.Errorf("生成的提示词为空")
+	}
+
+	fake.Script(pyfake.Success(pyfake.TripleOf("人物", "张三", "任职于", "公司", "甲公司")))
+	created, err := client.CreateTask(ctx, &py_service.PythonCreateTaskRequest{
+		Files:      []py_service.File{{MaterialId: 1, URL: fake.URL + "/materials/1.txt"}},
+		PromptText: "抽取人物与公司的任职关系",
+		Provider:   "openai",
+	})
+	if err != nil {
+		t.Fatalf("创建任务失败: %v", err)
+	}
+	status, err := client.WaitTask(ctx, created.TaskID, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("等待任务失败: %v", err)
+	}
+	if status.Status != "completed" || len(status.Result) != 1 || status.Result[0].TriplesCount != 1 {
+		t.Errorf("任务结果不符合预期: %+v", status)
+	}
+
+	fake.Script(pyfake.Slow(20, time.Second))
+	created, err = client.CreateTask(ctx, &py_service.PythonCreateTaskRequest{
+		Files:      []py_service.File{{MaterialId: 1, URL: fake.URL + "/materials/1.txt"}},
+		PromptText: "抽取人物与公司的任职关系",
+		Provider:   "openai",
+	})
+	if err != nil {
+		t.Fatalf("创建任务失败: %v", err)
+	}
+	if err = client.CancelTask(ctx, created.TaskID); err != nil {
+		t.Fatalf("取消任务失败: %v", err)
+	}
+	status, err = client.GetTaskStatus(ctx, created.TaskID)
+	if err != nil {
+		t.Fatalf("获取任务状态失败: %v", err)
+	}
+	if status.Status != "cancelled" {
+		t.Errorf("任务状态为%s, 期望cancelled", status.Status)
+	}
+
+	fake.SetHealthy(false)
+	defer fake.SetHealthy(true)
+	if err = client.HealthCheck(ctx); err == nil {
+		t.Errorf("服务不健康时健康检查应失败")
+	}
+}
+
+// postCallback 以 Python 服务的方式签名并推送回调
+func postCallback(t *testing.T, payload map[string]any) int {
+	t.Helper()
+	body, _ := json.Marshal(payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	url := fmt.Sprintf("http://127.0.0.1:%d/python/callback", server.GetListenedPort())
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Timestamp", timestamp)
+	req.Header.Set("X-Signature", signCallback(integrationSecret, timestamp, body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("推送回调失败: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestDuplicateTerminalCallback(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, 2)
+	fake.Script(pyfake.Success(pyfake.TripleOf("人物", "张三", "任职于", "公司", "甲公司")))
+
+	taskId := f.createExtractTask(t)
+	task := waitTask(t, taskId, 30*time.Second)
+	if task.Status != "completed" {
+		t.Fatalf("任务状态为%s, 期望completed, 错误信息: %s", task.Status, task.ErrorMessage)
+	}
+
+	wordsUsed := func() int {
+		value, err := dao.UserSubscriptions.Ctx(ctx).Where("user_id", f.userId).Value("words_used")
+		if err != nil {
+			t.Fatalf("查询用量失败: %v", err)
+		}
+		return value.Int()
+	}
+	projectTripleUrl := func() string {
+		value, err := dao.Projects.Ctx(ctx).Where("id", f.projectId).Value("triple_url")
+		if err != nil {
+			t.Fatalf("查询项目失败: %v", err)
+		}
+		return value.String()
+	}
+	usedBefore, tripleUrlBefore := wordsUsed(), projectTripleUrl()
+
+	status, err := py_service.GetPythonClient().GetTaskStatus(ctx, task.PyTaskId)
+	if err != nil {
+		t.Fatalf("获取任务状态失败: %v", err)
+	}
+	payload := map[string]any{}
+	raw, _ := json.Marshal(status)
+	_ = json.Unmarshal(raw, &payload)
+	payload["event_id"] = "duplicate-" + gtime.TimestampNanoStr()
+
+	// 事件ID不同的重复终态回调应被接受但不再处理
+	if code := postCallback(t, payload); code != http.StatusOK {
+		t.Fatalf("重复回调返回%d, 期望200", code)
+	}
+	if used := wordsUsed(); used != usedBefore {
+		t.Errorf("重复回调后用量为%d, 期望%d", used, usedBefore)
+	}
+	if tripleUrl := projectTripleUrl(); tripleUrl != tripleUrlBefore {
+		t.Errorf("重复回调后项目triple_url为%s, 期望%s", tripleUrl, tripleUrlBefore)
+	}
+}
@@ -0,0 +1,581 @@
+package py_service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/google/uuid"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/storage"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/utils"
+)
+
+// nativeSystemPrompt 约束模型输出与 Python 服务一致的三元组格式
+const nativeSystemPrompt = `你是知识图谱三元组抽取助手。请从用户给出的文本中抽取三元组，只输出 JSON 数组，不要输出任何解释。
+数组元素格式：{"head":{"type":"实体类型","label":"实体名称"},"relationship":{"type":"关系类型","label":"关系名称"},"tail":{"type":"实体类型","label":"实体名称"}}
+没有可抽取的内容时输出 []。`
+
+// nativeTaskTTL 结束后的任务在内存中保留的时间
+const nativeTaskTTL = time.Hour
+
+// NativeOptions 直连抽取的配置
+type NativeOptions struct {
+	ChunkSize     int           // 每个文本块的字符数
+	ChunkOverlap  int           // 相邻文本块重叠的字符数
+	Concurrency   int           // 同时请求模型的文本块数
+	Timeout       time.Duration // 单次模型请求超时
+	RetryCount    int           // 模型请求失败后的重试次数
+	RetryInterval time.Duration // 首次重试间隔, 之后按指数增长
+	Temperature   float64
+}
+
+// loadNativeOptions 从 extractor.native 配置中读取直连抽取配置
+func loadNativeOptions(ctx context.Context) NativeOptions {
+	return NativeOptions{
+		ChunkSize:     g.Cfg().MustGet(ctx, "extractor.native.chunkSize", 2000).Int(),
+		ChunkOverlap:  g.Cfg().MustGet(ctx, "extractor.native.chunkOverlap", 200).Int(),
+		Concurrency:   g.Cfg().MustGet(ctx, "extractor.native.concurrency", 4).Int(),
+		Timeout:       time.Duration(g.Cfg().MustGet(ctx, "extractor.native.timeout", 120).Int()) * time.Second,
+		RetryCount:    g.Cfg().MustGet(ctx, "extractor.native.retryCount", 2).Int(),
+		RetryInterval: time.Duration(g.Cfg().MustGet(ctx, "extractor.native.retryInterval", 2).Int()) * time.Second,
+		Temperature:   g.Cfg().MustGet(ctx, "extractor.native.temperature", 0).Float64(),
+	}
+}
+
+// nativeTask 内存中的抽取任务
+type nativeTask struct {
+	status PythonTaskStatus
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NativeExtractor 直接调用 OpenAI 兼容对话接口的抽取后端，在 Go 侧完成文本分块、提示词填充和三元组解析
+// 任务只保存在内存中, 进程重启后进行中的任务会丢失
+type NativeExtractor struct {
+	httpClient *http.Client
+	options    NativeOptions
+	tasks      map[string]*nativeTask
+	mutex      sync.RWMutex
+	// saveResult 保存单个素材的三元组并返回文件地址, 默认写入对象存储
+	saveResult func(ctx context.Context, fileName string, materialId int, content []byte) (string, error)
+}
+
+// NewNativeExtractor 创建直连抽取后端
+func NewNativeExtractor() *NativeExtractor {
+	options := loadNativeOptions(context.Background())
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = 2000
+	}
+	if options.ChunkOverlap < 0 || options.ChunkOverlap >= options.ChunkSize/2 {
+		options.ChunkOverlap = options.ChunkSize / 10
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+	return &NativeExtractor{
+		httpClient: &http.Client{},
+		options:    options,
+		tasks:      make(map[string]*nativeTask),
+		saveResult: saveNativeResult,
+	}
+}
+
+// CreateTask 创建抽取任务并在后台执行
+func (e *NativeExtractor) CreateTask(ctx context.Context, req *PythonCreateTaskRequest) (*PythonCreateTaskResponse, error) {
+	if len(req.Files) == 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "抽取文件不能为空")
+	}
+	if req.BaseURL == "" || req.Model == "" {
+		return nil, gerror.NewCodef(gcode.CodeMissingConfiguration, "未配置模型提供方的调用地址或模型编码: %s", req.Provider)
+	}
+
+	taskID := uuid.New().String()
+	taskCtx, cancel := context.WithCancel(context.Background())
+	task := &nativeTask{
+		status: PythonTaskStatus{
+			TaskID:    taskID,
+			Status:    "created",
+			CreatedAt: time.Now().Format(time.RFC3339),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	e.mutex.Lock()
+	e.tasks[taskID] = task
+	e.mutex.Unlock()
+
+	go e.run(taskCtx, task, *req)
+
+	g.Log().Infof(ctx, "直连抽取任务已创建: %s, 模型: %s", taskID, req.Model)
+	return &PythonCreateTaskResponse{
+		TaskID:  taskID,
+		Status:  "created",
+		Message: "任务已创建",
+	}, nil
+}
+
+// GetTaskStatus 获取任务状态
+func (e *NativeExtractor) GetTaskStatus(ctx context.Context, taskID string) (*PythonTaskStatus, error) {
+	task, err := e.getTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	status := task.status
+	status.Result = append([]TaskFile(nil), task.status.Result...)
+	return &status, nil
+}
+
+// WaitTask 等待任务结束, 任务在进程内执行, 无需轮询
+func (e *NativeExtractor) WaitTask(ctx context.Context, taskID string, interval time.Duration) (*PythonTaskStatus, error) {
+	task, err := e.getTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, gerror.Newf("等待抽取任务超时: %s", taskID)
+	case <-task.done:
+	}
+	return e.GetTaskStatus(ctx, taskID)
+}
+
+// CancelTask 取消任务, 正在进行的模型请求会被中断
+func (e *NativeExtractor) CancelTask(ctx context.Context, taskID string) error {
+	task, err := e.getTask(taskID)
+	if err != nil {
+		return err
+	}
+
+	task.cancel()
+	g.Log().Infof(ctx, "直连抽取任务取消成功: %s", taskID)
+	return nil
+}
+
+// WatchTask 任务结束后更新 Go 任务、素材与项目的抽取结果
+func (e *NativeExtractor) WatchTask(ctx context.Context, taskID string, goTaskID int) error {
+	if _, err := e.getTask(taskID); err != nil {
+		return err
+	}
+	if err := bindPythonTask(ctx, taskID, goTaskID); err != nil {
+		return err
+	}
+
+	go func() {
+		ctx := context.Background()
+		status, err := e.WaitTask(ctx, taskID, 0)
+		if err == nil {
+			err = processTaskStatus(goTaskID, status)
+		}
+		if err != nil {
+			g.Log().Errorf(ctx, "处理直连抽取结果失败: %v", err)
+			_, err = dao.Tasks.Ctx(ctx).Where("id", goTaskID).Update(g.Map{
+				"status":        consts.TaskStatusFailed,
+				"error_message": err.Error(),
+				"updated_at":    gtime.Now(),
+			})
+			if err != nil {
+				g.Log().Errorf(ctx, "更新任务状态失败: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (e *NativeExtractor) getTask(taskID string) (*nativeTask, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	task, exists := e.tasks[taskID]
+	if !exists {
+		return nil, gerror.NewCodef(gcode.CodeNotFound, "抽取任务不存在: %s", taskID)
+	}
+	return task, nil
+}
+
+// update 修改任务状态
+func (e *NativeExtractor) update(task *nativeTask, fn func(status *PythonTaskStatus)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	fn(&task.status)
+}
+
+// nativeFile 单个素材的分块与抽取结果
+type nativeFile struct {
+	file    File
+	chunks  []string
+	triples [][]map[string]interface{} // 每个文本块的三元组
+	errs    []error                    // 每个文本块的错误
+	err     error                      // 读取素材失败
+}
+
+// run 执行抽取：读取素材文本并分块，并发请求模型，按素材汇总并保存三元组
+func (e *NativeExtractor) run(ctx context.Context, task *nativeTask, req PythonCreateTaskRequest) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.Log().Errorf(ctx, "直连抽取任务异常: %v", r)
+			e.finish(task, "failed", fmt.Sprintf("抽取任务异常: %v", r), nil)
+		}
+		close(task.done)
+		time.AfterFunc(nativeTaskTTL, func() {
+			e.mutex.Lock()
+			delete(e.tasks, task.status.TaskID)
+			e.mutex.Unlock()
+		})
+	}()
+
+	e.update(task, func(status *PythonTaskStatus) {
+		status.Status = "processing"
+		status.StartedAt = time.Now().Format(time.RFC3339)
+	})
+
+	// 读取素材文本并分块
+	files := make([]*nativeFile, 0, len(req.Files))
+	total := 0
+	for _, f := range req.Files {
+		item := &nativeFile{file: f}
+		text, err := utils.DownloadTextFromURL(ctx, f.URL)
+		if err != nil {
+			item.err = gerror.Newf("读取素材文本失败: %v", err)
+		} else {
+			item.chunks = splitText(text, e.options.ChunkSize, e.options.ChunkOverlap)
+			item.triples = make([][]map[string]interface{}, len(item.chunks))
+			item.errs = make([]error, len(item.chunks))
+			total += len(item.chunks)
+		}
+		files = append(files, item)
+	}
+
+	// 并发抽取各文本块
+	var (
+		wg       sync.WaitGroup
+		finished int
+	)
+	sem := make(chan struct{}, e.options.Concurrency)
+	for _, item := range files {
+		for i, chunk := range item.chunks {
+			wg.Add(1)
+			go func(item *nativeFile, i int, chunk string) {
+				defer wg.Done()
+				select {
+				case <-ctx.Done():
+					item.errs[i] = ctx.Err()
+					return
+				case sem <- struct{}{}:
+				}
+				defer func() { <-sem }()
+
+				item.triples[i], item.errs[i] = e.extractChunk(ctx, &req, chunk, i)
+				e.update(task, func(status *PythonTaskStatus) {
+					finished++
+					status.Progress = float64(finished) / float64(total)
+				})
+			}(item, i, chunk)
+		}
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		e.finish(task, "cancelled", "任务已取消", nil)
+		return
+	}
+
+	// 按素材汇总并保存
+	results := make([]TaskFile, 0, len(files))
+	var firstErr error
+	succeeded := 0
+	for _, item := range files {
+		result := e.saveFile(ctx, task.status.TaskID, item)
+		if result.Status == "success" {
+			succeeded++
+		} else if firstErr == nil {
+			firstErr = gerror.New(result.Error)
+		}
+		results = append(results, result)
+	}
+	if succeeded == 0 {
+		e.finish(task, "failed", firstErr.Error(), results)
+		return
+	}
+	e.finish(task, "completed", "", results)
+}
+
+// finish 设置任务的结束状态
+func (e *NativeExtractor) finish(task *nativeTask, state string, errorMessage string, results []TaskFile) {
+	e.update(task, func(status *PythonTaskStatus) {
+		status.Status = state
+		status.Error = errorMessage
+		status.Result = results
+		status.CompletedAt = time.Now().Format(time.RFC3339)
+		if state == "completed" {
+			status.Progress = 1
+		}
+	})
+	g.Log().Infof(context.Background(), "直连抽取任务结束: %s, 状态: %s", task.status.TaskID, state)
+}
+
+// saveFile 合并素材各文本块的三元组并保存, 全部文本块失败时该素材失败
+func (e *NativeExtractor) saveFile(ctx context.Context, taskID string, item *nativeFile) TaskFile {
+	result := TaskFile{
+		FileName:   path.Base(item.file.URL),
+		MaterialId: item.file.MaterialId,
+		Status:     "failed",
+	}
+	if item.err != nil {
+		result.Error = item.err.Error()
+		return result
+	}
+
+	triples := make([]map[string]interface{}, 0)
+	failed := 0
+	for i, err := range item.errs {
+		if err != nil {
+			failed++
+			g.Log().Warningf(ctx, "文本块抽取失败: %v, 素材ID: %d, 文本块: %d", err, item.file.MaterialId, i)
+			if result.Error == "" {
+				result.Error = err.Error()
+			}
+			continue
+		}
+		triples = append(triples, item.triples[i]...)
+	}
+	if len(item.chunks) > 0 && failed == len(item.chunks) {
+		return result
+	}
+
+	content, _ := json.Marshal(triples)
+	fileUrl, err := e.saveResult(ctx, fmt.Sprintf("triples_native_%s_%d", taskID[:8], item.file.MaterialId), item.file.MaterialId, content)
+	if err != nil {
+		result.Error = fmt.Sprintf("保存抽取结果失败: %v", err)
+		return result
+	}
+
+	result.Status = "success"
+	result.Error = ""
+	result.TriplesCount = len(triples)
+	result.OutputFiles = map[string]string{
+		"jsonl": fileUrl,
+	}
+	return result
+}
+
+// saveNativeResult 将三元组保存到对象存储, 计入素材所属用户的存储用量
+func saveNativeResult(ctx context.Context, fileName string, materialId int, content []byte) (string, error) {
+	saveDataOutput, err := storage.New().SaveData(ctx, &upload.SaveDataInput{
+		FileName: utils.RemoveExt(fileName),
+		Content:  string(content),
+		DataType: "json",
+		UserId:   materialOwner(ctx, materialId),
+	}, consts.StorageSourceArtifact)
+	if err != nil {
+		return "", err
+	}
+	return upload.NewUpload().GenerateFileUrl(ctx, saveDataOutput.FileName), nil
+}
+
+// extractChunk 抽取单个文本块的三元组，并添加与 Python 服务一致的溯源字段
+func (e *NativeExtractor) extractChunk(ctx context.Context, req *PythonCreateTaskRequest, chunk string, index int) ([]map[string]interface{}, error) {
+	content, err := e.chat(ctx, req, renderPrompt(req.PromptText, chunk))
+	if err != nil {
+		return nil, err
+	}
+	triples, err := parseTripleJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, triple := range triples {
+		triple["_chunk_index"] = index
+		triple["_source_text"] = chunk
+	}
+	return triples, nil
+}
+
+// chat 调用 OpenAI 兼容的 /chat/completions 接口, 失败时按指数退避重试
+func (e *NativeExtractor) chat(ctx context.Context, req *PythonCreateTaskRequest, prompt string) (string, error) {
+	payload, err := json.Marshal(g.Map{
+		"model":       req.Model,
+		"temperature": e.options.Temperature,
+		"messages": []g.Map{
+			{"role": "system", "content": nativeSystemPrompt},
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return "", gerror.Newf("序列化请求失败: %v", err)
+	}
+	url := strings.TrimRight(req.BaseURL, "/") + "/chat/completions"
+
+	for attempt := 0; ; attempt++ {
+		content, err := e.doChat(ctx, url, req.APIKey, payload)
+		if err == nil {
+			return content, nil
+		}
+		if ctx.Err() != nil || !isRetryable(err) || attempt >= e.options.RetryCount {
+			return "", err
+		}
+
+		wait := e.options.RetryInterval << attempt
+		g.Log().Warningf(ctx, "请求模型失败, %v后进行第%d次重试: %v", wait, attempt+1, err)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (e *NativeExtractor) doChat(ctx context.Context, url string, apiKey string, payload []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.options.Timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return "", gerror.Newf("创建HTTP请求失败: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := e.httpClient.Do(httpReq)
+	if err != nil {
+		return "", gerror.Newf("发送HTTP请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", &statusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", gerror.Newf("解析响应失败: %v", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", gerror.New("模型未返回内容")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
+
+// renderPrompt 填充提示词模板：包含 {text} 占位符时替换为文本块, 否则将文本块附加在提示词之后
+func renderPrompt(prompt string, chunk string) string {
+	if strings.Contains(prompt, "{text}") {
+		return strings.ReplaceAll(prompt, "{text}", chunk)
+	}
+	return prompt + "\n\n待抽取文本：\n" + chunk
+}
+
+// splitText 按字符数切分文本，尽量在换行或句末断开，相邻文本块保留 overlap 个字符的重叠
+func splitText(text string, size int, overlap int) []string {
+	runes := []rune(text)
+	chunks := make([]string, 0, len(runes)/size+1)
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			end = len(runes)
+		} else {
+			// 在文本块后 20% 的范围内寻找断句位置
+			for i := end - 1; i > start+size*4/5; i-- {
+				if strings.ContainsRune("\n。！？；.!?;", runes[i]) {
+					end = i + 1
+					break
+				}
+			}
+		}
+
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+		if end >= len(runes) {
+			break
+		}
+		start = end - overlap
+	}
+	return chunks
+}
+
+// parseTripleJSON 解析模型输出的三元组 JSON, 兼容 markdown 代码块和 {"triples": [...]} 格式
+// 缺少头实体或尾实体的三元组会被丢弃
+func parseTripleJSON(content string) ([]map[string]interface{}, error) {
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+	content = strings.TrimSpace(content)
+
+	var list []map[string]interface{}
+	if strings.HasPrefix(content, "{") {
+		var wrapped struct {
+			Triples []map[string]interface{} `json:"triples"`
+		}
+		if err := json.Unmarshal([]byte(content), &wrapped); err != nil {
+			return nil, gerror.Newf("模型输出不是有效的JSON: %v", err)
+		}
+		list = wrapped.Triples
+	} else {
+		start := strings.Index(content, "[")
+		end := strings.LastIndex(content, "]")
+		if start < 0 || end < start {
+			return nil, gerror.New("模型输出中未找到三元组数组")
+		}
+		if err := json.Unmarshal([]byte(content[start:end+1]), &list); err != nil {
+			return nil, gerror.Newf("模型输出不是有效的JSON: %v", err)
+		}
+	}
+
+	triples := make([]map[string]interface{}, 0, len(list))
+	for _, triple := range list {
+		head, _ := triple["head"].(map[string]interface{})
+		tail, _ := triple["tail"].(map[string]interface{})
+		if getStringValue(head, "label") == "" || getStringValue(tail, "label") == "" {
+			continue
+		}
+		// 关系只给出名称时补全为对象
+		if relationship, ok := triple["relationship"].(string); ok {
+			triple["relationship"] = map[string]interface{}{"type": relationship, "label": relationship}
+		}
+		if _, ok := triple["relationship"].(map[string]interface{}); !ok {
+			continue
+		}
+		triples = append(triples, triple)
+	}
+	return triples, nil
+}
+
+// materialOwner 素材所属项目的所有者, 直连抽取的结果计入其存储用量
+func materialOwner(ctx context.Context, materialId int) int {
+	userId, err := dao.Projects.Ctx(ctx).
+		Where("id = (select project_id from materials where id = ?)", materialId).
+		Value("user_id")
+	if err != nil {
+		g.Log().Errorf(ctx, "获取素材所属用户失败: %v, 素材ID: %d", err, materialId)
+		return 0
+	}
+	return userId.Int()
+}
@@ -0,0 +1,146 @@
+package py_service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcfg"
+)
+
+const mockMaterialText = "张三任职于甲公司。"
+
+// newMockLLM 模拟素材文件服务和 OpenAI 兼容的对话接口
+func newMockLLM(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /materials/{name}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mockMaterialText))
+	})
+	mux.HandleFunc("POST /v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Messages) != 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if body.Model == "broken-model" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"model not found"}`))
+			return
+		}
+		if !strings.Contains(body.Messages[1].Content, mockMaterialText) {
+			t.Errorf("提示词中缺少待抽取文本: %s", body.Messages[1].Content)
+		}
+
+		reply := "```json\n" + `[{"head":{"type":"人物","label":"张三"},"relationship":"任职于","tail":{"type":"公司","label":"甲公司"}},` +
+			`{"head":{"type":"人物","label":""},"relationship":"任职于","tail":{"type":"公司","label":"乙公司"}}]` + "\n```"
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"content": reply}}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNativeExtractorWithMockLLM(t *testing.T) {
+	server := newMockLLM(t)
+
+	adapter, err := gcfg.NewAdapterContent(`{"extractor":{"native":{"retryCount":0,"timeout":5}}}`)
+	if err != nil {
+		t.Fatalf("创建配置失败: %v", err)
+	}
+	g.Cfg().SetAdapter(adapter)
+
+	var mutex sync.Mutex
+	saved := make(map[int][]byte)
+	e := NewNativeExtractor()
+	e.saveResult = func(ctx context.Context, fileName string, materialId int, content []byte) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		saved[materialId] = content
+		return "mem://" + fileName, nil
+	}
+
+	newRequest := func(model string) *PythonCreateTaskRequest {
+		return &PythonCreateTaskRequest{
+			Files: []File{
+				{MaterialId: 1, URL: server.URL + "/materials/1.txt"},
+				{MaterialId: 2, URL: server.URL + "/materials/2.txt"},
+			},
+			PromptText: "抽取人物与公司的任职关系",
+			Provider:   "openai",
+			Model:      model,
+			APIKey:     "test-key",
+			BaseURL:    server.URL + "/v1",
+		}
+	}
+	run := func(req *PythonCreateTaskRequest) *PythonTaskStatus {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		created, err := e.CreateTask(ctx, req)
+		if err != nil {
+			t.Fatalf("创建任务失败: %v", err)
+		}
+		status, err := e.WaitTask(ctx, created.TaskID, 0)
+		if err != nil {
+			t.Fatalf("等待任务失败: %v", err)
+		}
+		return status
+	}
+
+	status := run(newRequest("mock-model"))
+	if status.Status != "completed" || len(status.Result) != 2 {
+		t.Fatalf("任务结果不符合预期: %+v", status)
+	}
+	for _, result := range status.Result {
+		if result.Status != "success" || result.TriplesCount != 1 {
+			t.Errorf("素材%d的结果不符合预期: %+v", result.MaterialId, result)
+		}
+		if !strings.HasPrefix(result.OutputFiles["jsonl"], "mem://") {
+			t.Errorf("素材%d的输出文件不符合预期: %v", result.MaterialId, result.OutputFiles)
+		}
+	}
+
+	var triples []map[string]any
+	if err := json.Unmarshal(saved[1], &triples); err != nil {
+		t.Fatalf("解析保存的三元组失败: %v", err)
+	}
+	if len(triples) != 1 {
+		t.Fatalf("保存的三元组数量为%d, 期望1", len(triples))
+	}
+	if relationship, _ := triples[0]["relationship"].(map[string]any); relationship["label"] != "任职于" {
+		t.Errorf("关系未补全为对象: %v", triples[0]["relationship"])
+	}
+	if triples[0]["_source_text"] != mockMaterialText {
+		t.Errorf("溯源文本为%v, 期望%s", triples[0]["_source_text"], mockMaterialText)
+	}
+
+	// 模型请求失败时任务失败且不保存结果
+	mutex.Lock()
+	clear(saved)
+	mutex.Unlock()
+	status = run(newRequest("broken-model"))
+	if status.Status != "failed" || status.Error == "" {
+		t.Errorf("模型请求失败时任务状态为%s, 错误信息: %s", status.Status, status.Error)
+	}
+	if len(saved) != 0 {
+		t.Errorf("失败的任务不应保存结果")
+	}
+}
@@ -0,0 +1,263 @@
+package py_service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtimer"
+)
+
+// taskWorkerKeyPrefix Redis 中记录 py_task_id 所属节点的键前缀，重启后仍可路由到原节点
+const taskWorkerKeyPrefix = "py_service:task_worker:"
+
+// taskWorkerTTL 任务与节点对应关系的保留时间
+const taskWorkerTTL = 7 * 24 * time.Hour
+
+// Worker Python 抽取服务节点
+type Worker struct {
+	Name     string `json:"name"`
+	BaseURL  string `json:"baseUrl"`
+	Weight   int    `json:"weight"`   // 权重, 越大分到的任务越多
+	Capacity int    `json:"capacity"` // 最大并发任务数, 0 表示不限制
+
+	healthy     bool
+	outstanding int
+	breaker     *circuitBreaker
+}
+
+// WorkerStatus 节点运行状态
+type WorkerStatus struct {
+	Name        string `json:"name"`
+	BaseURL     string `json:"baseUrl"`
+	Weight      int    `json:"weight"`
+	Capacity    int    `json:"capacity"`
+	Healthy     bool   `json:"healthy"`
+	Outstanding int    `json:"outstanding" dc:"进行中的任务数"`
+}
+
+// WorkerPool Python 节点池：按未完成任务数选择节点，定期健康检查剔除异常节点
+type WorkerPool struct {
+	workers []*Worker
+	tasks   map[string]*Worker // py_task_id -> 进行中任务所在节点
+	mutex   sync.RWMutex
+}
+
+var (
+	workerPool     *WorkerPool
+	workerPoolOnce sync.Once
+)
+
+// getWorkerPool 获取节点池单例, 所有客户端共用节点池、进行中任务数和健康检查
+func getWorkerPool(ctx context.Context, options ClientOptions, client *http.Client) *WorkerPool {
+	workerPoolOnce.Do(func() {
+		workerPool = newWorkerPool(ctx, options)
+		workerPool.startHealthCheck(ctx, client, options.HealthInterval, options.Timeout)
+	})
+	return workerPool
+}
+
+// newWorkerPool 从 python.workers 配置创建节点池，未配置时使用 python.baseUrl 作为唯一节点
+func newWorkerPool(ctx context.Context, options ClientOptions) *WorkerPool {
+	var workers []*Worker
+	if err := g.Cfg().MustGet(ctx, "python.workers").Scan(&workers); err != nil {
+		g.Log().Errorf(ctx, "解析Python节点配置失败: %v", err)
+	}
+	if len(workers) == 0 {
+		workers = []*Worker{{
+			Name:    "default",
+			BaseURL: g.Cfg().MustGet(ctx, "python.baseUrl", "http://localhost:8000").String(),
+			Weight:  1,
+		}}
+	}
+
+	for i, worker := range workers {
+		if worker.Name == "" {
+			worker.Name = fmt.Sprintf("worker-%d", i+1)
+		}
+		if worker.Weight <= 0 {
+			worker.Weight = 1
+		}
+		worker.healthy = true
+		worker.breaker = newCircuitBreaker(options.FailureThreshold, options.OpenTimeout)
+	}
+
+	return &WorkerPool{
+		workers: workers,
+		tasks:   make(map[string]*Worker),
+	}
+}
+
+// pick 选择负载最低的节点, 不占用任务名额, 用于生成提示词等非任务请求
+func (p *WorkerPool) pick() (*Worker, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.selectWorker()
+}
+
+// reserve 选择负载最低的节点并占用一个任务名额, 容量检查与计数在同一把锁内完成
+// 任务创建失败时调用 unreserve 归还名额
+func (p *WorkerPool) reserve() (*Worker, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	worker, err := p.selectWorker()
+	if err != nil {
+		return nil, err
+	}
+	worker.outstanding++
+	return worker, nil
+}
+
+// unreserve 归还 reserve 占用的任务名额
+func (p *WorkerPool) unreserve(worker *Worker) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	worker.outstanding--
+}
+
+// selectWorker 进行中任务数 / 权重 最小的节点，跳过不健康、已熔断和已满的节点, 调用方需持有锁
+func (p *WorkerPool) selectWorker() (*Worker, error) {
+	var (
+		selected  *Worker
+		bestScore float64
+	)
+	for _, worker := range p.workers {
+		if !worker.healthy || !worker.breaker.available() {
+			continue
+		}
+		if worker.Capacity > 0 && worker.outstanding >= worker.Capacity {
+			continue
+		}
+		score := float64(worker.outstanding) / float64(worker.Weight)
+		if selected == nil || score < bestScore {
+			selected = worker
+			bestScore = score
+		}
+	}
+	if selected == nil {
+		return nil, gerror.NewCode(gcode.CodeServerBusy, "暂无可用的Python服务节点，请稍后重试")
+	}
+	return selected, nil
+}
+
+// assign 记录任务所属节点, 进行中任务数已在 reserve 时计入
+func (p *WorkerPool) assign(ctx context.Context, taskID string, worker *Worker) {
+	p.mutex.Lock()
+	if _, exists := p.tasks[taskID]; exists {
+		worker.outstanding--
+	} else {
+		p.tasks[taskID] = worker
+	}
+	p.mutex.Unlock()
+
+	if _, err := g.Redis().Set(ctx, taskWorkerKeyPrefix+taskID, worker.Name); err != nil {
+		g.Log().Warningf(ctx, "记录任务所属节点失败: %v, 任务ID: %s", err, taskID)
+		return
+	}
+	_, _ = g.Redis().Expire(ctx, taskWorkerKeyPrefix+taskID, int64(taskWorkerTTL.Seconds()))
+}
+
+// release 任务结束后释放节点的进行中任务数，重复调用无副作用
+func (p *WorkerPool) release(taskID string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if worker, exists := p.tasks[taskID]; exists {
+		worker.outstanding--
+		delete(p.tasks, taskID)
+	}
+}
+
+// workerFor 获取任务所属节点，找不到记录时使用第一个节点
+func (p *WorkerPool) workerFor(ctx context.Context, taskID string) *Worker {
+	p.mutex.RLock()
+	worker, exists := p.tasks[taskID]
+	p.mutex.RUnlock()
+	if exists {
+		return worker
+	}
+
+	// 进程重启后从 Redis 恢复
+	name, err := g.Redis().Get(ctx, taskWorkerKeyPrefix+taskID)
+	if err != nil {
+		g.Log().Warningf(ctx, "获取任务所属节点失败: %v, 任务ID: %s", err, taskID)
+	}
+	if err == nil && !name.IsEmpty() {
+		for _, worker := range p.workers {
+			if worker.Name == name.String() {
+				return worker
+			}
+		}
+	}
+	return p.workers[0]
+}
+
+// Status 获取所有节点的运行状态
+func (p *WorkerPool) Status() []WorkerStatus {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	list := make([]WorkerStatus, 0, len(p.workers))
+	for _, worker := range p.workers {
+		list = append(list, WorkerStatus{
+			Name:        worker.Name,
+			BaseURL:     worker.BaseURL,
+			Weight:      worker.Weight,
+			Capacity:    worker.Capacity,
+			Healthy:     worker.healthy,
+			Outstanding: worker.outstanding,
+		})
+	}
+	return list
+}
+
+// startHealthCheck 定期检查所有节点的 /health，将异常节点移出调度
+func (p *WorkerPool) startHealthCheck(ctx context.Context, client *http.Client, interval time.Duration, timeout time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	gtimer.AddSingleton(ctx, interval, func(ctx context.Context) {
+		for _, worker := range p.workers {
+			err := checkWorkerHealth(ctx, client, worker, timeout)
+
+			p.mutex.Lock()
+			healthy := err == nil
+			if worker.healthy != healthy {
+				if healthy {
+					g.Log().Infof(ctx, "Python节点恢复健康: %s", worker.Name)
+				} else {
+					g.Log().Warningf(ctx, "Python节点健康检查失败, 暂停调度: %s, %v", worker.Name, err)
+				}
+			}
+			worker.healthy = healthy
+			p.mutex.Unlock()
+		}
+	})
+}
+
+// checkWorkerHealth 检查单个节点
+func checkWorkerHealth(ctx context.Context, client *http.Client, worker *Worker, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/health", worker.BaseURL), nil)
+	if err != nil {
+		return gerror.Newf("创建健康检查请求失败: %v", err)
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return gerror.Newf("健康检查请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{StatusCode: resp.StatusCode, Body: "Python服务不健康"}
+	}
+	return nil
+}
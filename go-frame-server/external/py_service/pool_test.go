@@ -0,0 +1,121 @@
+package py_service
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// testPool 构造健康且熔断器关闭的节点池
+func testPool(workers ...*Worker) *WorkerPool {
+	for _, worker := range workers {
+		worker.healthy = true
+		worker.breaker = newCircuitBreaker(1, time.Minute)
+	}
+	return &WorkerPool{workers: workers, tasks: make(map[string]*Worker)}
+}
+
+func TestWorkerPoolReserve(t *testing.T) {
+	cases := []struct {
+		name    string
+		workers []*Worker
+		prepare func(p *WorkerPool)
+		reserve int    // 连续占用的次数
+		want    string // 最后一次占用的节点, 为空时期望无可用节点
+	}{
+		{
+			name:    "选择进行中任务最少的节点",
+			workers: []*Worker{{Name: "a", Weight: 1, outstanding: 2}, {Name: "b", Weight: 1, outstanding: 1}},
+			reserve: 1, want: "b",
+		},
+		{
+			name:    "按权重折算负载",
+			workers: []*Worker{{Name: "a", Weight: 1, outstanding: 1}, {Name: "b", Weight: 3, outstanding: 2}},
+			reserve: 1, want: "b",
+		},
+		{
+			name:    "占用后计入进行中任务数",
+			workers: []*Worker{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}},
+			reserve: 2, want: "b",
+		},
+		{
+			name:    "跳过已满的节点",
+			workers: []*Worker{{Name: "a", Weight: 1, Capacity: 1, outstanding: 1}, {Name: "b", Weight: 1, outstanding: 5}},
+			reserve: 1, want: "b",
+		},
+		{
+			name:    "所有节点已满",
+			workers: []*Worker{{Name: "a", Weight: 1, Capacity: 2}},
+			reserve: 3,
+		},
+		{
+			name:    "跳过不健康的节点",
+			workers: []*Worker{{Name: "a", Weight: 1}, {Name: "b", Weight: 1, outstanding: 3}},
+			prepare: func(p *WorkerPool) { p.workers[0].healthy = false },
+			reserve: 1, want: "b",
+		},
+		{
+			name:    "跳过已熔断的节点",
+			workers: []*Worker{{Name: "a", Weight: 1}, {Name: "b", Weight: 1, outstanding: 3}},
+			prepare: func(p *WorkerPool) { p.workers[0].breaker.record(&statusError{StatusCode: http.StatusBadGateway}) },
+			reserve: 1, want: "b",
+		},
+	}
+	for _, c := range cases {
+		p := testPool(c.workers...)
+		if c.prepare != nil {
+			c.prepare(p)
+		}
+		var (
+			worker *Worker
+			err    error
+		)
+		for i := 0; i < c.reserve; i++ {
+			if worker, err = p.reserve(); err != nil {
+				break
+			}
+		}
+		switch {
+		case c.want == "":
+			if err == nil {
+				t.Errorf("%s: 应无可用节点, 得到 %s", c.name, worker.Name)
+			}
+		case err != nil:
+			t.Errorf("%s: 不应返回错误: %v", c.name, err)
+		case worker.Name != c.want:
+			t.Errorf("%s: 选择了 %s, 期望 %s", c.name, worker.Name, c.want)
+		}
+	}
+}
+
+func TestWorkerPoolRelease(t *testing.T) {
+	p := testPool(&Worker{Name: "a", Weight: 1, Capacity: 1})
+	worker, err := p.reserve()
+	if err != nil {
+		t.Fatalf("占用节点失败: %v", err)
+	}
+	if _, err = p.reserve(); err == nil {
+		t.Fatalf("节点已满时应无法占用")
+	}
+
+	// 任务创建失败时归还名额
+	p.unreserve(worker)
+	if worker.outstanding != 0 {
+		t.Fatalf("归还后进行中任务数为 %d", worker.outstanding)
+	}
+
+	// 任务结束后释放名额, 重复释放和释放未知任务无副作用
+	if worker, err = p.reserve(); err != nil {
+		t.Fatalf("归还后应可再次占用: %v", err)
+	}
+	p.tasks["t1"] = worker
+	for _, taskID := range []string{"t1", "t1", "unknown"} {
+		p.release(taskID)
+	}
+	if worker.outstanding != 0 || len(p.tasks) != 0 {
+		t.Errorf("释放后进行中任务数为 %d, 记录的任务 %d 个", worker.outstanding, len(p.tasks))
+	}
+	if _, err = p.reserve(); err != nil {
+		t.Errorf("释放后应可再次占用: %v", err)
+	}
+}
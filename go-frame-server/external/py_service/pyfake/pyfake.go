@@ -0,0 +1,481 @@
+// Package pyfake 进程内的 Python 抽取服务替身，实现与 Python 服务相同的 HTTP 接口，
+// 用于在没有 Flask 服务和大模型 Key 的情况下测试 py_service 及任务相关接口。
+package pyfake
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Node 三元组的头/尾实体或关系
+type Node struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// Triple 与 Python 服务输出一致的三元组格式
+type Triple struct {
+	Head         Node   `json:"head"`
+	Relationship Node   `json:"relationship"`
+	Tail         Node   `json:"tail"`
+	ChunkIndex   int    `json:"_chunk_index"`
+	SourceText   string `json:"_source_text,omitempty"`
+}
+
+// Outcome 下一个任务的执行结果
+type Outcome struct {
+	Error       string        // 不为空时任务失败
+	Triples     []Triple      // 每个素材输出的三元组
+	Steps       int           // 完成前推送的进度次数
+	StepDelay   time.Duration // 每次进度之间的间隔
+	Disconnects int           // 前几次 SSE 连接在推送第一条进度后断开
+}
+
+// Success 任务成功，每个素材输出给定的三元组
+func Success(triples ...Triple) Outcome {
+	return Outcome{Triples: triples, Steps: 1, StepDelay: 10 * time.Millisecond}
+}
+
+// Failure 任务失败
+func Failure(message string) Outcome {
+	return Outcome{Error: message, Steps: 1, StepDelay: 10 * time.Millisecond}
+}
+
+// Slow 任务缓慢执行，完成前推送 steps 次进度
+func Slow(steps int, delay time.Duration, triples ...Triple) Outcome {
+	return Outcome{Triples: triples, Steps: steps, StepDelay: delay}
+}
+
+// Disconnect 前 n 次 SSE 连接在中途断开，任务仍会成功完成
+func Disconnect(n int, triples ...Triple) Outcome {
+	return Outcome{Triples: triples, Steps: 5, StepDelay: 50 * time.Millisecond, Disconnects: n}
+}
+
+type file struct {
+	MaterialId int    `json:"material_id"`
+	URL        string `json:"url"`
+}
+
+// CreateTaskRequest 收到的创建任务请求
+type CreateTaskRequest struct {
+	Files       []file `json:"files"`
+	PromptText  string `json:"prompt_text"`
+	Provider    string `json:"provider"`
+	Model       string `json:"model,omitempty"`
+	APIKey      string `json:"api_key"`
+	BaseURL     string `json:"base_url,omitempty"`
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+type taskFile struct {
+	FileName     string            `json:"file_name"`
+	MaterialId   int               `json:"material_id"`
+	Status       string            `json:"status"`
+	TriplesCount int               `json:"triples_count"`
+	OutputFiles  map[string]string `json:"output_files"`
+	Error        string            `json:"error,omitempty"`
+}
+
+type taskStatus struct {
+	TaskID      string     `json:"task_id"`
+	Status      string     `json:"status"`
+	Progress    float64    `json:"progress,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Result      []taskFile `json:"results,omitempty"`
+	CreatedAt   string     `json:"created_at,omitempty"`
+	CompletedAt string     `json:"completed_at,omitempty"`
+	Type        string     `json:"type,omitempty"`
+}
+
+type task struct {
+	request     CreateTaskRequest
+	outcome     Outcome
+	status      taskStatus
+	version     int // 每次状态变化加一，SSE 据此推送
+	disconnects int
+}
+
+// Server 进程内的 Python 服务替身
+type Server struct {
+	*httptest.Server
+
+	// WebhookSecret 不为空且任务带有 callback_url 时，状态变化会以签名回调的方式推送
+	WebhookSecret string
+
+	// MaterialText /materials/{name} 返回的素材文本
+	MaterialText string
+
+	mutex     sync.Mutex
+	scripts   []Outcome
+	tasks     map[string]*task
+	files     map[string][]Triple
+	requests  []CreateTaskRequest
+	healthy   bool
+	genPrompt map[string]any
+	seq       int
+}
+
+// New 启动替身服务，未编排结果的任务默认成功且不输出三元组
+func New() *Server {
+	s := &Server{
+		tasks:        make(map[string]*task),
+		files:        make(map[string][]Triple),
+		healthy:      true,
+		MaterialText: "张三任职于甲公司。李四任职于乙公司。",
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/tasks", s.createTask)
+	mux.HandleFunc("GET /api/v1/tasks/{id}", s.getTask)
+	mux.HandleFunc("DELETE /api/v1/tasks/{id}", s.cancelTask)
+	mux.HandleFunc("GET /api/v1/tasks/{id}/stream", s.streamTask)
+	mux.HandleFunc("POST /api/v1/genprompt", s.genPromptHandler)
+	mux.HandleFunc("GET /health", s.health)
+	mux.HandleFunc("GET /files/{name}", s.serveFile)
+	mux.HandleFunc("GET /materials/{name}", s.serveMaterial)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Script 按顺序编排后续创建的任务的结果
+func (s *Server) Script(outcomes ...Outcome) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.scripts = append(s.scripts, outcomes...)
+}
+
+// SetHealthy 设置 /health 的返回结果
+func (s *Server) SetHealthy(healthy bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.healthy = healthy
+}
+
+// SetGenPrompt 设置 /genprompt 的返回内容
+func (s *Server) SetGenPrompt(response map[string]any) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.genPrompt = response
+}
+
+// Requests 已收到的创建任务请求
+func (s *Server) Requests() []CreateTaskRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]CreateTaskRequest(nil), s.requests...)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req CreateTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	if len(req.Files) == 0 || req.PromptText == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "files and prompt_text are required"})
+		return
+	}
+
+	s.mutex.Lock()
+	outcome := Success()
+	if len(s.scripts) > 0 {
+		outcome = s.scripts[0]
+		s.scripts = s.scripts[1:]
+	}
+	s.seq++
+	id := fmt.Sprintf("fake-task-%d", s.seq)
+	t := &task{
+		request: req,
+		outcome: outcome,
+		status: taskStatus{
+			TaskID:    id,
+			Status:    "created",
+			CreatedAt: time.Now().Format(time.RFC3339),
+		},
+	}
+	s.tasks[id] = t
+	s.requests = append(s.requests, req)
+	s.mutex.Unlock()
+
+	go s.run(id)
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"task_id": id,
+		"status":  "created",
+		"message": "task created",
+	})
+}
+
+// run 按编排推进任务状态
+func (s *Server) run(id string) {
+	s.mutex.Lock()
+	t := s.tasks[id]
+	outcome := t.outcome
+	s.mutex.Unlock()
+
+	steps := outcome.Steps
+	if steps <= 0 {
+		steps = 1
+	}
+	for i := 1; i <= steps; i++ {
+		time.Sleep(outcome.StepDelay)
+		if !s.update(id, func(status *taskStatus) {
+			status.Status = "processing"
+			status.Progress = float64(i) / float64(steps+1)
+		}) {
+			return
+		}
+	}
+
+	time.Sleep(outcome.StepDelay)
+	s.update(id, func(status *taskStatus) {
+		status.CompletedAt = time.Now().Format(time.RFC3339)
+		status.Progress = 1
+		if outcome.Error != "" {
+			status.Status = "failed"
+			status.Error = outcome.Error
+			return
+		}
+		status.Status = "completed"
+		for _, f := range t.request.Files {
+			name := fmt.Sprintf("%s-%d.json", id, f.MaterialId)
+			s.files[name] = outcome.Triples
+			status.Result = append(status.Result, taskFile{
+				FileName:     name,
+				MaterialId:   f.MaterialId,
+				Status:       "success",
+				TriplesCount: len(outcome.Triples),
+				OutputFiles:  map[string]string{"jsonl": s.URL + "/files/" + name},
+			})
+		}
+	})
+}
+
+// update 修改任务状态，任务已结束(如被取消)时返回 false
+func (s *Server) update(id string, fn func(status *taskStatus)) bool {
+	s.mutex.Lock()
+	t := s.tasks[id]
+	if isTerminal(t.status.Status) {
+		s.mutex.Unlock()
+		return false
+	}
+	fn(&t.status)
+	t.version++
+	status := t.status
+	callbackURL := t.request.CallbackURL
+	version := t.version
+	s.mutex.Unlock()
+
+	if callbackURL != "" && s.WebhookSecret != "" {
+		go s.sendCallback(callbackURL, fmt.Sprintf("%s-%d", id, version), status)
+	}
+	return true
+}
+
+// sendCallback 推送签名回调，失败时重试
+func (s *Server) sendCallback(url string, eventID string, status taskStatus) {
+	payload := map[string]any{"event_id": eventID}
+	raw, _ := json.Marshal(status)
+	_ = json.Unmarshal(raw, &payload)
+	body, _ := json.Marshal(payload)
+
+	for attempt := 0; attempt < 5; attempt++ {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(s.WebhookSecret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+
+		req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				return
+			}
+		}
+		time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
+	}
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	t, ok := s.tasks[r.PathValue("id")]
+	var status taskStatus
+	if ok {
+		status = t.status
+	}
+	s.mutex.Unlock()
+
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "task not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) cancelTask(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mutex.Lock()
+	_, ok := s.tasks[id]
+	s.mutex.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "task not found"})
+		return
+	}
+
+	s.update(id, func(status *taskStatus) {
+		status.Status = "cancelled"
+		status.Message = "task cancelled"
+	})
+	writeJSON(w, http.StatusOK, map[string]any{"task_id": id, "status": "cancelled"})
+}
+
+// streamTask SSE 推送任务状态，直到任务结束或按编排断开
+func (s *Server) streamTask(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mutex.Lock()
+	t, ok := s.tasks[id]
+	drop := false
+	if ok && t.disconnects < t.outcome.Disconnects {
+		t.disconnects++
+		drop = true
+	}
+	s.mutex.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "task not found"})
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	lastVersion := -1
+	heartbeat := time.NewTicker(time.Second)
+	defer heartbeat.Stop()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, "data: {\"type\":\"heartbeat\"}\n\n")
+			if flusher != nil {
+				flusher.Flush()
+			}
+		case <-ticker.C:
+			s.mutex.Lock()
+			version := t.version
+			status := t.status
+			s.mutex.Unlock()
+			if version == lastVersion {
+				continue
+			}
+			lastVersion = version
+
+			data, _ := json.Marshal(status)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			if flusher != nil {
+				flusher.Flush()
+			}
+			if isTerminal(status.Status) {
+				return
+			}
+			// 推送第一条进度后断开, 模拟网络中断
+			if drop && status.Status == "processing" {
+				return
+			}
+		}
+	}
+}
+
+func (s *Server) genPromptHandler(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	schemaURL, _ := req["schema_url"].(string)
+	if schemaURL == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "schema_url is required"})
+		return
+	}
+
+	s.mutex.Lock()
+	response := s.genPrompt
+	s.mutex.Unlock()
+	if response == nil {
+		response = map[string]any{
+			"prompt":     "fake prompt for " + schemaURL,
+			"schema_url": schemaURL,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	healthy := s.healthy
+	s.mutex.Unlock()
+	if !healthy {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"status": "unhealthy"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "healthy"})
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	triples, ok := s.files[r.PathValue("name")]
+	s.mutex.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if triples == nil {
+		triples = []Triple{}
+	}
+	writeJSON(w, http.StatusOK, triples)
+}
+
+// serveMaterial 任意素材名都返回同一段文本
+func (s *Server) serveMaterial(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(s.MaterialText))
+}
+
+func isTerminal(status string) bool {
+	return status == "completed" || status == "failed" || status == "cancelled"
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// TripleOf 便于构造三元组: TripleOf("人物", "张三", "任职于", "公司", "某公司")
+func TripleOf(headType, head, relationship, tailType, tail string) Triple {
+	return Triple{
+		Head:         Node{Type: headType, Label: head},
+		Relationship: Node{Type: relationship, Label: relationship},
+		Tail:         Node{Type: tailType, Label: tail},
+		SourceText:   strings.Join([]string{head, relationship, tail}, ""),
+	}
+}
@@ -0,0 +1,217 @@
+package py_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 熔断器状态
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+// ClientOptions Python 客户端的超时、重试与熔断配置
+type ClientOptions struct {
+	Timeout          time.Duration // 单次请求超时
+	RetryCount       int           // 幂等请求的最大重试次数
+	RetryInterval    time.Duration // 首次重试间隔, 之后按指数增长
+	MaxRetryInterval time.Duration // 重试间隔上限
+	FailureThreshold int           // 连续失败多少次后熔断
+	OpenTimeout      time.Duration // 熔断后多久允许试探请求
+	SSEReconnect     int           // SSE 断开后的重连次数, 超过后改为轮询
+	PollInterval     time.Duration // 轮询任务状态的间隔
+	HealthInterval   time.Duration // 节点健康检查间隔, 0 表示不检查
+	CompletionMode   string        // 任务完成通知方式, sse 或 webhook
+	CallbackURL      string        // webhook 模式下 Python 服务回调的地址
+	WebhookSecret    string        // 回调签名密钥
+	WebhookTolerance time.Duration // 回调时间戳允许的误差
+	CallbackTimeout  time.Duration // webhook 模式下等待回调的最长时间, 超时后主动查询任务状态
+}
+
+// loadClientOptions 从 python 配置中读取客户端配置
+func loadClientOptions(ctx context.Context) ClientOptions {
+	return ClientOptions{
+		Timeout:          time.Duration(g.Cfg().MustGet(ctx, "python.timeout", 30).Int()) * time.Second,
+		RetryCount:       g.Cfg().MustGet(ctx, "python.retryCount", 3).Int(),
+		RetryInterval:    time.Duration(g.Cfg().MustGet(ctx, "python.retryInterval", 5).Int()) * time.Second,
+		MaxRetryInterval: time.Duration(g.Cfg().MustGet(ctx, "python.maxRetryInterval", 60).Int()) * time.Second,
+		FailureThreshold: g.Cfg().MustGet(ctx, "python.circuitBreaker.failureThreshold", 5).Int(),
+		OpenTimeout:      time.Duration(g.Cfg().MustGet(ctx, "python.circuitBreaker.openTimeout", 30).Int()) * time.Second,
+		SSEReconnect:     g.Cfg().MustGet(ctx, "python.sse.reconnectCount", 3).Int(),
+		PollInterval:     time.Duration(g.Cfg().MustGet(ctx, "python.sse.pollInterval", 10).Int()) * time.Second,
+		HealthInterval:   time.Duration(g.Cfg().MustGet(ctx, "python.healthCheckInterval", 30).Int()) * time.Second,
+		CompletionMode:   g.Cfg().MustGet(ctx, "python.completionMode", CompletionModeSSE).String(),
+		CallbackURL:      g.Cfg().MustGet(ctx, "python.webhook.callbackUrl").String(),
+		WebhookSecret:    g.Cfg().MustGet(ctx, "python.webhook.secret").String(),
+		WebhookTolerance: time.Duration(g.Cfg().MustGet(ctx, "python.webhook.tolerance", 300).Int()) * time.Second,
+		CallbackTimeout:  time.Duration(g.Cfg().MustGet(ctx, "python.webhook.timeout", 7200).Int()) * time.Second,
+	}
+}
+
+// statusError Python 服务返回的非预期状态码
+type statusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("Python服务返回错误: %d, %s", e.StatusCode, e.Body)
+}
+
+// isRetryable 网络错误与 5xx/429 可重试, 其余 4xx 直接返回
+func isRetryable(err error) bool {
+	if gerror.Code(err) == gcode.CodeServerBusy {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= http.StatusInternalServerError || se.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// circuitBreaker 简单的熔断器：连续失败达到阈值后熔断，超时后放行一次试探请求
+type circuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+	state       string
+	failures    int
+	openedAt    time.Time
+	mutex       sync.Mutex
+}
+
+func newCircuitBreaker(threshold int, openTimeout time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	return &circuitBreaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		state:       breakerClosed,
+	}
+}
+
+// allow 判断当前是否允许发起请求
+func (b *circuitBreaker) allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return gerror.NewCode(gcode.CodeServerBusy, "Python服务暂不可用，请稍后重试")
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// 试探请求进行中，其余请求直接失败
+		return gerror.NewCode(gcode.CodeServerBusy, "Python服务暂不可用，请稍后重试")
+	default:
+		return nil
+	}
+}
+
+// record 记录请求结果，仅服务端错误计入失败次数
+func (b *circuitBreaker) record(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err == nil || !isRetryable(err) {
+		if b.state != breakerClosed {
+			g.Log().Infof(context.Background(), "Python服务已恢复，熔断器关闭")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			g.Log().Warningf(context.Background(), "Python服务连续失败%d次，熔断器打开", b.failures)
+		}
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// available 节点是否可以接收新请求, 不改变熔断器状态
+func (b *circuitBreaker) available() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state == breakerClosed || (b.state == breakerOpen && time.Since(b.openedAt) >= b.openTimeout)
+}
+
+// release 试探请求被调用方取消时，恢复为熔断状态以便下次重新试探
+func (b *circuitBreaker) release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// call 在节点熔断器保护下执行一次带超时的请求
+func (c *PythonClient) call(ctx context.Context, worker *Worker, fn func(ctx context.Context, worker *Worker) error) error {
+	if err := worker.breaker.allow(); err != nil {
+		return err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, c.options.Timeout)
+	defer cancel()
+
+	err := fn(callCtx, worker)
+	// 调用方主动取消不计入熔断
+	if ctx.Err() != nil {
+		worker.breaker.release()
+		return err
+	}
+	worker.breaker.record(err)
+	return err
+}
+
+// callWithRetry 幂等请求：失败后按指数退避重试，每次重试重新选择节点
+func (c *PythonClient) callWithRetry(ctx context.Context, name string, selector func() (*Worker, error), fn func(ctx context.Context, worker *Worker) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var worker *Worker
+		if worker, err = selector(); err == nil {
+			err = c.call(ctx, worker, fn)
+		}
+		if err == nil || !isRetryable(err) || attempt >= c.options.RetryCount {
+			return err
+		}
+
+		wait := c.backoff(attempt)
+		g.Log().Warningf(ctx, "%s失败, %v后进行第%d次重试: %v", name, wait, attempt+1, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff 计算第 attempt 次重试的等待时间，附加随机抖动避免同时重试
+func (c *PythonClient) backoff(attempt int) time.Duration {
+	wait := c.options.RetryInterval << attempt
+	if wait <= 0 || wait > c.options.MaxRetryInterval {
+		wait = c.options.MaxRetryInterval
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
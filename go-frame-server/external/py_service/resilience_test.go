@@ -0,0 +1,75 @@
+package py_service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		serverErr = &statusError{StatusCode: http.StatusBadGateway}
+		clientErr = &statusError{StatusCode: http.StatusBadRequest}
+		netErr    = errors.New("connection refused")
+	)
+	// 每一步的操作: fail-服务端错误, net-网络错误, client-4xx 错误, ok-成功, expire-熔断超时, release-试探请求被取消
+	// allow 为执行操作后 allow 的期望结果, 执行 allow 本身会使超时的熔断器进入半开状态
+	cases := []struct {
+		name  string
+		steps string
+		state string // 最后一步后的期望状态
+		allow bool
+	}{
+		{"未达到阈值", "fail fail", breakerClosed, true},
+		{"连续失败达到阈值后熔断", "fail net fail", breakerOpen, false},
+		{"成功后重新计数", "fail fail ok fail fail", breakerClosed, true},
+		{"4xx 不计入失败", "fail fail client client fail", breakerClosed, true},
+		{"熔断超时后放行一次试探请求", "fail fail fail expire", breakerHalfOpen, true},
+		{"试探成功后关闭", "fail fail fail expire allow ok", breakerClosed, true},
+		{"试探失败后重新熔断", "fail fail fail expire allow fail", breakerOpen, false},
+		{"试探进行中拒绝其他请求", "fail fail fail expire allow", breakerHalfOpen, false},
+		{"试探被取消后下一个请求重新试探", "fail fail fail expire allow release", breakerHalfOpen, true},
+	}
+	for _, c := range cases {
+		b := newCircuitBreaker(3, time.Minute)
+		for _, step := range strings.Fields(c.steps) {
+			switch step {
+			case "fail":
+				b.record(serverErr)
+			case "net":
+				b.record(netErr)
+			case "client":
+				b.record(clientErr)
+			case "ok":
+				b.record(nil)
+			case "expire":
+				b.openedAt = time.Now().Add(-time.Minute)
+			case "allow":
+				_ = b.allow()
+			case "release":
+				b.release()
+			}
+		}
+		allowed := b.allow() == nil
+		if b.state != c.state || allowed != c.allow {
+			t.Errorf("%s: 状态 %s, 放行 %v; 期望 %s, %v", c.name, b.state, allowed, c.state, c.allow)
+		}
+	}
+}
+
+func TestCircuitBreakerAvailable(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute)
+	if !b.available() {
+		t.Errorf("关闭状态应可用")
+	}
+	b.record(&statusError{StatusCode: http.StatusServiceUnavailable})
+	if b.available() {
+		t.Errorf("熔断后应不可用")
+	}
+	b.openedAt = time.Now().Add(-time.Minute)
+	if !b.available() || b.state != breakerOpen {
+		t.Errorf("熔断超时后应可用, 且不改变状态: %s", b.state)
+	}
+}
@@ -0,0 +1,200 @@
+package py_service
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// ExtractTaskInput 创建抽取任务的参数
+type ExtractTaskInput struct {
+	UserId         int // 操作用户, 须为项目所有者
+	PipelineId     int
+	ProjectId      int
+	MaterialIdList []int
+	Config         ExtractConfig // 抽取配置, 随任务保存, 重试时沿用
+	ParentTaskId   int           // 重试任务对应的原任务, 完成后与项目已有的三元组合并
+	ScheduleId     int           // 创建任务的定时运行计划, 完成后与项目已有的三元组合并
+}
+
+// CreateExtractTask 创建抽取任务并提交给 GetExtractor 配置的抽取后端
+// 任务结束后由抽取后端的 WatchTask 更新任务、素材与项目的抽取结果
+func CreateExtractTask(ctx context.Context, in *ExtractTaskInput) (*entity.Tasks, error) {
+	if len(in.MaterialIdList) == 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "请选择素材")
+	}
+	if in.Config.ModelId == 0 || in.Config.Prompt == "" {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "请选择模型并填写提示词")
+	}
+
+	var project *entity.Projects
+	if err := dao.Projects.Ctx(ctx).Where("id", in.ProjectId).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "项目不存在")
+	}
+	if project.UserId != in.UserId {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "无权操作该项目")
+	}
+	count, err := dao.Pipelines.Ctx(ctx).Where("id", in.PipelineId).Where("project_id", in.ProjectId).Count()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "管道不存在")
+	}
+
+	var model *entity.Models
+	if err = dao.Models.Ctx(ctx).Where("id", in.Config.ModelId).Scan(&model); err != nil {
+		return nil, err
+	}
+	if model == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "模型不存在")
+	}
+
+	var materials []*entity.Materials
+	err = dao.Materials.Ctx(ctx).
+		WhereIn("id", in.MaterialIdList).
+		Where("project_id", in.ProjectId).
+		OrderAsc("id").
+		Scan(&materials)
+	if err != nil {
+		return nil, err
+	}
+	if len(materials) != len(in.MaterialIdList) {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "素材不存在或不属于该项目")
+	}
+
+	now := gtime.Now()
+	task := &entity.Tasks{
+		Type:           consts.TaskTypeExtract,
+		PipelineId:     in.PipelineId,
+		ProjectId:      in.ProjectId,
+		MaterialIdList: in.MaterialIdList,
+		Status:         consts.TaskStatusPending,
+		ExtractConfig:  gjson.New(in.Config),
+		ParentTaskId:   in.ParentTaskId,
+		ScheduleId:     in.ScheduleId,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	// 合并三元组所需的字段须在创建时写入, 任务可能在后续更新之前就已结束
+	data := g.Map{
+		"type":             task.Type,
+		"pipeline_id":      task.PipelineId,
+		"project_id":       task.ProjectId,
+		"material_id_list": task.MaterialIdList,
+		"status":           task.Status,
+		"extract_config":   task.ExtractConfig,
+		"created_at":       now,
+		"updated_at":       now,
+	}
+	if in.ParentTaskId > 0 {
+		data["parent_task_id"] = in.ParentTaskId
+	}
+	if in.ScheduleId > 0 {
+		data["schedule_id"] = in.ScheduleId
+	}
+	// 引用提示词库版本时与任务一同记录使用情况, 任务结束后同步状态和三元组数量
+	err = dao.Tasks.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		id, err := dao.Tasks.Ctx(ctx).TX(tx).Data(data).InsertAndGetId()
+		if err != nil {
+			return gerror.Wrap(err, "创建抽取任务失败")
+		}
+		task.Id = int(id)
+		if in.Config.PromptVersionId == nil {
+			return nil
+		}
+		_, err = dao.PromptUsages.Ctx(ctx).TX(tx).Data(g.Map{
+			"prompt_version_id": *in.Config.PromptVersionId,
+			"user_id":           in.UserId,
+			"project_id":        in.ProjectId,
+			"task_id":           task.Id,
+			"status":            task.Status,
+			"created_at":        now,
+			"updated_at":        now,
+		}).Insert()
+		return gerror.Wrap(err, "记录提示词使用失败")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 优先使用 OCR 后的文本
+	uploadLogic := upload.NewUpload()
+	files := make([]File, 0, len(materials))
+	for _, material := range materials {
+		fileUrl := material.TextUrl
+		if fileUrl == "" {
+			fileUrl = material.Url
+		}
+		files = append(files, File{
+			MaterialId: material.Id,
+			URL:        uploadLogic.GenerateFileUrl(ctx, fileUrl),
+		})
+	}
+
+	extractor := GetExtractor()
+	created, err := extractor.CreateTask(ctx, NewCreateTaskRequest(ctx, model, in.Config.Prompt, files))
+	if err != nil {
+		failTask(ctx, task, err)
+		return task, nil
+	}
+
+	task.Status = consts.TaskStatusProcessing
+	task.PyTaskId = created.TaskID
+	task.StartTime = gtime.Now()
+	_, err = dao.Tasks.Ctx(ctx).Where("id", task.Id).Where("status", consts.TaskStatusPending).Update(g.Map{
+		"status":     task.Status,
+		"py_task_id": task.PyTaskId,
+		"start_time": task.StartTime,
+		"updated_at": task.StartTime,
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "更新抽取任务状态失败: %v, 任务ID: %d", err, task.Id)
+	}
+	updatePromptUsage(ctx, task.Id, task.Status)
+	if err = extractor.WatchTask(ctx, created.TaskID, task.Id); err != nil {
+		failTask(ctx, task, err)
+	}
+	return task, nil
+}
+
+// failTask 提交或跟踪失败时将任务标记为失败, 任务记录保留以便查看原因和重试
+func failTask(ctx context.Context, task *entity.Tasks, cause error) {
+	g.Log().Errorf(ctx, "提交抽取任务失败: %v, 任务ID: %d", cause, task.Id)
+	task.Status = consts.TaskStatusFailed
+	task.ErrorMessage = cause.Error()
+	_, err := dao.Tasks.Ctx(ctx).Where("id", task.Id).Update(g.Map{
+		"status":        task.Status,
+		"error_message": task.ErrorMessage,
+		"finish_time":   gtime.Now(),
+		"updated_at":    gtime.Now(),
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "更新任务状态失败: %v", err)
+	}
+	updatePromptUsage(ctx, task.Id, task.Status)
+}
+
+// updatePromptUsage 同步任务对应的提示词使用记录的状态, 未引用提示词库版本的任务没有使用记录
+func updatePromptUsage(ctx context.Context, taskId int, status string) {
+	_, err := dao.PromptUsages.Ctx(ctx).Where("task_id", taskId).Update(g.Map{
+		"status":     status,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "更新提示词使用记录失败: %v, 任务ID: %d", err, taskId)
+	}
+}
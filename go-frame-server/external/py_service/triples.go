@@ -0,0 +1,228 @@
+package py_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/google/uuid"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/storage"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/model/entity"
+	"kgplatform-backend/internal/neo4j"
+	"kgplatform-backend/internal/utils"
+)
+
+// ParseTriples 解析 Python 服务输出的三元组文本，转换为 SimpleTriple 并添加溯源信息
+func ParseTriples(text string, material *entity.Materials) ([]neo4j.SimpleTriple, error) {
+	var tripleDataList []map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &tripleDataList); err != nil {
+		return nil, err
+	}
+
+	tripleList := []neo4j.SimpleTriple{}
+	for _, tripleData := range tripleDataList {
+		triple := neo4j.SimpleTriple{}
+
+		// 解析标准字段（head, relationship, tail）
+		if headData, ok := tripleData["head"].(map[string]interface{}); ok {
+			triple.Head.Type = getStringValue(headData, "type")
+			triple.Head.Label = getStringValue(headData, "label")
+		}
+		if relData, ok := tripleData["relationship"].(map[string]interface{}); ok {
+			triple.Relationship.Type = getStringValue(relData, "type")
+			triple.Relationship.Label = getStringValue(relData, "label")
+		}
+		if tailData, ok := tripleData["tail"].(map[string]interface{}); ok {
+			triple.Tail.Type = getStringValue(tailData, "type")
+			triple.Tail.Label = getStringValue(tailData, "label")
+		}
+
+		// 添加溯源信息（如果存在_chunk_index和_source_text）
+		if chunkIndexVal, ok := tripleData["_chunk_index"].(float64); ok && material != nil {
+			sourceText := ""
+			if textVal, ok := tripleData["_source_text"].(string); ok {
+				sourceText = textVal
+			}
+
+			// 获取素材名称（简化处理，使用URL的文件名部分）
+			materialName := material.Url
+			if lastSlash := strings.LastIndex(material.Url, "/"); lastSlash >= 0 {
+				materialName = material.Url[lastSlash+1:]
+			}
+
+			triple.SourceInfo = &neo4j.TripleSourceInfo{
+				MaterialId:   material.Id,
+				MaterialName: materialName,
+				ChunkIndex:   int(chunkIndexVal),
+				SourceText:   sourceText,
+			}
+		}
+
+		tripleList = append(tripleList, triple)
+	}
+
+	return tripleList, nil
+}
+
+// TripleTypeKey 三元组类型标识, 形式为 head.type-relationship.type-tail.type
+func TripleTypeKey(triple neo4j.SimpleTriple) string {
+	return fmt.Sprintf("%s-%s-%s", triple.Head.Type, triple.Relationship.Type, triple.Tail.Type)
+}
+
+// MergeProjectTriples 将新抽取的三元组合并到项目已有的三元组中, 同一素材的旧三元组会被替换
+// 没有溯源信息的旧三元组无法判断来自哪个素材, 与新三元组相同时去重
+func MergeProjectTriples(ctx context.Context, project *entity.Projects, tripleList []neo4j.SimpleTriple) ([]neo4j.SimpleTriple, error) {
+	if project.TripleUrl == "" {
+		return tripleList, nil
+	}
+	text, err := utils.DownloadTextFromURL(ctx, upload.NewUpload().GenerateFileUrl(ctx, project.TripleUrl))
+	if err != nil {
+		return nil, gerror.Newf("读取项目三元组失败: %v", err)
+	}
+	var existing []neo4j.SimpleTriple
+	if err = json.Unmarshal([]byte(text), &existing); err != nil {
+		return nil, gerror.Newf("解析项目三元组失败: %v", err)
+	}
+
+	return mergeTriples(existing, tripleList), nil
+}
+
+// mergeTriples 用新三元组替换同一素材的旧三元组, 并去掉与新三元组相同的无溯源信息的旧三元组
+func mergeTriples(existing []neo4j.SimpleTriple, tripleList []neo4j.SimpleTriple) []neo4j.SimpleTriple {
+	replaced := make(map[int]bool)
+	extracted := make(map[string]bool, len(tripleList))
+	for _, triple := range tripleList {
+		if triple.SourceInfo != nil {
+			replaced[triple.SourceInfo.MaterialId] = true
+		}
+		extracted[tripleKey(triple)] = true
+	}
+	merged := make([]neo4j.SimpleTriple, 0, len(existing)+len(tripleList))
+	for _, triple := range existing {
+		if triple.SourceInfo != nil && replaced[triple.SourceInfo.MaterialId] {
+			continue
+		}
+		if triple.SourceInfo == nil && extracted[tripleKey(triple)] {
+			continue
+		}
+		merged = append(merged, triple)
+	}
+	return append(merged, tripleList...)
+}
+
+// tripleKey 三元组的去重标识, 头实体、关系和尾实体的类型与名称都相同的三元组视为同一个
+func tripleKey(triple neo4j.SimpleTriple) string {
+	return strings.Join([]string{
+		triple.Head.Type, triple.Head.Label,
+		triple.Relationship.Type, triple.Relationship.Label,
+		triple.Tail.Type, triple.Tail.Label,
+	}, "\x00")
+}
+
+// SaveProjectTriples 保存项目的三元组文件，并按照三元组type分类存储，更新 projects 表并释放被替换的文件
+func SaveProjectTriples(ctx context.Context, tx gdb.TX, projectId int, userId int, tripleList []neo4j.SimpleTriple) error {
+	store := storage.New()
+	var previous entity.Projects
+	err := dao.Projects.Ctx(ctx).TX(tx).Fields("triple_url", "triple_type_url").Where("id", projectId).Scan(&previous)
+	if err != nil {
+		return err
+	}
+
+	uuidStr := uuid.New().String()
+	timestamp := time.Now().Format("20060102150405")
+	uploadFileName := utils.RemoveExt("triples_project_" + strconv.Itoa(projectId) + "_" + timestamp + "_" + uuidStr[:8])
+
+	// 序列化包含SourceInfo的三元组列表
+	uploadContent, _ := json.Marshal(tripleList)
+	saveDataOutput, err := store.SaveData(ctx, &upload.SaveDataInput{
+		FileName: uploadFileName,
+		Content:  string(uploadContent),
+		DataType: "json",
+		UserId:   userId,
+	}, consts.StorageSourceArtifact)
+	if err != nil {
+		g.Log().Errorf(ctx, "保存数据失败: %v, 文件名: %s", err, uploadFileName)
+		return err
+	}
+
+	// 按照三元组type分类存储
+	var tripeTypeMap = make(map[string][]neo4j.SimpleTriple)
+	for _, triple := range tripleList {
+		tripleType := TripleTypeKey(triple)
+		tripeTypeMap[tripleType] = append(tripeTypeMap[tripleType], triple)
+	}
+	var tripleTypeFilenameMap = make(map[string]string)
+	for tripleType, triples := range tripeTypeMap {
+		uuidStr = uuid.New().String()
+		timestamp = time.Now().Format("20060102150405")
+		uploadFileName = utils.RemoveExt("triples_project_type" + tripleType + "_" + timestamp + "_" + uuidStr[:8])
+		uploadContent, _ = json.Marshal(triples)
+		saveMapOutput, err := store.SaveData(ctx, &upload.SaveDataInput{
+			FileName: uploadFileName,
+			Content:  string(uploadContent),
+			DataType: "json",
+			UserId:   userId,
+		}, consts.StorageSourceArtifact)
+		if err != nil {
+			g.Log().Errorf(ctx, "保存数据失败: %v, 文件名: %s", err, uploadFileName)
+			continue
+		}
+		tripleTypeFilenameMap[tripleType] = saveMapOutput.FileName
+	}
+
+	// 修改projects表
+	_, err = dao.Projects.Ctx(ctx).TX(tx).Where("id", projectId).Update(g.Map{
+		"triple_url":      saveDataOutput.FileName,
+		"triple_type_url": tripleTypeFilenameMap,
+	})
+	if err != nil {
+		return gerror.Newf("更新项目三元组失败: %v", err)
+	}
+	return releaseProjectTriples(ctx, tx, &previous)
+}
+
+// releaseProjectTriples 释放被替换的项目三元组文件, 事务提交后由清理任务从云存储中删除
+func releaseProjectTriples(ctx context.Context, tx gdb.TX, project *entity.Projects) error {
+	names := []string{project.TripleUrl}
+	if strings.TrimSpace(project.TripleTypeUrl) != "" {
+		for _, name := range gjson.New(project.TripleTypeUrl).Map() {
+			names = append(names, g.NewVar(name).String())
+		}
+	}
+	for _, name := range names {
+		if err := storage.Release(ctx, tx, name); err != nil {
+			return gerror.Wrapf(err, "释放项目三元组文件失败: %s", name)
+		}
+	}
+	return nil
+}
+
+// GetModelCostMultiplier 获取AI模型的成本系数，未配置时返回 1.0
+func GetModelCostMultiplier(ctx context.Context, modelName string) float64 {
+	modelName = strings.ToLower(modelName)
+	for _, group := range []string{"chinese", "english"} {
+		// ai_models 下每个场景为模型列表，按 name 匹配
+		for _, item := range g.Cfg().MustGet(ctx, "ai_models."+group).Maps() {
+			if strings.ToLower(g.NewVar(item["name"]).String()) != modelName {
+				continue
+			}
+			if multiplier := g.NewVar(item["cost_multiplier"]).Float64(); multiplier > 0 {
+				return multiplier
+			}
+		}
+	}
+
+	g.Log().Warningf(ctx, "未找到模型 %s 的成本系数配置，使用默认值: %f", modelName, 1.0)
+	return 1.0
+}
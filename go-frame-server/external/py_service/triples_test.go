@@ -0,0 +1,68 @@
+package py_service
+
+import (
+	"fmt"
+	"testing"
+
+	"kgplatform-backend/internal/neo4j"
+)
+
+// testTriple 构造三元组, materialId 为 0 时没有溯源信息
+func testTriple(head string, relationship string, tail string, materialId int) neo4j.SimpleTriple {
+	triple := neo4j.SimpleTriple{
+		Head:         neo4j.Node{Type: "人物", Label: head},
+		Relationship: neo4j.Node{Type: relationship, Label: relationship},
+		Tail:         neo4j.Node{Type: "机构", Label: tail},
+	}
+	if materialId > 0 {
+		triple.SourceInfo = &neo4j.TripleSourceInfo{MaterialId: materialId}
+	}
+	return triple
+}
+
+func TestMergeTriples(t *testing.T) {
+	cases := []struct {
+		name     string
+		existing []neo4j.SimpleTriple
+		extract  []neo4j.SimpleTriple
+		want     []string
+	}{
+		{
+			name:     "替换同一素材的旧三元组",
+			existing: []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 1), testTriple("李四", "任职", "乙公司", 2)},
+			extract:  []neo4j.SimpleTriple{testTriple("张三", "任职", "丙公司", 1)},
+			want:     []string{"李四-乙公司-2", "张三-丙公司-1"},
+		},
+		{
+			name:     "去掉与新三元组相同的无溯源信息的旧三元组",
+			existing: []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 0), testTriple("李四", "任职", "乙公司", 0)},
+			extract:  []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 1)},
+			want:     []string{"李四-乙公司-0", "张三-甲公司-1"},
+		},
+		{
+			name:     "其他素材的相同三元组保留",
+			existing: []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 2)},
+			extract:  []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 1)},
+			want:     []string{"张三-甲公司-2", "张三-甲公司-1"},
+		},
+		{
+			name:     "重复抽取无溯源信息的三元组不累积",
+			existing: []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 0)},
+			extract:  []neo4j.SimpleTriple{testTriple("张三", "任职", "甲公司", 0)},
+			want:     []string{"张三-甲公司-0"},
+		},
+	}
+	for _, c := range cases {
+		var got []string
+		for _, triple := range mergeTriples(c.existing, c.extract) {
+			materialId := 0
+			if triple.SourceInfo != nil {
+				materialId = triple.SourceInfo.MaterialId
+			}
+			got = append(got, fmt.Sprintf("%s-%s-%d", triple.Head.Label, triple.Tail.Label, materialId))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: 合并结果为 %v, 期望 %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package py_service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+)
+
+// 任务完成通知方式
+const (
+	CompletionModeSSE     = "sse"     // Go 侧为每个任务保持 SSE 长连接
+	CompletionModeWebhook = "webhook" // Python 服务回调 Go 侧接口
+)
+
+// PythonCallbackEvent Python 服务推送的任务状态回调
+type PythonCallbackEvent struct {
+	EventID string `json:"event_id"`
+	PythonTaskStatus
+}
+
+// CallbackInput 回调请求
+type CallbackInput struct {
+	Body      []byte
+	Timestamp string // X-Timestamp 请求头, Unix 秒
+	Signature string // X-Signature 请求头, 格式为 sha256=<hex>
+}
+
+// CompletionMode 当前部署使用的任务完成通知方式
+func (c *PythonClient) CompletionMode() string {
+	return c.options.CompletionMode
+}
+
+// VerifyCallbackSignature 校验回调签名：HMAC-SHA256(secret, timestamp + "." + body)
+func VerifyCallbackSignature(secret string, tolerance time.Duration, timestamp string, body []byte, signature string) error {
+	if secret == "" {
+		return gerror.NewCode(gcode.CodeMissingConfiguration, "未配置回调签名密钥")
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return gerror.NewCode(gcode.CodeNotAuthorized, "回调时间戳无效")
+	}
+	if tolerance > 0 && math.Abs(float64(time.Now().Unix()-ts)) > tolerance.Seconds() {
+		return gerror.NewCode(gcode.CodeNotAuthorized, "回调已过期")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256="))) {
+		return gerror.NewCode(gcode.CodeNotAuthorized, "回调签名错误")
+	}
+	return nil
+}
+
+// VerifyCallback 回调路由的签名校验中间件, 签名错误的请求直接返回 401, 不会进入回调处理
+func VerifyCallback(r *ghttp.Request) {
+	options := GetPythonClient().options
+	err := VerifyCallbackSignature(
+		options.WebhookSecret, options.WebhookTolerance,
+		r.Header.Get("X-Timestamp"), r.GetBody(), r.Header.Get("X-Signature"),
+	)
+	if err != nil {
+		g.Log().Warningf(r.Context(), "Python任务回调签名校验失败: %v, IP: %s", err, r.GetClientIp())
+		r.Response.WriteHeader(http.StatusUnauthorized)
+		r.Response.WriteJson(g.Map{"result": "fail", "message": err.Error()})
+		return
+	}
+	r.Middleware.Next()
+}
+
+// HandleCallback 处理 Python 服务的任务状态回调
+// 重复的 event_id 直接忽略; 处理失败时删除去重记录并返回错误, 由 Python 服务重试
+func (c *PythonClient) HandleCallback(ctx context.Context, in *CallbackInput) error {
+	err := VerifyCallbackSignature(c.options.WebhookSecret, c.options.WebhookTolerance, in.Timestamp, in.Body, in.Signature)
+	if err != nil {
+		return err
+	}
+
+	var event PythonCallbackEvent
+	if err = json.Unmarshal(in.Body, &event); err != nil {
+		return gerror.NewCode(gcode.CodeInvalidParameter, "回调数据格式错误")
+	}
+	if event.EventID == "" || event.TaskID == "" {
+		return gerror.NewCode(gcode.CodeInvalidParameter, "回调缺少event_id或task_id")
+	}
+
+	goTaskIdVar, err := dao.Tasks.Ctx(ctx).Where("py_task_id", event.TaskID).Value("id")
+	if err != nil {
+		return gerror.Newf("获取回调任务失败: %v", err)
+	}
+	if goTaskIdVar.IsEmpty() {
+		return gerror.NewCodef(gcode.CodeNotFound, "未找到Python任务对应的Go任务: %s", event.TaskID)
+	}
+	goTaskID := goTaskIdVar.Int()
+
+	// 按 event_id 去重
+	result, err := dao.PythonCallbackEvents.Ctx(ctx).Data(g.Map{
+		"event_id":   event.EventID,
+		"py_task_id": event.TaskID,
+		"task_id":    goTaskID,
+		"status":     event.Status,
+		"created_at": gtime.Now(),
+	}).InsertIgnore()
+	if err != nil {
+		return gerror.Newf("记录回调事件失败: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		g.Log().Infof(ctx, "重复的回调事件, 已忽略: %s", event.EventID)
+		return nil
+	}
+
+	// 与 SSE 一致, 只处理结束状态
+	if !isTerminalStatus(event.Status) {
+		return nil
+	}
+
+	if err = processTaskStatus(goTaskID, &event.PythonTaskStatus); err != nil {
+		_, _ = dao.PythonCallbackEvents.Ctx(ctx).Where("event_id", event.EventID).Delete()
+		return err
+	}
+	c.pool.release(event.TaskID)
+	g.Log().Infof(ctx, "Python任务回调处理完成: %s, 状态: %s", event.TaskID, event.Status)
+	return nil
+}
@@ -0,0 +1,123 @@
+package py_service_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gcfg"
+
+	"kgplatform-backend/external/py_service"
+)
+
+const webhookSecret = "webhook-test-secret"
+
+// signCallback 按 Python 服务的方式签名回调
+func signCallback(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyCallbackSignature(t *testing.T) {
+	body := []byte(`{"event_id":"e1","task_id":"t1","status":"completed"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	expired := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	cases := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   bool
+	}{
+		{"valid", webhookSecret, now, signCallback(webhookSecret, now, body), body, false},
+		{"valid without prefix", webhookSecret, now, strings.TrimPrefix(signCallback(webhookSecret, now, body), "sha256="), body, false},
+		{"wrong secret", webhookSecret, now, signCallback("other-secret", now, body), body, true},
+		{"tampered body", webhookSecret, now, signCallback(webhookSecret, now, body), []byte(`{"event_id":"e1","task_id":"t1","status":"failed"}`), true},
+		{"expired", webhookSecret, expired, signCallback(webhookSecret, expired, body), body, true},
+		{"invalid timestamp", webhookSecret, "abc", signCallback(webhookSecret, "abc", body), body, true},
+		{"missing signature", webhookSecret, now, "", body, true},
+		{"missing secret", "", now, signCallback("", now, body), body, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := py_service.VerifyCallbackSignature(tc.secret, 5*time.Minute, tc.timestamp, tc.body, tc.signature)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("校验结果为%v, 期望出错: %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyCallbackMiddleware(t *testing.T) {
+	adapter, err := gcfg.NewAdapterContent(fmt.Sprintf(
+		`{"python":{"healthCheckInterval":0,"webhook":{"secret":%q,"tolerance":300}}}`, webhookSecret,
+	))
+	if err != nil {
+		t.Fatalf("创建配置失败: %v", err)
+	}
+	g.Cfg().SetAdapter(adapter)
+
+	var reached atomic.Int32
+	s := g.Server("py_service_webhook")
+	s.SetPort(0)
+	s.SetDumpRouterMap(false)
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware(py_service.VerifyCallback)
+		group.POST("/python/callback", func(r *ghttp.Request) {
+			reached.Add(1)
+			r.Response.WriteJson(g.Map{"result": "success"})
+		})
+	})
+	if err = s.Start(); err != nil {
+		t.Fatalf("启动服务失败: %v", err)
+	}
+	defer s.Shutdown()
+
+	url := fmt.Sprintf("http://127.0.0.1:%d/python/callback", s.GetListenedPort())
+	body := []byte(`{"event_id":"e1","task_id":"t1","status":"completed"}`)
+	post := func(timestamp string, signature string) int {
+		req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if timestamp != "" {
+			req.Header.Set("X-Timestamp", timestamp)
+			req.Header.Set("X-Signature", signature)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("请求回调接口失败: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if code := post("", ""); code != http.StatusUnauthorized {
+		t.Errorf("未签名的回调返回%d, 期望401", code)
+	}
+	if code := post(now, signCallback("other-secret", now, body)); code != http.StatusUnauthorized {
+		t.Errorf("签名错误的回调返回%d, 期望401", code)
+	}
+	if reached.Load() != 0 {
+		t.Fatalf("签名校验失败的回调不应进入回调处理")
+	}
+	if code := post(now, signCallback(webhookSecret, now, body)); code != http.StatusOK {
+		t.Errorf("签名正确的回调返回%d, 期望200", code)
+	}
+	if reached.Load() != 1 {
+		t.Errorf("签名正确的回调应进入回调处理")
+	}
+}
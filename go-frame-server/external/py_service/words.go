@@ -0,0 +1,111 @@
+package py_service
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/usage"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// WordsUsage 一次抽取的字数用量
+type WordsUsage struct {
+	UserId         int
+	Words          int     // 抽取结果的原始字数
+	CostMultiplier float64 // 抽取所用模型的成本系数
+	TaskId         int
+	ProjectId      int
+	Model          string
+	Source         string
+}
+
+// CheckWordsQuota 锁定用户的订阅并校验再使用 words 字(已计入成本系数)后是否超出套餐的字数配额
+func CheckWordsQuota(ctx context.Context, tx gdb.TX, userId int, words int) error {
+	var subscription entity.UserSubscriptions
+	err := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", userId).LockUpdate().Scan(&subscription)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取用户订阅记录失败: %v, 用户ID: %d", err, userId)
+	}
+	return checkWordsQuota(ctx, &subscription, words)
+}
+
+// checkWordsQuota 校验订阅的字数用量加上 words 后是否超出套餐配额, 免费版和付费版分别使用错误码 1002 和 1003
+func checkWordsQuota(ctx context.Context, subscription *entity.UserSubscriptions, words int) error {
+	// 定义套餐字数配额
+	free_quota := g.Cfg().MustGet(ctx, "plans.free.words_quota").Int()
+	professional_quota := g.Cfg().MustGet(ctx, "plans.professional.words_quota").Int()
+	team_quota := g.Cfg().MustGet(ctx, "plans.team.words_quota").Int()
+	plansQuota := map[string]int{
+		"free":         free_quota,
+		"professional": professional_quota,
+		"team":         team_quota,
+	}
+
+	quota := plansQuota[subscription.UserPlan]
+	currentUsage := int(subscription.WordsUsed) + words
+	if currentUsage <= quota {
+		return nil
+	}
+	// 免费版不允许超额使用
+	if subscription.UserPlan == "free" {
+		// 使用独特的错误码1002表示免费版用户字数超出配额
+		return gerror.NewCodef(
+			gcode.New(1002, "FreePlanWordQuotaExceeded", "免费版字数超出配额"),
+			"免费版用户字数已超出配额，当前用量: %d, 配额: %d", currentUsage, quota,
+		)
+	}
+	// 使用独特的错误码1003表示付费版用户字数超出配额
+	//TODO: 添加字数超额提醒（目前已使用特殊code来表示，0表示成功）
+	return gerror.NewCodef(
+		gcode.New(1003, "PaidPlanWordQuotaExceeded", "付费版字数超出配额"),
+		"用户字数已超出配额，请注意用量，当前用量: %d, 配额: %d", currentUsage, quota,
+	)
+}
+
+// RecordWords 累加用户的文字用量并记录字数用量事件, 用于用量历史和按项目、模型、成员的用量分析
+// 在保存点内执行, 失败时只回滚用量并记录日志, 不影响调用方事务内已保存的抽取结果
+func RecordWords(ctx context.Context, tx gdb.TX, in *WordsUsage) {
+	if in.Words <= 0 || in.UserId <= 0 {
+		return
+	}
+	err := tx.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		result, err := dao.UserSubscriptions.Ctx(ctx).TX(tx).
+			Where("user_id", in.UserId).
+			Update(g.Map{
+				"words_used": &gdb.Counter{Field: "words_used", Value: float64(in.Words)},
+				"updated_at": gtime.Now(),
+			})
+		if err != nil {
+			return gerror.Wrap(err, "更新用户文字用量失败")
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			g.Log().Infof(ctx, "用户文字用量已更新: 用户ID=%d, 新增字数=%d", in.UserId, in.Words)
+		}
+
+		teamId, err := usage.TeamOf(ctx, tx, int64(in.UserId))
+		if err != nil {
+			return err
+		}
+		return usage.Record(ctx, tx, &usage.Event{
+			UserId:         int64(in.UserId),
+			TeamId:         teamId,
+			ResourceType:   billing.ResourceWords,
+			Amount:         float64(in.Words),
+			CostMultiplier: in.CostMultiplier,
+			TaskId:         in.TaskId,
+			ProjectId:      in.ProjectId,
+			Model:          in.Model,
+			Source:         in.Source,
+		})
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "记录字数用量失败: %v, 用户ID: %d, 任务ID: %d", err, in.UserId, in.TaskId)
+	}
+}
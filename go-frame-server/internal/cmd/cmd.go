@@ -2,18 +2,29 @@ package cmd
 
 import (
 	"context"
+	"kgplatform-backend/external/py_service"
+	"kgplatform-backend/internal/consts"
 	"kgplatform-backend/internal/controller/account"
 	"kgplatform-backend/internal/controller/alipay"
+	"kgplatform-backend/internal/controller/billing"
 	"kgplatform-backend/internal/controller/chat"
 	"kgplatform-backend/internal/controller/comments"
+	"kgplatform-backend/internal/controller/coupons"
+	"kgplatform-backend/internal/controller/cron_jobs"
 	"kgplatform-backend/internal/controller/email"
+	"kgplatform-backend/internal/controller/experiments"
 	"kgplatform-backend/internal/controller/graphs"
 	"kgplatform-backend/internal/controller/likes"
 	"kgplatform-backend/internal/controller/materials"
 	"kgplatform-backend/internal/controller/models"
+	"kgplatform-backend/internal/controller/payment"
 	"kgplatform-backend/internal/controller/pipelines"
 	"kgplatform-backend/internal/controller/professional_dictionary"
 	"kgplatform-backend/internal/controller/projects"
+	"kgplatform-backend/internal/controller/prompts"
+	"kgplatform-backend/internal/controller/python"
+	"kgplatform-backend/internal/controller/revenue"
+	"kgplatform-backend/internal/controller/schedules"
 	"kgplatform-backend/internal/controller/sms"
 	"kgplatform-backend/internal/controller/sse"
 	"kgplatform-backend/internal/controller/support_domains"
@@ -21,13 +32,16 @@ import (
 	"kgplatform-backend/internal/controller/teams"
 	"kgplatform-backend/internal/controller/upload"
 	"kgplatform-backend/internal/controller/users"
+	cron "kgplatform-backend/internal/corn"
 	"kgplatform-backend/internal/logic/middleware"
-	"kgplatform-backend/internal/service"
+	"kgplatform-backend/internal/logic/traffic"
 
+	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gcmd"
 
+	schedules2 "kgplatform-backend/internal/logic/schedules"
 	tasks2 "kgplatform-backend/internal/logic/tasks"
 )
 
@@ -37,8 +51,13 @@ var (
 		Usage: "main",
 		Brief: "start http server",
 		Func: func(ctx context.Context, parser *gcmd.Parser) (err error) {
-			// 初始化配额重置定时任务
-			service.UserSubscription.InitCronTask(ctx)
+			// 注册定时任务, 与 HTTP 服务一起启动, 配额按月重置由月度账单任务完成
+			if err = cron.RegisterCronJobs(ctx); err != nil {
+				return gerror.Wrap(err, "定时任务加载失败")
+			}
+			if err = schedules2.New().StartAll(ctx); err != nil {
+				return gerror.Wrap(err, "工作流定时运行计划加载失败")
+			}
 
 			s := g.Server()
 
@@ -66,14 +85,31 @@ var (
 						sms.NewV1(),
 						email.NewV1(),
 						alipay.NewV1Public(),
+						payment.NewV1Public(),
+						// 公开项目列表只返回项目的基本信息, 不含项目数据, 不计量流量
 						projects.NewV1Public(),
 					)
+					// Python任务回调, 签名校验通过后才进入回调处理
+					group.Group("/", func(group *ghttp.RouterGroup) {
+						group.Middleware(py_service.VerifyCallback)
+						group.Bind(python.NewV1())
+					})
 					// SSE发送流量预警通知，供前端调用
 					group.Group("/usage", func(group *ghttp.RouterGroup) {
 						group.GET("/stream", sse.NewV1().HandleSSE)
 					})
 					group.Group("/", func(group *ghttp.RouterGroup) {
 						group.Middleware(middleware.Auth)
+						// 计量项目数据读取、文件下载和导出的流量
+						group.Middleware(traffic.MeterRoutes(map[string]string{
+							"GET /v1/projects/{id}":                             consts.TrafficApiRead,
+							"POST /v1/projects/getTriplesType":                  consts.TrafficApiRead,
+							"POST /v1/projects/getTriplesByType":                consts.TrafficApiRead,
+							"POST /v1/projects/getEntities":                     consts.TrafficApiRead,
+							"POST /v1/projects/{projectId}/triplets/source":     consts.TrafficApiRead,
+							"POST /v1/projects/download/downloadExtractExample": consts.TrafficFileTransfer,
+							"POST /v1/projects/exportTriplesToZip":              consts.TrafficExport,
+						}))
 						group.Bind(
 							account.NewV1(),
 							upload.NewV1(),
@@ -83,15 +119,23 @@ var (
 							likes.NewV1(),
 							teams.NewV1(),
 							alipay.NewV1(),
+							payment.NewV1(),
 							tasks.NewV1(),
+							experiments.NewV1(),
+							prompts.NewV1(),
 							chat.NewV1(),
 							pipelines.NewV1(),
+							schedules.NewV1(),
 							models.NewV1(),
 							support_domains.NewV1(),
 							professional_dictionary.NewV1(),
+							cron_jobs.NewV1(),
+							revenue.NewV1(),
+							billing.NewV1(),
+							coupons.NewV1(),
 						)
 						group.Group("/", func(graphGroup *ghttp.RouterGroup) {
-							graphGroup.Middleware(middleware.TrafficStats("graph_query"))
+							graphGroup.Middleware(traffic.Meter(consts.TrafficGraphQuery))
 							graphGroup.Bind(graphs.NewV1())
 						})
 					})
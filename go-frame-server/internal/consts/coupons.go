@@ -0,0 +1,20 @@
+package consts
+
+// Coupon discount type constants
+const (
+	CouponPercent = "percent"
+	CouponFixed   = "fixed"
+)
+
+// Coupon status constants
+const (
+	CouponActive   = "active"
+	CouponDisabled = "disabled"
+)
+
+// Coupon redemption status constants
+const (
+	RedemptionPending  = "pending"
+	RedemptionRedeemed = "redeemed"
+	RedemptionReleased = "released"
+)
@@ -0,0 +1,14 @@
+package consts
+
+// Cron job trigger type constants
+const (
+	CronTriggerSchedule = "schedule"
+	CronTriggerManual   = "manual"
+)
+
+// Cron job run status constants
+const (
+	CronRunStatusRunning = "running"
+	CronRunStatusSuccess = "success"
+	CronRunStatusFailed  = "failed"
+)
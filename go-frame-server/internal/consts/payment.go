@@ -0,0 +1,62 @@
+package consts
+
+// Payment provider constants
+const (
+	PaymentProviderAlipay = "alipay"
+	PaymentProviderWechat = "wechat"
+	PaymentProviderMock   = "mock"
+)
+
+// Unified trade status constants, mapped from each provider's own status
+const (
+	TradeStatusPending  = "pending"
+	TradeStatusPaid     = "paid"
+	TradeStatusClosed   = "closed"
+	TradeStatusRefunded = "refunded"
+)
+
+// Refund status constants
+const (
+	RefundStatusProcessing = "processing"
+	RefundStatusSuccess    = "success"
+	RefundStatusFailed     = "failed"
+)
+
+// Payment order status constants
+const (
+	OrderStatusCreated  = "created"
+	OrderStatusPaid     = "paid"
+	OrderStatusRefunded = "refunded"
+	OrderStatusClosed   = "closed"
+)
+
+// Payment order type constants
+const (
+	OrderTypeProject      = "project"
+	OrderTypeSubscription = "subscription"
+	OrderTypeBilling      = "billing"
+)
+
+// Payment order event source constants
+const (
+	OrderEventNotify = "notify"
+	OrderEventQuery  = "query"
+	OrderEventExpire = "expire"
+	OrderEventRefund = "refund"
+	OrderEventCancel = "cancel"
+)
+
+// Payment reconcile report status constants
+const (
+	ReconcileMatched    = "matched"
+	ReconcileMismatched = "mismatched"
+)
+
+// Payment reconcile mismatch type constants
+const (
+	MismatchStatus      = "status"
+	MismatchAmount      = "amount"
+	MismatchTransaction = "transaction"
+	MismatchUnpaid      = "unpaid"
+	MismatchQueryFailed = "query_failed"
+)
@@ -0,0 +1,8 @@
+package consts
+
+// Prompt scope constants
+const (
+	PromptScopeUser   = "user"
+	PromptScopeTeam   = "team"
+	PromptScopePublic = "public"
+)
@@ -0,0 +1,13 @@
+package consts
+
+// Project purchase type constants
+const (
+	PurchaseTypeRead = "read" // 阅读权限: 在线浏览和查询
+	PurchaseTypeBuy  = "buy"  // 购买: 可导出和克隆
+)
+
+// Project purchase status constants
+const (
+	PurchaseStatusCompleted = "completed"
+	PurchaseStatusRefunded  = "refunded"
+)
@@ -0,0 +1,13 @@
+package consts
+
+// Creator revenue entry type constants
+const (
+	RevenueEntrySale   = "sale"
+	RevenueEntryRefund = "refund"
+)
+
+// Creator payout batch status constants
+const (
+	PayoutBatchPending = "pending"
+	PayoutBatchPaid    = "paid"
+)
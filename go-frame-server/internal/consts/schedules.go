@@ -0,0 +1,15 @@
+package consts
+
+// Pipeline schedule status constants
+const (
+	ScheduleStatusActive = "active"
+	ScheduleStatusPaused = "paused"
+)
+
+// Pipeline schedule run status constants
+const (
+	ScheduleRunStatusRunning   = "running"
+	ScheduleRunStatusCompleted = "completed"
+	ScheduleRunStatusFailed    = "failed"
+	ScheduleRunStatusSkipped   = "skipped"
+)
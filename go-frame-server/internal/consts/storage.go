@@ -0,0 +1,15 @@
+package consts
+
+// Storage object source constants
+const (
+	StorageSourceUpload   = "upload"
+	StorageSourceData     = "data"
+	StorageSourceArtifact = "artifact"
+	StorageSourceInvoice  = "invoice"
+)
+
+// Storage object status constants
+const (
+	StorageObjectPending = "pending" // quota reserved, object is being written or its owner has not committed yet
+	StorageObjectActive  = "active"
+)
@@ -0,0 +1,23 @@
+package consts
+
+// Subscription plan constants, in upgrade order
+const (
+	PlanFree         = "free"
+	PlanProfessional = "professional"
+	PlanTeam         = "team"
+)
+
+// Plan change type constants
+const (
+	PlanChangeUpgrade   = "upgrade"
+	PlanChangeDowngrade = "downgrade"
+)
+
+// Plan change status constants
+const (
+	PlanChangePending   = "pending"
+	PlanChangeScheduled = "scheduled"
+	PlanChangeApplied   = "applied"
+	PlanChangeCancelled = "cancelled"
+	PlanChangeReverted  = "reverted"
+)
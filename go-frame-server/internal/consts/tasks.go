@@ -5,6 +5,8 @@ const (
 	TaskTypeOCR     = "ocr"
 	TaskTypeExtract = "extract"
 	TaskTypeGraph   = "graph"
+
+	TaskTypeExperiment = "experiment"
 )
 
 const (
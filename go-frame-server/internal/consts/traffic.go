@@ -0,0 +1,15 @@
+package consts
+
+// Traffic type constants
+const (
+	TrafficGraphQuery   = "graph_query"
+	TrafficApiRead      = "api_read"
+	TrafficFileTransfer = "file_transfer"
+	TrafficExport       = "export"
+)
+
+// Traffic quota enforcement constants
+const (
+	TrafficEnforceHard = "hard"
+	TrafficEnforceSoft = "soft"
+)
@@ -0,0 +1,7 @@
+package consts
+
+// Usage event source constants, storage and traffic events use the storage source and traffic type
+const (
+	UsageSourceExtract    = "extract"
+	UsageSourceExperiment = "experiment"
+)
@@ -2,8 +2,20 @@ package alipay
 
 import (
 	"context"
+	"fmt"
 	v1 "kgplatform-backend/api/alipay/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/orders"
+	"kgplatform-backend/internal/model/entity"
 	"kgplatform-backend/internal/service"
+	"math"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
 )
 
 type ControllerV1 struct{}
@@ -12,9 +24,46 @@ func NewV1() *ControllerV1 {
 	return &ControllerV1{}
 }
 
-// CreateSubscriptionPay 创建订阅套餐支付订单
+// planNames 套餐名称, 用于订单标题
+var planNames = map[string]string{
+	"professional": "专业版",
+	"team":         "团队版",
+}
+
+// CreateSubscriptionPay 创建订阅套餐支付订单, 支付结果由支付订单状态机处理
 func (c *ControllerV1) CreateSubscriptionPay(ctx context.Context, req *v1.CreateSubscriptionPayReq) (res *v1.CreateSubscriptionPayRes, err error) {
-	return service.Alipay().CreateSubscriptionPay(ctx, req)
+	r := g.RequestFromCtx(ctx)
+	userId := r.GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	members := 1
+	if req.UserPlan == "team" {
+		members = req.MemberCount
+	}
+	out, err := orders.New().Create(ctx, &orders.CreateInput{
+		UserId:     userId,
+		OrderType:  consts.OrderTypeSubscription,
+		BizData:    g.Map{"plan": req.UserPlan, "memberCount": members},
+		Subject:    "订阅" + planNames[req.UserPlan],
+		AmountCent: int64(math.Round(billing.PlanPrice(ctx, req.UserPlan, members) * 100)),
+		Provider:   consts.PaymentProviderAlipay,
+		PayType:    req.PayType,
+		ClientIp:   r.GetClientIp(),
+		CouponCode: req.CouponCode,
+		Plan:       req.UserPlan,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CreateSubscriptionPayRes{
+		OrderString:    out.PayData,
+		OutTradeNo:     out.Order.OutTradeNo,
+		TotalAmount:    fmt.Sprintf("%.2f", float64(out.Order.AmountCent)/100),
+		DiscountAmount: discountAmount(out),
+	}, nil
 }
 
 // CreatePay 创建支付订单
@@ -56,12 +105,100 @@ func (c *ControllerV1) QueryOrder(ctx context.Context, req *v1.QueryOrderReq) (r
 	return service.Alipay().QueryOrder(ctx, req.OutTradeNo)
 }
 
-// Refund 申请退款
+// Refund 管理员申请退款, 支付订单状态机中的订单退款后收回权益, 历史订单仍由支付宝服务处理
 func (c *ControllerV1) Refund(ctx context.Context, req *v1.RefundReq) (res *v1.RefundRes, err error) {
-	return service.Alipay().Refund(ctx, req)
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	order, err := orders.New().Get(ctx, req.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return service.Alipay().Refund(ctx, req)
+	}
+	if order.Provider != consts.PaymentProviderAlipay {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "该订单不是支付宝订单")
+	}
+
+	refundCent := int64(math.Round(req.RefundAmount * 100))
+	order, out, err := orders.New().Refund(ctx, &orders.RefundInput{
+		OutTradeNo: req.OutTradeNo,
+		RefundCent: refundCent,
+		Reason:     req.RefundReason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RefundRes{
+		TradeNo:      order.TransactionId,
+		OutTradeNo:   order.OutTradeNo,
+		RefundAmount: fmt.Sprintf("%.2f", float64(refundCent)/100),
+		RefundStatus: out.Status,
+	}, nil
 }
 
-// CreateBillingPay 根据账单创建支付订单
+// CreateBillingPay 根据账单创建支付订单, 支付结果由支付订单状态机处理
 func (c *ControllerV1) CreateBillingPay(ctx context.Context, req *v1.CreateBillingPayReq) (res *v1.CreateBillingPayRes, err error) {
-	return service.Alipay().CreateBillingPay(ctx, req)
+	r := g.RequestFromCtx(ctx)
+	userId := r.GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	var record *entity.BillingRecords
+	err = dao.BillingRecords.Ctx(ctx).
+		Where("id", req.BillingId).
+		Where("user_id", userId).
+		Scan(&record)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "账单不存在")
+	}
+	if record.Status != "unpaid" {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "账单已支付")
+	}
+
+	// 优惠码的套餐限制按用户当前的套餐校验
+	plan, err := dao.UserSubscriptions.Ctx(ctx).Where("user_id", userId).Value("user_plan")
+	if err != nil {
+		return nil, err
+	}
+	subject := fmt.Sprintf("支付账单: %s", record.BillingPeriod)
+	if req.Remark != "" {
+		subject = req.Remark
+	}
+	out, err := orders.New().Create(ctx, &orders.CreateInput{
+		UserId:     userId,
+		OrderType:  consts.OrderTypeBilling,
+		BizId:      int(record.Id),
+		Subject:    subject,
+		AmountCent: int64(math.Round(record.TotalAmount * 100)),
+		Provider:   consts.PaymentProviderAlipay,
+		PayType:    req.PayType,
+		ClientIp:   r.GetClientIp(),
+		CouponCode: req.CouponCode,
+		Plan:       plan.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CreateBillingPayRes{
+		OrderString:    out.PayData,
+		OutTradeNo:     out.Order.OutTradeNo,
+		TotalAmount:    fmt.Sprintf("%.2f", float64(out.Order.AmountCent)/100),
+		DiscountAmount: discountAmount(out),
+	}, nil
+}
+
+// discountAmount 优惠码减免的金额, 未使用优惠码时为 0
+func discountAmount(out *orders.CreateOutput) string {
+	if out.Quote == nil {
+		return "0.00"
+	}
+	return fmt.Sprintf("%.2f", float64(out.Quote.DiscountCent)/100)
 }
@@ -4,7 +4,11 @@ package alipay
 import (
 	"context"
 	v1 "kgplatform-backend/api/alipay/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/logic/orders"
+	"kgplatform-backend/internal/logic/payment"
 	"kgplatform-backend/internal/service"
+	"net/http"
 
 	"github.com/gogf/gf/v2/frame/g"
 )
@@ -23,8 +27,12 @@ func (c *ControllerV1Public) Notify(ctx context.Context, req *v1.NotifyReq) (res
 	// 获取所有POST参数
 	params := r.GetFormMap()
 
-	// 验证并处理回调
-	err = service.Alipay().HandleNotify(ctx, params)
+	// 新订单走支付订单状态机, 历史订单仍由支付宝服务处理
+	if order, _ := orders.New().Get(ctx, r.GetForm("out_trade_no").String()); order != nil {
+		err = handleOrderNotify(ctx, r.Request)
+	} else {
+		err = service.Alipay().HandleNotify(ctx, params)
+	}
 	if err != nil {
 		g.Log().Error(ctx, "支付回调处理失败:", err)
 		r.Response.Write("fail")
@@ -35,3 +43,17 @@ func (c *ControllerV1Public) Notify(ctx context.Context, req *v1.NotifyReq) (res
 	r.Response.Write("success")
 	return nil, nil
 }
+
+// handleOrderNotify 验签后按支付订单状态机处理, 重复通知不会重复发放权益
+func handleOrderNotify(ctx context.Context, r *http.Request) error {
+	provider, err := payment.Get(ctx, consts.PaymentProviderAlipay)
+	if err != nil {
+		return err
+	}
+	n, err := provider.VerifyNotify(ctx, r)
+	if err != nil {
+		return err
+	}
+	_, err = orders.New().HandleNotify(ctx, n)
+	return err
+}
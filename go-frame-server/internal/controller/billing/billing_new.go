@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package billing
+
+import (
+	"kgplatform-backend/api/billing"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() billing.IBillingV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,32 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/orders"
+)
+
+func (c *ControllerV1) CancelPlanChange(ctx context.Context, req *v1.CancelPlanChangeReq) (res *v1.CancelPlanChangeRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	change, err := billing.New().CancelPlanChange(ctx, userId, req.ChangeId)
+	if err != nil {
+		return nil, err
+	}
+	// 关闭差价的支付订单, 关闭后仍支付成功的由支付订单状态机退款
+	if change.Status == consts.PlanChangePending {
+		if err = orders.New().CloseBiz(ctx, consts.OrderTypeSubscription, change.Id); err != nil {
+			g.Log().Errorf(ctx, "关闭套餐变更的支付订单失败: %v, 变更ID: %d", err, change.Id)
+		}
+	}
+	return &v1.CancelPlanChangeRes{}, nil
+}
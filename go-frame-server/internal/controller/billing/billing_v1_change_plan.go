@@ -0,0 +1,68 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/orders"
+)
+
+var planNames = map[string]string{
+	consts.PlanFree:         "免费版",
+	consts.PlanProfessional: "专业版",
+	consts.PlanTeam:         "团队版",
+}
+
+// ChangePlan 变更套餐, 需要补交差价的升级创建支付订单, 支付成功后由支付订单状态机使变更生效
+func (c *ControllerV1) ChangePlan(ctx context.Context, req *v1.ChangePlanReq) (res *v1.ChangePlanRes, err error) {
+	r := g.RequestFromCtx(ctx)
+	userId := r.GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	change, quote, err := billing.New().ChangePlan(ctx, userId, req.UserPlan, req.MemberCount)
+	if err != nil {
+		return nil, err
+	}
+	// 被替代的变更不再生效, 关闭其差价的支付订单
+	for _, id := range quote.Superseded {
+		if err = orders.New().CloseBiz(ctx, consts.OrderTypeSubscription, id); err != nil {
+			g.Log().Errorf(ctx, "关闭套餐变更的支付订单失败: %v, 变更ID: %d", err, id)
+		}
+	}
+	res = &v1.ChangePlanRes{Change: change, Quote: quote, TotalAmount: "0.00"}
+	if change.Status != consts.PlanChangePending {
+		return res, nil
+	}
+
+	out, err := orders.New().Create(ctx, &orders.CreateInput{
+		UserId:    int(userId),
+		OrderType: consts.OrderTypeSubscription,
+		BizId:     change.Id,
+		BizData: g.Map{
+			"plan":        change.ToPlan,
+			"memberCount": change.ToMembers,
+			"changeId":    change.Id,
+		},
+		Subject:    fmt.Sprintf("升级套餐: %s", planNames[change.ToPlan]),
+		AmountCent: change.ProratedAmountCent,
+		Provider:   req.Provider,
+		PayType:    req.PayType,
+		ClientIp:   r.GetClientIp(),
+		Plan:       change.ToPlan,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.OrderString = out.PayData
+	res.OutTradeNo = out.Order.OutTradeNo
+	res.TotalAmount = fmt.Sprintf("%.2f", float64(out.Order.AmountCent)/100)
+	return res, nil
+}
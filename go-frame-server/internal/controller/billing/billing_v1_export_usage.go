@@ -0,0 +1,36 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/usage"
+)
+
+func (c *ControllerV1) ExportUsage(ctx context.Context, req *v1.ExportUsageReq) (res *v1.ExportUsageRes, err error) {
+	r := g.RequestFromCtx(ctx)
+	userId := r.GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	fileName, content, err := usage.New().Export(ctx, &usage.QueryInput{
+		UserId:       userId,
+		MemberId:     req.MemberId,
+		ResourceType: req.ResourceType,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.Response.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	r.Response.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	r.Response.Write(content)
+	return nil, nil
+}
@@ -0,0 +1,28 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/invoices"
+)
+
+func (c *ControllerV1) GetInvoice(ctx context.Context, req *v1.GetInvoiceReq) (res *v1.GetInvoiceRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	invoice, err := invoices.New().Get(ctx, userId, req.BillingId)
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "该账单尚未申请发票")
+	}
+	return &v1.GetInvoiceRes{Invoice: invoice}, nil
+}
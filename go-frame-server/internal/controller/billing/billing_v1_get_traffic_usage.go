@@ -0,0 +1,31 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/traffic"
+)
+
+func (c *ControllerV1) GetTrafficUsage(ctx context.Context, req *v1.GetTrafficUsageReq) (res *v1.GetTrafficUsageRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	usage, err := traffic.New().Usage(ctx, &traffic.UsageInput{
+		UserId:      userId,
+		MemberId:    req.MemberId,
+		TrafficType: req.TrafficType,
+		Granularity: req.Granularity,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetTrafficUsageRes{Usage: usage}, nil
+}
@@ -0,0 +1,30 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/usage"
+)
+
+func (c *ControllerV1) GetUsageBreakdown(ctx context.Context, req *v1.GetUsageBreakdownReq) (res *v1.GetUsageBreakdownRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	breakdown, err := usage.New().Breakdown(ctx, &usage.QueryInput{
+		UserId:       userId,
+		MemberId:     req.MemberId,
+		ResourceType: req.ResourceType,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+	}, req.Dimension)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetUsageBreakdownRes{Breakdown: breakdown}, nil
+}
@@ -0,0 +1,24 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/billing"
+)
+
+func (c *ControllerV1) PreviewBill(ctx context.Context, req *v1.PreviewBillReq) (res *v1.PreviewBillRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	bill, err := billing.New().Preview(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PreviewBillRes{Bill: bill}, nil
+}
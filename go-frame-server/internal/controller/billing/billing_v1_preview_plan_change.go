@@ -0,0 +1,24 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/billing"
+)
+
+func (c *ControllerV1) PreviewPlanChange(ctx context.Context, req *v1.PreviewPlanChangeReq) (res *v1.PreviewPlanChangeRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	quote, err := billing.New().QuotePlanChange(ctx, userId, req.UserPlan, req.MemberCount)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PreviewPlanChangeRes{PlanChangeQuote: quote}, nil
+}
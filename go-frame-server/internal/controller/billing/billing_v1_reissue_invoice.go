@@ -0,0 +1,21 @@
+package billing
+
+import (
+	"context"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/invoices"
+)
+
+func (c *ControllerV1) ReissueInvoice(ctx context.Context, req *v1.ReissueInvoiceReq) (res *v1.ReissueInvoiceRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	invoice, err := invoices.New().Reissue(ctx, req.BillingId, req.Title, req.TaxId)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ReissueInvoiceRes{Invoice: invoice}, nil
+}
@@ -0,0 +1,29 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/invoices"
+)
+
+func (c *ControllerV1) RequestInvoice(ctx context.Context, req *v1.RequestInvoiceReq) (res *v1.RequestInvoiceRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int64()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	invoice, err := invoices.New().Request(ctx, &invoices.RequestInput{
+		UserId:    userId,
+		BillingId: req.BillingId,
+		Title:     req.Title,
+		TaxId:     req.TaxId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RequestInvoiceRes{Invoice: invoice}, nil
+}
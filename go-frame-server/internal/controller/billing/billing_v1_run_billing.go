@@ -0,0 +1,21 @@
+package billing
+
+import (
+	"context"
+
+	"kgplatform-backend/api/billing/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/billing"
+)
+
+func (c *ControllerV1) RunBilling(ctx context.Context, req *v1.RunBillingReq) (res *v1.RunBillingRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := billing.New().Run(ctx, req.Period)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RunBillingRes{RunResult: result}, nil
+}
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package coupons
+
+import (
+	"kgplatform-backend/api/coupons"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() coupons.ICouponsV1 {
+	return &ControllerV1{}
+}
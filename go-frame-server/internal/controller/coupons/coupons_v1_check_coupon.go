@@ -0,0 +1,82 @@
+package coupons
+
+import (
+	"context"
+	"math"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/access"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/coupons"
+	"kgplatform-backend/internal/model/entity"
+)
+
+func (c *ControllerV1) CheckCoupon(ctx context.Context, req *v1.CheckCouponReq) (res *v1.CheckCouponRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	in := &coupons.ApplyInput{
+		Code:      req.Code,
+		UserId:    userId,
+		OrderType: req.OrderType,
+		Plan:      req.UserPlan,
+	}
+	// 订单金额与下单接口的计算方式一致
+	switch req.OrderType {
+	case consts.OrderTypeSubscription:
+		members := 1
+		if req.UserPlan == "team" {
+			members = req.MemberCount
+		}
+		in.AmountCent = int64(math.Round(billing.PlanPrice(ctx, req.UserPlan, members) * 100))
+	case consts.OrderTypeBilling:
+		var record *entity.BillingRecords
+		err = dao.BillingRecords.Ctx(ctx).
+			Where("id", req.BillingId).
+			Where("user_id", userId).
+			Scan(&record)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil || record.Status != "unpaid" {
+			return nil, gerror.NewCode(gcode.CodeNotFound, "未找到待支付的账单")
+		}
+		plan, err := dao.UserSubscriptions.Ctx(ctx).Where("user_id", userId).Value("user_plan")
+		if err != nil {
+			return nil, err
+		}
+		in.Plan = plan.String()
+		in.AmountCent = int64(math.Round(record.TotalAmount * 100))
+	case consts.OrderTypeProject:
+		_, project, err := access.New().GetLevel(ctx, userId, req.ProjectId)
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			return nil, gerror.NewCode(gcode.CodeNotFound, "项目不存在")
+		}
+		price := project.BuyPriceCent
+		if req.PurchaseType == consts.PurchaseTypeRead {
+			price = project.ReadPriceCent
+		}
+		in.ProjectId = project.Id
+		in.AmountCent = int64(math.Round(price * 100))
+	}
+	if in.AmountCent <= 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "订单金额为零, 无需使用优惠码")
+	}
+
+	quote, err := coupons.New().Check(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CheckCouponRes{Quote: quote}, nil
+}
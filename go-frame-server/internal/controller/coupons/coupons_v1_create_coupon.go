@@ -0,0 +1,43 @@
+package coupons
+
+import (
+	"context"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/coupons"
+)
+
+func (c *ControllerV1) CreateCoupon(ctx context.Context, req *v1.CreateCouponReq) (res *v1.CreateCouponRes, err error) {
+	adminId, err := admin.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	couponId, err := coupons.New().Create(ctx, adminId, saveInput(req.Code, &req.CouponFields))
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CreateCouponRes{CouponId: couponId}, nil
+}
+
+func saveInput(code string, f *v1.CouponFields) *coupons.SaveInput {
+	return &coupons.SaveInput{
+		Code:              code,
+		Name:              f.Name,
+		DiscountType:      f.DiscountType,
+		PercentOff:        f.PercentOff,
+		AmountOffCent:     f.AmountOffCent,
+		MaxDiscountCent:   f.MaxDiscountCent,
+		MinAmountCent:     f.MinAmountCent,
+		OrderTypes:        f.OrderTypes,
+		Plans:             f.Plans,
+		ProjectIds:        f.ProjectIds,
+		FirstPurchaseOnly: f.FirstPurchaseOnly,
+		TotalLimit:        f.TotalLimit,
+		PerUserLimit:      f.PerUserLimit,
+		StartsAt:          f.StartsAt,
+		ExpiresAt:         f.ExpiresAt,
+		Status:            f.Status,
+	}
+}
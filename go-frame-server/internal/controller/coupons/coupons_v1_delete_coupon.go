@@ -0,0 +1,20 @@
+package coupons
+
+import (
+	"context"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/coupons"
+)
+
+func (c *ControllerV1) DeleteCoupon(ctx context.Context, req *v1.DeleteCouponReq) (res *v1.DeleteCouponRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = coupons.New().Delete(ctx, req.CouponId); err != nil {
+		return nil, err
+	}
+	return &v1.DeleteCouponRes{}, nil
+}
@@ -0,0 +1,21 @@
+package coupons
+
+import (
+	"context"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/coupons"
+)
+
+func (c *ControllerV1) GetCoupon(ctx context.Context, req *v1.GetCouponReq) (res *v1.GetCouponRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	coupon, err := coupons.New().Get(ctx, req.CouponId)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetCouponRes{Coupons: coupon}, nil
+}
@@ -0,0 +1,28 @@
+package coupons
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/coupons"
+)
+
+func (c *ControllerV1) ListCoupon(ctx context.Context, req *v1.ListCouponReq) (res *v1.ListCouponRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	list, total, err := coupons.New().List(ctx, req.Keyword, req.Status, req.Page, req.Size)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取优惠券列表失败: %v", err)
+		return nil, gerror.New("获取优惠券列表失败")
+	}
+	return &v1.ListCouponRes{
+		Total: total,
+		List:  list,
+	}, nil
+}
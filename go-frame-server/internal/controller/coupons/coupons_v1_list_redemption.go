@@ -0,0 +1,28 @@
+package coupons
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/coupons"
+)
+
+func (c *ControllerV1) ListRedemption(ctx context.Context, req *v1.ListRedemptionReq) (res *v1.ListRedemptionRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	list, total, err := coupons.New().ListRedemptions(ctx, req.CouponId, req.Page, req.Size)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取优惠券使用记录失败: %v", err)
+		return nil, gerror.New("获取优惠券使用记录失败")
+	}
+	return &v1.ListRedemptionRes{
+		Total: total,
+		List:  list,
+	}, nil
+}
@@ -0,0 +1,20 @@
+package coupons
+
+import (
+	"context"
+
+	"kgplatform-backend/api/coupons/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/coupons"
+)
+
+func (c *ControllerV1) UpdateCoupon(ctx context.Context, req *v1.UpdateCouponReq) (res *v1.UpdateCouponRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = coupons.New().Update(ctx, req.CouponId, saveInput("", &req.CouponFields)); err != nil {
+		return nil, err
+	}
+	return &v1.UpdateCouponRes{}, nil
+}
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package cron_jobs
+
+import (
+	"kgplatform-backend/api/cron_jobs"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() cron_jobs.ICronJobsV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,41 @@
+package cron_jobs
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/cron_jobs/v1"
+	cron "kgplatform-backend/internal/corn"
+	"kgplatform-backend/internal/logic/admin"
+)
+
+func (c *ControllerV1) ListCronJob(ctx context.Context, req *v1.ListCronJobReq) (res *v1.ListCronJobRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	registry := cron.Default()
+	lastRuns, err := registry.LastRuns(ctx)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取定时任务运行记录失败: %v", err)
+		return nil, gerror.New("获取定时任务列表失败")
+	}
+
+	res = &v1.ListCronJobRes{List: make([]*v1.CronJobItem, 0)}
+	for _, job := range registry.Jobs() {
+		running, err := cron.IsLocked(ctx, job.Name)
+		if err != nil {
+			g.Log().Errorf(ctx, "获取定时任务运行状态失败: %v, 任务: %s", err, job.Name)
+		}
+		res.List = append(res.List, &v1.CronJobItem{
+			Name:        job.Name,
+			Description: job.Description,
+			Pattern:     job.Pattern,
+			Running:     running,
+			LastRun:     lastRuns[job.Name],
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,38 @@
+package cron_jobs
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/cron_jobs/v1"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/model/entity"
+)
+
+func (c *ControllerV1) ListCronJobRun(ctx context.Context, req *v1.ListCronJobRunReq) (res *v1.ListCronJobRunRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		list  []*entity.CronJobRuns
+		total int
+	)
+	err = dao.CronJobRuns.Ctx(ctx).
+		Where("job_name", req.Name).
+		OrderDesc("id").
+		Page(req.Page, req.Size).
+		ScanAndCount(&list, &total, false)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取定时任务运行记录失败: %v", err)
+		return nil, gerror.New("获取定时任务运行记录失败")
+	}
+	res = &v1.ListCronJobRunRes{
+		Total: total,
+		List:  list,
+	}
+	return res, nil
+}
@@ -0,0 +1,34 @@
+package cron_jobs
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/cron_jobs/v1"
+	cron "kgplatform-backend/internal/corn"
+	"kgplatform-backend/internal/logic/admin"
+)
+
+func (c *ControllerV1) TriggerCronJob(ctx context.Context, req *v1.TriggerCronJobReq) (res *v1.TriggerCronJobRes, err error) {
+	userId, err := admin.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := cron.Default()
+	if registry.Get(req.Name) == nil {
+		return nil, gerror.New("定时任务不存在")
+	}
+	runId, err := registry.Trigger(ctx, req.Name, userId)
+	if err != nil {
+		if err == cron.ErrJobRunning {
+			return nil, err
+		}
+		g.Log().Errorf(ctx, "手动触发定时任务失败: %v, 任务: %s", err, req.Name)
+		return nil, gerror.New("触发定时任务失败")
+	}
+	g.Log().Infof(ctx, "用户 %d 手动触发定时任务: %s", userId, req.Name)
+	return &v1.TriggerCronJobRes{RunId: runId}, nil
+}
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package experiments
+
+import (
+	"kgplatform-backend/api/experiments"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() experiments.IExperimentsV1 {
+	return &ControllerV1{}
+}
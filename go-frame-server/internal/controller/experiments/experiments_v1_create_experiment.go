@@ -0,0 +1,44 @@
+package experiments
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/experiments/v1"
+	"kgplatform-backend/internal/logic/experiments"
+)
+
+func (c *ControllerV1) CreateExperiment(ctx context.Context, req *v1.CreateExperimentReq) (res *v1.CreateExperimentRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	runs := make([]experiments.RunInput, 0, len(req.Runs))
+	for _, run := range req.Runs {
+		runs = append(runs, experiments.RunInput{
+			ModelId:         run.ModelId,
+			Prompt:          run.Prompt,
+			PromptVersionId: run.PromptVersionId,
+		})
+	}
+	experiment, err := experiments.New().Create(ctx, &experiments.CreateExperimentInput{
+		UserId:         userId,
+		ProjectId:      req.ProjectId,
+		PipelineId:     req.PipelineId,
+		MaterialIdList: req.MaterialIDList,
+		SampleSize:     req.SampleSize,
+		Runs:           runs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.CreateExperimentRes{
+		ExperimentId: experiment.Id,
+		TaskId:       experiment.TaskId,
+		Status:       experiment.Status,
+	}
+	return res, nil
+}
@@ -0,0 +1,29 @@
+package experiments
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/experiments/v1"
+	"kgplatform-backend/internal/logic/experiments"
+)
+
+func (c *ControllerV1) GetExperiment(ctx context.Context, req *v1.GetExperimentReq) (res *v1.GetExperimentRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	experiment, runs, report, err := experiments.New().Get(ctx, userId, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.GetExperimentRes{
+		Experiment: experiment,
+		Runs:       runs,
+		Report:     report,
+	}
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package experiments
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/experiments/v1"
+	"kgplatform-backend/internal/logic/experiments"
+)
+
+func (c *ControllerV1) ListExperiment(ctx context.Context, req *v1.ListExperimentReq) (res *v1.ListExperimentRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	list, err := experiments.New().List(ctx, userId, req.ProjectId)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取实验列表失败: %v", err)
+		return nil, gerror.New("获取实验列表失败")
+	}
+	return &v1.ListExperimentRes{List: list}, nil
+}
@@ -0,0 +1,23 @@
+package experiments
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/experiments/v1"
+	"kgplatform-backend/internal/logic/experiments"
+)
+
+func (c *ControllerV1) PromoteExperimentRun(ctx context.Context, req *v1.PromoteExperimentRunReq) (res *v1.PromoteExperimentRunRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	if err = experiments.New().Promote(ctx, userId, req.ExperimentId, req.RunId); err != nil {
+		return nil, err
+	}
+	return &v1.PromoteExperimentRunRes{}, nil
+}
@@ -3,6 +3,7 @@ package graphs
 import (
 	"context"
 	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/graphs"
 
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -11,6 +12,12 @@ import (
 )
 
 func (c *ControllerV1) GetGraph(ctx context.Context, req *v1.GetGraphReq) (res *v1.GetGraphRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	level, _, err := access.New().Check(ctx, userId, req.ProjectId, access.LevelPreview)
+	if err != nil {
+		return nil, err
+	}
+
 	graphLogic := graphs.New()
 	graphOutput, err := graphLogic.GetGraph(ctx, &graphs.GetGraphInput{
 		ProjectId: req.ProjectId,
@@ -22,9 +29,38 @@ func (c *ControllerV1) GetGraph(ctx context.Context, req *v1.GetGraphReq) (res *
 	if graphOutput == nil {
 		return nil, gerror.New("该项目的图谱暂未生成")
 	}
-	return &v1.GetGraphRes{
+	res = &v1.GetGraphRes{
 		Id:    graphOutput.Id,
 		Nodes: graphOutput.Nodes,
 		Edges: graphOutput.Edges,
-	}, nil
+	}
+	if level == access.LevelPreview {
+		res.Nodes, res.Edges = previewSubgraph(res.Nodes, res.Edges, access.PreviewLimit(ctx))
+		res.Preview = true
+	}
+	return res, nil
+}
+
+// previewSubgraph 截取前 limit 个节点及它们之间的边
+func previewSubgraph(nodes, edges []map[string]string, limit int) ([]map[string]string, []map[string]string) {
+	if len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+	kept := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if id := node["id"]; id != "" {
+			kept[id] = true
+		}
+	}
+
+	subEdges := make([]map[string]string, 0)
+	for _, edge := range edges {
+		if len(subEdges) >= limit {
+			break
+		}
+		if kept[edge["source"]] && kept[edge["target"]] {
+			subEdges = append(subEdges, edge)
+		}
+	}
+	return nodes, subEdges
 }
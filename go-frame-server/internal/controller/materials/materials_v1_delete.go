@@ -3,16 +3,34 @@ package materials
 import (
 	"context"
 
+	"github.com/gogf/gf/v2/frame/g"
+
 	"kgplatform-backend/api/materials/v1"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/storage"
+	"kgplatform-backend/internal/model/entity"
 )
 
-// DeleteMaterial 删除材料
+// DeleteMaterial 删除材料, 删除后释放材料文件、文字化结果和抽取结果占用的存储空间
 func (c *ControllerV1) DeleteMaterial(ctx context.Context, req *v1.DeleteMaterialReq) (res *v1.DeleteMaterialRes, err error) {
+	var material *entity.Materials
+	if err = dao.Materials.Ctx(ctx).Where("id", req.Id).Scan(&material); err != nil {
+		return nil, err
+	}
 	err = c.materials.DeleteMaterial(ctx, req.Id)
 	if err != nil {
 		return nil, err
 	}
 
+	if material != nil {
+		store := storage.New()
+		for _, name := range []string{material.Url, material.TextUrl, material.TripleUrl} {
+			if err := store.Remove(ctx, name); err != nil {
+				g.Log().Errorf(ctx, "删除材料文件失败: %v, 材料ID: %d, 文件: %s", err, material.Id, name)
+			}
+		}
+	}
+
 	return &v1.DeleteMaterialRes{
 		Success: true,
 	}, nil
@@ -0,0 +1,24 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/os/gtime"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/reconcile"
+)
+
+// CreateReconcileReport 重新生成指定日期的支付对账报告
+func (c *ControllerV1) CreateReconcileReport(ctx context.Context, req *v1.CreateReconcileReportReq) (res *v1.CreateReconcileReportRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	report, err := reconcile.New().Report(ctx, gtime.NewFromStr(req.Date))
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CreateReconcileReportRes{Report: report}, nil
+}
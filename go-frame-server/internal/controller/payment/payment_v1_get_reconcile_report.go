@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"context"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/reconcile"
+)
+
+// GetReconcileReport 获取支付对账报告及差异明细
+func (c *ControllerV1) GetReconcileReport(ctx context.Context, req *v1.GetReconcileReportReq) (res *v1.GetReconcileReportRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	report, items, err := reconcile.New().GetReport(ctx, req.ReportId)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.GetReconcileReportRes{
+		Report: report,
+		Items:  items,
+	}, nil
+}
@@ -0,0 +1,16 @@
+package payment
+
+import (
+	"context"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/payment"
+)
+
+// ListPaymentProvider 获取可用的支付渠道
+func (c *ControllerV1) ListPaymentProvider(ctx context.Context, req *v1.ListPaymentProviderReq) (res *v1.ListPaymentProviderRes, err error) {
+	return &v1.ListPaymentProviderRes{
+		Providers: payment.Enabled(ctx),
+		Default:   payment.DefaultName(ctx),
+	}, nil
+}
@@ -0,0 +1,29 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/reconcile"
+)
+
+// ListReconcileReport 获取支付对账报告列表
+func (c *ControllerV1) ListReconcileReport(ctx context.Context, req *v1.ListReconcileReportReq) (res *v1.ListReconcileReportRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	list, total, err := reconcile.New().ListReports(ctx, req.Page, req.Size)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取对账报告列表失败: %v", err)
+		return nil, gerror.New("获取对账报告列表失败")
+	}
+	return &v1.ListReconcileReportRes{
+		Total: total,
+		List:  list,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package payment
+
+import (
+	"context"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/payment"
+)
+
+// MockPay 模拟支付, 只允许管理员调用, 否则开启模拟网关的环境中任何用户都可以免费获得权益
+func (c *ControllerV1) MockPay(ctx context.Context, req *v1.MockPayReq) (res *v1.MockPayRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+	n, err := payment.MockPay(ctx, &payment.MockPayInput{
+		OutTradeNo: req.OutTradeNo,
+		Status:     req.Status,
+		Repeat:     req.Repeat,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.MockPayRes{
+		NotifyId:      n.NotifyId,
+		TransactionId: n.TransactionId,
+		Status:        n.Status,
+	}, nil
+}
@@ -0,0 +1,45 @@
+// payment_v1_public.go - 不需要认证的接口（支付回调）
+package payment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/orders"
+	"kgplatform-backend/internal/logic/payment"
+)
+
+type ControllerV1Public struct{}
+
+func NewV1Public() *ControllerV1Public {
+	return &ControllerV1Public{}
+}
+
+// PaymentNotify 支付结果通知（不需要认证）
+func (c *ControllerV1Public) PaymentNotify(ctx context.Context, req *v1.PaymentNotifyReq) (res *v1.PaymentNotifyRes, err error) {
+	r := g.RequestFromCtx(ctx)
+	provider, err := payment.Get(ctx, req.Provider)
+	if err != nil {
+		g.Log().Errorf(ctx, "支付通知渠道不可用: %v", err)
+		r.Response.WriteHeader(http.StatusNotFound)
+		return nil, nil
+	}
+
+	n, err := provider.VerifyNotify(ctx, r.Request)
+	if err != nil {
+		g.Log().Errorf(ctx, "支付通知验签失败: %v, 渠道: %s", err, req.Provider)
+		provider.AckNotify(r, err)
+		return nil, nil
+	}
+	g.Log().Infof(ctx, "收到支付通知: 渠道 %s, 订单 %s, 状态 %s, 通知ID %s",
+		n.Provider, n.OutTradeNo, n.Status, n.NotifyId)
+
+	if _, err = orders.New().HandleNotify(ctx, n); err != nil {
+		g.Log().Errorf(ctx, "支付通知处理失败: %v, 订单: %s", err, n.OutTradeNo)
+	}
+	provider.AckNotify(r, err)
+	return nil, nil
+}
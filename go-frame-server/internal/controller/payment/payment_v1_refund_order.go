@@ -0,0 +1,35 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	v1 "kgplatform-backend/api/payment/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/orders"
+)
+
+// RefundOrder 管理员为已支付的订单申请退款
+func (c *ControllerV1) RefundOrder(ctx context.Context, req *v1.RefundOrderReq) (res *v1.RefundOrderRes, err error) {
+	userId, err := admin.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, out, err := orders.New().Refund(ctx, &orders.RefundInput{
+		OutTradeNo: req.OutTradeNo,
+		RefundCent: req.RefundCent,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.Log().Infof(ctx, "管理员 %d 为订单 %s 申请退款, 退款状态 %s", userId, req.OutTradeNo, out.Status)
+	return &v1.RefundOrderRes{
+		Order:        order,
+		OutRefundNo:  out.OutRefundNo,
+		RefundId:     out.RefundId,
+		RefundStatus: out.Status,
+	}, nil
+}
@@ -1,7 +1,12 @@
 package projects
 
 import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+
 	"kgplatform-backend/api/projects"
+	"kgplatform-backend/internal/logic/access"
 	projectsLogic "kgplatform-backend/internal/logic/projects"
 )
 
@@ -14,3 +19,10 @@ func NewV1() projects.IProjectsV1 {
 		projects: projectsLogic.NewProjects(),
 	}
 }
+
+// checkAccess 校验当前用户对项目的访问级别, 返回实际级别
+func checkAccess(ctx context.Context, projectId int, need access.Level) (access.Level, error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	level, _, err := access.New().Check(ctx, userId, projectId, need)
+	return level, err
+}
@@ -2,12 +2,16 @@ package projects
 
 import (
 	"context"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
 )
 
 func (c *ControllerV1) AddTripleToProject(ctx context.Context, req *v1.AddTripleToProjectReq) (res *v1.AddTripleToProjectRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelOwner); err != nil {
+		return nil, err
+	}
 	projectLogic := projects.NewProjects()
 	_, err = projectLogic.UpdateTriples(ctx, &projects.UpdateTriplesInput{
 		ProjectId:  req.ProjectId,
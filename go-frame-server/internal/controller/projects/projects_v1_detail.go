@@ -3,12 +3,22 @@ package projects
 import (
 	"context"
 	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
+	"kgplatform-backend/internal/logic/views"
+	"kgplatform-backend/internal/neo4j"
 
 	v1 "kgplatform-backend/api/projects/v1"
 )
 
 func (c *ControllerV1) GetProject(ctx context.Context, req *v1.GetProjectReq) (res *v1.GetProjectRes, err error) {
+	level, err := checkAccess(ctx, req.Id, access.LevelPreview)
+	if err != nil {
+		return nil, err
+	}
+
 	projectDetail, err := c.projects.GetProjectDetail(ctx, &projects.GetProjectDetailInput{
 		ProjectId: req.Id,
 	})
@@ -16,7 +26,18 @@ func (c *ControllerV1) GetProject(ctx context.Context, req *v1.GetProjectReq) (r
 		return nil, gerror.New("获取项目详情失败")
 	}
 
-	return &v1.GetProjectRes{
+	// 记录浏览, 同一访客在去重窗口内只计一次
+	r := g.RequestFromCtx(ctx)
+	_, err = views.New().Record(ctx, &views.RecordInput{
+		ProjectId: req.Id,
+		UserId:    r.GetCtxVar("userID").Int(),
+		Ip:        r.GetClientIp(),
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "记录项目浏览失败: %v", err)
+	}
+
+	res = &v1.GetProjectRes{
 		Id:              projectDetail.Id,
 		UserId:          projectDetail.UserId,
 		ProjectName:     projectDetail.ProjectName,
@@ -32,5 +53,37 @@ func (c *ControllerV1) GetProject(ctx context.Context, req *v1.GetProjectReq) (r
 		TripleList:      projectDetail.TripleList,
 		PipelineId:      projectDetail.PipelineId,
 		ExtractConfig:   projectDetail.ExtractConfig,
-	}, nil
+		AccessLevel:     level.String(),
+	}
+
+	// 克隆来源, 用于展示原作者署名
+	fork, err := dao.Projects.Ctx(ctx).Fields("forked_from", "forked_from_user_id").Where("id", req.Id).One()
+	if err != nil {
+		g.Log().Errorf(ctx, "获取项目克隆来源失败: %v", err)
+	} else if !fork.IsEmpty() {
+		res.ForkedFrom = fork["forked_from"].Int()
+		res.ForkedFromUserId = fork["forked_from_user_id"].Int()
+	}
+
+	// 素材原文、样例文件和抽取配置随项目购买提供, 只读购买和预览均不返回
+	if level < access.LevelBuy {
+		res.Materials = nil
+		res.SampleTextUrl = ""
+		res.SampleXlsxUrl = ""
+		res.ExtractConfig = nil
+	}
+	// 未购买时只返回部分三元组, 不返回溯源原文
+	if level == access.LevelPreview {
+		limit := access.PreviewLimit(ctx)
+		if len(res.TripleList) > limit {
+			res.TripleList = res.TripleList[:limit]
+		}
+		tripleList := make([]neo4j.SimpleTriple, 0, len(res.TripleList))
+		for _, triple := range res.TripleList {
+			triple.SourceInfo = nil
+			tripleList = append(tripleList, triple)
+		}
+		res.TripleList = tripleList
+	}
+	return res, nil
 }
@@ -2,12 +2,18 @@ package projects
 
 import (
 	"context"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
 )
 
 func (c *ControllerV1) DownloadExtractExample(ctx context.Context, req *v1.DownloadExtractExampleReq) (res *v1.DownloadExtractExampleRes, err error) {
+	if g.RequestFromCtx(ctx).GetCtxVar("userID").Int() == 0 {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "请先登录")
+	}
 	projectLogic := projects.NewProjects()
 	projectLogic.DownloadExtractExample(ctx)
 	return nil, nil
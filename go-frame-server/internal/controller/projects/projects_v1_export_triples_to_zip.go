@@ -2,12 +2,17 @@ package projects
 
 import (
 	"context"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
 )
 
 func (c *ControllerV1) ExportTriplesToZip(ctx context.Context, req *v1.ExportTriplesToZipReq) (res *v1.ExportTriplesToZipRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelBuy); err != nil {
+		return nil, err
+	}
+
 	projectLogic := projects.NewProjects()
 	projectLogic.ExportTriplesToExcelZip(ctx, &projects.ExportTriplesToExcelZipInput{
 		ProjectId: req.ProjectId,
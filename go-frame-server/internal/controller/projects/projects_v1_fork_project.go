@@ -0,0 +1,31 @@
+package projects
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	v1 "kgplatform-backend/api/projects/v1"
+	"kgplatform-backend/internal/logic/forks"
+)
+
+func (c *ControllerV1) ForkProject(ctx context.Context, req *v1.ForkProjectReq) (res *v1.ForkProjectRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	out, err := forks.New().Fork(ctx, &forks.ForkInput{
+		UserId:      userId,
+		ProjectId:   req.ProjectId,
+		ProjectName: req.ProjectName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ForkProjectRes{
+		ProjectId:  out.ProjectId,
+		PipelineId: out.PipelineId,
+	}, nil
+}
@@ -0,0 +1,31 @@
+package projects
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	v1 "kgplatform-backend/api/projects/v1"
+	"kgplatform-backend/internal/logic/access"
+)
+
+func (c *ControllerV1) GetProjectAccess(ctx context.Context, req *v1.GetProjectAccessReq) (res *v1.GetProjectAccessRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	level, project, err := access.New().GetLevel(ctx, userId, req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	if level == access.LevelNone {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "项目不可见或不存在")
+	}
+
+	return &v1.GetProjectAccessRes{
+		AccessLevel:   level.String(),
+		CanRead:       level >= access.LevelRead,
+		CanExport:     level >= access.LevelBuy,
+		ReadPriceCent: project.ReadPriceCent,
+		BuyPriceCent:  project.BuyPriceCent,
+	}, nil
+}
@@ -2,6 +2,7 @@ package projects
 
 import (
 	"context"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
@@ -9,6 +10,10 @@ import (
 
 // GetProjectEntities 获取项目实体结构
 func (c *ControllerV1) GetProjectEntities(ctx context.Context, req *v1.GetProjectEntitiesReq) (*v1.GetProjectEntitiesRes, error) {
+	if _, err := checkAccess(ctx, req.ProjectId, access.LevelRead); err != nil {
+		return nil, err
+	}
+
 	projectLogic := projects.NewProjects()
 	output, err := projectLogic.GetProjectEntities(ctx, &projects.GetProjectEntitiesInput{
 		ProjectId: req.ProjectId,
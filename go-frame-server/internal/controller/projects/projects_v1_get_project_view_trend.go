@@ -0,0 +1,37 @@
+package projects
+
+import (
+	"context"
+	v1 "kgplatform-backend/api/projects/v1"
+	"kgplatform-backend/internal/logic/views"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+func (c *ControllerV1) GetProjectViewTrend(ctx context.Context, req *v1.GetProjectViewTrendReq) (res *v1.GetProjectViewTrendRes, err error) {
+	// 检查项目可见性
+	visibility, err := g.Model("projects").
+		Where("id", req.ProjectId).
+		Fields("visibility").
+		Value()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if visibility.Int() != 1 {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "项目不可见或不存在")
+	}
+
+	trend, err := views.New().Trend(ctx, req.ProjectId, req.Days)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取浏览量趋势失败: %v", err)
+		return nil, gerror.New("获取浏览量趋势失败")
+	}
+
+	return &v1.GetProjectViewTrendRes{
+		List: trend,
+	}, nil
+}
@@ -3,7 +3,7 @@ package projects
 import (
 	"context"
 	v1 "kgplatform-backend/api/projects/v1"
-	"kgplatform-backend/internal/logic/projects"
+	"kgplatform-backend/internal/logic/views"
 
 	"github.com/gogf/gf/v2/errors/gcode"
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -11,8 +11,6 @@ import (
 )
 
 func (c *ControllerV1) GetProjectViewCount(ctx context.Context, req *v1.GetProjectViewCountReq) (res *v1.GetProjectViewCountRes, err error) {
-	projectLogic := projects.NewProjects()
-
 	// 检查项目可见性
 	visibility, err := g.Model("projects").
 		Where("id", req.ProjectId).
@@ -28,8 +26,9 @@ func (c *ControllerV1) GetProjectViewCount(ctx context.Context, req *v1.GetProje
 	}
 
 	// 获取浏览量
-	viewCount, err := projectLogic.GetViewCount(ctx, req.ProjectId)
+	viewCount, err := views.New().Count(ctx, req.ProjectId)
 	if err != nil {
+		g.Log().Errorf(ctx, "获取浏览量失败: %v", err)
 		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "获取浏览量失败")
 	}
 
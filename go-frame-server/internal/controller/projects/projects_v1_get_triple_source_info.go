@@ -4,10 +4,16 @@ import (
 	"context"
 
 	v1 "kgplatform-backend/api/projects/v1"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 )
 
 func (c *ControllerV1) GetTripleSourceInfo(ctx context.Context, req *v1.GetTripleSourceInfoReq) (res *v1.GetTripleSourceInfoRes, err error) {
+	// 溯源原文需要阅读权限
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelRead); err != nil {
+		return nil, err
+	}
+
 	result, err := c.projects.GetTripleSourceInfo(ctx, &projects.GetTripleSourceInfoInput{
 		ProjectId: req.ProjectId,
 		Triple:    req.Triple,
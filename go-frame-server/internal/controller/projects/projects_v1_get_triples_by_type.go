@@ -3,12 +3,17 @@ package projects
 import (
 	"context"
 	"github.com/gogf/gf/v2/errors/gerror"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
 )
 
 func (c *ControllerV1) GetTriplesByType(ctx context.Context, req *v1.GetTriplesByTypeReq) (res *v1.GetTriplesByTypeRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelRead); err != nil {
+		return nil, err
+	}
+
 	projectLogic := projects.NewProjects()
 	tripleType := req.TripleType.HeadType + "-" + req.TripleType.RelationshipType + "-" + req.TripleType.TailType
 	output, err := projectLogic.GetTriplesByType(ctx, &projects.GetTriplesByTypeInput{
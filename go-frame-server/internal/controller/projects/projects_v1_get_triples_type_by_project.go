@@ -3,6 +3,7 @@ package projects
 import (
 	"context"
 	"github.com/gogf/gf/v2/errors/gerror"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
@@ -10,6 +11,11 @@ import (
 
 // GetTriplesTypeByProject 获取项目下的三元组类型
 func (c *ControllerV1) GetTriplesTypeByProject(ctx context.Context, req *v1.GetTriplesTypeByProjectReq) (res *v1.GetTriplesTypeByProjectRes, err error) {
+	// 三元组类型只包含结构信息, 预览用户也可查看
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelPreview); err != nil {
+		return nil, err
+	}
+
 	projectLogic := projects.NewProjects()
 	output, err := projectLogic.GetProjectTripleType(ctx, &projects.GetProjectTripleTypeInput{
 		ProjectId: req.ProjectId,
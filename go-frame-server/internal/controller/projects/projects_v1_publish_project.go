@@ -2,6 +2,7 @@ package projects
 
 import (
 	"context"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -12,6 +13,9 @@ import (
 )
 
 func (c *ControllerV1) PublishProject(ctx context.Context, req *v1.PublishProjectReq) (res *v1.PublishProjectRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelOwner); err != nil {
+		return nil, err
+	}
 	projectLogic := projects.NewProjects()
 	err = projectLogic.UpdateProjectByGMap(ctx, req.ProjectId, g.Map{
 		"visibility":         req.Visibility,
@@ -21,6 +25,7 @@ func (c *ControllerV1) PublishProject(ctx context.Context, req *v1.PublishProjec
 		"snapshot_photo_url": req.SnapshotPhotoURL,
 		"buy_price_cent":     req.BuyPriceCent,
 		"read_price_cent":    req.ReadPriceCent,
+		"forkable":           req.Forkable,
 		"updated_at":         gtime.Now(),
 	})
 
@@ -2,64 +2,134 @@ package projects
 
 import (
 	"context"
+	"fmt"
 	v1 "kgplatform-backend/api/projects/v1"
-	projectsLogic "kgplatform-backend/internal/logic/projects"
-	"kgplatform-backend/internal/service"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/access"
+	"kgplatform-backend/internal/logic/orders"
+	"math"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
 )
 
-// PurchaseProject 购买项目接口
+// PurchaseProject 购买项目接口, 创建支付订单, 支付结果由支付订单状态机处理
 func (c *ControllerV1) PurchaseProject(ctx context.Context, req *v1.PurchaseProjectReq) (res *v1.PurchaseProjectRes, err error) {
-	// 调用逻辑层的BuyProject方法创建支付订单
-	output, err := c.projects.BuyProject(ctx, &projectsLogic.BuyProjectInput{
-		ProjectId: req.ProjectId,
+	r := g.RequestFromCtx(ctx)
+	userId := r.GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	level, project, err := access.New().GetLevel(ctx, userId, req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	need, price := access.LevelBuy, project.BuyPriceCent
+	if req.PurchaseType == consts.PurchaseTypeRead {
+		need, price = access.LevelRead, project.ReadPriceCent
+	}
+	switch {
+	case level == access.LevelNone:
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "项目不可见或不存在")
+	case level == access.LevelOwner:
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "不能购买自己的项目")
+	case level >= need:
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "已拥有该项目的权限, 无需购买")
+	}
+
+	// 项目价格以元存储
+	out, err := orders.New().Create(ctx, &orders.CreateInput{
+		UserId:     userId,
+		OrderType:  consts.OrderTypeProject,
+		BizId:      project.Id,
+		BizData:    g.Map{"purchaseType": req.PurchaseType},
+		Subject:    "购买项目: " + project.ProjectName,
+		AmountCent: int64(math.Round(price * 100)),
+		Provider:   req.Provider,
+		PayType:    req.PayType,
+		ClientIp:   r.GetClientIp(),
+		CouponCode: req.CouponCode,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 转换逻辑层输出为API响应
-	return &v1.PurchaseProjectRes{
-		OrderString: output.OrderString,
-		OutTradeNo:  output.OutTradeNo,
-		TotalAmount: output.TotalAmount,
-	}, nil
+	res = &v1.PurchaseProjectRes{
+		OrderString:    out.PayData,
+		OutTradeNo:     out.Order.OutTradeNo,
+		TotalAmount:    fmt.Sprintf("%.2f", float64(out.Order.AmountCent)/100),
+		Provider:       out.Order.Provider,
+		DiscountAmount: "0.00",
+	}
+	if out.Quote != nil {
+		res.DiscountAmount = fmt.Sprintf("%.2f", float64(out.Quote.DiscountCent)/100)
+	}
+	return res, nil
 }
 
-// QueryProjectPurchaseStatus 查询项目购买状态接口
+// QueryProjectPurchaseStatus 查询项目购买状态接口, 只能查询自己购买该项目的订单
 func (c *ControllerV1) QueryProjectPurchaseStatus(ctx context.Context, req *v1.QueryProjectPurchaseStatusReq) (res *v1.QueryProjectPurchaseStatusRes, err error) {
-	// 1. 调用支付宝服务查询订单状态
-	alipayRes, err := service.Alipay().QueryOrder(ctx, req.OutTradeNo)
-	if err != nil {
-		return &v1.QueryProjectPurchaseStatusRes{
-			IsPurchased: false,
-			TradeStatus: "",
-			Message:     "查询订单失败: " + err.Error(),
-		}, nil
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
 	}
 
-	// 2. 判断交易状态是否为支付成功
-	isPurchased := false
-	message := "订单未支付"
-
-	// 支付宝交易状态说明：
-	// - WAIT_BUYER_PAY：等待买家付款
-	// - TRADE_CLOSED：交易关闭或已退款
-	// - TRADE_SUCCESS：支付成功
-	// - TRADE_FINISHED：交易结束，不可退款
-
-	if alipayRes.TradeStatus == "TRADE_SUCCESS" || alipayRes.TradeStatus == "TRADE_FINISHED" {
-		isPurchased = true
-		message = "支付成功"
-	} else if alipayRes.TradeStatus == "TRADE_CLOSED" {
-		message = "交易已关闭"
-	} else if alipayRes.TradeStatus == "WAIT_BUYER_PAY" {
-		message = "等待支付"
+	order, err := orders.New().Get(ctx, req.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return queryLegacyPurchaseStatus(ctx, userId, req.ProjectId)
+	}
+	if order.UserId != userId || order.OrderType != consts.OrderTypeProject || order.BizId != req.ProjectId {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "订单不存在")
 	}
 
-	// 3. 返回查询结果
+	// 未支付时主动查询渠道, 支付成功的结果与通知一样只生效一次
+	if order.Status == consts.OrderStatusCreated {
+		if synced, err := orders.New().Sync(ctx, req.OutTradeNo); err != nil {
+			g.Log().Warningf(ctx, "同步支付订单状态失败: %v, 订单: %s", err, req.OutTradeNo)
+		} else {
+			order = synced
+		}
+	}
+	message := map[string]string{
+		consts.OrderStatusCreated:  "等待支付",
+		consts.OrderStatusPaid:     "支付成功",
+		consts.OrderStatusClosed:   "交易已关闭",
+		consts.OrderStatusRefunded: "已退款",
+	}[order.Status]
 	return &v1.QueryProjectPurchaseStatusRes{
-		IsPurchased: isPurchased,
-		TradeStatus: alipayRes.TradeStatus,
+		IsPurchased: order.Status == consts.OrderStatusPaid,
+		TradeStatus: order.Status,
 		Message:     message,
 	}, nil
 }
+
+// queryLegacyPurchaseStatus 支付订单状态机之前的订单没有记录下单用户, 按当前用户的购买记录返回状态
+func queryLegacyPurchaseStatus(ctx context.Context, userId, projectId int) (*v1.QueryProjectPurchaseStatusRes, error) {
+	status, err := dao.UserProjectPurchases.Ctx(ctx).
+		Where("user_id", userId).
+		Where("project_id", projectId).
+		Value("status")
+	if err != nil {
+		return nil, err
+	}
+	switch status.String() {
+	case consts.PurchaseStatusCompleted:
+		return &v1.QueryProjectPurchaseStatusRes{
+			IsPurchased: true,
+			TradeStatus: consts.OrderStatusPaid,
+			Message:     "支付成功",
+		}, nil
+	case consts.PurchaseStatusRefunded:
+		return &v1.QueryProjectPurchaseStatusRes{
+			TradeStatus: consts.OrderStatusRefunded,
+			Message:     "已退款",
+		}, nil
+	}
+	return nil, gerror.NewCode(gcode.CodeNotFound, "订单不存在")
+}
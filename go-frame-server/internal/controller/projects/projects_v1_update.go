@@ -2,6 +2,7 @@ package projects
 
 import (
 	"context"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"kgplatform-backend/api/projects/v1"
@@ -9,6 +10,9 @@ import (
 
 // UpdateProject 更新项目
 func (c *ControllerV1) UpdateProject(ctx context.Context, req *v1.UpdateProjectReq) (res *v1.UpdateProjectRes, err error) {
+	if _, err = checkAccess(ctx, req.Id, access.LevelOwner); err != nil {
+		return nil, err
+	}
 	input := &projects.UpdateProjectInput{
 		ProjectName:     req.ProjectName,
 		ProjectProgress: req.ProjectProgress,
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"kgplatform-backend/internal/consts"
 	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/storage"
 	"kgplatform-backend/internal/logic/tasks"
 	"kgplatform-backend/internal/logic/upload"
 	"time"
@@ -43,7 +45,11 @@ import (
 //}
 
 func (c *ControllerV1) UploadSchema(ctx context.Context, req *v1.UploadSchemaReq) (res *v1.UploadSchemaRes, err error) {
-	uploadLogic := upload.NewUpload()
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
 	var schemaWrapper tasks.SchemaWrapper
 	schemaWrapper.Schemas = req.Triples
 	bytes, err := json.Marshal(schemaWrapper)
@@ -55,20 +61,15 @@ func (c *ControllerV1) UploadSchema(ctx context.Context, req *v1.UploadSchemaReq
 	timestamp := time.Now().Format("20060102150405")
 
 	filename := fmt.Sprintf("%s_%s_%s", req.ProjectId, timestamp, uuidStr[:8])
-	saveDataOutput, err := uploadLogic.SaveData(ctx, &upload.SaveDataInput{
+	saveDataOutput, err := storage.New().SaveData(ctx, &upload.SaveDataInput{
 		Content:  string(bytes),
 		FileName: filename,
 		DataType: "json",
-	})
+		UserId:   userId,
+	}, consts.StorageSourceData)
 	if err != nil {
 		return nil, err
 	}
-	//userId := g.RequestFromCtx(ctx).GetCtxVar("userId").Int()
-
-	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
-	if userId == 0 {
-		return nil, gerror.New("请先登录")
-	}
 
 	_, err = dao.Projects.Ctx(ctx).Where("id", req.ProjectId).Where("user_id", userId).Update(g.Map{
 		"schema_url": saveDataOutput.FileName,
@@ -3,6 +3,7 @@ package projects
 import (
 	"context"
 	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
 
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -11,6 +12,9 @@ import (
 )
 
 func (c *ControllerV1) UploadProjectSnapshotPhoto(ctx context.Context, req *v1.UploadProjectSnapshotPhotoReq) (res *v1.UploadProjectSnapshotPhotoRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelOwner); err != nil {
+		return nil, err
+	}
 	projectLogic := projects.NewProjects()
 	project, err := projectLogic.GetProject(ctx, req.ProjectId)
 	if err != nil {
@@ -5,9 +5,13 @@ import (
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"kgplatform-backend/api/projects/v1"
+	"kgplatform-backend/internal/logic/access"
 )
 
 func (c *ControllerV1) UploadSampleText(ctx context.Context, req *v1.UploadSampleTextReq) (res *v1.UploadSampleTextRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelOwner); err != nil {
+		return nil, err
+	}
 	err = c.projects.UpdateProjectByGMap(ctx, req.ProjectId, g.Map{
 		"sample_text_url": req.FilePath,
 	})
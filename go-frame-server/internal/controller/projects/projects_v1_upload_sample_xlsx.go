@@ -3,6 +3,7 @@ package projects
 import (
 	"context"
 	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/internal/logic/access"
 
 	"github.com/gogf/gf/v2/errors/gerror"
 
@@ -10,6 +11,9 @@ import (
 )
 
 func (c *ControllerV1) UploadSampleXLSX(ctx context.Context, req *v1.UploadSampleXLSXReq) (res *v1.UploadSampleXLSXRes, err error) {
+	if _, err = checkAccess(ctx, req.ProjectId, access.LevelOwner); err != nil {
+		return nil, err
+	}
 	err = c.projects.UpdateProjectByGMap(ctx, req.ProjectId, g.Map{
 		"sample_xlsx_url": req.FilePath,
 	})
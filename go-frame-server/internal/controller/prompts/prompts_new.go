@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package prompts
+
+import (
+	"kgplatform-backend/api/prompts"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() prompts.IPromptsV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,37 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/prompts/v1"
+	"kgplatform-backend/internal/logic/prompts"
+)
+
+func (c *ControllerV1) CreatePrompt(ctx context.Context, req *v1.CreatePromptReq) (res *v1.CreatePromptRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	out, err := prompts.New().Create(ctx, &prompts.CreatePromptInput{
+		UserId:      userId,
+		Name:        req.Name,
+		Description: req.Description,
+		Scope:       req.Scope,
+		TeamId:      req.TeamId,
+		Content:     req.Content,
+		ChangeNote:  req.ChangeNote,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.CreatePromptRes{
+		PromptId:  out.Prompt.Id,
+		VersionId: out.Version.Id,
+		Version:   out.Version.Version,
+	}
+	return res, nil
+}
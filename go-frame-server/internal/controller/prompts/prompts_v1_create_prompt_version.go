@@ -0,0 +1,28 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/prompts/v1"
+	"kgplatform-backend/internal/logic/prompts"
+)
+
+func (c *ControllerV1) CreatePromptVersion(ctx context.Context, req *v1.CreatePromptVersionReq) (res *v1.CreatePromptVersionRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	version, err := prompts.New().CreateVersion(ctx, userId, req.PromptId, req.Content, req.ChangeNote)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.CreatePromptVersionRes{
+		VersionId: version.Id,
+		Version:   version.Version,
+	}
+	return res, nil
+}
@@ -0,0 +1,29 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/prompts/v1"
+	"kgplatform-backend/internal/logic/prompts"
+)
+
+func (c *ControllerV1) DiffPromptVersion(ctx context.Context, req *v1.DiffPromptVersionReq) (res *v1.DiffPromptVersionRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	from, to, lines, err := prompts.New().Diff(ctx, userId, req.FromVersionId, req.ToVersionId)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.DiffPromptVersionRes{
+		From:  from,
+		To:    to,
+		Lines: lines,
+	}
+	return res, nil
+}
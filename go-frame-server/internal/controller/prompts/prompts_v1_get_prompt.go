@@ -0,0 +1,28 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/prompts/v1"
+	"kgplatform-backend/internal/logic/prompts"
+)
+
+func (c *ControllerV1) GetPrompt(ctx context.Context, req *v1.GetPromptReq) (res *v1.GetPromptRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	prompt, versions, err := prompts.New().Get(ctx, userId, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.GetPromptRes{
+		Prompt:   prompt,
+		Versions: versions,
+	}
+	return res, nil
+}
@@ -0,0 +1,36 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/prompts/v1"
+	"kgplatform-backend/internal/logic/prompts"
+)
+
+func (c *ControllerV1) ListPrompt(ctx context.Context, req *v1.ListPromptReq) (res *v1.ListPromptRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	list, total, err := prompts.New().List(ctx, &prompts.ListPromptInput{
+		UserId:  userId,
+		Scope:   req.Scope,
+		TeamId:  req.TeamId,
+		Keyword: req.Keyword,
+		Page:    req.Page,
+		Size:    req.Size,
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "获取提示词列表失败: %v", err)
+		return nil, gerror.New("获取提示词列表失败")
+	}
+	res = &v1.ListPromptRes{
+		Total: total,
+		List:  list,
+	}
+	return res, nil
+}
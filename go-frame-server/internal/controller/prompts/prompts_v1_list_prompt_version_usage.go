@@ -0,0 +1,34 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/prompts/v1"
+	"kgplatform-backend/internal/logic/prompts"
+)
+
+func (c *ControllerV1) ListPromptVersionUsage(ctx context.Context, req *v1.ListPromptVersionUsageReq) (res *v1.ListPromptVersionUsageRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	list, total, stats, err := prompts.New().ListUsage(ctx, &prompts.ListUsageInput{
+		UserId:          userId,
+		PromptVersionId: req.VersionId,
+		Page:            req.Page,
+		Size:            req.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.ListPromptVersionUsageRes{
+		Stats: stats,
+		Total: total,
+		List:  list,
+	}
+	return res, nil
+}
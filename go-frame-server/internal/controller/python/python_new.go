@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package python
+
+import (
+	"kgplatform-backend/api/python"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() python.IPythonV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,44 @@
+// python_v1_callback.go - 不需要认证的接口（Python任务回调, 通过签名校验）
+package python
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/python/v1"
+	"kgplatform-backend/external/py_service"
+)
+
+// Callback Python 任务状态回调
+// 返回非 2xx 状态码时 Python 服务会重试
+func (c *ControllerV1) Callback(ctx context.Context, req *v1.CallbackReq) (res *v1.CallbackRes, err error) {
+	r := g.RequestFromCtx(ctx)
+
+	err = py_service.GetPythonClient().HandleCallback(ctx, &py_service.CallbackInput{
+		Body:      r.GetBody(),
+		Timestamp: r.Header.Get("X-Timestamp"),
+		Signature: r.Header.Get("X-Signature"),
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "Python任务回调处理失败: %v", err)
+		switch gerror.Code(err) {
+		case gcode.CodeNotAuthorized:
+			r.Response.WriteHeader(http.StatusUnauthorized)
+		case gcode.CodeInvalidParameter:
+			r.Response.WriteHeader(http.StatusBadRequest)
+		case gcode.CodeNotFound:
+			r.Response.WriteHeader(http.StatusNotFound)
+		default:
+			r.Response.WriteHeader(http.StatusInternalServerError)
+		}
+		r.Response.WriteJson(g.Map{"result": "fail", "message": err.Error()})
+		return nil, nil
+	}
+
+	r.Response.WriteJson(g.Map{"result": "success"})
+	return nil, nil
+}
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package revenue
+
+import (
+	"kgplatform-backend/api/revenue"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() revenue.IRevenueV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,25 @@
+package revenue
+
+import (
+	"context"
+
+	"kgplatform-backend/api/revenue/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+func (c *ControllerV1) CreatePayoutBatch(ctx context.Context, req *v1.CreatePayoutBatchReq) (res *v1.CreatePayoutBatchRes, err error) {
+	adminId, err := admin.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := revenue.New().CreateBatch(ctx, &revenue.CreateBatchInput{
+		AdminId: adminId,
+		Period:  req.Period,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CreatePayoutBatchRes{Batch: batch}, nil
+}
@@ -0,0 +1,29 @@
+package revenue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/revenue/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+func (c *ControllerV1) ExportPayoutBatch(ctx context.Context, req *v1.ExportPayoutBatchReq) (res *v1.ExportPayoutBatchRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	fileName, content, err := revenue.New().ExportBatch(ctx, req.BatchId)
+	if err != nil {
+		return nil, err
+	}
+
+	r := g.RequestFromCtx(ctx)
+	r.Response.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	r.Response.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	r.Response.Write(content)
+	return nil, nil
+}
@@ -0,0 +1,40 @@
+package revenue
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/api/revenue/v1"
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+func (c *ControllerV1) GetRevenueStatement(ctx context.Context, req *v1.GetRevenueStatementReq) (res *v1.GetRevenueStatementRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+	if req.Period == "" {
+		req.Period = gtime.Now().Format("Y-m")
+	}
+
+	out, err := revenue.New().Statement(ctx, &revenue.StatementInput{
+		CreatorId: userId,
+		Period:    req.Period,
+		Page:      req.Page,
+		Size:      req.Size,
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "获取收入对账单失败: %v", err)
+		return nil, gerror.New("获取收入对账单失败")
+	}
+	return &v1.GetRevenueStatementRes{
+		Period:  out.Period,
+		Summary: out.Summary,
+		Balance: out.Balance,
+		Total:   out.Total,
+		List:    out.List,
+	}, nil
+}
@@ -0,0 +1,28 @@
+package revenue
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/revenue/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+func (c *ControllerV1) ListCreatorBalance(ctx context.Context, req *v1.ListCreatorBalanceReq) (res *v1.ListCreatorBalanceRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	list, total, err := revenue.New().Balances(ctx, req.Page, req.Size)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取创作者余额失败: %v", err)
+		return nil, gerror.New("获取创作者余额失败")
+	}
+	return &v1.ListCreatorBalanceRes{
+		Total: total,
+		List:  list,
+	}, nil
+}
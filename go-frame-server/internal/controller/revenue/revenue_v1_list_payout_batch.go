@@ -0,0 +1,28 @@
+package revenue
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/revenue/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+func (c *ControllerV1) ListPayoutBatch(ctx context.Context, req *v1.ListPayoutBatchReq) (res *v1.ListPayoutBatchRes, err error) {
+	if _, err = admin.Check(ctx); err != nil {
+		return nil, err
+	}
+
+	list, total, err := revenue.New().ListBatches(ctx, req.Page, req.Size)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取结算批次列表失败: %v", err)
+		return nil, gerror.New("获取结算批次列表失败")
+	}
+	return &v1.ListPayoutBatchRes{
+		Total: total,
+		List:  list,
+	}, nil
+}
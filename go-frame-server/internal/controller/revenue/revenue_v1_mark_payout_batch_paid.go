@@ -0,0 +1,24 @@
+package revenue
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/revenue/v1"
+	"kgplatform-backend/internal/logic/admin"
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+func (c *ControllerV1) MarkPayoutBatchPaid(ctx context.Context, req *v1.MarkPayoutBatchPaidReq) (res *v1.MarkPayoutBatchPaidRes, err error) {
+	adminId, err := admin.Check(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = revenue.New().MarkBatchPaid(ctx, req.BatchId); err != nil {
+		return nil, err
+	}
+	g.Log().Infof(ctx, "管理员 %d 标记结算批次 %d 已打款", adminId, req.BatchId)
+	return &v1.MarkPayoutBatchPaidRes{}, nil
+}
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package schedules
+
+import (
+	"kgplatform-backend/api/schedules"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() schedules.ISchedulesV1 {
+	return &ControllerV1{}
+}
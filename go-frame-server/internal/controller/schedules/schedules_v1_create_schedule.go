@@ -0,0 +1,29 @@
+package schedules
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/schedules/v1"
+	"kgplatform-backend/internal/logic/schedules"
+)
+
+func (c *ControllerV1) CreateSchedule(ctx context.Context, req *v1.CreateScheduleReq) (res *v1.CreateScheduleRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	schedule, err := schedules.New().Create(ctx, &schedules.CreateScheduleInput{
+		UserId:          userId,
+		PipelineId:      req.PipelineId,
+		CronExpr:        req.CronExpr,
+		NotifyOnFailure: req.NotifyOnFailure,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.CreateScheduleRes{PipelineSchedules: schedule}, nil
+}
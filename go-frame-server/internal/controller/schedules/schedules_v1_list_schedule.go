@@ -0,0 +1,25 @@
+package schedules
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/schedules/v1"
+	"kgplatform-backend/internal/logic/schedules"
+)
+
+func (c *ControllerV1) ListSchedule(ctx context.Context, req *v1.ListScheduleReq) (res *v1.ListScheduleRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	list, err := schedules.New().List(ctx, userId, req.PipelineId)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取定时运行计划失败: %v", err)
+		return nil, gerror.New("获取定时运行计划失败")
+	}
+	return &v1.ListScheduleRes{List: list}, nil
+}
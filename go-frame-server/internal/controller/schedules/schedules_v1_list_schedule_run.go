@@ -0,0 +1,33 @@
+package schedules
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/schedules/v1"
+	"kgplatform-backend/internal/logic/schedules"
+)
+
+func (c *ControllerV1) ListScheduleRun(ctx context.Context, req *v1.ListScheduleRunReq) (res *v1.ListScheduleRunRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	list, total, err := schedules.New().ListRuns(ctx, &schedules.ListRunInput{
+		UserId:     userId,
+		ScheduleId: req.Id,
+		Page:       req.Page,
+		Size:       req.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.ListScheduleRunRes{
+		Total: total,
+		List:  list,
+	}
+	return res, nil
+}
@@ -0,0 +1,23 @@
+package schedules
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/api/schedules/v1"
+	"kgplatform-backend/internal/logic/schedules"
+)
+
+func (c *ControllerV1) PauseSchedule(ctx context.Context, req *v1.PauseScheduleReq) (res *v1.PauseScheduleRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	if err = schedules.New().Pause(ctx, userId, req.Id); err != nil {
+		return nil, err
+	}
+	return &v1.PauseScheduleRes{}, nil
+}
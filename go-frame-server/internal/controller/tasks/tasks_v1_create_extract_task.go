@@ -5,19 +5,34 @@ import (
 	"github.com/gogf/gf/v2/encoding/gjson"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
-	"github.com/gogf/gf/v2/os/gtime"
 	"kgplatform-backend/api/tasks/v1"
 	"kgplatform-backend/external/py_service"
-	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/logic/access"
 	"kgplatform-backend/internal/logic/projects"
-	"kgplatform-backend/internal/logic/tasks"
+	"kgplatform-backend/internal/logic/prompts"
 )
 
 func (c *ControllerV1) CreateExtractTask(ctx context.Context, req *v1.CreateExtractTaskReq) (res *v1.CreateExtractTaskRes, err error) {
+	// 抽取配置保存在项目上, 定时运行按项目所有者的配额执行, 只有所有者可以修改
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if _, _, err = access.New().Check(ctx, userId, req.ProjectId, access.LevelOwner); err != nil {
+		return nil, err
+	}
+
+	// 引用提示词库版本时使用版本内容
+	if req.PromptVersionId != nil {
+		version, err := prompts.New().GetVersion(ctx, userId, *req.PromptVersionId)
+		if err != nil {
+			return nil, err
+		}
+		req.Prompt = version.Content
+	}
+
 	// 保存抽取配置
 	projectLogic := projects.NewProjects()
 	extractConfig := py_service.ExtractConfig{
 		Prompt:                 req.Prompt,
+		PromptVersionId:        req.PromptVersionId,
 		ModelId:                *req.ModelId,
 		Method:                 req.Method,
 		SchemaURL:              req.SchemaURL,
@@ -36,21 +51,14 @@ func (c *ControllerV1) CreateExtractTask(ctx context.Context, req *v1.CreateExtr
 		return nil, gerror.New("抽取配置保存失败")
 	}
 
-	// 进行抽取
-	taskLogic := tasks.New()
-	input := &tasks.CreateTaskInput{
-		Type:           consts.TaskTypeExtract,
+	// 进行抽取, 本次的抽取配置随任务保存, 重试失败素材时沿用, 引用的提示词版本随任务记录使用情况
+	taskEntity, err := py_service.CreateExtractTask(ctx, &py_service.ExtractTaskInput{
+		UserId:         userId,
 		PipelineId:     req.PipelineId,
 		ProjectId:      req.ProjectId,
 		MaterialIdList: req.MaterialIDList,
-		Status:         consts.TaskStatusPending,
-		UpdatedAt:      gtime.Now(),
-		CreatedAt:      gtime.Now(),
-		Prompt:         req.Prompt,
-		Method:         req.Method,
-		ModelId:        req.ModelId,
-	}
-	taskEntity, err := taskLogic.Create(ctx, input)
+		Config:         extractConfig,
+	})
 	if err != nil {
 		return nil, err
 	}
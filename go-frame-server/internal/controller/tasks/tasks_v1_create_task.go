@@ -2,7 +2,9 @@ package tasks
 
 import (
 	"context"
+	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/os/gtime"
+	"kgplatform-backend/external/py_service"
 	"kgplatform-backend/internal/consts"
 	"kgplatform-backend/internal/logic/tasks"
 	"slices"
@@ -22,6 +24,31 @@ func (c *ControllerV1) CreateTask(ctx context.Context, req *v1.CreateTaskReq) (r
 	if !slices.Contains(TaskTypeArray, req.Type) {
 		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "不支持的任务类型")
 	}
+	// 抽取任务通过抽取后端提交
+	if req.Type == consts.TaskTypeExtract {
+		if req.ModelId == nil {
+			return nil, gerror.NewCode(gcode.CodeInvalidParameter, "请选择模型")
+		}
+		taskEntity, err := py_service.CreateExtractTask(ctx, &py_service.ExtractTaskInput{
+			UserId:         g.RequestFromCtx(ctx).GetCtxVar("userID").Int(),
+			PipelineId:     req.PipelineId,
+			ProjectId:      req.ProjectId,
+			MaterialIdList: req.MaterialIdList,
+			Config: py_service.ExtractConfig{
+				Prompt:  req.Prompt,
+				ModelId: *req.ModelId,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &v1.CreateTaskRes{
+			Status: taskEntity.Status,
+			TaskId: taskEntity.Id,
+			Type:   taskEntity.Type,
+		}, nil
+	}
+
 	taskLogic := tasks.New()
 	input := &tasks.CreateTaskInput{
 		Type:           req.Type,
@@ -3,6 +3,7 @@ package tasks
 import (
 	"context"
 	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/external/py_service"
 	"kgplatform-backend/internal/logic/tasks"
 
 	"github.com/gogf/gf/v2/errors/gerror"
@@ -17,12 +18,33 @@ func (c *ControllerV1) GetTask(ctx context.Context, req *v1.GetTaskReq) (res *v1
 		return nil, gerror.Newf("获取任务失败")
 	}
 	res = &v1.GetTaskRes{
-		Id:         task.Id,
-		Type:       task.Type,
-		Status:     task.Status,
-		StartTime:  task.StartTime.String(),
-		FinishTime: task.FinishTime.String(),
-		CreateTime: task.CreatedAt.String(),
+		Id:           task.Id,
+		Type:         task.Type,
+		Status:       task.Status,
+		StartTime:    task.StartTime.String(),
+		FinishTime:   task.FinishTime.String(),
+		CreateTime:   task.CreatedAt.String(),
+		ErrorMessage: task.ErrorMessage,
+		PyTaskId:     task.PyTaskId,
+		Results:      []v1.TaskFileResult{},
+	}
+
+	// 各素材的抽取结果
+	if task.Results != nil {
+		var files []py_service.TaskFile
+		if err = task.Results.Scan(&files); err != nil {
+			g.Log().Errorf(ctx, "解析任务结果失败: %v, 任务ID: %d", err, task.Id)
+		}
+		for _, file := range files {
+			res.Results = append(res.Results, v1.TaskFileResult{
+				MaterialId:   file.MaterialId,
+				FileName:     file.FileName,
+				Status:       file.Status,
+				TriplesCount: file.TriplesCount,
+				OutputFiles:  file.OutputFiles,
+				Error:        file.Error,
+			})
+		}
 	}
 	return res, nil
 }
@@ -0,0 +1,88 @@
+package tasks
+
+import (
+	"context"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"kgplatform-backend/api/tasks/v1"
+	"kgplatform-backend/external/py_service"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+func (c *ControllerV1) RetryFailedTask(ctx context.Context, req *v1.RetryFailedTaskReq) (res *v1.RetryFailedTaskRes, err error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return nil, gerror.New("请先登录")
+	}
+
+	var task *entity.Tasks
+	if err = dao.Tasks.Ctx(ctx).Where("id", req.Id).Scan(&task); err != nil {
+		g.Log().Errorf(ctx, "获取任务失败: %v", err)
+		return nil, gerror.New("获取任务失败")
+	}
+	if task == nil {
+		return nil, gerror.New("任务不存在")
+	}
+	if task.Type != consts.TaskTypeExtract {
+		return nil, gerror.New("只能重试抽取任务")
+	}
+	if task.Status != consts.TaskStatusCompleted && task.Status != consts.TaskStatusFailed {
+		return nil, gerror.New("任务尚未结束, 无法重试")
+	}
+
+	var project *entity.Projects
+	if err = dao.Projects.Ctx(ctx).Where("id", task.ProjectId).Scan(&project); err != nil {
+		g.Log().Errorf(ctx, "获取项目信息失败: %v", err)
+		return nil, gerror.New("获取项目信息失败")
+	}
+	if project == nil {
+		return nil, gerror.New("项目不存在")
+	}
+	if project.UserId != userId {
+		return nil, gerror.New("无权操作该任务")
+	}
+
+	// 只重试失败或没有输出的素材
+	materialIdList := py_service.FailedMaterials(task)
+	if len(materialIdList) == 0 {
+		return nil, gerror.New("没有需要重试的素材")
+	}
+
+	// 沿用原任务的抽取配置, 早期任务未保存配置时使用项目的抽取配置
+	var extractConfig py_service.ExtractConfig
+	config := task.ExtractConfig
+	if config == nil {
+		config = project.ExtractConfig
+	}
+	if config != nil {
+		if err = config.Scan(&extractConfig); err != nil {
+			g.Log().Errorf(ctx, "解析抽取配置失败: %v", err)
+		}
+	}
+	if extractConfig.ModelId == 0 || extractConfig.Prompt == "" {
+		return nil, gerror.New("未找到原任务的抽取配置")
+	}
+
+	// 标记为重试任务, 完成后与项目已有的三元组合并
+	taskEntity, err := py_service.CreateExtractTask(ctx, &py_service.ExtractTaskInput{
+		UserId:         userId,
+		PipelineId:     task.PipelineId,
+		ProjectId:      task.ProjectId,
+		MaterialIdList: materialIdList,
+		Config:         extractConfig,
+		ParentTaskId:   task.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res = &v1.RetryFailedTaskRes{
+		TaskId:         taskEntity.Id,
+		ParentTaskId:   task.Id,
+		Status:         taskEntity.Status,
+		MaterialIdList: materialIdList,
+	}
+	return res, nil
+}
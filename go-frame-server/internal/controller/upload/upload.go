@@ -2,15 +2,18 @@ package upload
 
 import (
 	"kgplatform-backend/api/upload"
+	"kgplatform-backend/internal/logic/storage"
 	uploadLogic "kgplatform-backend/internal/logic/upload"
 )
 
 type ControllerV1 struct {
-	upload *uploadLogic.Upload
+	upload  *uploadLogic.Upload
+	storage *storage.Storage
 }
 
 func NewV1() upload.IUploadV1 {
 	return &ControllerV1{
-		upload: uploadLogic.NewUpload(),
+		upload:  uploadLogic.NewUpload(),
+		storage: storage.New(),
 	}
 }
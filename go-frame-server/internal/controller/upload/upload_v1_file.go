@@ -3,11 +3,24 @@ package upload
 import (
 	"context"
 	v1 "kgplatform-backend/api/upload/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/logic/storage"
 	uploadLogic "kgplatform-backend/internal/logic/upload"
+
+	"github.com/gogf/gf/v2/frame/g"
 )
 
 func (c *ControllerV1) UploadFile(ctx context.Context, req *v1.UploadFileReq) (res *v1.UploadFileRes, err error) {
-	result, err := c.upload.UploadFile(ctx, &uploadLogic.UploadFileInput{})
+	r := g.RequestFromCtx(ctx)
+	userId := r.GetCtxVar("userID").Int()
+	// 上传前按请求大小校验存储配额, 上传后按文件的实际大小计入存储用量
+	result, err := c.storage.Write(ctx, &storage.WriteInput{
+		UserId: userId,
+		Size:   r.ContentLength,
+		Source: consts.StorageSourceUpload,
+	}, func(ctx context.Context) (*uploadLogic.SaveDataOutput, error) {
+		return c.upload.UploadFile(ctx, &uploadLogic.UploadFileInput{})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -22,11 +35,12 @@ func (c *ControllerV1) UploadFile(ctx context.Context, req *v1.UploadFileReq) (r
 }
 
 func (c *ControllerV1) SaveData(ctx context.Context, req *v1.SaveDataReq) (res *v1.SaveDataRes, err error) {
-	result, err := c.upload.SaveData(ctx, &uploadLogic.SaveDataInput{
+	result, err := c.storage.SaveData(ctx, &uploadLogic.SaveDataInput{
 		Content:  req.Content,
 		FileName: req.FileName,
 		DataType: req.DataType,
-	})
+		UserId:   g.RequestFromCtx(ctx).GetCtxVar("userID").Int(),
+	}, consts.StorageSourceData)
 	if err != nil {
 		return nil, err
 	}
@@ -2,33 +2,46 @@ package cron
 
 import (
 	"context"
+	"time"
 
-	"github.com/gogf/gf/v2/os/gcron"
 	"github.com/gogf/gf/v2/os/glog"
 )
 
 // CronJob 定时任务结构体
 type CronJob struct {
-	Name     string
-	Pattern  string
-	Function func(ctx context.Context)
+	Name        string
+	Description string
+	Pattern     string
+	Function    func(ctx context.Context) error
+	// LockTTL 分布式锁过期时间, 为 0 时使用 cron.lockTTL 配置
+	LockTTL time.Duration
 }
 
-// RegisterCronJobs 注册所有定时任务到系统
+// RegisterCronJobs 注册所有定时任务到系统并启动调度
 func RegisterCronJobs(ctx context.Context) error {
-	fileCleanJob := NewTmpFileCleanJob(ctx)
-	//syncViewCountJob := NewSyncViewCountJob(ctx)
-	_, err := gcron.AddSingleton(ctx, fileCleanJob.Pattern, fileCleanJob.Function, fileCleanJob.Name)
-	if err != nil {
-		glog.Error(ctx, "添加临时文件清理任务失败:", err)
-		return err
-	}
+	registry := Default()
 
-	//_, err = gcron.AddSingleton(ctx, syncViewCountJob.Pattern, syncViewCountJob.Function, syncViewCountJob.Name)
-	//if err != nil {
-	//	glog.Error(ctx, "添加浏览量同步任务失败:", err)
-	//	return err
-	//}
+	jobs := []*CronJob{
+		NewTmpFileCleanJob(ctx),
+		NewSyncViewCountJob(ctx),
+		NewSyncCreatorRevenueJob(ctx),
+		NewReconcilePaymentOrdersJob(ctx),
+		NewPaymentReconcileReportJob(ctx),
+		NewMonthlyBillingJob(ctx),
+		NewStoragePurgeJob(ctx),
+		NewTrafficRollupJob(ctx),
+		NewUsageRollupJob(ctx),
+	}
+	for _, job := range jobs {
+		if err := registry.Register(job); err != nil {
+			glog.Error(ctx, "注册定时任务失败:", err)
+			return err
+		}
+	}
 
+	if err := registry.Start(ctx); err != nil {
+		glog.Error(ctx, "启动定时任务失败:", err)
+		return err
+	}
 	return nil
 }
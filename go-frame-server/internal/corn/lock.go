@@ -0,0 +1,54 @@
+package cron
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/util/guid"
+)
+
+const lockKeyPrefix = "cron:lock:"
+
+// 只删除自己持有的锁, 避免锁过期后误删其他实例的锁
+const unlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// TryLock 获取 Redis 分布式锁, 保证多实例部署时同一任务只在一个实例上运行
+// 获取失败时 ok 为 false, 获取成功后需调用 unlock 释放
+func TryLock(ctx context.Context, name string, ttl time.Duration) (unlock func(), ok bool, err error) {
+	key := lockKeyPrefix + name
+	token := guid.S()
+	seconds := int64(ttl / time.Second)
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	v, err := g.Redis().Set(ctx, key, token, gredis.SetOption{
+		TTLOption: gredis.TTLOption{EX: &seconds},
+		NX:        true,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if v.IsNil() {
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		_, err := g.Redis().Eval(context.WithoutCancel(ctx), unlockScript, 1, []string{key}, []any{token})
+		if err != nil {
+			g.Log().Errorf(ctx, "释放定时任务锁失败: %v, 任务: %s", err, name)
+		}
+	}
+	return unlock, true, nil
+}
+
+// IsLocked 任务是否正在某个实例上运行
+func IsLocked(ctx context.Context, name string) (bool, error) {
+	n, err := g.Redis().Exists(ctx, lockKeyPrefix+name)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,25 @@
+package cron
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/logic/billing"
+)
+
+// NewMonthlyBillingJob 定义一个“月度出账任务”
+func NewMonthlyBillingJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "MonthlyBillingJob",
+		Description: "为上一个账期生成月度账单并重置按月计量的用量",
+		Pattern:     "0 30 0 1 * *", // 每月1日0点30分执行
+		Function:    RunMonthlyBilling,
+	}
+}
+
+// RunMonthlyBilling 为上一个账期出账
+func RunMonthlyBilling(ctx context.Context) error {
+	_, err := billing.New().Run(ctx, gtime.Now().AddDate(0, -1, 0).Format("Y-m"))
+	return err
+}
@@ -0,0 +1,25 @@
+package cron
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/logic/reconcile"
+)
+
+// NewPaymentReconcileReportJob 定义一个“支付对账报告任务”
+func NewPaymentReconcileReportJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "PaymentReconcileReportJob",
+		Description: "生成前一天的支付对账报告, 列出本地支付记录与渠道交易的差异",
+		Pattern:     "0 30 2 * * *", // 每天凌晨2点30分执行
+		Function:    GeneratePaymentReconcileReport,
+	}
+}
+
+// GeneratePaymentReconcileReport 生成前一天的支付对账报告
+func GeneratePaymentReconcileReport(ctx context.Context) error {
+	_, err := reconcile.New().Report(ctx, gtime.Now().AddDate(0, 0, -1))
+	return err
+}
@@ -0,0 +1,23 @@
+package cron
+
+import (
+	"context"
+
+	"kgplatform-backend/internal/logic/reconcile"
+)
+
+// NewReconcilePaymentOrdersJob 定义一个“支付订单对账任务”
+func NewReconcilePaymentOrdersJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "ReconcilePaymentOrdersJob",
+		Description: "向支付渠道查询长时间未支付的订单, 补处理丢失的支付通知并关闭过期订单",
+		Pattern:     "0 */5 * * * *", // 每5分钟执行一次
+		Function:    ReconcilePaymentOrders,
+	}
+}
+
+// ReconcilePaymentOrders 对账待支付订单
+func ReconcilePaymentOrders(ctx context.Context) error {
+	_, err := reconcile.New().Orders(ctx)
+	return err
+}
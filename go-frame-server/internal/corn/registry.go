@@ -0,0 +1,191 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// ErrJobRunning 任务正在其他实例或本实例上运行
+var ErrJobRunning = gerror.New("定时任务正在运行中")
+
+// Registry 定时任务注册表, 统一负责调度、分布式锁和运行记录
+type Registry struct {
+	mu   sync.RWMutex
+	jobs []*CronJob
+}
+
+var defaultRegistry = &Registry{}
+
+// Default 获取全局定时任务注册表
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register 注册定时任务, 任务名不能重复
+func (r *Registry) Register(job *CronJob) error {
+	if job == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, item := range r.jobs {
+		if item.Name == job.Name {
+			return gerror.Newf("定时任务重复注册: %s", job.Name)
+		}
+	}
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+// Start 将已注册的任务添加到 gcron
+func (r *Registry) Start(ctx context.Context) error {
+	for _, job := range r.Jobs() {
+		_, err := gcron.AddSingleton(ctx, job.Pattern, func(ctx context.Context) {
+			if _, err := r.run(ctx, job, consts.CronTriggerSchedule, 0, false); err != nil && err != ErrJobRunning {
+				g.Log().Errorf(ctx, "定时任务启动失败: %v, 任务: %s", err, job.Name)
+			}
+		}, job.Name)
+		if err != nil {
+			return gerror.Wrapf(err, "添加定时任务失败: %s", job.Name)
+		}
+		g.Log().Infof(ctx, "已添加定时任务: %s (%s)", job.Name, job.Pattern)
+	}
+	return nil
+}
+
+// Jobs 按注册顺序返回所有任务
+func (r *Registry) Jobs() []*CronJob {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	jobs := make([]*CronJob, len(r.jobs))
+	copy(jobs, r.jobs)
+	return jobs
+}
+
+// Get 按名称获取任务
+func (r *Registry) Get(name string) *CronJob {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, job := range r.jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+// Trigger 手动触发任务, 任务在后台运行, 返回运行记录ID
+func (r *Registry) Trigger(ctx context.Context, name string, userId int) (int, error) {
+	job := r.Get(name)
+	if job == nil {
+		return 0, gerror.Newf("定时任务不存在: %s", name)
+	}
+	return r.run(gctx.NeverDone(ctx), job, consts.CronTriggerManual, userId, true)
+}
+
+// run 获取锁并记录运行结果, async 为 true 时获取锁后在后台执行任务
+func (r *Registry) run(ctx context.Context, job *CronJob, trigger string, userId int, async bool) (int, error) {
+	unlock, ok, err := TryLock(ctx, job.Name, job.lockTTL(ctx))
+	if err != nil {
+		return 0, gerror.Wrap(err, "获取定时任务锁失败")
+	}
+	if !ok {
+		g.Log().Debugf(ctx, "定时任务正在运行, 跳过本次执行: %s", job.Name)
+		return 0, ErrJobRunning
+	}
+
+	start := gtime.Now()
+	runId, err := dao.CronJobRuns.Ctx(ctx).Data(g.Map{
+		"job_name":     job.Name,
+		"trigger_type": trigger,
+		"triggered_by": userId,
+		"instance":     instanceName(),
+		"status":       consts.CronRunStatusRunning,
+		"start_time":   start,
+		"created_at":   start,
+	}).InsertAndGetId()
+	if err != nil {
+		unlock()
+		return 0, gerror.Wrap(err, "创建定时任务运行记录失败")
+	}
+
+	execute := func() {
+		defer unlock()
+		jobErr := job.execute(ctx)
+
+		finish := gtime.Now()
+		data := g.Map{
+			"status":      consts.CronRunStatusSuccess,
+			"finish_time": finish,
+			"duration_ms": finish.Sub(start).Milliseconds(),
+		}
+		if jobErr != nil {
+			g.Log().Errorf(ctx, "定时任务运行失败: %v, 任务: %s", jobErr, job.Name)
+			data["status"] = consts.CronRunStatusFailed
+			data["error_message"] = jobErr.Error()
+		}
+		if _, err := dao.CronJobRuns.Ctx(ctx).Where("id", runId).Update(data); err != nil {
+			g.Log().Errorf(ctx, "更新定时任务运行记录失败: %v, 任务: %s", err, job.Name)
+		}
+	}
+	if async {
+		go execute()
+	} else {
+		execute()
+	}
+	return int(runId), nil
+}
+
+// LastRuns 获取每个任务最近一次的运行记录
+func (r *Registry) LastRuns(ctx context.Context) (map[string]*entity.CronJobRuns, error) {
+	var list []*entity.CronJobRuns
+	err := dao.CronJobRuns.Ctx(ctx).
+		Where("id IN (?)", dao.CronJobRuns.Ctx(ctx).Fields("MAX(id)").Group("job_name")).
+		Scan(&list)
+	if err != nil {
+		return nil, err
+	}
+	lastRuns := make(map[string]*entity.CronJobRuns, len(list))
+	for _, run := range list {
+		lastRuns[run.JobName] = run
+	}
+	return lastRuns, nil
+}
+
+// execute 执行任务, 将 panic 转为错误避免影响运行记录
+func (job *CronJob) execute(ctx context.Context) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = gerror.Newf("panic: %v\n%s", e, debug.Stack())
+		}
+	}()
+	return job.Function(ctx)
+}
+
+// lockTTL 锁的过期时间, 需大于任务的最长运行时间
+func (job *CronJob) lockTTL(ctx context.Context) time.Duration {
+	if job.LockTTL > 0 {
+		return job.LockTTL
+	}
+	return time.Duration(g.Cfg().MustGet(ctx, "cron.lockTTL", 3600).Int()) * time.Second
+}
+
+// instanceName 当前实例标识
+func instanceName() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
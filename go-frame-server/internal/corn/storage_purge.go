@@ -0,0 +1,23 @@
+package cron
+
+import (
+	"context"
+
+	"kgplatform-backend/internal/logic/storage"
+)
+
+// NewStoragePurgeJob 定义一个“存储对象清理任务”
+func NewStoragePurgeJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "StoragePurgeJob",
+		Description: "从云存储中删除已释放的对象, 如被替换的抽取结果",
+		Pattern:     "0 */10 * * * *", // 每10分钟执行一次
+		Function:    PurgeStorage,
+	}
+}
+
+// PurgeStorage 删除已释放的存储对象
+func PurgeStorage(ctx context.Context) error {
+	_, err := storage.New().Purge(ctx)
+	return err
+}
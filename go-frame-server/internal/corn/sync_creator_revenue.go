@@ -0,0 +1,23 @@
+package cron
+
+import (
+	"context"
+
+	"kgplatform-backend/internal/logic/revenue"
+)
+
+// NewSyncCreatorRevenueJob 定义一个“创作者收入同步任务”
+func NewSyncCreatorRevenueJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "SyncCreatorRevenueJob",
+		Description: "根据项目购买和退款记录同步创作者收入流水",
+		Pattern:     "0 */10 * * * *", // 每10分钟执行一次
+		Function:    SyncCreatorRevenue,
+	}
+}
+
+// SyncCreatorRevenue 同步创作者收入流水
+func SyncCreatorRevenue(ctx context.Context) error {
+	_, _, err := revenue.New().Sync(ctx)
+	return err
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/gogf/gf/v2/os/glog"
 )
 
-// NewTmpFileCleanJob 初始化临时文件清理任务
+// NewTmpFileCleanJob 初始化临时文件清理任务, 未启用时返回 nil
 func NewTmpFileCleanJob(ctx context.Context) *CronJob {
 	// 从配置中读取清理设置
 	enabled := g.Cfg().MustGet(ctx, "download.cleanup.enabled", true).Bool()
@@ -30,22 +30,23 @@ func NewTmpFileCleanJob(ctx context.Context) *CronJob {
 
 	// 添加定时任务
 	return &CronJob{
-		Name:    "tmp_file_clean",
-		Pattern: cronExpr,
-		Function: func(ctx context.Context) {
-			cleanTmpFiles(ctx, downloadPath, maxAge)
+		Name:        "tmp_file_clean",
+		Description: "清理过期的临时下载文件",
+		Pattern:     cronExpr,
+		Function: func(ctx context.Context) error {
+			return cleanTmpFiles(ctx, downloadPath, maxAge)
 		},
 	}
 }
 
 // cleanTmpFiles 清理临时文件
-func cleanTmpFiles(ctx context.Context, downloadPath string, maxAge int) {
+func cleanTmpFiles(ctx context.Context, downloadPath string, maxAge int) error {
 	logger := g.Log()
 
 	// 确保目录存在
 	if _, err := os.Stat(downloadPath); os.IsNotExist(err) {
 		logger.Info(ctx, "下载目录不存在，无需清理:", downloadPath)
-		return
+		return nil
 	}
 
 	// 遍历目录中的文件
@@ -75,7 +76,8 @@ func cleanTmpFiles(ctx context.Context, downloadPath string, maxAge int) {
 
 	if err != nil {
 		logger.Error(ctx, "遍历目录时出错:", err)
-	} else {
-		logger.Info(ctx, "临时文件清理完成")
+		return err
 	}
+	logger.Info(ctx, "临时文件清理完成")
+	return nil
 }
@@ -0,0 +1,22 @@
+package cron
+
+import (
+	"context"
+
+	"kgplatform-backend/internal/logic/traffic"
+)
+
+// NewTrafficRollupJob 定义一个“流量汇总任务”
+func NewTrafficRollupJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "TrafficRollupJob",
+		Description: "按用户、团队、流量类型和接口汇总每小时的流量",
+		Pattern:     "0 */10 * * * *", // 每10分钟执行一次
+		Function:    RollupTraffic,
+	}
+}
+
+// RollupTraffic 汇总每小时的流量
+func RollupTraffic(ctx context.Context) error {
+	return traffic.New().Rollup(ctx)
+}
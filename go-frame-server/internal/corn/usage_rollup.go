@@ -0,0 +1,22 @@
+package cron
+
+import (
+	"context"
+
+	"kgplatform-backend/internal/logic/usage"
+)
+
+// NewUsageRollupJob 定义一个“用量汇总任务”
+func NewUsageRollupJob(ctx context.Context) *CronJob {
+	return &CronJob{
+		Name:        "UsageRollupJob",
+		Description: "按成员、团队、资源类型、项目和模型汇总每日用量",
+		Pattern:     "0 */10 * * * *", // 每10分钟执行一次
+		Function:    RollupUsage,
+	}
+}
+
+// RollupUsage 汇总每日用量
+func RollupUsage(ctx context.Context) error {
+	return usage.New().Rollup(ctx)
+}
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// couponRedemptionsDao is the data access object for the table coupon_redemptions.
+// You can define custom methods on it to extend its functionality as needed.
+type couponRedemptionsDao struct {
+	*internal.CouponRedemptionsDao
+}
+
+var (
+	// CouponRedemptions is a globally accessible object for table coupon_redemptions operations.
+	CouponRedemptions = couponRedemptionsDao{internal.NewCouponRedemptionsDao()}
+)
+
+// Add your custom methods and functionality below.
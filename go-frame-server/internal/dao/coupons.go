@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// couponsDao is the data access object for the table coupons.
+// You can define custom methods on it to extend its functionality as needed.
+type couponsDao struct {
+	*internal.CouponsDao
+}
+
+var (
+	// Coupons is a globally accessible object for table coupons operations.
+	Coupons = couponsDao{internal.NewCouponsDao()}
+)
+
+// Add your custom methods and functionality below.
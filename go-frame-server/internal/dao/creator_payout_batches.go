@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// creatorPayoutBatchesDao is the data access object for the table creator_payout_batches.
+// You can define custom methods on it to extend its functionality as needed.
+type creatorPayoutBatchesDao struct {
+	*internal.CreatorPayoutBatchesDao
+}
+
+var (
+	// CreatorPayoutBatches is a globally accessible object for table creator_payout_batches operations.
+	CreatorPayoutBatches = creatorPayoutBatchesDao{internal.NewCreatorPayoutBatchesDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// creatorPayoutItemsDao is the data access object for the table creator_payout_items.
+// You can define custom methods on it to extend its functionality as needed.
+type creatorPayoutItemsDao struct {
+	*internal.CreatorPayoutItemsDao
+}
+
+var (
+	// CreatorPayoutItems is a globally accessible object for table creator_payout_items operations.
+	CreatorPayoutItems = creatorPayoutItemsDao{internal.NewCreatorPayoutItemsDao()}
+)
+
+// Add your custom methods and functionality below.
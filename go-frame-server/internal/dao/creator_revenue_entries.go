@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// creatorRevenueEntriesDao is the data access object for the table creator_revenue_entries.
+// You can define custom methods on it to extend its functionality as needed.
+type creatorRevenueEntriesDao struct {
+	*internal.CreatorRevenueEntriesDao
+}
+
+var (
+	// CreatorRevenueEntries is a globally accessible object for table creator_revenue_entries operations.
+	CreatorRevenueEntries = creatorRevenueEntriesDao{internal.NewCreatorRevenueEntriesDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// cronJobRunsDao is the data access object for the table cron_job_runs.
+// You can define custom methods on it to extend its functionality as needed.
+type cronJobRunsDao struct {
+	*internal.CronJobRunsDao
+}
+
+var (
+	// CronJobRuns is a globally accessible object for table cron_job_runs operations.
+	CronJobRuns = cronJobRunsDao{internal.NewCronJobRunsDao()}
+)
+
+// Add your custom methods and functionality below.
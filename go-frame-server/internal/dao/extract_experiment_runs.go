@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// extractExperimentRunsDao is the data access object for the table extract_experiment_runs.
+// You can define custom methods on it to extend its functionality as needed.
+type extractExperimentRunsDao struct {
+	*internal.ExtractExperimentRunsDao
+}
+
+var (
+	// ExtractExperimentRuns is a globally accessible object for table extract_experiment_runs operations.
+	ExtractExperimentRuns = extractExperimentRunsDao{internal.NewExtractExperimentRunsDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// extractExperimentsDao is the data access object for the table extract_experiments.
+// You can define custom methods on it to extend its functionality as needed.
+type extractExperimentsDao struct {
+	*internal.ExtractExperimentsDao
+}
+
+var (
+	// ExtractExperiments is a globally accessible object for table extract_experiments operations.
+	ExtractExperiments = extractExperimentsDao{internal.NewExtractExperimentsDao()}
+)
+
+// Add your custom methods and functionality below.
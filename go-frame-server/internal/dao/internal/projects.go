@@ -41,6 +41,9 @@ type ProjectsColumns struct {
 	TripleUrl        string //
 	Description      string //
 	ExtractConfig    string //
+	Forkable         string // 是否允许未购买的用户克隆
+	ForkedFrom       string // 克隆来源项目ID
+	ForkedFromUserId string // 克隆来源项目的所有者
 }
 
 // projectsColumns holds the columns for the table projects.
@@ -65,6 +68,9 @@ var projectsColumns = ProjectsColumns{
 	TripleUrl:        "triple_url",
 	Description:      "description",
 	ExtractConfig:    "extract_config",
+	Forkable:         "forkable",
+	ForkedFrom:       "forked_from",
+	ForkedFromUserId: "forked_from_user_id",
 }
 
 // NewProjectsDao creates and returns a new DAO object for table data access.
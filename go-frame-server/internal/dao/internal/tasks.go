@@ -32,6 +32,11 @@ type TasksColumns struct {
 	MaterialIdList string // 任务需处理的材料
 	ErrorMessage   string // 任务失败日志
 	ProjectId      string //
+	PyTaskId       string // Python服务任务ID
+	Results        string // 各素材的抽取结果
+	ParentTaskId   string // 重试任务对应的原任务ID
+	ExtractConfig  string // 创建任务时的抽取配置
+	ScheduleId     string // 创建该任务的定时运行计划ID
 }
 
 // tasksColumns holds the columns for the table tasks.
@@ -47,6 +52,11 @@ var tasksColumns = TasksColumns{
 	MaterialIdList: "material_id_list",
 	ErrorMessage:   "error_message",
 	ProjectId:      "project_id",
+	PyTaskId:       "py_task_id",
+	Results:        "results",
+	ParentTaskId:   "parent_task_id",
+	ExtractConfig:  "extract_config",
+	ScheduleId:     "schedule_id",
 }
 
 // NewTasksDao creates and returns a new DAO object for table data access.
@@ -21,28 +21,32 @@ type UserProjectPurchasesDao struct {
 
 // UserProjectPurchasesColumns defines and stores column names for the table user_project_purchases.
 type UserProjectPurchasesColumns struct {
-	Id            string //
-	UserId        string //
-	ProjectId     string //
-	BillingId     string //
-	PaymentId     string //
-	PurchasePrice string //
-	Status        string //
-	CreatedAt     string //
-	UpdatedAt     string //
+	Id             string //
+	UserId         string //
+	ProjectId      string //
+	BillingId      string //
+	PaymentId      string //
+	PurchasePrice  string //
+	PurchaseType   string // 购买类型, read-阅读权限, buy-购买
+	CommissionRate string // 购买时的平台抽成比例, 为空时按当前配置
+	Status         string //
+	CreatedAt      string //
+	UpdatedAt      string //
 }
 
 // userProjectPurchasesColumns holds the columns for the table user_project_purchases.
 var userProjectPurchasesColumns = UserProjectPurchasesColumns{
-	Id:            "id",
-	UserId:        "user_id",
-	ProjectId:     "project_id",
-	BillingId:     "billing_id",
-	PaymentId:     "payment_id",
-	PurchasePrice: "purchase_price",
-	Status:        "status",
-	CreatedAt:     "created_at",
-	UpdatedAt:     "updated_at",
+	Id:             "id",
+	UserId:         "user_id",
+	ProjectId:      "project_id",
+	BillingId:      "billing_id",
+	PaymentId:      "payment_id",
+	PurchasePrice:  "purchase_price",
+	PurchaseType:   "purchase_type",
+	CommissionRate: "commission_rate",
+	Status:         "status",
+	CreatedAt:      "created_at",
+	UpdatedAt:      "updated_at",
 }
 
 // NewUserProjectPurchasesDao creates and returns a new DAO object for table data access.
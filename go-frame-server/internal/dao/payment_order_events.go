@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// paymentOrderEventsDao is the data access object for the table payment_order_events.
+// You can define custom methods on it to extend its functionality as needed.
+type paymentOrderEventsDao struct {
+	*internal.PaymentOrderEventsDao
+}
+
+var (
+	// PaymentOrderEvents is a globally accessible object for table payment_order_events operations.
+	PaymentOrderEvents = paymentOrderEventsDao{internal.NewPaymentOrderEventsDao()}
+)
+
+// Add your custom methods and functionality below.
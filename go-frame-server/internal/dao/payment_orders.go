@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// paymentOrdersDao is the data access object for the table payment_orders.
+// You can define custom methods on it to extend its functionality as needed.
+type paymentOrdersDao struct {
+	*internal.PaymentOrdersDao
+}
+
+var (
+	// PaymentOrders is a globally accessible object for table payment_orders operations.
+	PaymentOrders = paymentOrdersDao{internal.NewPaymentOrdersDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// paymentReconcileItemsDao is the data access object for the table payment_reconcile_items.
+// You can define custom methods on it to extend its functionality as needed.
+type paymentReconcileItemsDao struct {
+	*internal.PaymentReconcileItemsDao
+}
+
+var (
+	// PaymentReconcileItems is a globally accessible object for table payment_reconcile_items operations.
+	PaymentReconcileItems = paymentReconcileItemsDao{internal.NewPaymentReconcileItemsDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// paymentReconcileReportsDao is the data access object for the table payment_reconcile_reports.
+// You can define custom methods on it to extend its functionality as needed.
+type paymentReconcileReportsDao struct {
+	*internal.PaymentReconcileReportsDao
+}
+
+var (
+	// PaymentReconcileReports is a globally accessible object for table payment_reconcile_reports operations.
+	PaymentReconcileReports = paymentReconcileReportsDao{internal.NewPaymentReconcileReportsDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// paymentRefundsDao is the data access object for the table payment_refunds.
+// You can define custom methods on it to extend its functionality as needed.
+type paymentRefundsDao struct {
+	*internal.PaymentRefundsDao
+}
+
+var (
+	// PaymentRefunds is a globally accessible object for table payment_refunds operations.
+	PaymentRefunds = paymentRefundsDao{internal.NewPaymentRefundsDao()}
+)
+
+// Add your custom methods and functionality below.
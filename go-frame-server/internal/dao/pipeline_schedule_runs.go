@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// pipelineScheduleRunsDao is the data access object for the table pipeline_schedule_runs.
+// You can define custom methods on it to extend its functionality as needed.
+type pipelineScheduleRunsDao struct {
+	*internal.PipelineScheduleRunsDao
+}
+
+var (
+	// PipelineScheduleRuns is a globally accessible object for table pipeline_schedule_runs operations.
+	PipelineScheduleRuns = pipelineScheduleRunsDao{internal.NewPipelineScheduleRunsDao()}
+)
+
+// Add your custom methods and functionality below.
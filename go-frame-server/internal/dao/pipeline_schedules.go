@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// pipelineSchedulesDao is the data access object for the table pipeline_schedules.
+// You can define custom methods on it to extend its functionality as needed.
+type pipelineSchedulesDao struct {
+	*internal.PipelineSchedulesDao
+}
+
+var (
+	// PipelineSchedules is a globally accessible object for table pipeline_schedules operations.
+	PipelineSchedules = pipelineSchedulesDao{internal.NewPipelineSchedulesDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// projectViewDailyDao is the data access object for the table project_view_daily.
+// You can define custom methods on it to extend its functionality as needed.
+type projectViewDailyDao struct {
+	*internal.ProjectViewDailyDao
+}
+
+var (
+	// ProjectViewDaily is a globally accessible object for table project_view_daily operations.
+	ProjectViewDaily = projectViewDailyDao{internal.NewProjectViewDailyDao()}
+)
+
+// Add your custom methods and functionality below.
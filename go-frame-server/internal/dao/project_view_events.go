@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// projectViewEventsDao is the data access object for the table project_view_events.
+// You can define custom methods on it to extend its functionality as needed.
+type projectViewEventsDao struct {
+	*internal.ProjectViewEventsDao
+}
+
+var (
+	// ProjectViewEvents is a globally accessible object for table project_view_events operations.
+	ProjectViewEvents = projectViewEventsDao{internal.NewProjectViewEventsDao()}
+)
+
+// Add your custom methods and functionality below.
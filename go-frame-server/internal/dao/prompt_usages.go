@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// promptUsagesDao is the data access object for the table prompt_usages.
+// You can define custom methods on it to extend its functionality as needed.
+type promptUsagesDao struct {
+	*internal.PromptUsagesDao
+}
+
+var (
+	// PromptUsages is a globally accessible object for table prompt_usages operations.
+	PromptUsages = promptUsagesDao{internal.NewPromptUsagesDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// promptVersionsDao is the data access object for the table prompt_versions.
+// You can define custom methods on it to extend its functionality as needed.
+type promptVersionsDao struct {
+	*internal.PromptVersionsDao
+}
+
+var (
+	// PromptVersions is a globally accessible object for table prompt_versions operations.
+	PromptVersions = promptVersionsDao{internal.NewPromptVersionsDao()}
+)
+
+// Add your custom methods and functionality below.
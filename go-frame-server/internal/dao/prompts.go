@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// promptsDao is the data access object for the table prompts.
+// You can define custom methods on it to extend its functionality as needed.
+type promptsDao struct {
+	*internal.PromptsDao
+}
+
+var (
+	// Prompts is a globally accessible object for table prompts operations.
+	Prompts = promptsDao{internal.NewPromptsDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// pythonCallbackEventsDao is the data access object for the table python_callback_events.
+// You can define custom methods on it to extend its functionality as needed.
+type pythonCallbackEventsDao struct {
+	*internal.PythonCallbackEventsDao
+}
+
+var (
+	// PythonCallbackEvents is a globally accessible object for table python_callback_events operations.
+	PythonCallbackEvents = pythonCallbackEventsDao{internal.NewPythonCallbackEventsDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// storageObjectsDao is the data access object for the table storage_objects.
+// You can define custom methods on it to extend its functionality as needed.
+type storageObjectsDao struct {
+	*internal.StorageObjectsDao
+}
+
+var (
+	// StorageObjects is a globally accessible object for table storage_objects operations.
+	StorageObjects = storageObjectsDao{internal.NewStorageObjectsDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// subscriptionChangesDao is the data access object for the table subscription_changes.
+// You can define custom methods on it to extend its functionality as needed.
+type subscriptionChangesDao struct {
+	*internal.SubscriptionChangesDao
+}
+
+var (
+	// SubscriptionChanges is a globally accessible object for table subscription_changes operations.
+	SubscriptionChanges = subscriptionChangesDao{internal.NewSubscriptionChangesDao()}
+)
+
+// Add your custom methods and functionality below.
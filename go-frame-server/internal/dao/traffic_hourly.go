@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// trafficHourlyDao is the data access object for the table traffic_hourly.
+// You can define custom methods on it to extend its functionality as needed.
+type trafficHourlyDao struct {
+	*internal.TrafficHourlyDao
+}
+
+var (
+	// TrafficHourly is a globally accessible object for table traffic_hourly operations.
+	TrafficHourly = trafficHourlyDao{internal.NewTrafficHourlyDao()}
+)
+
+// Add your custom methods and functionality below.
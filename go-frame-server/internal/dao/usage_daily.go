@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// usageDailyDao is the data access object for the table usage_daily.
+// You can define custom methods on it to extend its functionality as needed.
+type usageDailyDao struct {
+	*internal.UsageDailyDao
+}
+
+var (
+	// UsageDaily is a globally accessible object for table usage_daily operations.
+	UsageDaily = usageDailyDao{internal.NewUsageDailyDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"kgplatform-backend/internal/dao/internal"
+)
+
+// usageEventsDao is the data access object for the table usage_events.
+// You can define custom methods on it to extend its functionality as needed.
+type usageEventsDao struct {
+	*internal.UsageEventsDao
+}
+
+var (
+	// UsageEvents is a globally accessible object for table usage_events operations.
+	UsageEvents = usageEventsDao{internal.NewUsageEventsDao()}
+)
+
+// Add your custom methods and functionality below.
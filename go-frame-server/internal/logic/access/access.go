@@ -0,0 +1,125 @@
+package access
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Level 用户对项目的访问级别, 级别越高权限越大
+type Level int
+
+const (
+	LevelNone    Level = iota // 无权访问: 私有项目的非所有者
+	LevelPreview              // 预览: 公开项目的未购买用户, 只能查看受限的子图, 不含溯源原文
+	LevelRead                 // 阅读: 已购买阅读权限, 可在线浏览和查询完整图谱
+	LevelBuy                  // 购买: 已购买项目, 可导出和克隆
+	LevelOwner                // 项目所有者
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelPreview:
+		return "preview"
+	case LevelRead:
+		return "read"
+	case LevelBuy:
+		return "buy"
+	case LevelOwner:
+		return "owner"
+	default:
+		return "none"
+	}
+}
+
+// Access 项目市场的访问控制
+type Access struct{}
+
+func New() *Access {
+	return &Access{}
+}
+
+// GetLevel 获取用户对项目的访问级别, userId 为 0 表示未登录
+func (a *Access) GetLevel(ctx context.Context, userId int, projectId int) (Level, *entity.Projects, error) {
+	var project *entity.Projects
+	if err := dao.Projects.Ctx(ctx).Where("id", projectId).Scan(&project); err != nil {
+		return LevelNone, nil, err
+	}
+	if project == nil {
+		return LevelNone, nil, gerror.NewCode(gcode.CodeNotFound, "项目不存在")
+	}
+	if userId > 0 && project.UserId == userId {
+		return LevelOwner, project, nil
+	}
+	if project.Visibility != 1 {
+		return LevelNone, project, nil
+	}
+
+	// 免费的权限直接开放
+	level := LevelPreview
+	if project.ReadPriceCent <= 0 {
+		level = LevelRead
+	}
+	if project.BuyPriceCent <= 0 {
+		level = LevelBuy
+	}
+	if level == LevelBuy || userId == 0 {
+		return level, project, nil
+	}
+
+	purchaseTypes, err := dao.UserProjectPurchases.Ctx(ctx).
+		Where("user_id", userId).
+		Where("project_id", projectId).
+		Where("status", consts.PurchaseStatusCompleted).
+		Array("purchase_type")
+	if err != nil {
+		return LevelNone, project, err
+	}
+	for _, purchaseType := range purchaseTypes {
+		switch purchaseType.String() {
+		case consts.PurchaseTypeBuy:
+			level = LevelBuy
+		case consts.PurchaseTypeRead:
+			if level < LevelRead {
+				level = LevelRead
+			}
+		}
+	}
+	return level, project, nil
+}
+
+// Check 校验用户至少具有 need 级别的访问权限, 返回实际的访问级别
+func (a *Access) Check(ctx context.Context, userId int, projectId int, need Level) (Level, *entity.Projects, error) {
+	level, project, err := a.GetLevel(ctx, userId, projectId)
+	if err != nil {
+		return level, project, err
+	}
+	if level >= need {
+		return level, project, nil
+	}
+
+	switch need {
+	case LevelRead:
+		if level == LevelPreview {
+			return level, project, gerror.NewCode(gcode.CodeNotAuthorized, "购买阅读权限后可查看完整内容")
+		}
+	case LevelBuy:
+		if level >= LevelPreview {
+			return level, project, gerror.NewCode(gcode.CodeNotAuthorized, "购买项目后可导出和克隆")
+		}
+	case LevelOwner:
+		return level, project, gerror.NewCode(gcode.CodeNotAuthorized, "无权操作该项目")
+	}
+	return level, project, gerror.NewCode(gcode.CodeNotAuthorized, "项目不可见或不存在")
+}
+
+// PreviewLimit 预览时最多返回的节点或三元组数量
+func PreviewLimit(ctx context.Context) int {
+	return g.Cfg().MustGet(ctx, "access.previewLimit", 50).Int()
+}
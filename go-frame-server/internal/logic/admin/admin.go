@@ -0,0 +1,33 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// IsAdmin 判断用户是否为 admin.userIds 配置中的管理员
+func IsAdmin(ctx context.Context, userId int) bool {
+	if userId == 0 {
+		return false
+	}
+	for _, id := range g.Cfg().MustGet(ctx, "admin.userIds").Ints() {
+		if id == userId {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 校验当前登录用户为管理员, 返回用户ID
+func Check(ctx context.Context) (int, error) {
+	userId := g.RequestFromCtx(ctx).GetCtxVar("userID").Int()
+	if userId == 0 {
+		return 0, gerror.New("请先登录")
+	}
+	if !IsAdmin(ctx, userId) {
+		return 0, gerror.New("无权访问")
+	}
+	return userId, nil
+}
@@ -0,0 +1,334 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Billing 月度出账: 每个账期结束后按套餐月费和当期超额用量为每个账户生成月度账单
+type Billing struct{}
+
+func New() *Billing {
+	return &Billing{}
+}
+
+// RunResult 一次出账的结果
+type RunResult struct {
+	Period      string  `json:"period" dc:"账期"`
+	Accounts    int     `json:"accounts" dc:"处理的账户数"`
+	Created     int     `json:"created" dc:"新生成的账单数"`
+	Updated     int     `json:"updated" dc:"重新计算的未支付账单数"`
+	Skipped     int     `json:"skipped" dc:"无需出账或已支付的账户数"`
+	Failed      int     `json:"failed" dc:"出账失败的账户数"`
+	TotalAmount float64 `json:"totalAmount" dc:"本次生成和更新的账单应付总额"`
+}
+
+// accountRow 待出账的账户
+type accountRow struct {
+	UserId  int64  `orm:"user_id"`
+	TeamId  int64  `orm:"team_id"`
+	Plan    string `orm:"user_plan"`
+	Members int    `orm:"members"`
+}
+
+// Run 为账期出账, 可重复执行: 未支付的账单按记录的超额用量重新计算, 已支付的账单不变
+// 首次出账时清零当期的字数、算力和流量用量, 存储为占用量不清零
+func (b *Billing) Run(ctx context.Context, period string) (*RunResult, error) {
+	start, err := periodStart(period)
+	if err != nil {
+		return nil, err
+	}
+	nextStart := start.AddDate(0, 1, 0)
+	if nextStart.After(gtime.Now()) {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "账期尚未结束, 不能出账")
+	}
+
+	// 本账期尚未结算的订阅, 以及本账期已有未支付月度账单的账户
+	var accounts []*accountRow
+	err = g.DB().GetScan(ctx, &accounts, `
+		select us.user_id, coalesce(t.id, 0) as team_id, us.user_plan, coalesce(t.member_count, 1) as members
+		from user_subscriptions us
+		left join teams t on t.id = us.team_id and t.owner_id = us.user_id and t.status = 'active'
+		where (us.team_id is null or t.id is not null)
+		  and ((us.subscription_status = 'active' and us.quota_reset_date <= ?)
+		    or exists (select 1 from billing_records br
+		               where br.user_id = us.user_id and br.billing_period = ?
+		                 and br.billing_type = 'month' and br.status = 'unpaid'))
+		order by us.user_id`, nextStart.Format("Y-m-d"), period)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询待出账账户失败")
+	}
+
+	result := &RunResult{Period: period, Accounts: len(accounts)}
+	for _, account := range accounts {
+		bill, created, err := b.billAccount(ctx, period, nextStart, account)
+		switch {
+		case err != nil:
+			g.Log().Errorf(ctx, "账户出账失败: %v, 用户: %d, 团队: %d, 账期: %s", err, account.UserId, account.TeamId, period)
+			result.Failed++
+		case bill == nil:
+			result.Skipped++
+		case created:
+			result.Created++
+			result.TotalAmount = round2(result.TotalAmount + bill.Total)
+		default:
+			result.Updated++
+			result.TotalAmount = round2(result.TotalAmount + bill.Total)
+		}
+	}
+	g.Log().Infof(ctx, "账期 %s 出账完成: 账户 %d, 新账单 %d, 更新 %d, 跳过 %d, 失败 %d, 应付总额 %.2f",
+		period, result.Accounts, result.Created, result.Updated, result.Skipped, result.Failed, result.TotalAmount)
+	return result, nil
+}
+
+// billAccount 在一个事务内为账户出账, 返回 nil 账单表示无需出账
+func (b *Billing) billAccount(ctx context.Context, period string, nextStart *gtime.Time, row *accountRow) (bill *Bill, created bool, err error) {
+	err = dao.BillingRecords.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var sub *entity.UserSubscriptions
+		err := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", row.UserId).LockUpdate().Scan(&sub)
+		if err != nil || sub == nil {
+			return err
+		}
+
+		var record *entity.BillingRecords
+		err = monthRecordModel(ctx, tx, row.UserId, row.TeamId, period).LockUpdate().Scan(&record)
+		if err != nil {
+			return err
+		}
+
+		account := &Account{UserId: row.UserId, TeamId: row.TeamId, Plan: row.Plan, Members: row.Members}
+		switch {
+		case record != nil && (record.Status == "paid" || record.Status == "refunded"):
+			// 已退款的账单视为已结清, 不再重新出账
+			return nil
+		case record != nil:
+			amounts, err := recordedOverage(ctx, tx, record.Id)
+			if err != nil {
+				return err
+			}
+			bill = computeWithOverage(ctx, period, account, amounts)
+		case sub.QuotaResetDate != nil && sub.QuotaResetDate.After(nextStart):
+			// 本账期已结算且无需账单
+			return nil
+		default:
+			if err = loadUsage(ctx, tx, account, sub); err != nil {
+				return err
+			}
+			if err = applyScheduled(ctx, tx, account, nextStart); err != nil {
+				return err
+			}
+			bill = Compute(ctx, period, account)
+			created = true
+			if err = closePeriod(ctx, tx, account, nextStart); err != nil {
+				return err
+			}
+			if bill.Total <= 0 {
+				bill = nil
+				return nil
+			}
+		}
+		return saveBill(ctx, tx, record, bill)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return bill, created, nil
+}
+
+// loadUsage 读取账户当期的用量和配额, 团队使用团队的共享配额
+func loadUsage(ctx context.Context, tx gdb.TX, account *Account, sub *entity.UserSubscriptions) error {
+	if account.TeamId == 0 {
+		setUsage(ctx, account, sub, nil)
+		return nil
+	}
+
+	var team *entity.Teams
+	err := dao.Teams.Ctx(ctx).TX(tx).Where("id", account.TeamId).LockUpdate().Scan(&team)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return gerror.Newf("团队不存在: %d", account.TeamId)
+	}
+	setUsage(ctx, account, sub, team)
+	return nil
+}
+
+// setUsage 设置账户的用量和配额, team 为空时按个人套餐计算
+func setUsage(ctx context.Context, account *Account, sub *entity.UserSubscriptions, team *entity.Teams) {
+	if team == nil {
+		account.Quota = PlanQuota(ctx, account.Plan)
+		account.Used = Usage{
+			Words:   float64(sub.WordsUsed),
+			Storage: float64(sub.StorageUsed),
+			Traffic: sub.TrafficUsed,
+			Cu:      float64(sub.CuUsed),
+		}
+		return
+	}
+	account.Quota = Usage{
+		Words:   float64(team.TotalWordsQuota),
+		Storage: float64(team.TotalStorageQuota),
+		Traffic: float64(team.TotalTrafficQuota),
+		Cu:      float64(team.TotalCuQuota),
+	}
+	account.Used = Usage{
+		Words:   float64(team.WordsUsed),
+		Storage: float64(team.StorageUsed),
+		Traffic: team.TrafficUsed,
+		Cu:      float64(team.CuUsed),
+	}
+}
+
+// closePeriod 结束账户的当期: 清零按月计量的用量和用量提醒, 下次结算日为下一账期结束时
+func closePeriod(ctx context.Context, tx gdb.TX, account *Account, nextStart *gtime.Time) error {
+	_, err := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", account.UserId).Data(g.Map{
+		"words_used":               0,
+		"cu_used":                  0,
+		"traffic_used":             0,
+		"words_warning_80_sent":    false,
+		"words_warning_100_sent":   false,
+		"cu_warning_80_sent":       false,
+		"cu_warning_100_sent":      false,
+		"traffic_warning_80_sent":  false,
+		"traffic_warning_100_sent": false,
+		"overage_words_fee":        0,
+		"overage_storage_fee":      0,
+		"overage_traffic_fee":      0,
+		"overage_cu_fee":           0,
+		"total_overage_fee":        0,
+		"quota_reset_date":         nextStart.AddDate(0, 1, 0).Format("Y-m-d"),
+		"updated_at":               gtime.Now(),
+	}).Update()
+	if err != nil || account.TeamId == 0 {
+		return err
+	}
+
+	_, err = dao.Teams.Ctx(ctx).TX(tx).Where("id", account.TeamId).Data(g.Map{
+		"words_used":   0,
+		"cu_used":      0,
+		"traffic_used": 0,
+		"updated_at":   gtime.Now(),
+	}).Update()
+	if err != nil {
+		return err
+	}
+	_, err = dao.TeamMembers.Ctx(ctx).TX(tx).Where("team_id", account.TeamId).Data(g.Map{
+		"personal_words_used":   0,
+		"personal_cu_used":      0,
+		"personal_traffic_used": 0,
+	}).Update()
+	return err
+}
+
+// saveBill 写入或更新月度账单、超额明细, 并为账单创建发票记录
+func saveBill(ctx context.Context, tx gdb.TX, record *entity.BillingRecords, bill *Bill) error {
+	now := gtime.Now()
+	data := g.Map{
+		"base_subscription_fee": bill.BaseFee,
+		"overage_fee":           bill.OverageFee,
+		"subtotal":              bill.Subtotal,
+		"discount_amount":       bill.Discount,
+		"total_amount":          bill.Total,
+		"remark":                billRemark(bill),
+		"updated_at":            now,
+	}
+
+	var billingId int64
+	if record != nil {
+		billingId = record.Id
+		if _, err := dao.BillingRecords.Ctx(ctx).TX(tx).Where("id", billingId).Data(data).Update(); err != nil {
+			return err
+		}
+	} else {
+		data["user_id"] = bill.UserId
+		if bill.TeamId > 0 {
+			data["team_id"] = bill.TeamId
+		}
+		data["billing_period"] = bill.Period
+		data["billing_date"] = now.Format("Y-m-d")
+		data["billing_type"] = "month"
+		data["status"] = "unpaid"
+		data["created_at"] = now
+		id, err := dao.BillingRecords.Ctx(ctx).TX(tx).Data(data).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		billingId = id
+	}
+
+	overage := g.Map{
+		"billing_id":        billingId,
+		"total_overage_fee": bill.OverageFee,
+		"updated_at":        now,
+	}
+	for _, resource := range resources {
+		overage[resource+"_overage_amount"] = 0
+		overage[resource+"_overage_fee"] = 0
+	}
+	for _, line := range bill.Overages {
+		overage[line.Resource+"_overage_amount"] = line.Amount
+		overage[line.Resource+"_overage_fee"] = line.Fee
+	}
+	if _, err := dao.BillingOverages.Ctx(ctx).TX(tx).Data(overage).OnConflict("billing_id").Save(); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`insert into billing_invoices (billing_id, created_at, updated_at) values (?, now(), now())
+		on conflict (billing_id) do nothing`, billingId)
+	return err
+}
+
+// recordedOverage 读取账单已记录的超额用量
+func recordedOverage(ctx context.Context, tx gdb.TX, billingId int64) (map[string]float64, error) {
+	var overage *entity.BillingOverages
+	if err := dao.BillingOverages.Ctx(ctx).TX(tx).Where("billing_id", billingId).Scan(&overage); err != nil {
+		return nil, err
+	}
+	if overage == nil {
+		return map[string]float64{}, nil
+	}
+	return map[string]float64{
+		ResourceWords:   overage.WordsOverageAmount,
+		ResourceStorage: overage.StorageOverageAmount,
+		ResourceTraffic: overage.TrafficOverageAmount,
+		ResourceCu:      overage.CuOverageAmount,
+	}, nil
+}
+
+// monthRecordModel 账户在账期的月度账单
+func monthRecordModel(ctx context.Context, tx gdb.TX, userId int64, teamId int64, period string) *gdb.Model {
+	m := dao.BillingRecords.Ctx(ctx).TX(tx).
+		Where("user_id", userId).
+		Where("billing_period", period).
+		Where("billing_type", "month")
+	if teamId > 0 {
+		return m.Where("team_id", teamId)
+	}
+	return m.WhereNull("team_id")
+}
+
+func billRemark(bill *Bill) string {
+	remark := "月度账单 " + bill.Period
+	if bill.DiscountReason != "" {
+		remark += ", " + bill.DiscountReason
+	}
+	return remark
+}
+
+// periodStart 账期的第一天
+func periodStart(period string) (*gtime.Time, error) {
+	start, err := gtime.StrToTimeFormat(period+"-01", "Y-m-d")
+	if err != nil {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "账期格式错误, 应为 YYYY-MM")
+	}
+	return start, nil
+}
@@ -0,0 +1,423 @@
+package billing
+
+import (
+	"context"
+	"math"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// planRank 套餐等级, 用于区分升级和降级
+var planRank = map[string]int{
+	consts.PlanFree:         0,
+	consts.PlanProfessional: 1,
+	consts.PlanTeam:         2,
+}
+
+// PlanChangeQuote 套餐变更的报价
+type PlanChangeQuote struct {
+	ChangeType     string      `json:"changeType" dc:"变更类型: upgrade-升级, 补交差价后立即生效; downgrade-降级, 账期结束时生效"`
+	FromPlan       string      `json:"fromPlan" dc:"当前套餐"`
+	FromMembers    int         `json:"fromMembers" dc:"当前人数"`
+	ToPlan         string      `json:"toPlan" dc:"目标套餐"`
+	ToMembers      int         `json:"toMembers" dc:"目标人数"`
+	PeriodEnd      *gtime.Time `json:"periodEnd" dc:"当前账期结束时间, 即配额重置日期"`
+	RemainingRatio float64     `json:"remainingRatio" dc:"账期剩余时间占比"`
+	ProratedAmount float64     `json:"proratedAmount" dc:"升级需补交的差价, 按账期剩余时间折算"`
+	// Superseded 被本次变更取消的变更, 待支付变更的支付订单由调用方关闭
+	Superseded []int `json:"-"`
+	teamId     int64
+}
+
+// QuotePlanChange 计算变更到目标套餐和人数的差价, 不做变更
+func (b *Billing) QuotePlanChange(ctx context.Context, userId int64, plan string, members int) (*PlanChangeQuote, error) {
+	var quote *PlanChangeQuote
+	err := dao.SubscriptionChanges.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		sub, team, err := changeAccount(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+		quote, err = quotePlanChange(ctx, tx, sub, team, plan, members, gtime.Now())
+		return err
+	})
+	return quote, err
+}
+
+// ChangePlan 变更套餐: 升级差价为零时立即生效, 否则等待支付差价; 降级在账期结束时生效
+// 用户同时只有一个未完成的变更, 新的变更会取消之前待支付和待生效的变更, 取消的变更记录在 quote.Superseded
+func (b *Billing) ChangePlan(ctx context.Context, userId int64, plan string, members int) (*entity.SubscriptionChanges, *PlanChangeQuote, error) {
+	var (
+		change *entity.SubscriptionChanges
+		quote  *PlanChangeQuote
+	)
+	err := dao.SubscriptionChanges.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		sub, team, err := changeAccount(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+		now := gtime.Now()
+		if quote, err = quotePlanChange(ctx, tx, sub, team, plan, members, now); err != nil {
+			return err
+		}
+
+		superseded, err := dao.SubscriptionChanges.Ctx(ctx).TX(tx).
+			Where("user_id", userId).
+			WhereIn("status", []string{consts.PlanChangePending, consts.PlanChangeScheduled}).
+			LockUpdate().
+			Fields("id").
+			Array()
+		if err != nil {
+			return err
+		}
+		if len(superseded) > 0 {
+			_, err = dao.SubscriptionChanges.Ctx(ctx).TX(tx).WhereIn("id", superseded).Data(g.Map{
+				"status":     consts.PlanChangeCancelled,
+				"updated_at": now,
+			}).Update()
+			if err != nil {
+				return err
+			}
+			for _, id := range superseded {
+				quote.Superseded = append(quote.Superseded, id.Int())
+			}
+		}
+
+		data := g.Map{
+			"user_id":              userId,
+			"team_id":              quote.teamId,
+			"change_type":          quote.ChangeType,
+			"from_plan":            quote.FromPlan,
+			"from_members":         quote.FromMembers,
+			"to_plan":              quote.ToPlan,
+			"to_members":           quote.ToMembers,
+			"prorated_amount_cent": int64(math.Round(quote.ProratedAmount * 100)),
+			"created_at":           now,
+			"updated_at":           now,
+		}
+		if quote.ChangeType == consts.PlanChangeDowngrade {
+			data["status"] = consts.PlanChangeScheduled
+			data["effective_at"] = quote.PeriodEnd
+		} else {
+			data["status"] = consts.PlanChangePending
+			data["effective_at"] = now
+		}
+		id, err := dao.SubscriptionChanges.Ctx(ctx).TX(tx).Data(data).InsertAndGetId()
+		if err != nil {
+			return gerror.Wrap(err, "创建套餐变更失败")
+		}
+		if err = dao.SubscriptionChanges.Ctx(ctx).TX(tx).Where("id", id).Scan(&change); err != nil {
+			return err
+		}
+
+		if change.ChangeType == consts.PlanChangeUpgrade && change.ProratedAmountCent == 0 {
+			return applyChange(ctx, tx, change, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return change, quote, nil
+}
+
+// CancelPlanChange 取消待支付或待生效的套餐变更, 待支付变更的支付订单由调用方关闭
+func (b *Billing) CancelPlanChange(ctx context.Context, userId int64, changeId int) (*entity.SubscriptionChanges, error) {
+	var change *entity.SubscriptionChanges
+	err := dao.SubscriptionChanges.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		err := dao.SubscriptionChanges.Ctx(ctx).TX(tx).
+			Where("id", changeId).
+			Where("user_id", userId).
+			LockUpdate().
+			Scan(&change)
+		if err != nil {
+			return err
+		}
+		if change == nil || (change.Status != consts.PlanChangePending && change.Status != consts.PlanChangeScheduled) {
+			return gerror.NewCode(gcode.CodeNotFound, "未找到可取消的套餐变更")
+		}
+		_, err = dao.SubscriptionChanges.Ctx(ctx).TX(tx).Where("id", change.Id).Data(g.Map{
+			"status":     consts.PlanChangeCancelled,
+			"updated_at": gtime.Now(),
+		}).Update()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// ListPlanChanges 获取用户的套餐变更记录
+func (b *Billing) ListPlanChanges(ctx context.Context, userId int64, page int, size int) ([]*entity.SubscriptionChanges, int, error) {
+	var (
+		list  []*entity.SubscriptionChanges
+		total int
+	)
+	err := dao.SubscriptionChanges.Ctx(ctx).
+		Where("user_id", userId).
+		OrderDesc("id").
+		Page(page, size).
+		ScanAndCount(&list, &total, false)
+	return list, total, err
+}
+
+// ApplyChange 升级差价支付成功后使变更生效, 与支付订单的状态更新在同一事务内
+// 变更已被取消或被新的变更替代时不再生效并返回 false, 由支付订单退款
+func ApplyChange(ctx context.Context, tx gdb.TX, changeId int, at *gtime.Time) (bool, error) {
+	var change *entity.SubscriptionChanges
+	err := dao.SubscriptionChanges.Ctx(ctx).TX(tx).Where("id", changeId).LockUpdate().Scan(&change)
+	if err != nil {
+		return false, err
+	}
+	if change == nil {
+		return false, gerror.Newf("套餐变更不存在: %d", changeId)
+	}
+	switch change.Status {
+	case consts.PlanChangeApplied:
+		return true, nil
+	case consts.PlanChangePending:
+		return true, applyChange(ctx, tx, change, at)
+	}
+	g.Log().Warningf(ctx, "套餐变更 %d 状态为 %s, 支付的差价将退款", change.Id, change.Status)
+	return false, nil
+}
+
+// RevertChange 已生效的升级差价退款后撤销变更, 订阅和团队恢复为变更前的套餐和人数
+// 变更未生效时不做处理并返回 false
+func RevertChange(ctx context.Context, tx gdb.TX, changeId int) (bool, error) {
+	var change *entity.SubscriptionChanges
+	err := dao.SubscriptionChanges.Ctx(ctx).TX(tx).Where("id", changeId).LockUpdate().Scan(&change)
+	if err != nil {
+		return false, err
+	}
+	if change == nil {
+		return false, gerror.Newf("套餐变更不存在: %d", changeId)
+	}
+	if change.Status != consts.PlanChangeApplied {
+		return false, nil
+	}
+	revert := &entity.SubscriptionChanges{
+		UserId:      change.UserId,
+		TeamId:      change.TeamId,
+		FromPlan:    change.ToPlan,
+		FromMembers: change.ToMembers,
+		ToPlan:      change.FromPlan,
+		ToMembers:   change.FromMembers,
+	}
+	if err = updateAccount(ctx, tx, revert); err != nil {
+		return false, err
+	}
+	_, err = dao.SubscriptionChanges.Ctx(ctx).TX(tx).Where("id", change.Id).Data(g.Map{
+		"status":     consts.PlanChangeReverted,
+		"updated_at": gtime.Now(),
+	}).Update()
+	if err != nil {
+		return false, err
+	}
+	g.Log().Infof(ctx, "用户 %d 套餐变更 %d 已退款, 套餐恢复为 %s(%d人)",
+		change.UserId, change.Id, change.FromPlan, change.FromMembers)
+	return true, nil
+}
+
+// applyScheduled 出账时使账期结束前待生效的降级生效, 账户按降级后的套餐计算下一账期的月费
+// 当期超额仍按账户已读取的降级前配额计算
+func applyScheduled(ctx context.Context, tx gdb.TX, account *Account, nextStart *gtime.Time) error {
+	var change *entity.SubscriptionChanges
+	err := dao.SubscriptionChanges.Ctx(ctx).TX(tx).
+		Where("user_id", account.UserId).
+		Where("status", consts.PlanChangeScheduled).
+		WhereLTE("effective_at", nextStart).
+		OrderDesc("id").
+		Limit(1).
+		Scan(&change)
+	if err != nil || change == nil {
+		return err
+	}
+	if err = applyChange(ctx, tx, change, nextStart); err != nil {
+		return err
+	}
+	account.Plan = change.ToPlan
+	account.Members = change.ToMembers
+	return nil
+}
+
+// applyChange 使变更生效, 个人套餐的配额按套餐配置读取, 变更套餐后立即生效
+func applyChange(ctx context.Context, tx gdb.TX, change *entity.SubscriptionChanges, at *gtime.Time) error {
+	if err := updateAccount(ctx, tx, change); err != nil {
+		return err
+	}
+	// 首次开通的订阅从下一账期开始按月出账
+	_, err := tx.Exec(`update user_subscriptions set quota_reset_date = ?
+		where user_id = ? and (quota_reset_date is null or quota_reset_date <= ?)`,
+		nextPeriodStart(at), change.UserId, at.Format("Y-m-d"))
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.SubscriptionChanges.Ctx(ctx).TX(tx).Where("id", change.Id).Data(g.Map{
+		"status":     consts.PlanChangeApplied,
+		"applied_at": at,
+		"updated_at": gtime.Now(),
+	}).Update()
+	if err != nil {
+		return err
+	}
+	g.Log().Infof(ctx, "用户 %d 套餐由 %s(%d人) 变更为 %s(%d人)",
+		change.UserId, change.FromPlan, change.FromMembers, change.ToPlan, change.ToMembers)
+	return nil
+}
+
+// updateAccount 按变更更新订阅和团队的套餐、人数和配额
+func updateAccount(ctx context.Context, tx gdb.TX, change *entity.SubscriptionChanges) error {
+	now := gtime.Now()
+	sub := g.Map{
+		"user_plan":           change.ToPlan,
+		"subscription_status": "active",
+		"updated_at":          now,
+	}
+	switch {
+	case change.ToPlan == consts.PlanTeam:
+		quota := TeamQuota(ctx, change.ToMembers)
+		_, err := dao.Teams.Ctx(ctx).TX(tx).Where("id", change.TeamId).Data(g.Map{
+			"status":              "active",
+			"member_count":        change.ToMembers,
+			"total_words_quota":   int(quota.Words),
+			"total_storage_quota": int(quota.Storage),
+			"total_cu_quota":      int(quota.Cu),
+			"total_traffic_quota": int(quota.Traffic),
+			"updated_at":          now,
+		}).Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新团队配额失败")
+		}
+		sub["team_id"] = change.TeamId
+	case change.FromPlan == consts.PlanTeam:
+		_, err := dao.Teams.Ctx(ctx).TX(tx).Where("id", change.TeamId).Data(g.Map{
+			"status":     "suspended",
+			"updated_at": now,
+		}).Update()
+		if err != nil {
+			return gerror.Wrap(err, "停用团队失败")
+		}
+		sub["team_id"] = nil
+	}
+	if _, err := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", change.UserId).Data(sub).Update(); err != nil {
+		return gerror.Wrap(err, "更新订阅失败")
+	}
+	return nil
+}
+
+// changeAccount 读取变更套餐的订阅和团队, 团队套餐只能由团队所有者变更
+// 个人套餐返回用户创建的团队, 用于升级到团队版
+func changeAccount(ctx context.Context, tx gdb.TX, userId int64) (*entity.UserSubscriptions, *entity.Teams, error) {
+	var sub *entity.UserSubscriptions
+	err := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", userId).LockUpdate().Scan(&sub)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sub == nil {
+		return nil, nil, gerror.NewCode(gcode.CodeNotFound, "未找到订阅信息")
+	}
+
+	var team *entity.Teams
+	if sub.TeamId > 0 {
+		if err := dao.Teams.Ctx(ctx).TX(tx).Where("id", sub.TeamId).Scan(&team); err != nil {
+			return nil, nil, err
+		}
+		if team != nil && team.OwnerId != userId {
+			return nil, nil, gerror.NewCode(gcode.CodeNotAuthorized, "团队套餐只能由团队所有者变更")
+		}
+		return sub, team, nil
+	}
+	err = dao.Teams.Ctx(ctx).TX(tx).
+		Where("owner_id", userId).
+		WhereNot("status", "deleted").
+		OrderDesc("id").
+		Limit(1).
+		Scan(&team)
+	return sub, team, err
+}
+
+// quotePlanChange 区分升级和降级并按账期剩余时间折算差价
+// 账期以配额重置日期为结束时间, 向前一个月为开始时间
+func quotePlanChange(ctx context.Context, tx gdb.TX, sub *entity.UserSubscriptions, team *entity.Teams, plan string, members int, now *gtime.Time) (*PlanChangeQuote, error) {
+	if _, ok := planRank[plan]; !ok {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "套餐类型错误")
+	}
+	from, fromMembers := sub.UserPlan, 1
+	if sub.SubscriptionStatus != "active" || from == "" {
+		from = consts.PlanFree
+	}
+	if from == consts.PlanTeam && team != nil {
+		fromMembers = team.MemberCount
+	}
+
+	if plan != consts.PlanTeam {
+		members = 1
+	} else {
+		if team == nil {
+			return nil, gerror.NewCode(gcode.CodeInvalidOperation, "请先创建团队")
+		}
+		minMembers := g.Cfg().MustGet(ctx, "team.min_member_count", 3).Int()
+		maxMembers := g.Cfg().MustGet(ctx, "team.max_member_count", 100).Int()
+		if members < minMembers || members > maxMembers {
+			return nil, gerror.NewCodef(gcode.CodeInvalidParameter, "团队人数应在 %d 到 %d 人之间", minMembers, maxMembers)
+		}
+		active, err := dao.TeamMembers.Ctx(ctx).TX(tx).Where("team_id", team.Id).Where("status", "active").Count()
+		if err != nil {
+			return nil, err
+		}
+		if members < active {
+			return nil, gerror.NewCodef(gcode.CodeInvalidOperation, "团队当前有 %d 名成员, 请先移除成员", active)
+		}
+	}
+
+	quote := &PlanChangeQuote{
+		FromPlan:    from,
+		FromMembers: fromMembers,
+		ToPlan:      plan,
+		ToMembers:   members,
+		PeriodEnd:   currentPeriodEnd(sub, now),
+	}
+	if team != nil && (plan == consts.PlanTeam || from == consts.PlanTeam) {
+		quote.teamId = team.Id
+	}
+	switch {
+	case planRank[plan] > planRank[from], plan == from && members > fromMembers:
+		quote.ChangeType = consts.PlanChangeUpgrade
+	case planRank[plan] < planRank[from], plan == from && members < fromMembers:
+		quote.ChangeType = consts.PlanChangeDowngrade
+	default:
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "套餐未变化")
+	}
+
+	start := quote.PeriodEnd.AddDate(0, -1, 0)
+	quote.RemainingRatio = round3(math.Min(math.Max(
+		float64(quote.PeriodEnd.Sub(now))/float64(quote.PeriodEnd.Sub(start)), 0), 1))
+	if quote.ChangeType == consts.PlanChangeUpgrade {
+		diff := PlanPrice(ctx, plan, members) - PlanPrice(ctx, from, fromMembers)
+		quote.ProratedAmount = round2(math.Max(diff, 0) * quote.RemainingRatio)
+	}
+	return quote, nil
+}
+
+// currentPeriodEnd 当前账期的结束时间, 未开通或配额重置日期已过时为下月1日
+func currentPeriodEnd(sub *entity.UserSubscriptions, now *gtime.Time) *gtime.Time {
+	if sub.QuotaResetDate != nil && sub.QuotaResetDate.After(now) {
+		return sub.QuotaResetDate.StartOfDay()
+	}
+	return gtime.NewFromStr(nextPeriodStart(now))
+}
+
+// nextPeriodStart 下一账期的第一天
+func nextPeriodStart(t *gtime.Time) string {
+	return gtime.NewFromStr(t.Format("Y-m-01")).AddDate(0, 1, 0).Format("Y-m-d")
+}
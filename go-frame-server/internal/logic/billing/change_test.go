@@ -0,0 +1,90 @@
+package billing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// 变更为团队版时需要查询团队成员, 不在此处覆盖
+func TestQuotePlanChange(t *testing.T) {
+	ctx := context.Background()
+	// 账期为 10月1日 至 11月1日, 共 31 天, 10月11日时剩余 21 天
+	now := gtime.NewFromStr("2026-10-11 00:00:00")
+	resetDate := gtime.NewFromStr("2026-11-01 00:00:00")
+	professional := &entity.UserSubscriptions{UserPlan: consts.PlanProfessional, SubscriptionStatus: "active", QuotaResetDate: resetDate}
+	team := &entity.Teams{Id: 9, MemberCount: 4}
+
+	cases := []struct {
+		name       string
+		sub        *entity.UserSubscriptions
+		team       *entity.Teams
+		plan       string
+		now        *gtime.Time
+		changeType string // 为空时期望返回错误
+		periodEnd  string
+		ratio      float64
+		amount     float64
+		teamId     int64
+	}{
+		{
+			name: "免费版升级为专业版", sub: &entity.UserSubscriptions{UserPlan: consts.PlanFree, SubscriptionStatus: "active", QuotaResetDate: resetDate},
+			plan: consts.PlanProfessional, changeType: consts.PlanChangeUpgrade, periodEnd: "2026-11-01", ratio: 0.677, amount: 33.85,
+		},
+		{
+			name: "已过期的订阅按免费版升级", sub: &entity.UserSubscriptions{UserPlan: consts.PlanProfessional, SubscriptionStatus: "expired", QuotaResetDate: resetDate},
+			plan: consts.PlanProfessional, changeType: consts.PlanChangeUpgrade, periodEnd: "2026-11-01", ratio: 0.677, amount: 33.85,
+		},
+		{
+			name: "账期第一天升级补交全额差价", sub: &entity.UserSubscriptions{QuotaResetDate: resetDate},
+			plan: consts.PlanProfessional, now: gtime.NewFromStr("2026-10-01 00:00:00"),
+			changeType: consts.PlanChangeUpgrade, periodEnd: "2026-11-01", ratio: 1, amount: 50,
+		},
+		{
+			name: "配额重置日期已过时账期到下月1日", sub: &entity.UserSubscriptions{QuotaResetDate: gtime.NewFromStr("2026-10-01 00:00:00")},
+			plan: consts.PlanProfessional, changeType: consts.PlanChangeUpgrade, periodEnd: "2026-11-01", ratio: 0.677, amount: 33.85,
+		},
+		{
+			name: "剩余时间超过一个账期时按全额计", sub: &entity.UserSubscriptions{QuotaResetDate: gtime.NewFromStr("2027-01-01 00:00:00")},
+			plan: consts.PlanProfessional, changeType: consts.PlanChangeUpgrade, periodEnd: "2027-01-01", ratio: 1, amount: 50,
+		},
+		{
+			name: "专业版降级为免费版不补差价", sub: professional,
+			plan: consts.PlanFree, changeType: consts.PlanChangeDowngrade, periodEnd: "2026-11-01", ratio: 0.677,
+		},
+		{
+			name: "团队版降级为专业版", sub: &entity.UserSubscriptions{UserPlan: consts.PlanTeam, SubscriptionStatus: "active", QuotaResetDate: resetDate},
+			team: team, plan: consts.PlanProfessional, changeType: consts.PlanChangeDowngrade, periodEnd: "2026-11-01", ratio: 0.677, teamId: 9,
+		},
+		{name: "套餐未变化", sub: professional, plan: consts.PlanProfessional},
+		{name: "套餐类型错误", sub: professional, plan: "enterprise"},
+		{name: "未创建团队时升级为团队版", sub: professional, plan: consts.PlanTeam},
+	}
+	for _, c := range cases {
+		at := now
+		if c.now != nil {
+			at = c.now
+		}
+		quote, err := quotePlanChange(ctx, nil, c.sub, c.team, c.plan, 1, at)
+		if c.changeType == "" {
+			if err == nil {
+				t.Errorf("%s: 应返回错误, 得到 %+v", c.name, quote)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: 不应返回错误: %v", c.name, err)
+			continue
+		}
+		if quote.ChangeType != c.changeType || quote.PeriodEnd.Format("Y-m-d") != c.periodEnd ||
+			quote.RemainingRatio != c.ratio || quote.ProratedAmount != c.amount || quote.teamId != c.teamId {
+			t.Errorf("%s: 报价为 %s, 账期结束 %s, 剩余 %v, 差价 %v, 团队 %d; 期望 %s, %s, %v, %v, %d", c.name,
+				quote.ChangeType, quote.PeriodEnd.Format("Y-m-d"), quote.RemainingRatio, quote.ProratedAmount, quote.teamId,
+				c.changeType, c.periodEnd, c.ratio, c.amount, c.teamId)
+		}
+	}
+}
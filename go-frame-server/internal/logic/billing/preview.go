@@ -0,0 +1,55 @@
+package billing
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Preview 按当前用量预估用户本账期的账单, 团队账单仅团队所有者和管理员可查看
+func (b *Billing) Preview(ctx context.Context, userId int64) (*Bill, error) {
+	var sub *entity.UserSubscriptions
+	if err := dao.UserSubscriptions.Ctx(ctx).Where("user_id", userId).Scan(&sub); err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "未找到订阅信息")
+	}
+
+	var (
+		account = &Account{UserId: userId, Plan: sub.UserPlan, Members: 1}
+		team    *entity.Teams
+	)
+	if sub.TeamId > 0 {
+		if err := dao.Teams.Ctx(ctx).Where("id", sub.TeamId).Scan(&team); err != nil {
+			return nil, err
+		}
+		if team == nil {
+			return nil, gerror.NewCode(gcode.CodeNotFound, "团队不存在")
+		}
+		if team.OwnerId != userId {
+			role, err := dao.TeamMembers.Ctx(ctx).
+				Where("team_id", team.Id).
+				Where("user_id", userId).
+				Where("status", "active").
+				Value("role")
+			if err != nil {
+				return nil, err
+			}
+			if role.String() != "admin" {
+				return nil, gerror.NewCode(gcode.CodeNotAuthorized, "仅团队所有者和管理员可查看团队账单")
+			}
+		}
+		account.UserId = team.OwnerId
+		account.TeamId = team.Id
+		account.Members = team.MemberCount
+	}
+
+	setUsage(ctx, account, sub, team)
+	return Compute(ctx, gtime.Now().Format("Y-m"), account), nil
+}
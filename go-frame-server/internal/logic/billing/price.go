@@ -0,0 +1,222 @@
+package billing
+
+import (
+	"context"
+	"math"
+
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// 计费资源, 与 overage_fees 配置的键一致
+const (
+	ResourceWords   = "words"
+	ResourceStorage = "storage"
+	ResourceTraffic = "traffic"
+	ResourceCu      = "cu"
+)
+
+var resources = []string{ResourceWords, ResourceStorage, ResourceTraffic, ResourceCu}
+
+// Usage 各资源的用量或配额: 字数为字, 存储为 MB, 流量为 GB, 算力为 CU
+type Usage struct {
+	Words   float64 `json:"words" dc:"字数"`
+	Storage float64 `json:"storage" dc:"存储(MB)"`
+	Traffic float64 `json:"traffic" dc:"流量(GB)"`
+	Cu      float64 `json:"cu" dc:"算力(CU)"`
+}
+
+func (u *Usage) get(resource string) float64 {
+	switch resource {
+	case ResourceWords:
+		return u.Words
+	case ResourceStorage:
+		return u.Storage
+	case ResourceTraffic:
+		return u.Traffic
+	default:
+		return u.Cu
+	}
+}
+
+// Account 出账对象: 个人订阅按用户出账, 团队按团队出账, 账单归属团队所有者
+type Account struct {
+	UserId  int64  `json:"userId"`
+	TeamId  int64  `json:"teamId"`
+	Plan    string `json:"plan"`
+	Members int    `json:"members"`
+	Quota   Usage  `json:"quota"`
+	Used    Usage  `json:"used"`
+}
+
+// OverageLine 一项资源的超额明细
+type OverageLine struct {
+	Resource string `json:"resource" dc:"资源: words-字数, storage-存储, traffic-流量, cu-算力"`
+	// Amount 超出配额的用量, 与 billing_overages 一致: 字数为千字, 存储和流量为 GB, 算力为 CU
+	Amount float64 `json:"amount" dc:"超额用量"`
+	Unit   float64 `json:"unit" dc:"计费单位"`
+	Price  float64 `json:"price" dc:"每计费单位的价格"`
+	Fee    float64 `json:"fee" dc:"超额费用"`
+}
+
+// overageScale 用量换算为超额记录单位的倍数, 存储用量以 MB 记录
+var overageScale = map[string]float64{
+	ResourceWords:   1000,
+	ResourceStorage: 1024,
+	ResourceTraffic: 1,
+	ResourceCu:      1,
+}
+
+// unitScale 超额记录单位换算为计费单位的倍数, 字数的计费单位按字配置
+var unitScale = map[string]float64{
+	ResourceWords:   1000,
+	ResourceStorage: 1,
+	ResourceTraffic: 1,
+	ResourceCu:      1,
+}
+
+// Bill 一个账户一个账期的账单
+type Bill struct {
+	Period         string         `json:"period" dc:"账期, 格式 YYYY-MM"`
+	UserId         int64          `json:"userId"`
+	TeamId         int64          `json:"teamId"`
+	Plan           string         `json:"plan" dc:"套餐"`
+	Members        int            `json:"members" dc:"团队人数, 个人为1"`
+	BaseFee        float64        `json:"baseFee" dc:"基础订阅费"`
+	Overages       []*OverageLine `json:"overages" dc:"超额明细"`
+	OverageFee     float64        `json:"overageFee" dc:"超额费用合计"`
+	Subtotal       float64        `json:"subtotal" dc:"小计"`
+	Discount       float64        `json:"discount" dc:"折扣金额"`
+	DiscountReason string         `json:"discountReason" dc:"折扣说明"`
+	Total          float64        `json:"total" dc:"应付金额"`
+}
+
+// discountRule 折扣规则, 配置于 billing.discounts, 多条满足时取优惠最大的一条
+type discountRule struct {
+	Name       string  `json:"name"`
+	Plan       string  `json:"plan"`
+	MinMembers int     `json:"minMembers"`
+	Rate       float64 `json:"rate"`
+	Amount     float64 `json:"amount"`
+}
+
+// PlanPrice 套餐月费, 团队版按人数计费
+func PlanPrice(ctx context.Context, plan string, members int) float64 {
+	price := g.Cfg().MustGet(ctx, "plans."+plan+".plan_price", 0).Float64()
+	if members > 1 {
+		price *= float64(members)
+	}
+	return round2(price)
+}
+
+// PlanQuota 个人套餐的月度配额
+func PlanQuota(ctx context.Context, plan string) Usage {
+	cfg := g.Cfg().MustGet(ctx, "plans."+plan).MapStrVar()
+	return Usage{
+		Words:   cfg["words_quota"].Float64(),
+		Storage: cfg["storage_quota"].Float64(),
+		Traffic: cfg["traffic_quota"].Float64(),
+		Cu:      cfg["cu_quota"].Float64(),
+	}
+}
+
+// TeamQuota 团队版的月度共享配额, 团队版配额按最少人数计算, 每多一人增加人均配额乘以倍增系数
+func TeamQuota(ctx context.Context, members int) Usage {
+	quota := PlanQuota(ctx, "team")
+	minMembers := g.Cfg().MustGet(ctx, "team.min_member_count", 3).Float64()
+	multiplier := g.Cfg().MustGet(ctx, "team.member_quota_multiplier", 1).Float64()
+	if minMembers <= 0 || float64(members) <= minMembers {
+		return quota
+	}
+	scale := 1 + multiplier*(float64(members)-minMembers)/minMembers
+	return Usage{
+		Words:   math.Round(quota.Words * scale),
+		Storage: math.Round(quota.Storage * scale),
+		Traffic: math.Round(quota.Traffic * scale),
+		Cu:      math.Round(quota.Cu * scale),
+	}
+}
+
+// Compute 计算账单, 相同的输入总是得到相同的账单
+func Compute(ctx context.Context, period string, account *Account) *Bill {
+	amounts := make(map[string]float64, len(resources))
+	for _, resource := range resources {
+		amount := math.Max(account.Used.get(resource)-account.Quota.get(resource), 0)
+		amounts[resource] = round3(amount / overageScale[resource])
+	}
+	return computeWithOverage(ctx, period, account, amounts)
+}
+
+// computeWithOverage 按已确定的超额用量计算账单, 重新出账时使用已记录的超额用量
+func computeWithOverage(ctx context.Context, period string, account *Account, amounts map[string]float64) *Bill {
+	members := max(account.Members, 1)
+	bill := &Bill{
+		Period:  period,
+		UserId:  account.UserId,
+		TeamId:  account.TeamId,
+		Plan:    account.Plan,
+		Members: members,
+		BaseFee: PlanPrice(ctx, account.Plan, members),
+	}
+
+	for _, resource := range resources {
+		amount := amounts[resource]
+		if amount <= 0 {
+			continue
+		}
+		unit := g.Cfg().MustGet(ctx, "overage_fees."+resource+".unit", 1).Float64()
+		price := g.Cfg().MustGet(ctx, "overage_fees."+resource+".price", 0).Float64()
+		if unit <= 0 {
+			unit = 1
+		}
+		// 不足一个计费单位的按一个单位计费
+		line := &OverageLine{
+			Resource: resource,
+			Amount:   amount,
+			Unit:     unit,
+			Price:    price,
+			Fee:      round2(math.Ceil(round3(amount*unitScale[resource]/unit)) * price),
+		}
+		bill.Overages = append(bill.Overages, line)
+		bill.OverageFee = round2(bill.OverageFee + line.Fee)
+	}
+
+	bill.Subtotal = round2(bill.BaseFee + bill.OverageFee)
+	bill.Discount, bill.DiscountReason = discount(ctx, bill)
+	bill.Total = round2(bill.Subtotal - bill.Discount)
+	return bill
+}
+
+// discount 计算账单适用的最大折扣
+func discount(ctx context.Context, bill *Bill) (float64, string) {
+	var rules []*discountRule
+	if err := g.Cfg().MustGet(ctx, "billing.discounts").Scan(&rules); err != nil {
+		g.Log().Warningf(ctx, "解析折扣配置失败: %v", err)
+		return 0, ""
+	}
+
+	var (
+		best   float64
+		reason string
+	)
+	for _, rule := range rules {
+		if rule.Plan != "" && rule.Plan != bill.Plan {
+			continue
+		}
+		if bill.Members < rule.MinMembers {
+			continue
+		}
+		amount := round2(math.Min(bill.Subtotal*rule.Rate+rule.Amount, bill.Subtotal))
+		if amount > best {
+			best, reason = amount, rule.Name
+		}
+	}
+	return best, reason
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,164 @@
+package billing
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcfg"
+)
+
+const testConfig = `
+plans:
+  professional:
+    plan_price: 50
+    words_quota: 50000
+    storage_quota: 2048
+    traffic_quota: 5
+    cu_quota: 10
+  team:
+    plan_price: 100
+    words_quota: 100000
+    storage_quota: 1024
+    traffic_quota: 10
+    cu_quota: 100
+team:
+  min_member_count: 3
+  member_quota_multiplier: 1
+overage_fees:
+  words:
+    unit: 1000
+    price: 0.5
+  storage:
+    unit: 1
+    price: 2
+  traffic:
+    unit: 1
+    price: 1
+  cu:
+    unit: 10
+    price: 3
+billing:
+  discounts:
+    - name: 团队折扣
+      plan: team
+      minMembers: 5
+      rate: 0.1
+    - name: 立减
+      amount: 20
+`
+
+func TestMain(m *testing.M) {
+	adapter, err := gcfg.NewAdapterContent(testConfig)
+	if err != nil {
+		panic(err)
+	}
+	g.Cfg().SetAdapter(adapter)
+	os.Exit(m.Run())
+}
+
+func TestTeamQuota(t *testing.T) {
+	ctx := context.Background()
+	base := Usage{Words: 100000, Storage: 1024, Traffic: 10, Cu: 100}
+	cases := []struct {
+		name    string
+		members int
+		want    Usage
+	}{
+		{"不足最少人数", 2, base},
+		{"等于最少人数", 3, base},
+		{"多一人", 4, Usage{Words: 133333, Storage: 1365, Traffic: 13, Cu: 133}},
+		{"人数翻倍", 6, Usage{Words: 200000, Storage: 2048, Traffic: 20, Cu: 200}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := TeamQuota(ctx, c.members); got != c.want {
+				t.Errorf("%d人的配额为%+v, 期望%+v", c.members, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	ctx := context.Background()
+
+	// 字数超出2500字按3千字计费, 存储超出1GB, 算力超出1CU按一个计费单位计费, 流量未超出
+	bill := Compute(ctx, "2026-09", &Account{
+		UserId:  1,
+		Plan:    "professional",
+		Members: 1,
+		Quota:   PlanQuota(ctx, "professional"),
+		Used:    Usage{Words: 52500, Storage: 3072, Traffic: 4, Cu: 11},
+	})
+	want := map[string]OverageLine{
+		ResourceWords:   {Resource: ResourceWords, Amount: 2.5, Unit: 1000, Price: 0.5, Fee: 1.5},
+		ResourceStorage: {Resource: ResourceStorage, Amount: 1, Unit: 1, Price: 2, Fee: 2},
+		ResourceCu:      {Resource: ResourceCu, Amount: 1, Unit: 10, Price: 3, Fee: 3},
+	}
+	if len(bill.Overages) != len(want) {
+		t.Fatalf("超额明细为%d项, 期望%d项", len(bill.Overages), len(want))
+	}
+	for _, line := range bill.Overages {
+		if *line != want[line.Resource] {
+			t.Errorf("%s的超额明细为%+v, 期望%+v", line.Resource, *line, want[line.Resource])
+		}
+	}
+	if bill.BaseFee != 50 || bill.OverageFee != 6.5 || bill.Subtotal != 56.5 {
+		t.Errorf("基础费用%v, 超额费用%v, 小计%v, 期望50, 6.5, 56.5", bill.BaseFee, bill.OverageFee, bill.Subtotal)
+	}
+	if bill.Discount != 20 || bill.DiscountReason != "立减" || bill.Total != 36.5 {
+		t.Errorf("折扣%v(%s), 应付%v, 期望20(立减), 36.5", bill.Discount, bill.DiscountReason, bill.Total)
+	}
+
+	// 相同的输入总是得到相同的账单
+	again := Compute(ctx, "2026-09", &Account{
+		UserId:  1,
+		Plan:    "professional",
+		Members: 1,
+		Quota:   PlanQuota(ctx, "professional"),
+		Used:    Usage{Words: 52500, Storage: 3072, Traffic: 4, Cu: 11},
+	})
+	if again.Total != bill.Total || len(again.Overages) != len(bill.Overages) {
+		t.Errorf("重复计算的账单不一致: %+v, %+v", again, bill)
+	}
+
+	// 团队版按人数计费, 未超出配额时没有超额明细
+	bill = Compute(ctx, "2026-09", &Account{
+		TeamId:  1,
+		Plan:    "team",
+		Members: 6,
+		Quota:   TeamQuota(ctx, 6),
+		Used:    Usage{Words: 150000, Storage: 2000, Traffic: 15, Cu: 150},
+	})
+	if len(bill.Overages) != 0 || bill.BaseFee != 600 {
+		t.Errorf("团队账单基础费用%v, 超额明细%d项, 期望600, 0项", bill.BaseFee, len(bill.Overages))
+	}
+	if bill.Discount != 60 || bill.DiscountReason != "团队折扣" || bill.Total != 540 {
+		t.Errorf("折扣%v(%s), 应付%v, 期望60(团队折扣), 540", bill.Discount, bill.DiscountReason, bill.Total)
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name       string
+		bill       *Bill
+		wantAmount float64
+		wantReason string
+	}{
+		{"取优惠最大的规则", &Bill{Plan: "team", Members: 5, Subtotal: 500}, 50, "团队折扣"},
+		{"人数不足", &Bill{Plan: "team", Members: 4, Subtotal: 500}, 20, "立减"},
+		{"套餐不符", &Bill{Plan: "professional", Members: 5, Subtotal: 500}, 20, "立减"},
+		{"折扣不超过小计", &Bill{Plan: "professional", Members: 1, Subtotal: 10}, 10, "立减"},
+		{"零元账单", &Bill{Plan: "professional", Members: 1}, 0, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			amount, reason := discount(ctx, c.bill)
+			if amount != c.wantAmount || reason != c.wantReason {
+				t.Errorf("折扣为%v(%s), 期望%v(%s)", amount, reason, c.wantAmount, c.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package coupons
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Coupons 优惠券: 管理员维护优惠码, 用户下单时使用, 下单占用次数, 支付后核销, 订单关闭时释放
+type Coupons struct{}
+
+func New() *Coupons {
+	return &Coupons{}
+}
+
+// SaveInput 创建和修改优惠券的参数, 修改时不能变更优惠码
+type SaveInput struct {
+	Code              string
+	Name              string
+	DiscountType      string
+	PercentOff        int
+	AmountOffCent     int64
+	MaxDiscountCent   int64
+	MinAmountCent     int64
+	OrderTypes        []string
+	Plans             []string
+	ProjectIds        []int
+	FirstPurchaseOnly bool
+	TotalLimit        int
+	PerUserLimit      int
+	StartsAt          *gtime.Time
+	ExpiresAt         *gtime.Time
+	Status            string
+}
+
+// Create 创建优惠券, 返回优惠券ID
+func (c *Coupons) Create(ctx context.Context, adminId int, in *SaveInput) (int, error) {
+	if err := checkInput(in); err != nil {
+		return 0, err
+	}
+	code := normalizeCode(in.Code)
+	count, err := dao.Coupons.Ctx(ctx).Where("code", code).Count()
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, gerror.NewCode(gcode.CodeInvalidParameter, "优惠码已存在")
+	}
+
+	data := saveData(in)
+	data["code"] = code
+	data["created_by"] = adminId
+	data["created_at"] = gtime.Now()
+	id, err := dao.Coupons.Ctx(ctx).Data(data).InsertAndGetId()
+	if err != nil {
+		return 0, gerror.Wrap(err, "创建优惠券失败")
+	}
+	return int(id), nil
+}
+
+// Update 修改优惠券, 已占用和核销的次数不受影响
+func (c *Coupons) Update(ctx context.Context, id int, in *SaveInput) error {
+	if _, err := c.Get(ctx, id); err != nil {
+		return err
+	}
+	if err := checkInput(in); err != nil {
+		return err
+	}
+	_, err := dao.Coupons.Ctx(ctx).Where("id", id).Data(saveData(in)).Update()
+	return err
+}
+
+// Delete 删除未使用过的优惠券, 使用过的优惠券只能停用
+func (c *Coupons) Delete(ctx context.Context, id int) error {
+	if _, err := c.Get(ctx, id); err != nil {
+		return err
+	}
+	count, err := dao.CouponRedemptions.Ctx(ctx).Where("coupon_id", id).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return gerror.NewCode(gcode.CodeInvalidOperation, "优惠券已被使用, 只能停用")
+	}
+	_, err = dao.Coupons.Ctx(ctx).Where("id", id).Delete()
+	return err
+}
+
+// Get 获取优惠券
+func (c *Coupons) Get(ctx context.Context, id int) (*entity.Coupons, error) {
+	var coupon *entity.Coupons
+	if err := dao.Coupons.Ctx(ctx).Where("id", id).Scan(&coupon); err != nil {
+		return nil, err
+	}
+	if coupon == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "优惠券不存在")
+	}
+	return coupon, nil
+}
+
+// List 获取优惠券列表, keyword 匹配优惠码和名称
+func (c *Coupons) List(ctx context.Context, keyword string, status string, page int, size int) ([]*entity.Coupons, int, error) {
+	var (
+		list  []*entity.Coupons
+		total int
+		model = dao.Coupons.Ctx(ctx)
+	)
+	if keyword != "" {
+		model = model.Where("(code ilike ? or name ilike ?)", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if status != "" {
+		model = model.Where("status", status)
+	}
+	err := model.OrderDesc("id").Page(page, size).ScanAndCount(&list, &total, false)
+	return list, total, err
+}
+
+// ListRedemptions 获取优惠券的使用记录
+func (c *Coupons) ListRedemptions(ctx context.Context, couponId int, page int, size int) ([]*entity.CouponRedemptions, int, error) {
+	var (
+		list  []*entity.CouponRedemptions
+		total int
+	)
+	err := dao.CouponRedemptions.Ctx(ctx).
+		Where("coupon_id", couponId).
+		OrderDesc("id").
+		Page(page, size).
+		ScanAndCount(&list, &total, false)
+	return list, total, err
+}
+
+func checkInput(in *SaveInput) error {
+	switch in.DiscountType {
+	case consts.CouponPercent:
+		if in.PercentOff < 1 || in.PercentOff > 100 {
+			return gerror.NewCode(gcode.CodeInvalidParameter, "优惠比例必须在1到100之间")
+		}
+	case consts.CouponFixed:
+		if in.AmountOffCent <= 0 {
+			return gerror.NewCode(gcode.CodeInvalidParameter, "减免金额必须大于0")
+		}
+	default:
+		return gerror.NewCode(gcode.CodeInvalidParameter, "优惠类型错误")
+	}
+	if in.StartsAt != nil && in.ExpiresAt != nil && !in.ExpiresAt.After(in.StartsAt) {
+		return gerror.NewCode(gcode.CodeInvalidParameter, "过期时间必须晚于生效时间")
+	}
+	return nil
+}
+
+func saveData(in *SaveInput) g.Map {
+	status := in.Status
+	if status == "" {
+		status = consts.CouponActive
+	}
+	return g.Map{
+		"name":                in.Name,
+		"discount_type":       in.DiscountType,
+		"percent_off":         in.PercentOff,
+		"amount_off_cent":     in.AmountOffCent,
+		"max_discount_cent":   in.MaxDiscountCent,
+		"min_amount_cent":     in.MinAmountCent,
+		"order_types":         in.OrderTypes,
+		"plans":               in.Plans,
+		"project_ids":         in.ProjectIds,
+		"first_purchase_only": in.FirstPurchaseOnly,
+		"total_limit":         in.TotalLimit,
+		"per_user_limit":      in.PerUserLimit,
+		"starts_at":           in.StartsAt,
+		"expires_at":          in.ExpiresAt,
+		"status":              status,
+		"updated_at":          gtime.Now(),
+	}
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
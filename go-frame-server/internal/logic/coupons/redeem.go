@@ -0,0 +1,204 @@
+package coupons
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// ApplyInput 使用优惠码的订单信息
+type ApplyInput struct {
+	Code       string
+	UserId     int
+	OrderType  string
+	Plan       string // 订阅的套餐, 账单订单为用户当前的套餐
+	ProjectId  int
+	AmountCent int64
+}
+
+// Quote 使用优惠码后的订单金额
+type Quote struct {
+	CouponId     int    `json:"couponId"`
+	Code         string `json:"code" dc:"优惠码"`
+	Name         string `json:"name" dc:"优惠券名称"`
+	OriginalCent int64  `json:"originalCent" dc:"优惠前金额（分）"`
+	DiscountCent int64  `json:"discountCent" dc:"优惠金额（分）"`
+	PayCent      int64  `json:"payCent" dc:"应付金额（分）"`
+}
+
+// Check 校验优惠码并计算优惠金额, 不占用使用次数, 用于下单前展示
+func (c *Coupons) Check(ctx context.Context, in *ApplyInput) (*Quote, error) {
+	var q *Quote
+	err := dao.Coupons.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var coupon *entity.Coupons
+		err := dao.Coupons.Ctx(ctx).TX(tx).Where("code", normalizeCode(in.Code)).Scan(&coupon)
+		if err != nil {
+			return err
+		}
+		q, err = quote(ctx, tx, coupon, in)
+		return err
+	})
+	return q, err
+}
+
+// Reserve 下单时校验优惠码并占用一次使用次数, 与订单创建在同一事务内
+func Reserve(ctx context.Context, tx gdb.TX, in *ApplyInput, outTradeNo string) (*Quote, error) {
+	var coupon *entity.Coupons
+	err := dao.Coupons.Ctx(ctx).TX(tx).
+		Where("code", normalizeCode(in.Code)).
+		LockUpdate().
+		Scan(&coupon)
+	if err != nil {
+		return nil, err
+	}
+	q, err := quote(ctx, tx, coupon, in)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = dao.CouponRedemptions.Ctx(ctx).TX(tx).Data(g.Map{
+		"coupon_id":            coupon.Id,
+		"user_id":              in.UserId,
+		"order_type":           in.OrderType,
+		"out_trade_no":         outTradeNo,
+		"original_amount_cent": q.OriginalCent,
+		"discount_cent":        q.DiscountCent,
+		"status":               consts.RedemptionPending,
+		"created_at":           gtime.Now(),
+		"updated_at":           gtime.Now(),
+	}).Insert()
+	if err != nil {
+		return nil, gerror.Wrap(err, "占用优惠券失败")
+	}
+	if _, err = dao.Coupons.Ctx(ctx).TX(tx).Where("id", coupon.Id).Increment("used_count", 1); err != nil {
+		return nil, gerror.Wrap(err, "占用优惠券失败")
+	}
+	return q, nil
+}
+
+// GetRedemption 获取订单使用的优惠券, 未使用时返回 nil
+func GetRedemption(ctx context.Context, tx gdb.TX, outTradeNo string) (*entity.CouponRedemptions, error) {
+	var redemption *entity.CouponRedemptions
+	err := dao.CouponRedemptions.Ctx(ctx).TX(tx).Where("out_trade_no", outTradeNo).Scan(&redemption)
+	return redemption, err
+}
+
+// Redeem 订单支付后核销优惠券, 关闭后又支付成功的订单重新占用已释放的次数
+// 此时可能超出使用次数上限, 用户已经完成支付, 仍按优惠后的金额处理
+func Redeem(ctx context.Context, tx gdb.TX, outTradeNo string, billingId int) error {
+	redemption, err := GetRedemption(ctx, tx, outTradeNo)
+	if err != nil || redemption == nil || redemption.Status == consts.RedemptionRedeemed {
+		return err
+	}
+	_, err = dao.CouponRedemptions.Ctx(ctx).TX(tx).Where("id", redemption.Id).Data(g.Map{
+		"status":      consts.RedemptionRedeemed,
+		"billing_id":  billingId,
+		"redeemed_at": gtime.Now(),
+		"updated_at":  gtime.Now(),
+	}).Update()
+	if err != nil {
+		return gerror.Wrap(err, "核销优惠券失败")
+	}
+	if redemption.Status == consts.RedemptionReleased {
+		_, err = dao.Coupons.Ctx(ctx).TX(tx).Where("id", redemption.CouponId).Increment("used_count", 1)
+	}
+	return err
+}
+
+// Release 订单关闭时释放占用的使用次数, 已核销的优惠券在退款后不再返还
+func Release(ctx context.Context, tx gdb.TX, outTradeNo string) error {
+	redemption, err := GetRedemption(ctx, tx, outTradeNo)
+	if err != nil || redemption == nil || redemption.Status != consts.RedemptionPending {
+		return err
+	}
+	_, err = dao.CouponRedemptions.Ctx(ctx).TX(tx).Where("id", redemption.Id).Data(g.Map{
+		"status":     consts.RedemptionReleased,
+		"updated_at": gtime.Now(),
+	}).Update()
+	if err != nil {
+		return gerror.Wrap(err, "释放优惠券失败")
+	}
+	_, err = dao.Coupons.Ctx(ctx).TX(tx).
+		Where("id", redemption.CouponId).
+		WhereGT("used_count", 0).
+		Decrement("used_count", 1)
+	return err
+}
+
+// quote 校验优惠券对订单是否可用并计算优惠金额
+func quote(ctx context.Context, tx gdb.TX, coupon *entity.Coupons, in *ApplyInput) (*Quote, error) {
+	now := gtime.Now()
+	switch {
+	case coupon == nil || coupon.Status != consts.CouponActive:
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "优惠码无效")
+	case coupon.StartsAt != nil && coupon.StartsAt.After(now):
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "优惠码尚未生效")
+	case coupon.ExpiresAt != nil && !coupon.ExpiresAt.After(now):
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "优惠码已过期")
+	case len(coupon.OrderTypes) > 0 && !slices.Contains(coupon.OrderTypes, in.OrderType):
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "该优惠码不适用于此类订单")
+	case len(coupon.Plans) > 0 && !slices.Contains(coupon.Plans, in.Plan):
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "该优惠码不适用于当前套餐")
+	case len(coupon.ProjectIds) > 0 && (in.OrderType != consts.OrderTypeProject || !slices.Contains(coupon.ProjectIds, in.ProjectId)):
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "该优惠码不适用于此项目")
+	case in.AmountCent < coupon.MinAmountCent:
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, "订单金额满 %.2f 元可使用该优惠码", float64(coupon.MinAmountCent)/100)
+	case coupon.TotalLimit > 0 && coupon.UsedCount >= coupon.TotalLimit:
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "优惠码已被领完")
+	}
+
+	if coupon.PerUserLimit > 0 {
+		used, err := dao.CouponRedemptions.Ctx(ctx).TX(tx).
+			Where("coupon_id", coupon.Id).
+			Where("user_id", in.UserId).
+			WhereIn("status", []string{consts.RedemptionPending, consts.RedemptionRedeemed}).
+			Count()
+		if err != nil {
+			return nil, err
+		}
+		if used >= coupon.PerUserLimit {
+			return nil, gerror.NewCode(gcode.CodeInvalidParameter, "您已使用过该优惠码, 未支付的订单关闭后可再次使用")
+		}
+	}
+	if coupon.FirstPurchaseOnly {
+		paid, err := dao.BillingRecords.Ctx(ctx).TX(tx).
+			Where("user_id", in.UserId).
+			Where("status", "paid").
+			WhereGT("total_amount", 0).
+			Count()
+		if err != nil {
+			return nil, err
+		}
+		if paid > 0 {
+			return nil, gerror.NewCode(gcode.CodeInvalidParameter, "该优惠码仅限首次购买使用")
+		}
+	}
+
+	discount := coupon.AmountOffCent
+	if coupon.DiscountType == consts.CouponPercent {
+		discount = int64(math.Round(float64(in.AmountCent) * float64(coupon.PercentOff) / 100))
+		if coupon.MaxDiscountCent > 0 {
+			discount = min(discount, coupon.MaxDiscountCent)
+		}
+	}
+	// 支付渠道不支持零元订单, 优惠后至少支付 0.01 元
+	discount = max(min(discount, in.AmountCent-1), 0)
+	return &Quote{
+		CouponId:     coupon.Id,
+		Code:         coupon.Code,
+		Name:         coupon.Name,
+		OriginalCent: in.AmountCent,
+		DiscountCent: discount,
+		PayCent:      in.AmountCent - discount,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package coupons
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// 每用户次数和首购限制需要查询数据库, 不在此处覆盖
+func TestQuote(t *testing.T) {
+	now := gtime.Now()
+	subscription := &ApplyInput{OrderType: consts.OrderTypeSubscription, Plan: consts.PlanProfessional, AmountCent: 5000}
+	cases := []struct {
+		name     string
+		coupon   entity.Coupons
+		in       *ApplyInput
+		discount int64 // 期望的优惠金额, 为 -1 时期望不可用
+	}{
+		{"固定减免", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000}, subscription, 1000},
+		{"按比例优惠", entity.Coupons{DiscountType: consts.CouponPercent, PercentOff: 15}, subscription, 750},
+		{"按比例优惠四舍五入", entity.Coupons{DiscountType: consts.CouponPercent, PercentOff: 33},
+			&ApplyInput{OrderType: consts.OrderTypeSubscription, AmountCent: 999}, 330},
+		{"按比例优惠不超过最高减免", entity.Coupons{DiscountType: consts.CouponPercent, PercentOff: 50, MaxDiscountCent: 800}, subscription, 800},
+		{"减免超过订单金额时至少支付一分", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 8000}, subscription, 4999},
+		{"满减门槛", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, MinAmountCent: 5000}, subscription, 1000},
+		{"未达满减门槛", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, MinAmountCent: 5001}, subscription, -1},
+		{"已停用", entity.Coupons{Status: consts.CouponDisabled, DiscountType: consts.CouponFixed, AmountOffCent: 1000}, subscription, -1},
+		{"尚未生效", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, StartsAt: now.Add(gtime.H)}, subscription, -1},
+		{"已过期", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, ExpiresAt: now}, subscription, -1},
+		{"订单类型不适用", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, OrderTypes: []string{consts.OrderTypeProject}}, subscription, -1},
+		{"套餐不适用", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, Plans: []string{consts.PlanTeam}}, subscription, -1},
+		{"项目券用于订阅", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, ProjectIds: []int{7}}, subscription, -1},
+		{"项目券用于指定项目", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, ProjectIds: []int{7}},
+			&ApplyInput{OrderType: consts.OrderTypeProject, ProjectId: 7, AmountCent: 5000}, 1000},
+		{"项目券用于其他项目", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, ProjectIds: []int{7}},
+			&ApplyInput{OrderType: consts.OrderTypeProject, ProjectId: 8, AmountCent: 5000}, -1},
+		{"已领完", entity.Coupons{DiscountType: consts.CouponFixed, AmountOffCent: 1000, TotalLimit: 10, UsedCount: 10}, subscription, -1},
+	}
+	for _, c := range cases {
+		coupon := c.coupon
+		if coupon.Status == "" {
+			coupon.Status = consts.CouponActive
+		}
+		q, err := quote(context.Background(), nil, &coupon, c.in)
+		if c.discount < 0 {
+			if err == nil {
+				t.Errorf("%s: 优惠码应不可用, 得到优惠 %d 分", c.name, q.DiscountCent)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: 不应返回错误: %v", c.name, err)
+			continue
+		}
+		if q.DiscountCent != c.discount || q.PayCent != c.in.AmountCent-c.discount || q.OriginalCent != c.in.AmountCent {
+			t.Errorf("%s: 优惠 %d 分, 应付 %d 分, 期望优惠 %d 分", c.name, q.DiscountCent, q.PayCent, c.discount)
+		}
+	}
+
+	if _, err := quote(context.Background(), nil, nil, subscription); err == nil {
+		t.Errorf("优惠码不存在时应不可用")
+	}
+}
@@ -0,0 +1,26 @@
+package email
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"gopkg.in/gomail.v2"
+)
+
+// SendHTML 使用 email 配置的 SMTP 账号发送 HTML 邮件, 供各类业务通知共用
+func SendHTML(ctx context.Context, to string, subject string, body string) error {
+	cfg := g.Cfg()
+	host := cfg.MustGet(ctx, "email.host").String()
+	port := cfg.MustGet(ctx, "email.port", 587).Int()
+	username := cfg.MustGet(ctx, "email.username").String()
+	password := cfg.MustGet(ctx, "email.password").String()
+	from := cfg.MustGet(ctx, "email.from", username).String()
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetHeader("To", to)
+	m.SetHeader("Subject", subject)
+	m.SetBody("text/html", body)
+
+	return gomail.NewDialer(host, port, username, password).DialAndSend(m)
+}
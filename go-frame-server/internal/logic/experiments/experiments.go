@@ -0,0 +1,548 @@
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/google/uuid"
+
+	"kgplatform-backend/external/py_service"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/prompts"
+	"kgplatform-backend/internal/logic/storage"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/model/entity"
+	"kgplatform-backend/internal/neo4j"
+	"kgplatform-backend/internal/utils"
+)
+
+// Experiments 模型对比实验：用多个模型/提示词对同一批抽样素材并行抽取，并生成对比报告
+type Experiments struct {
+	extractor py_service.Extractor
+}
+
+func New() *Experiments {
+	return &Experiments{
+		extractor: py_service.GetExtractor(),
+	}
+}
+
+type RunInput struct {
+	ModelId         int
+	Prompt          string
+	PromptVersionId *int
+}
+
+type CreateExperimentInput struct {
+	UserId         int
+	ProjectId      int
+	PipelineId     int
+	MaterialIdList []int
+	SampleSize     int
+	Runs           []RunInput
+}
+
+// runContext 单次运行所需的上下文
+type runContext struct {
+	userId    int
+	projectId int
+	run       *entity.ExtractExperimentRuns
+	model     *entity.Models
+	materials map[int]*entity.Materials
+	files     []py_service.File
+	schema    map[string]bool
+}
+
+// Create 创建实验并异步执行所有运行
+func (e *Experiments) Create(ctx context.Context, in *CreateExperimentInput) (*entity.ExtractExperiments, error) {
+	var project entity.Projects
+	err := dao.Projects.Ctx(ctx).Where("id", in.ProjectId).Where("user_id", in.UserId).Scan(&project)
+	if err != nil || project.Id == 0 {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "项目不存在或无权限")
+	}
+	pipelineCount, err := dao.Pipelines.Ctx(ctx).Where("id", in.PipelineId).Where("project_id", project.Id).Count()
+	if err != nil {
+		return nil, err
+	}
+	if pipelineCount == 0 {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "管道不存在或无权限")
+	}
+
+	// 抽样素材
+	materialIdList := in.MaterialIdList
+	maxSampleSize := g.Cfg().MustGet(ctx, "experiment.maxSampleSize", 5).Int()
+	sampleSize := in.SampleSize
+	if sampleSize <= 0 || sampleSize > maxSampleSize {
+		sampleSize = maxSampleSize
+	}
+	if len(materialIdList) > sampleSize {
+		materialIdList = materialIdList[:sampleSize]
+	}
+	var materialList []*entity.Materials
+	err = dao.Materials.Ctx(ctx).
+		WhereIn("id", materialIdList).
+		Where("project_id", project.Id).
+		Scan(&materialList)
+	if err != nil {
+		return nil, err
+	}
+	if len(materialList) == 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "未找到可用的素材")
+	}
+
+	// 校验模型
+	modelIds := make([]int, 0, len(in.Runs))
+	for _, run := range in.Runs {
+		modelIds = append(modelIds, run.ModelId)
+	}
+	var modelList []*entity.Models
+	err = dao.Models.Ctx(ctx).WhereIn("id", modelIds).Where("status", 1).Scan(&modelList)
+	if err != nil {
+		return nil, err
+	}
+	modelMap := make(map[int]*entity.Models)
+	for _, model := range modelList {
+		modelMap[model.Id] = model
+	}
+
+	// 默认提示词取项目抽取配置中的提示词
+	var extractConfig py_service.ExtractConfig
+	if project.ExtractConfig != nil {
+		_ = project.ExtractConfig.Scan(&extractConfig)
+	}
+	for i, run := range in.Runs {
+		if _, ok := modelMap[run.ModelId]; !ok {
+			return nil, gerror.NewCodef(gcode.CodeInvalidParameter, "模型不存在或未启用: %d", run.ModelId)
+		}
+		if run.PromptVersionId != nil {
+			version, err := prompts.New().GetVersion(ctx, in.UserId, *run.PromptVersionId)
+			if err != nil {
+				return nil, err
+			}
+			in.Runs[i].Prompt = version.Content
+		} else if run.Prompt == "" {
+			in.Runs[i].Prompt = extractConfig.Prompt
+		}
+		if in.Runs[i].Prompt == "" {
+			return nil, gerror.NewCode(gcode.CodeInvalidParameter, "请输入提示词")
+		}
+	}
+
+	var (
+		experiment entity.ExtractExperiments
+		runList    []*entity.ExtractExperimentRuns
+	)
+	err = dao.ExtractExperiments.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		sampledIds := make([]int, 0, len(materialList))
+		for _, material := range materialList {
+			sampledIds = append(sampledIds, material.Id)
+		}
+
+		taskId, err := dao.Tasks.Ctx(ctx).TX(tx).Data(g.Map{
+			"type":             consts.TaskTypeExperiment,
+			"pipeline_id":      in.PipelineId,
+			"project_id":       project.Id,
+			"material_id_list": sampledIds,
+			"status":           consts.TaskStatusProcessing,
+			"start_time":       gtime.Now(),
+			"created_at":       gtime.Now(),
+			"updated_at":       gtime.Now(),
+		}).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+
+		experimentId, err := dao.ExtractExperiments.Ctx(ctx).TX(tx).Data(g.Map{
+			"user_id":          in.UserId,
+			"project_id":       project.Id,
+			"task_id":          taskId,
+			"material_id_list": sampledIds,
+			"status":           consts.TaskStatusProcessing,
+		}).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+
+		for _, run := range in.Runs {
+			runId, err := dao.ExtractExperimentRuns.Ctx(ctx).TX(tx).Data(g.Map{
+				"experiment_id":   experimentId,
+				"model_id":        run.ModelId,
+				"prompt":          run.Prompt,
+				"status":          consts.TaskStatusPending,
+				"cost_multiplier": py_service.GetModelCostMultiplier(ctx, modelMap[run.ModelId].ModelCode),
+			}).InsertAndGetId()
+			if err != nil {
+				return err
+			}
+			if run.PromptVersionId != nil {
+				_, err = dao.PromptUsages.Ctx(ctx).TX(tx).Data(g.Map{
+					"prompt_version_id": *run.PromptVersionId,
+					"user_id":           in.UserId,
+					"project_id":        project.Id,
+					"experiment_run_id": runId,
+					"status":            consts.TaskStatusPending,
+					"created_at":        gtime.Now(),
+					"updated_at":        gtime.Now(),
+				}).Insert()
+				if err != nil {
+					return err
+				}
+			}
+			runList = append(runList, &entity.ExtractExperimentRuns{
+				Id:             int(runId),
+				ExperimentId:   int(experimentId),
+				ModelId:        run.ModelId,
+				Prompt:         run.Prompt,
+				Status:         consts.TaskStatusPending,
+				CostMultiplier: py_service.GetModelCostMultiplier(ctx, modelMap[run.ModelId].ModelCode),
+			})
+		}
+
+		return dao.ExtractExperiments.Ctx(ctx).TX(tx).Where("id", experimentId).Scan(&experiment)
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "创建模型对比实验失败: %v", err)
+		return nil, gerror.New("创建模型对比实验失败")
+	}
+
+	// 构建运行上下文
+	uploadLogic := upload.NewUpload()
+	materialMap := make(map[int]*entity.Materials)
+	files := make([]py_service.File, 0, len(materialList))
+	for _, material := range materialList {
+		materialMap[material.Id] = material
+		fileUrl := material.TextUrl
+		if fileUrl == "" {
+			fileUrl = material.Url
+		}
+		files = append(files, py_service.File{
+			MaterialId: material.Id,
+			URL:        uploadLogic.GenerateFileUrl(ctx, fileUrl),
+		})
+	}
+	schema := loadSchemaTypes(ctx, &project)
+
+	runContexts := make([]*runContext, 0, len(runList))
+	for _, run := range runList {
+		runContexts = append(runContexts, &runContext{
+			userId:    in.UserId,
+			projectId: project.Id,
+			run:       run,
+			model:     modelMap[run.ModelId],
+			materials: materialMap,
+			files:     files,
+			schema:    schema,
+		})
+	}
+
+	go e.execute(gctx.NeverDone(ctx), &experiment, runContexts, schema != nil)
+
+	return &experiment, nil
+}
+
+// execute 并行执行所有运行，完成后生成对比报告
+func (e *Experiments) execute(ctx context.Context, experiment *entity.ExtractExperiments, runContexts []*runContext, schemaAvailable bool) {
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		runTriple = make(map[int][]neo4j.SimpleTriple)
+	)
+	for _, rc := range runContexts {
+		wg.Add(1)
+		go func(rc *runContext) {
+			defer wg.Done()
+			triples, err := e.executeRun(ctx, rc)
+			if err != nil {
+				g.Log().Errorf(ctx, "实验运行失败: experiment=%d, run=%d, err=%v", experiment.Id, rc.run.Id, err)
+				_, _ = dao.ExtractExperimentRuns.Ctx(ctx).Where("id", rc.run.Id).Update(g.Map{
+					"status":        consts.TaskStatusFailed,
+					"error_message": err.Error(),
+					"finish_time":   gtime.Now(),
+					"updated_at":    gtime.Now(),
+				})
+				_, _ = dao.PromptUsages.Ctx(ctx).Where("experiment_run_id", rc.run.Id).Update(g.Map{
+					"status":     consts.TaskStatusFailed,
+					"updated_at": gtime.Now(),
+				})
+				return
+			}
+			mutex.Lock()
+			runTriple[rc.run.Id] = triples
+			mutex.Unlock()
+		}(rc)
+	}
+	wg.Wait()
+
+	var runList []*entity.ExtractExperimentRuns
+	err := dao.ExtractExperimentRuns.Ctx(ctx).Where("experiment_id", experiment.Id).OrderAsc("id").Scan(&runList)
+	if err != nil {
+		g.Log().Errorf(ctx, "获取实验运行结果失败: %v", err)
+		return
+	}
+
+	status := consts.TaskStatusCompleted
+	errorMessage := ""
+	if len(runTriple) == 0 {
+		status = consts.TaskStatusFailed
+		errorMessage = "所有运行均失败"
+	}
+	report := BuildReport(ctx, runList, runTriple, schemaAvailable)
+
+	now := gtime.Now()
+	_, err = dao.ExtractExperiments.Ctx(ctx).Where("id", experiment.Id).Update(g.Map{
+		"status":        status,
+		"report":        gjson.New(report),
+		"error_message": errorMessage,
+		"finish_time":   now,
+		"updated_at":    now,
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "更新实验状态失败: %v", err)
+	}
+	taskUpdate := g.Map{
+		"status":      status,
+		"finish_time": now,
+		"updated_at":  now,
+	}
+	if errorMessage != "" {
+		taskUpdate["error_message"] = errorMessage
+	}
+	_, err = dao.Tasks.Ctx(ctx).Where("id", experiment.TaskId).Update(taskUpdate)
+	if err != nil {
+		g.Log().Errorf(ctx, "更新实验任务状态失败: %v", err)
+	}
+	g.Log().Infof(ctx, "模型对比实验完成: ID=%d, Status=%s", experiment.Id, status)
+}
+
+// executeRun 执行单次运行：提交抽取任务、等待完成、解析并保存三元组
+func (e *Experiments) executeRun(ctx context.Context, rc *runContext) ([]neo4j.SimpleTriple, error) {
+	// 字数配额已用完时不再提交, 与抽取任务使用同一套餐配额
+	err := dao.ExtractExperimentRuns.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		return py_service.CheckWordsQuota(ctx, tx, rc.userId, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pyReq := py_service.NewCreateTaskRequest(ctx, rc.model, rc.run.Prompt, rc.files)
+	pyRes, err := e.extractor.CreateTask(ctx, pyReq)
+	if err != nil {
+		return nil, err
+	}
+	_, err = dao.ExtractExperimentRuns.Ctx(ctx).Where("id", rc.run.Id).Update(g.Map{
+		"py_task_id": pyRes.TaskID,
+		"status":     consts.TaskStatusProcessing,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := g.Cfg().MustGet(ctx, "experiment.timeout", 1800).Int()
+	pollInterval := g.Cfg().MustGet(ctx, "experiment.pollInterval", 5).Int()
+	waitCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+	status, err := e.extractor.WaitTask(waitCtx, pyRes.TaskID, time.Duration(pollInterval)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status != "completed" {
+		errorMessage := status.Error
+		if errorMessage == "" {
+			errorMessage = status.Message
+		}
+		return nil, gerror.Newf("抽取任务未完成: %s, %s", status.Status, errorMessage)
+	}
+
+	// 解析每个素材的抽取结果
+	var (
+		triples    []neo4j.SimpleTriple
+		totalWords int
+	)
+	for _, result := range status.Result {
+		if result.Status != "success" || result.OutputFiles == nil {
+			continue
+		}
+		jsonlPath, exists := result.OutputFiles["jsonl"]
+		if !exists {
+			continue
+		}
+		text, err := utils.ExtractTextFromFile(ctx, jsonlPath)
+		if err != nil {
+			g.Log().Errorf(ctx, "提取文件文本失败: %v, 文件路径: %s", err, jsonlPath)
+			continue
+		}
+		totalWords += len(text)
+		materialTriples, err := py_service.ParseTriples(text, rc.materials[result.MaterialId])
+		if err != nil {
+			g.Log().Errorf(ctx, "三元组格式错误: %v, 文件路径: %s", err, jsonlPath)
+			continue
+		}
+		triples = append(triples, materialTriples...)
+	}
+
+	// 保存本次运行的三元组
+	uuidStr := uuid.New().String()
+	timestamp := time.Now().Format("20060102150405")
+	content, _ := json.Marshal(triples)
+	saveDataOutput, err := storage.New().SaveData(ctx, &upload.SaveDataInput{
+		FileName: utils.RemoveExt(fmt.Sprintf("triples_experiment_%d_run_%d_%s_%s", rc.run.ExperimentId, rc.run.Id, timestamp, uuidStr[:8])),
+		Content:  string(content),
+		DataType: "json",
+		UserId:   rc.userId,
+	}, consts.StorageSourceArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	// 与抽取任务相同, 按计入成本系数的字数校验配额, 并在同一事务内累加用户的文字用量
+	wordsUsed := max(int(float64(totalWords)*rc.run.CostMultiplier), 1)
+	err = dao.ExtractExperimentRuns.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if err := py_service.CheckWordsQuota(ctx, tx, rc.userId, wordsUsed); err != nil {
+			return err
+		}
+		_, err := dao.ExtractExperimentRuns.Ctx(ctx).TX(tx).Where("id", rc.run.Id).Update(g.Map{
+			"status":           consts.TaskStatusCompleted,
+			"triples_count":    len(triples),
+			"triple_url":       saveDataOutput.FileName,
+			"words_used":       wordsUsed,
+			"cost":             estimateCost(ctx, wordsUsed),
+			"conformance_rate": conformanceRate(triples, rc.schema),
+			"finish_time":      gtime.Now(),
+			"updated_at":       gtime.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		py_service.RecordWords(ctx, tx, &py_service.WordsUsage{
+			UserId:         rc.userId,
+			Words:          totalWords,
+			CostMultiplier: rc.run.CostMultiplier,
+			ProjectId:      rc.projectId,
+			Model:          rc.model.ModelCode,
+			Source:         consts.UsageSourceExperiment,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 同步提示词使用记录
+	_, err = dao.PromptUsages.Ctx(ctx).Where("experiment_run_id", rc.run.Id).Update(g.Map{
+		"status":        consts.TaskStatusCompleted,
+		"triples_count": len(triples),
+		"updated_at":    gtime.Now(),
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "更新提示词使用记录失败: %v", err)
+	}
+
+	return triples, nil
+}
+
+// Get 获取实验详情及对比报告
+func (e *Experiments) Get(ctx context.Context, userId int, experimentId int) (*entity.ExtractExperiments, []*entity.ExtractExperimentRuns, *Report, error) {
+	var experiment entity.ExtractExperiments
+	err := dao.ExtractExperiments.Ctx(ctx).Where("id", experimentId).Where("user_id", userId).Scan(&experiment)
+	if err != nil || experiment.Id == 0 {
+		return nil, nil, nil, gerror.NewCode(gcode.CodeNotFound, "实验不存在")
+	}
+
+	var runList []*entity.ExtractExperimentRuns
+	err = dao.ExtractExperimentRuns.Ctx(ctx).Where("experiment_id", experimentId).OrderAsc("id").Scan(&runList)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var report *Report
+	if experiment.Report != nil {
+		report = &Report{}
+		if err = experiment.Report.Scan(report); err != nil {
+			g.Log().Errorf(ctx, "解析实验报告失败: %v", err)
+			report = nil
+		}
+	}
+	return &experiment, runList, report, nil
+}
+
+// List 获取项目下的实验列表
+func (e *Experiments) List(ctx context.Context, userId int, projectId int) ([]*entity.ExtractExperiments, error) {
+	var list []*entity.ExtractExperiments
+	err := dao.ExtractExperiments.Ctx(ctx).
+		Where("project_id", projectId).
+		Where("user_id", userId).
+		OrderDesc("id").
+		Scan(&list)
+	return list, err
+}
+
+// Promote 将某次运行的抽取结果合并到项目的三元组中
+func (e *Experiments) Promote(ctx context.Context, userId int, experimentId int, runId int) error {
+	var experiment entity.ExtractExperiments
+	err := dao.ExtractExperiments.Ctx(ctx).Where("id", experimentId).Where("user_id", userId).Scan(&experiment)
+	if err != nil || experiment.Id == 0 {
+		return gerror.NewCode(gcode.CodeNotFound, "实验不存在")
+	}
+	if experiment.Status != consts.TaskStatusCompleted {
+		return gerror.NewCode(gcode.CodeInvalidOperation, "实验尚未完成")
+	}
+
+	var run entity.ExtractExperimentRuns
+	err = dao.ExtractExperimentRuns.Ctx(ctx).Where("id", runId).Where("experiment_id", experimentId).Scan(&run)
+	if err != nil || run.Id == 0 {
+		return gerror.NewCode(gcode.CodeNotFound, "运行结果不存在")
+	}
+	if run.Status != consts.TaskStatusCompleted || run.TripleUrl == "" {
+		return gerror.NewCode(gcode.CodeInvalidOperation, "该运行没有可用的抽取结果")
+	}
+
+	text, err := utils.DownloadTextFromURL(ctx, upload.NewUpload().GenerateFileUrl(ctx, run.TripleUrl))
+	if err != nil {
+		g.Log().Errorf(ctx, "下载实验三元组失败: %v", err)
+		return gerror.New("下载实验三元组失败")
+	}
+	var triples []neo4j.SimpleTriple
+	if err = json.Unmarshal([]byte(text), &triples); err != nil {
+		return gerror.New("实验三元组格式错误")
+	}
+
+	return dao.Projects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 实验只抽取了部分素材, 与重试任务一样替换这些素材的三元组, 保留项目其他素材的三元组
+		var project entity.Projects
+		err := dao.Projects.Ctx(ctx).TX(tx).Where("id", experiment.ProjectId).LockUpdate().Scan(&project)
+		if err != nil {
+			return err
+		}
+		merged, err := py_service.MergeProjectTriples(ctx, &project, triples)
+		if err != nil {
+			return err
+		}
+		if err = py_service.SaveProjectTriples(ctx, tx, experiment.ProjectId, userId, merged); err != nil {
+			return err
+		}
+		_, err = dao.Projects.Ctx(ctx).TX(tx).
+			Where("id", experiment.ProjectId).
+			WhereLT("project_progress", 3).
+			Update(g.Map{"project_progress": 3})
+		if err != nil {
+			return err
+		}
+		_, err = dao.ExtractExperiments.Ctx(ctx).TX(tx).Where("id", experimentId).Update(g.Map{
+			"promoted_run_id": runId,
+			"updated_at":      gtime.Now(),
+		})
+		return err
+	})
+}
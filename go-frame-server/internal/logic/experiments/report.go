@@ -0,0 +1,168 @@
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/external/py_service"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/model/entity"
+	"kgplatform-backend/internal/neo4j"
+	"kgplatform-backend/internal/utils"
+)
+
+// Report 模型对比报告
+type Report struct {
+	SchemaAvailable bool         `json:"schemaAvailable" dc:"项目是否上传了主体结构, 否则不计算符合率"`
+	Runs            []RunReport  `json:"runs"`
+	Pairs           []PairReport `json:"pairs" dc:"两两运行之间的三元组重合度"`
+}
+
+// RunReport 单次运行的统计
+type RunReport struct {
+	RunId           int            `json:"runId"`
+	ModelId         int            `json:"modelId"`
+	Status          string         `json:"status"`
+	TriplesCount    int            `json:"triplesCount"`
+	TypeCounts      map[string]int `json:"typeCounts" dc:"按三元组类型统计的数量"`
+	ConformanceRate float64        `json:"conformanceRate" dc:"符合主体结构的三元组比例"`
+	WordsUsed       int            `json:"wordsUsed"`
+	Cost            float64        `json:"cost"`
+	ErrorMessage    string         `json:"errorMessage,omitempty"`
+}
+
+// PairReport 两次运行之间的重合度
+type PairReport struct {
+	RunId      int     `json:"runId"`
+	OtherRunId int     `json:"otherRunId"`
+	Overlap    int     `json:"overlap" dc:"相同三元组数量"`
+	Jaccard    float64 `json:"jaccard"`
+}
+
+// BuildReport 根据运行结果生成对比报告
+func BuildReport(ctx context.Context, runList []*entity.ExtractExperimentRuns, runTriples map[int][]neo4j.SimpleTriple, schemaAvailable bool) *Report {
+	report := &Report{
+		SchemaAvailable: schemaAvailable,
+		Runs:            make([]RunReport, 0, len(runList)),
+		Pairs:           make([]PairReport, 0),
+	}
+
+	tripleSets := make(map[int]map[string]bool)
+	for _, run := range runList {
+		typeCounts := make(map[string]int)
+		set := make(map[string]bool)
+		for _, triple := range runTriples[run.Id] {
+			typeCounts[py_service.TripleTypeKey(triple)]++
+			set[tripleKey(triple)] = true
+		}
+		tripleSets[run.Id] = set
+
+		report.Runs = append(report.Runs, RunReport{
+			RunId:           run.Id,
+			ModelId:         run.ModelId,
+			Status:          run.Status,
+			TriplesCount:    run.TriplesCount,
+			TypeCounts:      typeCounts,
+			ConformanceRate: run.ConformanceRate,
+			WordsUsed:       run.WordsUsed,
+			Cost:            run.Cost,
+			ErrorMessage:    run.ErrorMessage,
+		})
+	}
+
+	for i := 0; i < len(runList); i++ {
+		for j := i + 1; j < len(runList); j++ {
+			a, b := tripleSets[runList[i].Id], tripleSets[runList[j].Id]
+			overlap := 0
+			for key := range a {
+				if b[key] {
+					overlap++
+				}
+			}
+			union := len(a) + len(b) - overlap
+			jaccard := 0.0
+			if union > 0 {
+				jaccard = round4(float64(overlap) / float64(union))
+			}
+			report.Pairs = append(report.Pairs, PairReport{
+				RunId:      runList[i].Id,
+				OtherRunId: runList[j].Id,
+				Overlap:    overlap,
+				Jaccard:    jaccard,
+			})
+		}
+	}
+
+	return report
+}
+
+// tripleKey 用于比较的三元组标识，忽略大小写和首尾空格
+func tripleKey(triple neo4j.SimpleTriple) string {
+	return strings.ToLower(strings.Join([]string{
+		strings.TrimSpace(triple.Head.Label),
+		strings.TrimSpace(triple.Relationship.Type),
+		strings.TrimSpace(triple.Tail.Label),
+	}, "|"))
+}
+
+// loadSchemaTypes 读取项目主体结构中允许的三元组类型，未上传主体结构时返回 nil
+func loadSchemaTypes(ctx context.Context, project *entity.Projects) map[string]bool {
+	if project.SchemaUrl == "" {
+		return nil
+	}
+	text, err := utils.DownloadTextFromURL(ctx, upload.NewUpload().GenerateFileUrl(ctx, project.SchemaUrl))
+	if err != nil {
+		g.Log().Errorf(ctx, "下载项目主体结构失败: %v", err)
+		return nil
+	}
+
+	var schema struct {
+		Triples []struct {
+			Head         struct{ Type string } `json:"head"`
+			Relationship struct{ Type string } `json:"relationship"`
+			Tail         struct{ Type string } `json:"tail"`
+		} `json:"triples"`
+	}
+	if err = json.Unmarshal([]byte(text), &schema); err != nil {
+		g.Log().Errorf(ctx, "解析项目主体结构失败: %v", err)
+		return nil
+	}
+
+	types := make(map[string]bool)
+	for _, item := range schema.Triples {
+		types[item.Head.Type+"-"+item.Relationship.Type+"-"+item.Tail.Type] = true
+	}
+	return types
+}
+
+// conformanceRate 计算符合主体结构的三元组比例
+func conformanceRate(triples []neo4j.SimpleTriple, schema map[string]bool) float64 {
+	if len(schema) == 0 || len(triples) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, triple := range triples {
+		if schema[py_service.TripleTypeKey(triple)] {
+			matched++
+		}
+	}
+	return round4(float64(matched) / float64(len(triples)))
+}
+
+// estimateCost 按字数超额单价估算费用
+func estimateCost(ctx context.Context, wordsUsed int) float64 {
+	unit := g.Cfg().MustGet(ctx, "overage_fees.words.unit", 1000).Float64()
+	price := g.Cfg().MustGet(ctx, "overage_fees.words.price", 1.0).Float64()
+	if unit <= 0 {
+		return 0
+	}
+	return math.Round(float64(wordsUsed)/unit*price*100) / 100
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
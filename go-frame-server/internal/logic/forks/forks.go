@@ -0,0 +1,282 @@
+package forks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/google/uuid"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/access"
+	"kgplatform-backend/internal/logic/storage"
+	"kgplatform-backend/internal/logic/tasks"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/utils"
+)
+
+// Forks 将项目克隆为调用者的私有项目, 便于在已购买或公开的项目上继续添加素材
+type Forks struct{}
+
+func New() *Forks {
+	return &Forks{}
+}
+
+type ForkInput struct {
+	UserId      int
+	ProjectId   int
+	ProjectName string
+}
+
+type ForkOutput struct {
+	ProjectId  int
+	PipelineId int
+}
+
+// Fork 克隆项目
+// 只有项目所有者、已购买用户或允许克隆的公开项目可以克隆
+// 主体结构、示例原文、示例表格、封面、三元组和图谱文件都复制一份归属新项目, 删除来源项目的文件不影响克隆的项目
+// 图数据库中的子图在克隆后由图谱化任务重建
+func (f *Forks) Fork(ctx context.Context, in *ForkInput) (*ForkOutput, error) {
+	level, project, err := access.New().GetLevel(ctx, in.UserId, in.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	if level < access.LevelBuy && !(project.Forkable && level >= access.LevelPreview) {
+		if level == access.LevelNone {
+			return nil, gerror.NewCode(gcode.CodeNotAuthorized, "项目不可见或不存在")
+		}
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "购买项目后可克隆")
+	}
+
+	// 克隆失败时删除已复制的文件, 避免留下无主文件占用存储配额
+	files := &forkFiles{userId: in.UserId}
+	schemaUrl, err := files.copy(ctx, project.SchemaUrl, "json")
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制主体结构失败")
+	}
+	sampleTextUrl, err := files.copy(ctx, project.SampleTextUrl, "text")
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制示例原文失败")
+	}
+	sampleXlsxUrl, err := files.copyObject(ctx, project.SampleXlsxUrl)
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制示例表格失败")
+	}
+	snapshotPhotoUrl, err := files.copyObject(ctx, project.SnapshotPhotoUrl)
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制封面失败")
+	}
+	tripleUrl, err := files.copy(ctx, project.TripleUrl, "json")
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制三元组失败")
+	}
+	tripleTypeUrl, err := files.copyTripleTypes(ctx, project.TripleTypeUrl)
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制三元组分类失败")
+	}
+	graphUrl, err := files.copyGraph(ctx, project.GraphId)
+	if err != nil {
+		files.remove(ctx)
+		return nil, gerror.Wrap(err, "复制图谱失败")
+	}
+
+	projectName := in.ProjectName
+	if projectName == "" {
+		projectName = project.ProjectName + " (克隆)"
+	}
+
+	out := &ForkOutput{}
+	err = dao.Projects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		now := gtime.Now()
+		data := g.Map{
+			"user_id":             in.UserId,
+			"project_name":        projectName,
+			"project_progress":    project.ProjectProgress,
+			"schema_url":          schemaUrl,
+			"sample_text_url":     sampleTextUrl,
+			"sample_xlsx_url":     sampleXlsxUrl,
+			"snapshot_photo_url":  snapshotPhotoUrl,
+			"triple_url":          tripleUrl,
+			"triple_type_url":     tripleTypeUrl,
+			"description":         project.Description,
+			"extract_config":      project.ExtractConfig,
+			"visibility":          0,
+			"forkable":            false,
+			"forked_from":         project.Id,
+			"forked_from_user_id": project.UserId,
+			"created_at":          now,
+			"updated_at":          now,
+		}
+		if graphUrl != "" {
+			graphId, err := dao.Graphs.Ctx(ctx).TX(tx).Data(g.Map{
+				"url":        graphUrl,
+				"created_at": now,
+				"updated_at": now,
+			}).InsertAndGetId()
+			if err != nil {
+				return err
+			}
+			data["graph_id"] = graphId
+		}
+
+		projectId, err := dao.Projects.Ctx(ctx).TX(tx).Data(data).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		out.ProjectId = int(projectId)
+
+		// 新项目沿用来源项目工作流的起始步骤
+		startStep, err := dao.Pipelines.Ctx(ctx).TX(tx).Where("project_id", project.Id).OrderAsc("id").Value("start_step")
+		if err != nil {
+			return err
+		}
+		pipelineId, err := dao.Pipelines.Ctx(ctx).TX(tx).Data(g.Map{
+			"project_id": out.ProjectId,
+			"start_step": startStep.String(),
+			"created_at": now,
+			"updated_at": now,
+		}).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		out.PipelineId = int(pipelineId)
+		return nil
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "克隆项目失败: %v, 来源项目ID: %d", err, project.Id)
+		files.remove(ctx)
+		return nil, gerror.New("克隆项目失败")
+	}
+
+	// 图数据库中的子图按项目划分, 由图谱化任务根据复制的三元组为新项目重建
+	if tripleUrl != "" {
+		_, err = tasks.New().Create(ctx, &tasks.CreateTaskInput{
+			Type:       consts.TaskTypeGraph,
+			PipelineId: out.PipelineId,
+			ProjectId:  out.ProjectId,
+			Status:     consts.TaskStatusPending,
+			UpdatedAt:  gtime.Now(),
+			CreatedAt:  gtime.Now(),
+		})
+		if err != nil {
+			// 项目已克隆成功, 用户可在工作流中重新执行图谱化
+			g.Log().Errorf(ctx, "创建克隆项目的图谱化任务失败: %v, 项目ID: %d", err, out.ProjectId)
+		}
+	}
+
+	g.Log().Infof(ctx, "用户 %d 克隆项目 %d 为 %d", in.UserId, project.Id, out.ProjectId)
+	return out, nil
+}
+
+// forkFiles 克隆过程中复制的文件
+type forkFiles struct {
+	userId int
+	names  []string
+}
+
+// copy 复制一份文本或 JSON 文件, 返回新文件名, 原文件为空时返回空
+func (f *forkFiles) copy(ctx context.Context, fileName string, dataType string) (string, error) {
+	if fileName == "" {
+		return "", nil
+	}
+	uploadLogic := upload.NewUpload()
+	content, err := utils.DownloadTextFromURL(ctx, uploadLogic.GenerateFileUrl(ctx, fileName))
+	if err != nil {
+		return "", err
+	}
+
+	output, err := storage.New().SaveData(ctx, &upload.SaveDataInput{
+		FileName: utils.RemoveExt(forkFileName()),
+		Content:  content,
+		DataType: dataType,
+		UserId:   f.userId,
+	}, consts.StorageSourceData)
+	if err != nil {
+		return "", err
+	}
+	f.names = append(f.names, output.FileName)
+	return output.FileName, nil
+}
+
+// copyObject 复制一份二进制文件, 保留原文件的扩展名和内容类型, 原文件为空时返回空
+func (f *forkFiles) copyObject(ctx context.Context, fileName string) (string, error) {
+	if fileName == "" {
+		return "", nil
+	}
+	content, contentType, err := upload.NewUpload().ReadObject(ctx, fileName)
+	if err != nil {
+		return "", err
+	}
+	output, err := storage.New().SaveObject(ctx, &upload.SaveObjectInput{
+		FileName:    forkFileName() + path.Ext(fileName),
+		Content:     content,
+		ContentType: contentType,
+		UserId:      f.userId,
+	}, consts.StorageSourceUpload)
+	if err != nil {
+		return "", err
+	}
+	f.names = append(f.names, output.FileName)
+	return output.FileName, nil
+}
+
+// forkFileName 克隆文件的对象名, 不含扩展名
+func forkFileName() string {
+	timestamp := time.Now().Format("20060102150405")
+	return fmt.Sprintf("fork_%s_%s", timestamp, uuid.New().String()[:8])
+}
+
+// copyTripleTypes 复制按三元组类型分类的文件, tripleTypeUrl 为 类型 -> 文件名 的 JSON
+func (f *forkFiles) copyTripleTypes(ctx context.Context, tripleTypeUrl string) (g.Map, error) {
+	if strings.TrimSpace(tripleTypeUrl) == "" {
+		return nil, nil
+	}
+	fileMap := gjson.New(tripleTypeUrl).Map()
+	copied := make(g.Map, len(fileMap))
+	for tripleType, fileName := range fileMap {
+		newFileName, err := f.copy(ctx, g.NewVar(fileName).String(), "json")
+		if err != nil {
+			return nil, err
+		}
+		copied[tripleType] = newFileName
+	}
+	return copied, nil
+}
+
+// copyGraph 复制图谱的节点和边文件
+func (f *forkFiles) copyGraph(ctx context.Context, graphId int) (string, error) {
+	if graphId == 0 {
+		return "", nil
+	}
+	graphUrl, err := dao.Graphs.Ctx(ctx).Where("id", graphId).Value("url")
+	if err != nil {
+		return "", err
+	}
+	return f.copy(ctx, graphUrl.String(), "json")
+}
+
+// remove 删除已复制的文件并释放存储用量
+func (f *forkFiles) remove(ctx context.Context) {
+	for _, name := range f.names {
+		if err := storage.New().Remove(ctx, name); err != nil {
+			g.Log().Errorf(ctx, "删除克隆失败遗留的文件失败: %v, 文件: %s", err, name)
+		}
+	}
+	f.names = nil
+}
@@ -0,0 +1,357 @@
+package invoices
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+// ttfFont 嵌入发票 PDF 的 TrueType 字体, 文本按字形ID编码, 嵌入时只保留用到的字形
+type ttfFont struct {
+	tables   map[string][]byte
+	glyphs   map[rune]uint16
+	advances []uint16 // 各字形的前进宽度, 单位为 1/1000 字号
+	offsets  []int    // 各字形在 glyf 表中的起始偏移, 最后一项为结束偏移
+}
+
+var fonts struct {
+	sync.Mutex
+	path string
+	font *ttfFont
+}
+
+// invoiceFont 读取 invoice.fontFile 配置的 TrueType 字体并缓存
+// 未配置时返回 nil, 发票使用阅读器内置的 STSong-Light 字体, 未安装中文字体包的阅读器可能无法显示中文
+func invoiceFont(ctx context.Context) (*ttfFont, error) {
+	path := g.Cfg().MustGet(ctx, "invoice.fontFile").String()
+	if path == "" {
+		return nil, nil
+	}
+	fonts.Lock()
+	defer fonts.Unlock()
+	if fonts.path == path {
+		return fonts.font, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, gerror.Wrapf(err, "读取发票字体失败: %s", path)
+	}
+	font, err := parseTTF(data)
+	if err != nil {
+		return nil, gerror.Wrapf(err, "解析发票字体失败: %s", path)
+	}
+	fonts.path, fonts.font = path, font
+	return font, nil
+}
+
+// parseTTF 解析 TrueType 字体的字符映射和字形宽度, 只支持 glyf 轮廓的字体
+func parseTTF(data []byte) (*ttfFont, error) {
+	tables, err := ttfTables(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range []string{"cmap", "head", "hhea", "hmtx", "maxp", "loca", "glyf"} {
+		if tables[tag] == nil {
+			return nil, gerror.Newf("字体缺少 %s 表, 请使用 TrueType 字体", tag)
+		}
+	}
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, gerror.New("字体表不完整")
+	}
+	unitsPerEm := float64(binary.BigEndian.Uint16(head[18:]))
+	if unitsPerEm == 0 {
+		return nil, gerror.New("字体的 unitsPerEm 为零")
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	hmtx := tables["hmtx"]
+	if numMetrics == 0 || numMetrics > numGlyphs || len(hmtx) < numMetrics*4 {
+		return nil, gerror.New("字体的 hmtx 表不完整")
+	}
+	font := &ttfFont{tables: tables, advances: make([]uint16, numGlyphs)}
+	for gid := range font.advances {
+		advance := binary.BigEndian.Uint16(hmtx[min(gid, numMetrics-1)*4:])
+		font.advances[gid] = uint16(float64(advance) * 1000 / unitsPerEm)
+	}
+
+	if font.offsets, err = parseLoca(tables["loca"], binary.BigEndian.Uint16(head[50:]), numGlyphs, len(tables["glyf"])); err != nil {
+		return nil, err
+	}
+	if font.glyphs, err = parseCmap(tables["cmap"], numGlyphs); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+// parseLoca 读取各字形轮廓在 glyf 表中的偏移, format 为 0 时偏移按 2 字节存储并除以 2
+func parseLoca(loca []byte, format uint16, numGlyphs int, glyfLength int) ([]int, error) {
+	size := 2
+	if format == 1 {
+		size = 4
+	}
+	if len(loca) < (numGlyphs+1)*size {
+		return nil, gerror.New("字体的 loca 表不完整")
+	}
+	offsets := make([]int, numGlyphs+1)
+	for gid := range offsets {
+		if size == 2 {
+			offsets[gid] = int(binary.BigEndian.Uint16(loca[gid*2:])) * 2
+		} else {
+			offsets[gid] = int(binary.BigEndian.Uint32(loca[gid*4:]))
+		}
+		if offsets[gid] > glyfLength || (gid > 0 && offsets[gid] < offsets[gid-1]) {
+			return nil, gerror.New("字体的 loca 表与 glyf 表不一致")
+		}
+	}
+	return offsets, nil
+}
+
+// subsetTables 嵌入 PDF 的 TrueType 字体需要的表, 字符映射等其他表由 PDF 的字体字典提供
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// subset 生成只保留 gids 及其复合字形引用的字形轮廓的字体文件, 其余字形为空轮廓
+// 字形ID保持不变, 字宽表和 ToUnicode 仍按原字形ID生成; 中文字体有数万个字形, 发票只用到其中几十个
+func (f *ttfFont) subset(gids []uint16) []byte {
+	numGlyphs := len(f.advances)
+	keep := make([]bool, numGlyphs)
+	queue := []int{0}
+	for _, gid := range gids {
+		queue = append(queue, int(gid))
+	}
+	for len(queue) > 0 {
+		gid := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if gid >= numGlyphs || keep[gid] {
+			continue
+		}
+		keep[gid] = true
+		queue = append(queue, glyphComponents(f.glyph(gid))...)
+	}
+
+	var glyf []byte
+	loca := make([]byte, 0, (numGlyphs+1)*4)
+	for gid := 0; gid < numGlyphs; gid++ {
+		loca = binary.BigEndian.AppendUint32(loca, uint32(len(glyf)))
+		if keep[gid] {
+			glyf = append(glyf, f.glyph(gid)...)
+			for len(glyf)%4 != 0 {
+				glyf = append(glyf, 0)
+			}
+		}
+	}
+	loca = binary.BigEndian.AppendUint32(loca, uint32(len(glyf)))
+
+	// loca 统一使用 4 字节偏移, 字体校验和在写入文件后重新计算
+	head := slices.Clone(f.tables["head"])
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := make(map[string][]byte, len(subsetTables))
+	for _, tag := range subsetTables {
+		if data := f.tables[tag]; data != nil {
+			tables[tag] = data
+		}
+	}
+	tables["glyf"], tables["loca"], tables["head"] = glyf, loca, head
+	return writeTTF(tables)
+}
+
+// glyph 字形在 glyf 表中的轮廓数据
+func (f *ttfFont) glyph(gid int) []byte {
+	return f.tables["glyf"][f.offsets[gid]:f.offsets[gid+1]]
+}
+
+// glyphComponents 复合字形引用的字形ID, 简单字形返回 nil
+func glyphComponents(glyph []byte) []int {
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 {
+		return nil
+	}
+	var gids []int
+	for at := 10; at+4 <= len(glyph); {
+		flags := binary.BigEndian.Uint16(glyph[at:])
+		gids = append(gids, int(binary.BigEndian.Uint16(glyph[at+2:])))
+		at += 4
+		// 参数为两个字或两个字节, 之后是可选的缩放或变换矩阵
+		if flags&0x0001 != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&0x0008 != 0:
+			at += 2
+		case flags&0x0040 != 0:
+			at += 4
+		case flags&0x0080 != 0:
+			at += 8
+		}
+		if flags&0x0020 == 0 {
+			break
+		}
+	}
+	return gids
+}
+
+// writeTTF 按标签顺序写出字体文件, 各表按 4 字节对齐, 并重新计算 head 表中的字体校验和
+func writeTTF(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= len(tags) {
+		searchRange *= 2
+		entrySelector++
+	}
+	font := binary.BigEndian.AppendUint32(nil, 0x00010000)
+	font = binary.BigEndian.AppendUint16(font, uint16(len(tags)))
+	font = binary.BigEndian.AppendUint16(font, uint16(searchRange*16))
+	font = binary.BigEndian.AppendUint16(font, uint16(entrySelector))
+	font = binary.BigEndian.AppendUint16(font, uint16((len(tags)-searchRange)*16))
+
+	offset := len(font) + len(tags)*16
+	headOffset := 0
+	for _, tag := range tags {
+		data := tables[tag]
+		if tag == "head" {
+			headOffset = offset
+		}
+		font = append(font, tag...)
+		font = binary.BigEndian.AppendUint32(font, ttfChecksum(data))
+		font = binary.BigEndian.AppendUint32(font, uint32(offset))
+		font = binary.BigEndian.AppendUint32(font, uint32(len(data)))
+		offset += (len(data) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		font = append(font, tables[tag]...)
+		for len(font)%4 != 0 {
+			font = append(font, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-ttfChecksum(font))
+	return font
+}
+
+// ttfChecksum 按 4 字节大端整数累加的校验和, 不足 4 字节的部分补零
+func ttfChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// ttfTables 读取字体的表目录
+func ttfTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, gerror.New("字体文件不完整")
+	}
+	if version := binary.BigEndian.Uint32(data); version != 0x00010000 && version != 0x74727565 {
+		return nil, gerror.New("不是 TrueType 字体文件")
+	}
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+count*16 {
+		return nil, gerror.New("字体文件不完整")
+	}
+	tables := make(map[string][]byte, count)
+	for i := 0; i < count; i++ {
+		record := data[12+i*16:]
+		offset := int(binary.BigEndian.Uint32(record[8:]))
+		length := int(binary.BigEndian.Uint32(record[12:]))
+		if offset < 0 || length < 0 || offset+length > len(data) {
+			return nil, gerror.Newf("字体表 %s 超出文件范围", record[:4])
+		}
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+	return tables, nil
+}
+
+// parseCmap 读取 Unicode 字符映射, 优先使用支持基本平面以外字符的格式 12 子表
+func parseCmap(cmap []byte, numGlyphs int) (map[rune]uint16, error) {
+	if len(cmap) < 4 {
+		return nil, gerror.New("字体的 cmap 表不完整")
+	}
+	var format4, format12 []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count && 4+i*8+8 <= len(cmap); i++ {
+		record := cmap[4+i*8:]
+		platform, encoding := binary.BigEndian.Uint16(record), binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if (platform != 0 && platform != 3) || (platform == 3 && encoding != 1 && encoding != 10) || offset+2 > len(cmap) {
+			continue
+		}
+		switch binary.BigEndian.Uint16(cmap[offset:]) {
+		case 4:
+			format4 = cmap[offset:]
+		case 12:
+			format12 = cmap[offset:]
+		}
+	}
+
+	glyphs := make(map[rune]uint16)
+	add := func(r rune, gid int) {
+		if gid > 0 && gid < numGlyphs {
+			glyphs[r] = uint16(gid)
+		}
+	}
+	switch {
+	case format12 != nil:
+		if len(format12) < 16 {
+			return nil, gerror.New("字体的 cmap 子表不完整")
+		}
+		groups := int(binary.BigEndian.Uint32(format12[12:]))
+		for i := 0; i < groups && 16+i*12+12 <= len(format12); i++ {
+			group := format12[16+i*12:]
+			start, end := binary.BigEndian.Uint32(group), binary.BigEndian.Uint32(group[4:])
+			gid := int(binary.BigEndian.Uint32(group[8:]))
+			for c := start; c <= end && c <= 0x10FFFF; c++ {
+				add(rune(c), gid+int(c-start))
+			}
+		}
+	case format4 != nil:
+		if len(format4) < 14 {
+			return nil, gerror.New("字体的 cmap 子表不完整")
+		}
+		segments := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		endCodes := 14
+		startCodes := endCodes + segments*2 + 2
+		deltas := startCodes + segments*2
+		rangeOffsets := deltas + segments*2
+		if len(format4) < rangeOffsets+segments*2 {
+			return nil, gerror.New("字体的 cmap 子表不完整")
+		}
+		for i := 0; i < segments; i++ {
+			end := int(binary.BigEndian.Uint16(format4[endCodes+i*2:]))
+			start := int(binary.BigEndian.Uint16(format4[startCodes+i*2:]))
+			delta := int(binary.BigEndian.Uint16(format4[deltas+i*2:]))
+			rangeOffset := int(binary.BigEndian.Uint16(format4[rangeOffsets+i*2:]))
+			for c := start; c <= end && c != 0xFFFF; c++ {
+				if rangeOffset == 0 {
+					add(rune(c), (c+delta)&0xFFFF)
+					continue
+				}
+				at := rangeOffsets + i*2 + rangeOffset + (c-start)*2
+				if at+2 > len(format4) {
+					break
+				}
+				if gid := int(binary.BigEndian.Uint16(format4[at:])); gid != 0 {
+					add(rune(c), (gid+delta)&0xFFFF)
+				}
+			}
+		}
+	default:
+		return nil, gerror.New("字体没有 Unicode 字符映射")
+	}
+	return glyphs, nil
+}
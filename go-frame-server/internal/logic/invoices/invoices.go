@@ -0,0 +1,250 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/storage"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Invoices 账单发票: 用户为已支付的账单申请发票, 系统生成 PDF 保存到云存储并邮件通知用户
+type Invoices struct{}
+
+func New() *Invoices {
+	return &Invoices{}
+}
+
+// Invoice 发票信息及下载地址
+type Invoice struct {
+	*entity.BillingInvoices
+	InvoiceNo   string `json:"invoiceNo" dc:"发票编号"`
+	DownloadUrl string `json:"downloadUrl" dc:"发票下载地址, 未开具时为空"`
+}
+
+// RequestInput 申请发票的参数
+type RequestInput struct {
+	UserId    int64
+	BillingId int64
+	Title     string
+	TaxId     string
+}
+
+// Request 为用户已支付的账单申请发票并立即开具, 每个账单只能开具一次
+// 开具失败时保留申请信息, 用户可以再次申请
+func (i *Invoices) Request(ctx context.Context, in *RequestInput) (*Invoice, error) {
+	record, err := ownedRecord(ctx, in.UserId, in.BillingId)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != "paid" {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "仅已支付的账单可以申请发票")
+	}
+	if record.TotalAmount <= 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "账单金额为零, 无需开具发票")
+	}
+	return i.issue(ctx, record, &issueInput{Title: in.Title, TaxId: in.TaxId})
+}
+
+// Reissue 按新的抬头和税号重新开具发票, 为空时沿用原申请信息, 供管理员处理开具有误的发票
+func (i *Invoices) Reissue(ctx context.Context, billingId int64, title string, taxId string) (*Invoice, error) {
+	var record *entity.BillingRecords
+	if err := dao.BillingRecords.Ctx(ctx).Where("id", billingId).Scan(&record); err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "账单不存在")
+	}
+	if record.Status != "paid" {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "仅已支付的账单可以开具发票")
+	}
+	return i.issue(ctx, record, &issueInput{Title: title, TaxId: taxId, Reissue: true})
+}
+
+// Get 获取用户账单的发票信息, 未申请时返回 nil
+func (i *Invoices) Get(ctx context.Context, userId int64, billingId int64) (*Invoice, error) {
+	record, err := ownedRecord(ctx, userId, billingId)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := getInvoice(ctx, billingId)
+	if err != nil || invoice == nil {
+		return nil, err
+	}
+	return newInvoice(ctx, record, invoice), nil
+}
+
+// issueInput 开具发票的参数, 重新开具时抬头和税号为空则沿用原申请信息
+type issueInput struct {
+	Title   string
+	TaxId   string
+	Reissue bool
+}
+
+// issue 锁定发票记录后生成发票 PDF 并保存, 同一账单的并发申请只开具一次, 成功后异步邮件通知账单所有者
+func (i *Invoices) issue(ctx context.Context, record *entity.BillingRecords, in *issueInput) (*Invoice, error) {
+	font, err := invoiceFont(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var overage *entity.BillingOverages
+	if err = dao.BillingOverages.Ctx(ctx).Where("billing_id", record.Id).Scan(&overage); err != nil {
+		return nil, err
+	}
+
+	var (
+		invoice  *entity.BillingInvoices
+		previous string
+		output   *upload.SaveDataOutput
+	)
+	err = dao.BillingInvoices.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if invoice, err = lockInvoice(ctx, tx, record.Id); err != nil {
+			return err
+		}
+		title, taxId := in.Title, in.TaxId
+		if in.Reissue {
+			if !invoice.InvoiceRequired {
+				return gerror.NewCode(gcode.CodeInvalidOperation, "用户未申请该账单的发票")
+			}
+			if title == "" {
+				title = invoice.InvoiceTitle
+			}
+			if taxId == "" {
+				taxId = invoice.InvoiceTaxId
+			}
+		} else if invoice.InvoiceUrl != "" {
+			return gerror.NewCode(gcode.CodeInvalidOperation, "该账单的发票已开具, 如需修改请联系客服")
+		}
+		if err = saveTitle(ctx, tx, invoice, title, taxId); err != nil {
+			return err
+		}
+
+		issuedAt := gtime.Now()
+		output, err = upload.NewUpload().SaveObject(ctx, &upload.SaveObjectInput{
+			FileName:    fmt.Sprintf("invoice_%s_%s.pdf", invoiceNo(record), issuedAt.Format("YmdHis")),
+			Content:     render(ctx, font, record, overage, invoice, issuedAt),
+			ContentType: "application/pdf",
+			UserId:      int(record.UserId),
+		})
+		if err != nil {
+			return gerror.Wrap(err, "保存发票文件失败")
+		}
+		// 发票由系统开具, 计入账单所有者的存储用量但不受存储配额限制
+		err = storage.New().Record(ctx, &storage.RecordInput{
+			UserId:     int(record.UserId),
+			ObjectName: output.FileName,
+			Size:       output.FileSize,
+			Source:     consts.StorageSourceInvoice,
+		})
+		if err != nil {
+			g.Log().Errorf(ctx, "记录发票存储用量失败: %v, 账单ID: %d", err, record.Id)
+		}
+
+		_, err = dao.BillingInvoices.Ctx(ctx).TX(tx).Where("id", invoice.Id).Data(g.Map{
+			"invoice_url":       output.FileName,
+			"invoice_issued_at": issuedAt,
+			"updated_at":        gtime.Now(),
+		}).Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新发票信息失败")
+		}
+		previous = invoice.InvoiceUrl
+		invoice.InvoiceUrl = output.FileName
+		invoice.InvoiceIssuedAt = issuedAt
+		return nil
+	})
+	if err != nil {
+		// 文件已保存但发票未更新时删除文件, 避免留下无主的对象
+		if output != nil {
+			if removeErr := upload.NewUpload().RemoveObject(ctx, output.FileName); removeErr != nil {
+				g.Log().Errorf(ctx, "删除未使用的发票文件失败: %v, 文件: %s", removeErr, output.FileName)
+			}
+		}
+		return nil, err
+	}
+
+	// 重新开具后原发票文件不再使用
+	if previous != "" && previous != invoice.InvoiceUrl {
+		if err = storage.New().Remove(ctx, previous); err != nil {
+			g.Log().Errorf(ctx, "删除原发票文件失败: %v, 账单ID: %d", err, record.Id)
+		}
+	}
+	g.Log().Infof(ctx, "账单 %d 发票已开具: %s", record.Id, invoice.InvoiceUrl)
+
+	result := newInvoice(ctx, record, invoice)
+	go notifyIssued(gctx.NeverDone(ctx), record, result)
+	return result, nil
+}
+
+// ownedRecord 获取用户的账单, 团队账单归属团队所有者
+func ownedRecord(ctx context.Context, userId int64, billingId int64) (*entity.BillingRecords, error) {
+	var record *entity.BillingRecords
+	err := dao.BillingRecords.Ctx(ctx).
+		Where("id", billingId).
+		Where("user_id", userId).
+		Scan(&record)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "账单不存在")
+	}
+	return record, nil
+}
+
+func getInvoice(ctx context.Context, billingId int64) (*entity.BillingInvoices, error) {
+	var invoice *entity.BillingInvoices
+	err := dao.BillingInvoices.Ctx(ctx).Where("billing_id", billingId).Scan(&invoice)
+	return invoice, err
+}
+
+// lockInvoice 锁定账单的发票记录, 出账时未生成占位记录的账单先补上, 同一账单的开具依次执行
+func lockInvoice(ctx context.Context, tx gdb.TX, billingId int64) (*entity.BillingInvoices, error) {
+	_, err := tx.Exec(`insert into billing_invoices (billing_id, created_at, updated_at) values (?, now(), now())
+		on conflict (billing_id) do nothing`, billingId)
+	if err != nil {
+		return nil, gerror.Wrap(err, "创建发票记录失败")
+	}
+	var invoice *entity.BillingInvoices
+	err = dao.BillingInvoices.Ctx(ctx).TX(tx).Where("billing_id", billingId).LockUpdate().Scan(&invoice)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// saveTitle 保存发票抬头和税号
+func saveTitle(ctx context.Context, tx gdb.TX, invoice *entity.BillingInvoices, title string, taxId string) error {
+	invoice.InvoiceRequired = true
+	invoice.InvoiceTitle = strings.TrimSpace(title)
+	invoice.InvoiceTaxId = strings.ToUpper(strings.TrimSpace(taxId))
+	_, err := dao.BillingInvoices.Ctx(ctx).TX(tx).Where("id", invoice.Id).Data(g.Map{
+		"invoice_required": invoice.InvoiceRequired,
+		"invoice_title":    invoice.InvoiceTitle,
+		"invoice_tax_id":   invoice.InvoiceTaxId,
+		"updated_at":       gtime.Now(),
+	}).Update()
+	if err != nil {
+		return gerror.Wrap(err, "保存发票申请失败")
+	}
+	return nil
+}
+
+func newInvoice(ctx context.Context, record *entity.BillingRecords, invoice *entity.BillingInvoices) *Invoice {
+	result := &Invoice{BillingInvoices: invoice, InvoiceNo: invoiceNo(record)}
+	if invoice.InvoiceUrl != "" {
+		result.DownloadUrl = upload.NewUpload().GenerateFileUrl(ctx, invoice.InvoiceUrl)
+	}
+	return result
+}
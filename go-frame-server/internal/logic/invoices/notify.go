@@ -0,0 +1,33 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/email"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// notifyIssued 发票开具后给账单所有者发送邮件, 发送失败只记录日志
+func notifyIssued(ctx context.Context, record *entity.BillingRecords, invoice *Invoice) {
+	var user *entity.Users
+	if err := dao.Users.Ctx(ctx).Where("id", record.UserId).Scan(&user); err != nil {
+		g.Log().Errorf(ctx, "获取用户信息失败: %v", err)
+		return
+	}
+	if user == nil || user.Email == "" {
+		return
+	}
+
+	subject := fmt.Sprintf("您的发票已开具（账单 #%d）", record.Id)
+	body := fmt.Sprintf(
+		"您申请的发票已开具。<br/>发票编号: %s<br/>账期: %s<br/>发票抬头: %s<br/>金额: %.2f 元<br/><a href=\"%s\">点击下载发票</a>",
+		invoice.InvoiceNo, record.BillingPeriod, invoice.InvoiceTitle, record.TotalAmount, invoice.DownloadUrl,
+	)
+	if err := email.SendHTML(ctx, user.Email, subject, body); err != nil {
+		g.Log().Errorf(ctx, "发送发票通知邮件失败: %v, 账单ID: %d", err, record.Id)
+	}
+}
@@ -0,0 +1,194 @@
+package invoices
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// A4 纸张尺寸, 单位为点
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+)
+
+// pdfPage 单页 PDF, 指定字体时嵌入该字体, 否则中文使用阅读器内置的 STSong-Light 字体
+type pdfPage struct {
+	content strings.Builder
+	font    *ttfFont
+	used    map[uint16]rune // 用到的字形及对应字符, 用于字宽表和复制文本
+}
+
+func newPdfPage(font *ttfFont) *pdfPage {
+	return &pdfPage{font: font, used: make(map[uint16]rune)}
+}
+
+// text 在 (x, y) 处输出文本, y 从页面底部起算
+func (p *pdfPage) text(x float64, y float64, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F1 %.1f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, y, p.encode(s))
+}
+
+// textRight 文本右对齐于 x
+func (p *pdfPage) textRight(x float64, y float64, size float64, s string) {
+	p.text(x-p.textWidth(s, size), y, size, s)
+}
+
+// textCenter 文本居中于 x
+func (p *pdfPage) textCenter(x float64, y float64, size float64, s string) {
+	p.text(x-p.textWidth(s, size)/2, y, size, s)
+}
+
+// encode 嵌入字体时按字形ID编码, 字体中没有的字符输出为空白字形
+func (p *pdfPage) encode(s string) string {
+	if p.font == nil {
+		return encodeText(s)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		gid := p.font.glyphs[r]
+		if gid != 0 {
+			p.used[gid] = r
+		}
+		fmt.Fprintf(&b, "%04X", gid)
+	}
+	return b.String()
+}
+
+// textWidth 嵌入字体时按字形宽度计算文本宽度, 否则估算
+func (p *pdfPage) textWidth(s string, size float64) float64 {
+	if p.font == nil {
+		return textWidth(s, size)
+	}
+	var width float64
+	for _, r := range s {
+		width += float64(p.font.advances[p.font.glyphs[r]]) * size / 1000
+	}
+	return width
+}
+
+// line 画一条线段
+func (p *pdfPage) line(x1 float64, y1 float64, x2 float64, y2 float64, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// bytes 生成完整的 PDF 文件
+func (p *pdfPage) bytes() []byte {
+	stream := p.content.String()
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 4 0 R >> >> /Contents 7 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light-UniGB-UCS2-H /Encoding /UniGB-UCS2-H /DescendantFonts [5 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> /FontDescriptor 6 0 R /DW 1000 /W [1 95 500] >>",
+		"<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream),
+	}
+	if p.font != nil {
+		objects[3] = "<< /Type /Font /Subtype /Type0 /BaseFont /InvoiceFont /Encoding /Identity-H /DescendantFonts [5 0 R] /ToUnicode 9 0 R >>"
+		objects[4] = fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /InvoiceFont /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor 6 0 R /DW 1000 /W [%s] /CIDToGIDMap /Identity >>", p.widths())
+		objects[5] = "<< /Type /FontDescriptor /FontName /InvoiceFont /Flags 4 /FontBBox [0 -200 1000 900] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 700 /StemV 80 /FontFile2 8 0 R >>"
+		objects = append(objects, p.fontFile(), p.toUnicode())
+	}
+
+	var (
+		buf     bytes.Buffer
+		offsets = make([]int, len(objects))
+	)
+	buf.WriteString("%PDF-1.4\n")
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// sortedGlyphs 用到的字形ID, 按升序排列
+func (p *pdfPage) sortedGlyphs() []uint16 {
+	gids := make([]uint16, 0, len(p.used))
+	for gid := range p.used {
+		gids = append(gids, gid)
+	}
+	slices.Sort(gids)
+	return gids
+}
+
+// widths 用到的字形的宽度表
+func (p *pdfPage) widths() string {
+	var b strings.Builder
+	for _, gid := range p.sortedGlyphs() {
+		fmt.Fprintf(&b, "%d [%d] ", gid, p.font.advances[gid])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// fontFile 压缩后嵌入的字体文件, 只包含用到的字形
+func (p *pdfPage) fontFile() string {
+	data := p.font.subset(p.sortedGlyphs())
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", buf.Len(), len(data), buf.String())
+}
+
+// toUnicode 字形到字符的映射, 使发票中的文本可以复制和搜索
+func (p *pdfPage) toUnicode() string {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	gids := p.sortedGlyphs()
+	for len(gids) > 0 {
+		// 每段最多 100 项
+		n := min(len(gids), 100)
+		fmt.Fprintf(&b, "%d beginbfchar\n", n)
+		for _, gid := range gids[:n] {
+			fmt.Fprintf(&b, "<%04X> <", gid)
+			for _, unit := range utf16.Encode([]rune{p.used[gid]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+		gids = gids[n:]
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CIDResource defineresource pop\nend\nend\n")
+	cmap := b.String()
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(cmap), cmap)
+}
+
+// encodeText 按 UniGB-UCS2-H 编码为十六进制字符串, 基本平面以外的字符以问号代替
+func encodeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF || r == utf8.RuneError {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+// textWidth 估算文本宽度: 半角字符按半个字宽, 其余按一个字宽
+func textWidth(s string, size float64) float64 {
+	var width float64
+	for _, r := range s {
+		if r < 0x80 {
+			width += size / 2
+		} else {
+			width += size
+		}
+	}
+	return width
+}
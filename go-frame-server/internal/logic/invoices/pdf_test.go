@@ -0,0 +1,298 @@
+package invoices
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcfg"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/model/entity"
+)
+
+// testFont 构造只有三个字形的 TrueType 字体: 发->1, 票->2, A->3, 票为引用 A 的复合字形
+func testFont() []byte {
+	u16 := func(v ...int) []byte {
+		b := make([]byte, 0, len(v)*2)
+		for _, n := range v {
+			b = binary.BigEndian.AppendUint16(b, uint16(n))
+		}
+		return b
+	}
+
+	// cmap 格式 4, 每个字符一段, 最后一段以 0xFFFF 结束
+	codes := []int{'A', '发', '票', 0xFFFF}
+	gids := []int{3, 1, 2, 0}
+	var ends, starts, deltas, offsets []int
+	for i, c := range codes {
+		ends = append(ends, c)
+		starts = append(starts, c)
+		deltas = append(deltas, (gids[i]-c+0x10000)&0xFFFF)
+		offsets = append(offsets, 0)
+	}
+	if codes[len(codes)-1] == 0xFFFF {
+		deltas[len(deltas)-1] = 1
+	}
+	sub := u16(4, 0, 0, len(codes)*2, 0, 0, 0)
+	sub = append(sub, u16(ends...)...)
+	sub = append(sub, u16(0)...)
+	sub = append(sub, u16(starts...)...)
+	sub = append(sub, u16(deltas...)...)
+	sub = append(sub, u16(offsets...)...)
+	binary.BigEndian.PutUint16(sub[2:], uint16(len(sub)))
+	cmap := append(u16(0, 1, 3, 1), binary.BigEndian.AppendUint32(nil, 12)...)
+	cmap = append(cmap, sub...)
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 2048)
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[34:], 4)
+	maxp := u16(0, 0x5000, 4)
+	hmtx := u16(1024, 0, 2048, 0, 2048, 0, 1229, 0)
+	// 简单字形为一个轮廓的字形头加两字节填充, 复合字形引用字形 3, 参数为两个字节
+	simple := func(fill int) []byte {
+		return append(u16(1, 0, 0, 100, 100), byte(fill), byte(fill))
+	}
+	glyf := slices.Concat(simple(0xA0), simple(0xA1), u16(0xFFFF, 0, 0, 100, 100, 0, 3, 0), simple(0xA3))
+	loca := u16(0, 6, 12, 20, 26)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"cmap", cmap}, {"glyf", glyf}, {"head", head}, {"hhea", hhea}, {"hmtx", hmtx}, {"loca", loca}, {"maxp", maxp}}
+	font := binary.BigEndian.AppendUint32(nil, 0x00010000)
+	font = append(font, u16(len(tables), 0, 0, 0)...)
+	offset := len(font) + len(tables)*16
+	for _, table := range tables {
+		font = append(font, table.tag...)
+		font = binary.BigEndian.AppendUint32(font, 0)
+		font = binary.BigEndian.AppendUint32(font, uint32(offset))
+		font = binary.BigEndian.AppendUint32(font, uint32(len(table.data)))
+		offset += len(table.data)
+	}
+	for _, table := range tables {
+		font = append(font, table.data...)
+	}
+	return font
+}
+
+// checkXref 校验交叉引用表中每个对象的偏移量
+func checkXref(t *testing.T, pdf []byte) {
+	t.Helper()
+	matches := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if matches == nil {
+		t.Fatalf("PDF 缺少 startxref")
+	}
+	xref, _ := strconv.Atoi(string(matches[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref 未指向交叉引用表")
+	}
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(strings.Fields(lines[2+i])[0])
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Errorf("对象 %d 的偏移量 %d 错误", i, offset)
+		}
+	}
+}
+
+func TestParseTTF(t *testing.T) {
+	font, err := parseTTF(testFont())
+	if err != nil {
+		t.Fatalf("解析字体失败: %v", err)
+	}
+	for r, want := range map[rune]uint16{'发': 1, '票': 2, 'A': 3, 'B': 0} {
+		if gid := font.glyphs[r]; gid != want {
+			t.Errorf("%c 的字形为 %d, 期望 %d", r, gid, want)
+		}
+	}
+	if want := []uint16{500, 1000, 1000, 600}; fmt.Sprint(font.advances) != fmt.Sprint(want) {
+		t.Errorf("字形宽度为 %v, 期望 %v", font.advances, want)
+	}
+
+	if _, err = parseTTF([]byte("OTTO0000000000")); err == nil {
+		t.Errorf("非 TrueType 字体应解析失败")
+	}
+}
+
+func TestSubset(t *testing.T) {
+	font, err := parseTTF(testFont())
+	if err != nil {
+		t.Fatalf("解析字体失败: %v", err)
+	}
+	for _, c := range []struct {
+		gids []uint16
+		kept []int // 保留轮廓的字形
+	}{
+		{nil, []int{0}},
+		{[]uint16{1}, []int{0, 1}},
+		{[]uint16{2}, []int{0, 2, 3}}, // 复合字形引用的字形同时保留
+		{[]uint16{3, 1}, []int{0, 1, 3}},
+	} {
+		data := font.subset(c.gids)
+		if sum := ttfChecksum(data); sum != 0xB1B0AFBA {
+			t.Errorf("%v: 字体校验和为 %08X", c.gids, sum)
+		}
+		tables, err := ttfTables(data)
+		if err != nil {
+			t.Fatalf("%v: 读取子集字体失败: %v", c.gids, err)
+		}
+		if tables["cmap"] != nil || binary.BigEndian.Uint16(tables["head"][50:]) != 1 {
+			t.Errorf("%v: 子集字体不应包含 cmap, 且 loca 应使用 4 字节偏移", c.gids)
+		}
+		offsets, err := parseLoca(tables["loca"], 1, len(font.advances), len(tables["glyf"]))
+		if err != nil {
+			t.Fatalf("%v: 读取子集字体的 loca 失败: %v", c.gids, err)
+		}
+		var kept []int
+		for gid := range font.advances {
+			glyph := tables["glyf"][offsets[gid]:offsets[gid+1]]
+			if len(glyph) == 0 {
+				continue
+			}
+			kept = append(kept, gid)
+			if original := font.glyph(gid); !bytes.HasPrefix(glyph, original) {
+				t.Errorf("%v: 字形 %d 的轮廓为 %X, 期望 %X", c.gids, gid, glyph, original)
+			}
+		}
+		if fmt.Sprint(kept) != fmt.Sprint(c.kept) {
+			t.Errorf("%v: 保留的字形为 %v, 期望 %v", c.gids, kept, c.kept)
+		}
+	}
+}
+
+func TestPdfPageEmbeddedFont(t *testing.T) {
+	font, err := parseTTF(testFont())
+	if err != nil {
+		t.Fatalf("解析字体失败: %v", err)
+	}
+	page := newPdfPage(font)
+	page.text(60, 700, 10, "发票A")
+	page.textRight(535, 680, 10, "票B")
+
+	if w := page.textWidth("发票A", 10); w != 26 {
+		t.Errorf("文本宽度为 %v, 期望 26", w)
+	}
+	pdf := page.bytes()
+	for _, want := range []string{
+		"<000100020003> Tj",
+		"<00020000> Tj",
+		"/CIDFontType2",
+		"/FontFile2 8 0 R",
+		"/ToUnicode 9 0 R",
+		"/W [1 [1000] 2 [1000] 3 [600]]",
+		"<0001> <53D1>",
+		"<0003> <0041>",
+	} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("PDF 中缺少 %q", want)
+		}
+	}
+	if bytes.Contains(pdf, []byte("STSong-Light")) {
+		t.Errorf("嵌入字体时不应引用 STSong-Light")
+	}
+	checkXref(t, pdf)
+}
+
+func TestPdfPageBuiltinFont(t *testing.T) {
+	page := newPdfPage(nil)
+	page.textCenter(297, 760, 22, "发票")
+	pdf := page.bytes()
+	if !bytes.Contains(pdf, []byte("<53D17968> Tj")) || !bytes.Contains(pdf, []byte("/STSong-Light")) {
+		t.Errorf("未使用内置字体输出文本")
+	}
+	if encodeText("A😀") != "0041003F" {
+		t.Errorf("基本平面以外的字符应以问号代替: %s", encodeText("A😀"))
+	}
+	checkXref(t, pdf)
+}
+
+func TestInvoiceFont(t *testing.T) {
+	ctx := context.Background()
+	setFontConfig := func(path string) {
+		adapter, err := gcfg.NewAdapterContent(fmt.Sprintf(`{"invoice":{"sellerName":"测试公司","fontFile":%q}}`, path))
+		if err != nil {
+			t.Fatalf("创建配置失败: %v", err)
+		}
+		g.Cfg().SetAdapter(adapter)
+	}
+
+	setFontConfig("")
+	if font, err := invoiceFont(ctx); font != nil || err != nil {
+		t.Errorf("未配置字体时应使用内置字体: %v, %v", font, err)
+	}
+
+	setFontConfig(filepath.Join(t.TempDir(), "missing.ttf"))
+	if _, err := invoiceFont(ctx); err == nil {
+		t.Errorf("字体文件不存在时应返回错误")
+	}
+
+	path := filepath.Join(t.TempDir(), "font.ttf")
+	if err := os.WriteFile(path, testFont(), 0o644); err != nil {
+		t.Fatalf("写入字体失败: %v", err)
+	}
+	setFontConfig(path)
+	font, err := invoiceFont(ctx)
+	if err != nil || font == nil {
+		t.Fatalf("读取字体失败: %v", err)
+	}
+	if cached, _ := invoiceFont(ctx); cached != font {
+		t.Errorf("字体未缓存")
+	}
+
+	pdf := render(ctx, font, &entity.BillingRecords{
+		Id:                  12,
+		BillingPeriod:       "2026-09",
+		BillingType:         "month",
+		BaseSubscriptionFee: 100,
+		Subtotal:            100,
+		TotalAmount:         100,
+	}, nil, &entity.BillingInvoices{InvoiceTitle: "发票A"}, gtime.NewFromStr("2026-10-01"))
+	if !bytes.Contains(pdf, []byte("/FontFile2")) || !bytes.Contains(pdf, []byte("000100020003> Tj")) {
+		t.Errorf("发票未使用配置的字体")
+	}
+	checkXref(t, pdf)
+}
+
+func TestLineItems(t *testing.T) {
+	record := &entity.BillingRecords{
+		BillingType:         "month",
+		BaseSubscriptionFee: 100,
+		OverageFee:          12.5,
+		Subtotal:            112.5,
+	}
+	overage := &entity.BillingOverages{
+		WordsOverageAmount:   2.5,
+		WordsOverageFee:      1.5,
+		StorageOverageAmount: 1,
+		StorageOverageFee:    11,
+	}
+	items := lineItems(record, overage)
+	var total float64
+	for _, item := range items {
+		total += item.Amount
+	}
+	if len(items) != 3 || round2(total) != record.Subtotal {
+		t.Errorf("明细为 %d 行, 合计 %v, 期望 3 行, 合计 %v", len(items), total, record.Subtotal)
+	}
+	if items[1].Quantity != "2.5 千字" {
+		t.Errorf("字数超额的数量为 %q", items[1].Quantity)
+	}
+
+	// 没有超额明细的历史账单按超额费用合计为一行
+	items = lineItems(record, nil)
+	if len(items) != 2 || items[1].Name != "超额用量费" || items[1].Amount != 12.5 {
+		t.Errorf("历史账单的明细不符合预期: %+v", items[1])
+	}
+}
@@ -0,0 +1,148 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/model/entity"
+)
+
+// lineItem 发票明细行
+type lineItem struct {
+	Name     string
+	Quantity string
+	Amount   float64
+}
+
+// billingTypeNames 账单类型对应的明细名称
+var billingTypeNames = map[string]string{
+	"subscription": "套餐订阅费",
+	"month":        "基础订阅费",
+	"project":      "项目购买",
+	"overage":      "超额用量费",
+	"refund":       "退款",
+}
+
+// invoiceNo 发票编号, 由账期和账单ID组成, 重新开具时保持不变
+func invoiceNo(record *entity.BillingRecords) string {
+	return fmt.Sprintf("INV%s%08d", strings.ReplaceAll(record.BillingPeriod, "-", ""), record.Id)
+}
+
+// lineItems 由账单和超额明细生成发票明细, 明细金额之和等于账单小计
+func lineItems(record *entity.BillingRecords, overage *entity.BillingOverages) []*lineItem {
+	var items []*lineItem
+	name := billingTypeNames[record.BillingType]
+	if record.BaseSubscriptionFee > 0 {
+		items = append(items, &lineItem{Name: name, Quantity: "1", Amount: record.BaseSubscriptionFee})
+	}
+
+	overageFee := 0.0
+	if overage != nil {
+		for _, line := range []struct {
+			name   string
+			amount float64
+			unit   string
+			fee    float64
+		}{
+			{"字数超额", overage.WordsOverageAmount, "千字", overage.WordsOverageFee},
+			{"存储超额", overage.StorageOverageAmount, "GB", overage.StorageOverageFee},
+			{"流量超额", overage.TrafficOverageAmount, "GB", overage.TrafficOverageFee},
+			{"算力超额", overage.CuOverageAmount, "CU", overage.CuOverageFee},
+		} {
+			if line.fee <= 0 {
+				continue
+			}
+			items = append(items, &lineItem{
+				Name:     line.name,
+				Quantity: fmt.Sprintf("%g %s", line.amount, line.unit),
+				Amount:   line.fee,
+			})
+			overageFee += line.fee
+		}
+	}
+	// 没有超额明细的历史账单按账单上的超额费用合计为一行
+	if overageFee == 0 && record.OverageFee > 0 {
+		items = append(items, &lineItem{Name: billingTypeNames["overage"], Quantity: "1", Amount: record.OverageFee})
+		overageFee = record.OverageFee
+	}
+
+	// 项目购买等订单账单只有小计, 以订单标题作为明细名称
+	if rest := round2(record.Subtotal - record.BaseSubscriptionFee - overageFee); rest > 0 {
+		if record.Remark != "" {
+			name = record.Remark
+		}
+		items = append(items, &lineItem{Name: name, Quantity: "1", Amount: rest})
+	}
+	return items
+}
+
+// render 生成发票 PDF, font 为空时使用阅读器内置字体
+func render(ctx context.Context, font *ttfFont, record *entity.BillingRecords, overage *entity.BillingOverages, invoice *entity.BillingInvoices, issuedAt *gtime.Time) []byte {
+	var (
+		page  = newPdfPage(font)
+		left  = 60.0
+		right = pageWidth - 60
+		y     = pageHeight - 80
+	)
+
+	page.textCenter(pageWidth/2, y, 22, "发  票")
+	y -= 16
+	page.line(pageWidth/2-60, y, pageWidth/2+60, y, 1)
+
+	y -= 36
+	page.text(left, y, 10, "发票编号: "+invoiceNo(record))
+	page.textRight(right, y, 10, "开具日期: "+issuedAt.Format("Y-m-d"))
+	y -= 18
+	page.text(left, y, 10, fmt.Sprintf("账单编号: %d", record.Id))
+	page.textRight(right, y, 10, "账期: "+record.BillingPeriod)
+
+	y -= 32
+	page.text(left, y, 11, "购买方名称: "+invoice.InvoiceTitle)
+	y -= 18
+	page.text(left, y, 11, "纳税人识别号: "+invoice.InvoiceTaxId)
+	y -= 26
+	page.text(left, y, 11, "销售方名称: "+g.Cfg().MustGet(ctx, "invoice.sellerName").String())
+	y -= 18
+	page.text(left, y, 11, "纳税人识别号: "+g.Cfg().MustGet(ctx, "invoice.sellerTaxId").String())
+
+	// 明细表
+	y -= 30
+	page.line(left, y, right, y, 1)
+	y -= 18
+	page.text(left+8, y, 11, "项目")
+	page.textRight(right-150, y, 11, "数量")
+	page.textRight(right-8, y, 11, "金额(元)")
+	y -= 10
+	page.line(left, y, right, y, 0.5)
+	for _, item := range lineItems(record, overage) {
+		y -= 20
+		page.text(left+8, y, 10, item.Name)
+		page.textRight(right-150, y, 10, item.Quantity)
+		page.textRight(right-8, y, 10, fmt.Sprintf("%.2f", item.Amount))
+	}
+	y -= 12
+	page.line(left, y, right, y, 0.5)
+
+	y -= 20
+	page.textRight(right-8, y, 10, fmt.Sprintf("小计: %.2f", record.Subtotal))
+	if record.DiscountAmount > 0 {
+		y -= 18
+		page.textRight(right-8, y, 10, fmt.Sprintf("折扣: -%.2f", record.DiscountAmount))
+	}
+	y -= 22
+	page.textRight(right-8, y, 12, fmt.Sprintf("合计: ￥%.2f", record.TotalAmount))
+	y -= 12
+	page.line(left, y, right, y, 1)
+
+	page.text(left, 60, 9, "本发票由系统根据已支付账单自动生成, 如信息有误请联系客服重新开具。")
+	return page.bytes()
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,191 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/coupons"
+	"kgplatform-backend/internal/logic/revenue"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// onPaid 支付成功: 记录账单和支付记录, 并按订单类型发放权益
+// 只在订单状态变为已支付时调用一次, 与状态更新在同一事务内
+func onPaid(ctx context.Context, tx gdb.TX, order *entity.PaymentOrders, paidAt *gtime.Time) (billingId int, paymentId int, err error) {
+	amount := float64(order.AmountCent) / 100
+	bizData := gjson.New(order.BizData)
+
+	// 使用优惠码的订单, 账单记录优惠前的金额和优惠金额
+	redemption, err := coupons.GetRedemption(ctx, tx, order.OutTradeNo)
+	if err != nil {
+		return 0, 0, err
+	}
+	subtotal, discount := amount, 0.0
+	if redemption != nil {
+		subtotal = float64(redemption.OriginalAmountCent) / 100
+		discount = float64(redemption.DiscountCent) / 100
+	}
+
+	if order.OrderType == consts.OrderTypeBilling {
+		billingId = order.BizId
+		data := g.Map{
+			"status":     "paid",
+			"updated_at": gtime.Now(),
+		}
+		if redemption != nil {
+			data["discount_amount"] = gdb.Raw(fmt.Sprintf("discount_amount + %.2f", discount))
+			data["total_amount"] = amount
+		}
+		_, err = dao.BillingRecords.Ctx(ctx).TX(tx).Where("id", billingId).Data(data).Update()
+	} else {
+		data := g.Map{
+			"user_id":         order.UserId,
+			"billing_period":  paidAt.Format("Y-m"),
+			"billing_date":    paidAt.Format("Y-m-d"),
+			"billing_type":    order.OrderType,
+			"subtotal":        subtotal,
+			"discount_amount": discount,
+			"total_amount":    amount,
+			"status":          "paid",
+			"remark":          order.Subject,
+		}
+		if order.OrderType == consts.OrderTypeSubscription {
+			data["base_subscription_fee"] = subtotal
+		}
+		var id int64
+		id, err = dao.BillingRecords.Ctx(ctx).TX(tx).Data(data).InsertAndGetId()
+		billingId = int(id)
+	}
+	if err != nil {
+		return 0, 0, gerror.Wrap(err, "更新账单失败")
+	}
+
+	id, err := dao.BillingPayments.Ctx(ctx).TX(tx).Data(g.Map{
+		"billing_id":             billingId,
+		"payment_status":         "paid",
+		"payment_method":         order.Provider,
+		"payment_amount":         amount,
+		"payment_transaction_id": order.TransactionId,
+		"payment_channel":        order.Provider,
+		"paid_at":                paidAt,
+	}).InsertAndGetId()
+	if err != nil {
+		return 0, 0, gerror.Wrap(err, "记录支付失败")
+	}
+	paymentId = int(id)
+
+	if err = coupons.Redeem(ctx, tx, order.OutTradeNo, billingId); err != nil {
+		return 0, 0, err
+	}
+
+	switch order.OrderType {
+	case consts.OrderTypeProject:
+		purchaseType := bizData.Get("purchaseType", consts.PurchaseTypeBuy).String()
+		// 记录购买时的抽成比例, 创作者收入按此比例记账
+		var rate float64
+		if rate, err = revenue.CommissionRate(ctx); err != nil {
+			return 0, 0, err
+		}
+		_, err = tx.Exec(`
+			insert into user_project_purchases (user_id, project_id, billing_id, payment_id, purchase_price, purchase_type, commission_rate, status, created_at, updated_at)
+			values (?, ?, ?, ?, ?, ?, ?, ?, now(), now())
+			on conflict (user_id, project_id, purchase_type) do update
+			set billing_id = excluded.billing_id, payment_id = excluded.payment_id, purchase_price = excluded.purchase_price,
+			    commission_rate = excluded.commission_rate, status = excluded.status, updated_at = now()`,
+			order.UserId, order.BizId, billingId, paymentId, amount, purchaseType, rate, consts.PurchaseStatusCompleted)
+		if err != nil {
+			return 0, 0, gerror.Wrap(err, "发放项目权限失败")
+		}
+	case consts.OrderTypeSubscription:
+		// 升级套餐的订单支付差价后使变更生效
+		// 变更已取消时仍记录支付, 事务提交后退款
+		if changeId := bizData.Get("changeId").Int(); changeId > 0 {
+			if _, err = billing.ApplyChange(ctx, tx, changeId, paidAt); err != nil {
+				return 0, 0, gerror.Wrap(err, "变更套餐失败")
+			}
+			break
+		}
+		plan := bizData.Get("plan").String()
+		if plan == "" {
+			return 0, 0, gerror.Newf("订阅订单 %s 缺少套餐参数", order.OutTradeNo)
+		}
+		_, err = tx.Exec(`
+			insert into user_subscriptions (user_id, user_plan, subscription_status, quota_reset_date, created_at, updated_at)
+			values (?, ?, 'active', ?, now(), now())
+			on conflict (user_id) do update
+			set user_plan = excluded.user_plan, subscription_status = 'active', updated_at = now()`,
+			order.UserId, plan, nextPeriodStart(paidAt))
+		if err != nil {
+			return 0, 0, gerror.Wrap(err, "开通订阅失败")
+		}
+	}
+	return billingId, paymentId, nil
+}
+
+// onRefunded 退款成功: 账单和支付记录标记为已退款并收回权益, 项目退款会在创作者收益同步时生成冲正
+// 已退款的账单不能再开发票, 也不计入首单优惠的购买记录
+func onRefunded(ctx context.Context, tx gdb.TX, order *entity.PaymentOrders, refundedAt *gtime.Time) error {
+	if order.PaymentId > 0 {
+		_, err := dao.BillingPayments.Ctx(ctx).TX(tx).Where("id", order.PaymentId).Data(g.Map{
+			"payment_status": "refunded",
+			"refunded_at":    refundedAt,
+			"updated_at":     refundedAt,
+		}).Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新支付记录失败")
+		}
+	}
+	if order.BillingId > 0 {
+		_, err := dao.BillingRecords.Ctx(ctx).TX(tx).Where("id", order.BillingId).Data(g.Map{
+			"status":     "refunded",
+			"updated_at": refundedAt,
+		}).Update()
+		if err != nil {
+			return gerror.Wrap(err, "更新账单失败")
+		}
+	}
+
+	var err error
+	switch order.OrderType {
+	case consts.OrderTypeProject:
+		_, err = dao.UserProjectPurchases.Ctx(ctx).TX(tx).
+			Where("billing_id", order.BillingId).
+			Data(g.Map{
+				"status":     consts.PurchaseStatusRefunded,
+				"updated_at": refundedAt,
+			}).Update()
+	case consts.OrderTypeSubscription:
+		// 升级差价退款时撤销变更, 未生效的变更退款时订阅不受影响
+		if changeId := gjson.New(order.BizData).Get("changeId").Int(); changeId > 0 {
+			_, err = billing.RevertChange(ctx, tx, changeId)
+			break
+		}
+		_, err = dao.UserSubscriptions.Ctx(ctx).TX(tx).
+			Where("user_id", order.UserId).
+			Data(g.Map{
+				"subscription_status": "cancelled",
+				"updated_at":          refundedAt,
+			}).Update()
+	case consts.OrderTypeBilling:
+		// 月度账单退款后账单已标记为已退款, 视为已结清, 出账时不再重新生成
+		g.Log().Infof(ctx, "月度账单 %d 已退款, 订单号: %s", order.BillingId, order.OutTradeNo)
+	}
+	if err != nil {
+		return gerror.Wrap(err, "收回权益失败")
+	}
+	return nil
+}
+
+// nextPeriodStart 下一账期的第一天, 月度出账在账期结束后重置配额
+func nextPeriodStart(t *gtime.Time) string {
+	return gtime.NewFromStr(t.Format("Y-m-01")).AddDate(0, 1, 0).Format("Y-m-d")
+}
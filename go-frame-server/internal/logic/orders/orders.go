@@ -0,0 +1,460 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/grand"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/coupons"
+	"kgplatform-backend/internal/logic/payment"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Orders 支付订单: 下单时创建订单, 支付通知和主动查询的结果都通过状态机更新订单, 业务权益只在状态变化时发放一次
+type Orders struct{}
+
+func New() *Orders {
+	return &Orders{}
+}
+
+type CreateInput struct {
+	UserId    int
+	OrderType string
+	BizId     int
+	// BizData 发放权益需要的业务参数, 如购买类型、套餐和人数
+	BizData    g.Map
+	Subject    string
+	AmountCent int64
+	// Provider 支付渠道, 为空时使用默认渠道
+	Provider string
+	PayType  string
+	ClientIp string
+	// CouponCode 优惠码, 使用时 AmountCent 为优惠前的金额
+	CouponCode string
+	// Plan 套餐, 用于校验优惠码的适用范围
+	Plan string
+}
+
+type CreateOutput struct {
+	Order   *entity.PaymentOrders
+	PayData string
+	// Quote 使用优惠码时的优惠信息
+	Quote *coupons.Quote
+}
+
+// Create 创建支付订单并向支付渠道下单
+func (o *Orders) Create(ctx context.Context, in *CreateInput) (*CreateOutput, error) {
+	if in.AmountCent <= 0 {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "订单金额必须大于0")
+	}
+	if in.Provider == "" {
+		in.Provider = payment.DefaultName(ctx)
+	}
+	provider, err := payment.Get(ctx, in.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	now := gtime.Now()
+	expireAt := now.Add(time.Duration(g.Cfg().MustGet(ctx, "payment.orderExpire", 30).Int()) * time.Minute)
+	outTradeNo := generateOutTradeNo(in.OrderType, in.UserId)
+	var (
+		amountCent = in.AmountCent
+		quote      *coupons.Quote
+	)
+	err = dao.PaymentOrders.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var err error
+		if in.CouponCode != "" {
+			quote, err = coupons.Reserve(ctx, tx, &coupons.ApplyInput{
+				Code:       in.CouponCode,
+				UserId:     in.UserId,
+				OrderType:  in.OrderType,
+				Plan:       in.Plan,
+				ProjectId:  projectId(in),
+				AmountCent: in.AmountCent,
+			}, outTradeNo)
+			if err != nil {
+				return err
+			}
+			amountCent = quote.PayCent
+		}
+		_, err = dao.PaymentOrders.Ctx(ctx).TX(tx).Data(g.Map{
+			"out_trade_no": outTradeNo,
+			"provider":     in.Provider,
+			"user_id":      in.UserId,
+			"order_type":   in.OrderType,
+			"biz_id":       in.BizId,
+			"biz_data":     gjson.MustEncodeString(in.BizData),
+			"subject":      in.Subject,
+			"amount_cent":  amountCent,
+			"status":       consts.OrderStatusCreated,
+			"expire_at":    expireAt,
+			"created_at":   now,
+			"updated_at":   now,
+		}).Insert()
+		return err
+	})
+	if err != nil {
+		return nil, gerror.Wrap(err, "创建支付订单失败")
+	}
+
+	out, err := provider.CreateOrder(ctx, &payment.CreateOrderInput{
+		OutTradeNo: outTradeNo,
+		Subject:    in.Subject,
+		AmountCent: amountCent,
+		PayType:    in.PayType,
+		ClientIp:   in.ClientIp,
+		ExpireAt:   expireAt,
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "支付渠道下单失败: %v, 订单号: %s", err, outTradeNo)
+		o.closeUnpaid(ctx, outTradeNo)
+		return nil, gerror.Wrap(err, "支付下单失败")
+	}
+
+	order, err := o.Get(ctx, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateOutput{Order: order, PayData: out.PayData, Quote: quote}, nil
+}
+
+// closeUnpaid 渠道下单失败时关闭订单并释放占用的优惠券
+func (o *Orders) closeUnpaid(ctx context.Context, outTradeNo string) {
+	err := dao.PaymentOrders.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := dao.PaymentOrders.Ctx(ctx).TX(tx).
+			Where("out_trade_no", outTradeNo).
+			Where("status", consts.OrderStatusCreated).
+			Data(g.Map{
+				"status":     consts.OrderStatusClosed,
+				"closed_at":  gtime.Now(),
+				"updated_at": gtime.Now(),
+			}).Update()
+		if err != nil {
+			return err
+		}
+		return coupons.Release(ctx, tx, outTradeNo)
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "关闭下单失败的订单失败: %v, 订单号: %s", err, outTradeNo)
+	}
+}
+
+// projectId 项目订单的业务ID为项目ID
+func projectId(in *CreateInput) int {
+	if in.OrderType == consts.OrderTypeProject {
+		return in.BizId
+	}
+	return 0
+}
+
+// Get 按商户订单号获取订单, 不存在时返回 nil
+func (o *Orders) Get(ctx context.Context, outTradeNo string) (*entity.PaymentOrders, error) {
+	var order *entity.PaymentOrders
+	if err := dao.PaymentOrders.Ctx(ctx).Where("out_trade_no", outTradeNo).Scan(&order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// HandleNotify 处理验签通过的支付通知
+func (o *Orders) HandleNotify(ctx context.Context, n *payment.Notification) (*entity.PaymentOrders, error) {
+	key := fmt.Sprintf("%s:notify:%s", n.Provider, n.NotifyId)
+	if n.NotifyId == "" {
+		key = tradeEventKey(n.Provider, n.OutTradeNo, n.Status)
+	}
+	return o.apply(ctx, &transition{
+		OutTradeNo:    n.OutTradeNo,
+		Provider:      n.Provider,
+		TradeStatus:   n.Status,
+		TransactionId: n.TransactionId,
+		AmountCent:    n.AmountCent,
+		PaidAt:        n.PaidAt,
+		EventKey:      key,
+		Source:        consts.OrderEventNotify,
+		Raw:           n.Raw,
+	})
+}
+
+// Sync 主动向支付渠道查询订单并更新状态, 已是终态的订单直接返回
+func (o *Orders) Sync(ctx context.Context, outTradeNo string) (*entity.PaymentOrders, error) {
+	order, err := o.Get(ctx, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "订单不存在")
+	}
+	if order.Status == consts.OrderStatusRefunded {
+		return order, nil
+	}
+
+	provider, err := payment.Get(ctx, order.Provider)
+	if err != nil {
+		return nil, err
+	}
+	status, err := provider.QueryOrder(ctx, outTradeNo)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询支付渠道订单失败")
+	}
+	if status.Status == consts.TradeStatusPending {
+		return order, nil
+	}
+	return o.apply(ctx, &transition{
+		OutTradeNo:    outTradeNo,
+		Provider:      order.Provider,
+		TradeStatus:   status.Status,
+		TransactionId: status.TransactionId,
+		AmountCent:    status.AmountCent,
+		PaidAt:        status.PaidAt,
+		EventKey:      tradeEventKey(order.Provider, outTradeNo, status.Status),
+		Source:        consts.OrderEventQuery,
+		Raw:           status.Raw,
+	})
+}
+
+// Expire 关闭已过期的待支付订单, 关闭后仍收到支付成功时按迟到的支付处理
+func (o *Orders) Expire(ctx context.Context, outTradeNo string) (*entity.PaymentOrders, error) {
+	order, err := o.Get(ctx, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "订单不存在")
+	}
+	return o.apply(ctx, &transition{
+		OutTradeNo:  outTradeNo,
+		Provider:    order.Provider,
+		TradeStatus: consts.TradeStatusClosed,
+		EventKey:    fmt.Sprintf("%s:expire:%s", order.Provider, outTradeNo),
+		Source:      consts.OrderEventExpire,
+	})
+}
+
+// CloseBiz 业务取消后关闭对应的待支付订单, 关闭后仍收到支付成功时按迟到的支付处理
+func (o *Orders) CloseBiz(ctx context.Context, orderType string, bizId int) error {
+	outTradeNos, err := dao.PaymentOrders.Ctx(ctx).
+		Where("order_type", orderType).
+		Where("biz_id", bizId).
+		Where("status", consts.OrderStatusCreated).
+		Fields("out_trade_no").
+		Array()
+	if err != nil {
+		return err
+	}
+	for _, outTradeNo := range outTradeNos {
+		order, err := o.Get(ctx, outTradeNo.String())
+		if err != nil {
+			return err
+		}
+		_, err = o.apply(ctx, &transition{
+			OutTradeNo:  order.OutTradeNo,
+			Provider:    order.Provider,
+			TradeStatus: consts.TradeStatusClosed,
+			EventKey:    fmt.Sprintf("%s:cancel:%s", order.Provider, order.OutTradeNo),
+			Source:      consts.OrderEventCancel,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refundCancelledChange 套餐变更取消后才支付成功的订单全额退款, 变更不会生效
+func (o *Orders) refundCancelledChange(ctx context.Context, order *entity.PaymentOrders) {
+	changeId := gjson.New(order.BizData).Get("changeId").Int()
+	if order.OrderType != consts.OrderTypeSubscription || changeId == 0 {
+		return
+	}
+	status, err := dao.SubscriptionChanges.Ctx(ctx).Where("id", changeId).Value("status")
+	if err != nil {
+		g.Log().Errorf(ctx, "查询套餐变更状态失败: %v, 订单号: %s", err, order.OutTradeNo)
+		return
+	}
+	if status.String() == consts.PlanChangeApplied {
+		return
+	}
+	go func(ctx context.Context) {
+		_, _, err := o.Refund(ctx, &RefundInput{OutTradeNo: order.OutTradeNo, Reason: "套餐变更已取消"})
+		if err != nil {
+			g.Log().Errorf(ctx, "套餐变更已取消, 订单退款失败, 请人工处理: %v, 订单号: %s", err, order.OutTradeNo)
+		}
+	}(gctx.NeverDone(ctx))
+}
+
+// RefundInput 退款参数, RefundCent 为 0 时退还剩余的全部金额
+type RefundInput struct {
+	OutTradeNo string
+	RefundCent int64
+	Reason     string
+}
+
+// Refund 向支付渠道申请退款, 累计退款达到订单金额后订单变为已退款并收回权益
+// 退款申请在锁定订单的事务内记录, 处理中的退款重试时沿用原退款单号, 渠道不会重复退款
+func (o *Orders) Refund(ctx context.Context, in *RefundInput) (*entity.PaymentOrders, *payment.RefundOutput, error) {
+	var (
+		order  *entity.PaymentOrders
+		refund *entity.PaymentRefunds
+	)
+	err := dao.PaymentOrders.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var err error
+		order, refund, err = reserveRefund(ctx, tx, in)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider, err := payment.Get(ctx, order.Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := provider.Refund(ctx, &payment.RefundInput{
+		OutTradeNo:  order.OutTradeNo,
+		OutRefundNo: refund.OutRefundNo,
+		RefundCent:  refund.RefundCent,
+		TotalCent:   order.AmountCent,
+		Reason:      refund.Reason,
+	})
+	if err != nil {
+		// 退款保持处理中, 重试时以同一退款单号重新申请
+		return nil, nil, gerror.Wrap(err, "申请退款失败")
+	}
+	g.Log().Infof(ctx, "订单 %s 申请退款 %d 分, 退款单号 %s, 渠道结果 %s", order.OutTradeNo, refund.RefundCent, out.OutRefundNo, out.Status)
+
+	refundedCent, err := finishRefund(ctx, refund, out)
+	if err != nil {
+		return nil, nil, err
+	}
+	if refundedCent < order.AmountCent {
+		return order, out, nil
+	}
+	order, err = o.apply(ctx, &transition{
+		OutTradeNo:    order.OutTradeNo,
+		Provider:      order.Provider,
+		TradeStatus:   consts.TradeStatusRefunded,
+		TransactionId: order.TransactionId,
+		AmountCent:    refundedCent,
+		EventKey:      fmt.Sprintf("%s:refund:%s", order.Provider, refund.OutRefundNo),
+		Source:        consts.OrderEventRefund,
+		Raw:           out.RefundId,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, out, nil
+}
+
+// reserveRefund 锁定订单并记录退款申请, 有处理中的退款时返回该退款
+// 退款单号按订单的退款顺序编号, 累计的成功和处理中的退款金额不超过订单金额
+func reserveRefund(ctx context.Context, tx gdb.TX, in *RefundInput) (*entity.PaymentOrders, *entity.PaymentRefunds, error) {
+	var order *entity.PaymentOrders
+	err := dao.PaymentOrders.Ctx(ctx).TX(tx).Where("out_trade_no", in.OutTradeNo).LockUpdate().Scan(&order)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order == nil {
+		return nil, nil, gerror.NewCode(gcode.CodeNotFound, "订单不存在")
+	}
+	if order.Status != consts.OrderStatusPaid {
+		return nil, nil, gerror.NewCodef(gcode.CodeInvalidOperation, "订单状态为 %s, 不能退款", order.Status)
+	}
+
+	var refunds []*entity.PaymentRefunds
+	if err = dao.PaymentRefunds.Ctx(ctx).TX(tx).Where("order_id", order.Id).OrderAsc("id").Scan(&refunds); err != nil {
+		return nil, nil, err
+	}
+	var refundedCent int64
+	for _, refund := range refunds {
+		switch refund.Status {
+		case consts.RefundStatusProcessing:
+			if in.RefundCent != 0 && in.RefundCent != refund.RefundCent {
+				return nil, nil, gerror.NewCodef(gcode.CodeInvalidOperation,
+					"订单有处理中的退款 %s, 金额 %d 分, 请等待处理完成", refund.OutRefundNo, refund.RefundCent)
+			}
+			return order, refund, nil
+		case consts.RefundStatusSuccess:
+			refundedCent += refund.RefundCent
+		}
+	}
+
+	refundCent := in.RefundCent
+	if refundCent == 0 {
+		refundCent = order.AmountCent - refundedCent
+	}
+	if refundCent <= 0 || refundedCent+refundCent > order.AmountCent {
+		return nil, nil, gerror.NewCodef(gcode.CodeInvalidParameter,
+			"退款金额 %d 分超出可退金额 %d 分", refundCent, order.AmountCent-refundedCent)
+	}
+	now := gtime.Now()
+	refund := &entity.PaymentRefunds{
+		OrderId:     order.Id,
+		OutRefundNo: fmt.Sprintf("%s_R%d", order.OutTradeNo, len(refunds)+1),
+		RefundCent:  refundCent,
+		Reason:      in.Reason,
+		Status:      consts.RefundStatusProcessing,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	id, err := dao.PaymentRefunds.Ctx(ctx).TX(tx).Data(g.Map{
+		"order_id":      refund.OrderId,
+		"out_refund_no": refund.OutRefundNo,
+		"refund_cent":   refund.RefundCent,
+		"reason":        refund.Reason,
+		"status":        refund.Status,
+		"created_at":    now,
+		"updated_at":    now,
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "记录退款失败")
+	}
+	refund.Id = int(id)
+	return order, refund, nil
+}
+
+// finishRefund 保存渠道的退款结果, 返回订单累计成功退款的金额
+func finishRefund(ctx context.Context, refund *entity.PaymentRefunds, out *payment.RefundOutput) (int64, error) {
+	_, err := dao.PaymentRefunds.Ctx(ctx).Where("id", refund.Id).Data(g.Map{
+		"status":     out.Status,
+		"refund_id":  out.RefundId,
+		"updated_at": gtime.Now(),
+	}).Update()
+	if err != nil {
+		return 0, gerror.Wrap(err, "保存退款结果失败")
+	}
+	refunded, err := dao.PaymentRefunds.Ctx(ctx).
+		Where("order_id", refund.OrderId).
+		Where("status", consts.RefundStatusSuccess).
+		Sum("refund_cent")
+	if err != nil {
+		return 0, err
+	}
+	return int64(refunded), nil
+}
+
+// tradeEventKey 没有通知ID时按订单和交易状态去重, 关闭的交易可能没有渠道交易号
+func tradeEventKey(provider, outTradeNo, status string) string {
+	return fmt.Sprintf("%s:trade:%s:%s", provider, outTradeNo, status)
+}
+
+// generateOutTradeNo 订单号格式: CHIDU_{类型}_{时间戳}_{用户ID}_{随机数}
+func generateOutTradeNo(orderType string, userId int) string {
+	prefix := map[string]string{
+		consts.OrderTypeProject:      "PRJ",
+		consts.OrderTypeSubscription: "SUB",
+		consts.OrderTypeBilling:      "BILL",
+	}[orderType]
+	return fmt.Sprintf("CHIDU_%s_%d_%d_%s", prefix, time.Now().Unix(), userId, grand.Digits(4))
+}
@@ -0,0 +1,214 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/coupons"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// transition 一次来自支付渠道的交易状态, 由通知或主动查询产生
+type transition struct {
+	OutTradeNo    string
+	Provider      string
+	TradeStatus   string
+	TransactionId string
+	AmountCent    int64
+	PaidAt        *gtime.Time
+	// EventKey 去重键, 同一通知重发或同一交易状态重复查询时相同
+	EventKey string
+	Source   string
+	Raw      string
+}
+
+// statusRank 订单状态的先后顺序, 用于识别乱序到达的过期通知
+var statusRank = map[string]int{
+	consts.OrderStatusCreated:  0,
+	consts.OrderStatusClosed:   1,
+	consts.OrderStatusPaid:     2,
+	consts.OrderStatusRefunded: 3,
+}
+
+// allowedTransitions 允许的状态变化, 关闭后收到支付成功视为用户在关闭前已完成支付
+var allowedTransitions = map[string][]string{
+	consts.OrderStatusCreated: {consts.OrderStatusPaid, consts.OrderStatusClosed},
+	consts.OrderStatusClosed:  {consts.OrderStatusPaid},
+	consts.OrderStatusPaid:    {consts.OrderStatusRefunded},
+}
+
+// tradeToOrderStatus 渠道交易状态对应的订单状态, 待支付不引起变化
+var tradeToOrderStatus = map[string]string{
+	consts.TradeStatusPaid:     consts.OrderStatusPaid,
+	consts.TradeStatusClosed:   consts.OrderStatusClosed,
+	consts.TradeStatusRefunded: consts.OrderStatusRefunded,
+}
+
+func canTransit(from, to string) bool {
+	for _, status := range allowedTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// apply 在一个事务内锁定订单、记录事件、更新状态并发放权益
+// 重复通知、乱序到达的过期通知只记录事件不生效; 尚不能生效的通知返回错误, 由渠道稍后重试
+func (o *Orders) apply(ctx context.Context, t *transition) (*entity.PaymentOrders, error) {
+	to, ok := tradeToOrderStatus[t.TradeStatus]
+	if !ok {
+		return o.Get(ctx, t.OutTradeNo)
+	}
+
+	var (
+		order *entity.PaymentOrders
+		paid  bool
+	)
+	err := dao.PaymentOrders.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		err := dao.PaymentOrders.Ctx(ctx).TX(tx).Where("out_trade_no", t.OutTradeNo).LockUpdate().Scan(&order)
+		if err != nil {
+			return err
+		}
+		if order == nil {
+			return gerror.NewCodef(gcode.CodeNotFound, "支付订单不存在: %s", t.OutTradeNo)
+		}
+		from := order.Status
+		effective, err := resolve(order, t, to)
+		if err != nil {
+			return err
+		}
+		if !effective {
+			g.Log().Infof(ctx, "忽略重复或过期的支付事件: 订单 %s, 当前状态 %s, 事件状态 %s, 来源 %s",
+				order.OutTradeNo, from, to, t.Source)
+			return recordIgnoredEvent(ctx, tx, order.Id, t, from, to)
+		}
+
+		applied, err := insertAppliedEvent(tx, order.Id, t, from, to)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			g.Log().Infof(ctx, "支付事件已处理过: %s", t.EventKey)
+			return nil
+		}
+
+		if err = transit(ctx, tx, order, t, to); err != nil {
+			return err
+		}
+		paid = to == consts.OrderStatusPaid
+		return dao.PaymentOrders.Ctx(ctx).TX(tx).Where("id", order.Id).Scan(&order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if paid {
+		o.refundCancelledChange(ctx, order)
+	}
+	return order, nil
+}
+
+// resolve 判断事件能否使订单变为 to: 重复或乱序到达的过期事件返回 false, 只记录不生效
+// 渠道或支付金额与订单不一致、以及尚不能生效的事件返回错误
+func resolve(order *entity.PaymentOrders, t *transition, to string) (bool, error) {
+	if order.Provider != t.Provider {
+		return false, gerror.Newf("支付渠道不一致: 订单为 %s, 通知来自 %s", order.Provider, t.Provider)
+	}
+	if to == consts.OrderStatusPaid && t.AmountCent != order.AmountCent {
+		return false, gerror.Newf("支付金额不一致: 订单 %d 分, 实付 %d 分", order.AmountCent, t.AmountCent)
+	}
+	from := order.Status
+	if from == to || statusRank[to] < statusRank[from] {
+		return false, nil
+	}
+	if !canTransit(from, to) {
+		return false, gerror.NewCodef(gcode.CodeInvalidOperation, "订单 %s 状态为 %s, 暂不能变为 %s", order.OutTradeNo, from, to)
+	}
+	return true, nil
+}
+
+// transit 更新订单状态并触发对应的业务处理, 调用方已持有订单行锁
+func transit(ctx context.Context, tx gdb.TX, order *entity.PaymentOrders, t *transition, to string) error {
+	now := gtime.Now()
+	data := g.Map{
+		"status":     to,
+		"updated_at": now,
+	}
+	if t.TransactionId != "" {
+		data["transaction_id"] = t.TransactionId
+	}
+
+	var err error
+	switch to {
+	case consts.OrderStatusPaid:
+		paidAt := t.PaidAt
+		if paidAt == nil {
+			paidAt = now
+		}
+		data["paid_at"] = paidAt
+		if t.TransactionId != "" {
+			order.TransactionId = t.TransactionId
+		}
+		var billingId, paymentId int
+		if billingId, paymentId, err = onPaid(ctx, tx, order, paidAt); err != nil {
+			return err
+		}
+		data["billing_id"] = billingId
+		data["payment_id"] = paymentId
+	case consts.OrderStatusClosed:
+		data["closed_at"] = now
+		err = coupons.Release(ctx, tx, order.OutTradeNo)
+	case consts.OrderStatusRefunded:
+		data["refunded_at"] = now
+		err = onRefunded(ctx, tx, order, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = dao.PaymentOrders.Ctx(ctx).TX(tx).Where("id", order.Id).Data(data).Update()
+	if err != nil {
+		return err
+	}
+	g.Log().Infof(ctx, "支付订单 %s 状态由 %s 变为 %s, 来源 %s", order.OutTradeNo, order.Status, to, t.Source)
+	return nil
+}
+
+// insertAppliedEvent 写入生效的事件, 去重键已存在生效事件时返回 false
+func insertAppliedEvent(tx gdb.TX, orderId int, t *transition, from, to string) (bool, error) {
+	result, err := tx.Exec(`
+		insert into payment_order_events (order_id, event_key, source, from_status, to_status, applied, raw, created_at)
+		values (?, ?, ?, ?, ?, true, ?, ?)
+		on conflict (event_key) where applied do nothing`,
+		orderId, t.EventKey, t.Source, from, to, t.Raw, gtime.Now())
+	if err != nil {
+		return false, gerror.Wrap(err, "记录支付事件失败")
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// recordIgnoredEvent 记录未生效的事件, 便于排查重复和乱序通知
+func recordIgnoredEvent(ctx context.Context, tx gdb.TX, orderId int, t *transition, from, to string) error {
+	_, err := dao.PaymentOrderEvents.Ctx(ctx).TX(tx).Data(g.Map{
+		"order_id":    orderId,
+		"event_key":   t.EventKey,
+		"source":      t.Source,
+		"from_status": from,
+		"to_status":   to,
+		"applied":     false,
+		"raw":         t.Raw,
+		"created_at":  gtime.Now(),
+	}).Insert()
+	return err
+}
@@ -0,0 +1,87 @@
+package orders
+
+import (
+	"testing"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/model/entity"
+)
+
+func TestCanTransit(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{consts.OrderStatusCreated, consts.OrderStatusPaid, true},
+		{consts.OrderStatusCreated, consts.OrderStatusClosed, true},
+		{consts.OrderStatusCreated, consts.OrderStatusRefunded, false},
+		{consts.OrderStatusClosed, consts.OrderStatusPaid, true},
+		{consts.OrderStatusClosed, consts.OrderStatusRefunded, false},
+		{consts.OrderStatusPaid, consts.OrderStatusRefunded, true},
+		{consts.OrderStatusPaid, consts.OrderStatusClosed, false},
+		{consts.OrderStatusRefunded, consts.OrderStatusPaid, false},
+	}
+	for _, c := range cases {
+		if got := canTransit(c.from, c.to); got != c.want {
+			t.Errorf("%s -> %s: 得到 %v, 期望 %v", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    string
+		provider  string
+		amount    int64
+		to        string
+		effective bool
+		errCode   gcode.Code // 期望的错误码, 为 nil 时不应返回错误
+		anyErr    bool       // 期望返回错误但不校验错误码
+	}{
+		{name: "支付成功", status: consts.OrderStatusCreated, to: consts.OrderStatusPaid, effective: true},
+		{name: "重复的支付通知", status: consts.OrderStatusPaid, to: consts.OrderStatusPaid},
+		{name: "退款后到达的支付通知", status: consts.OrderStatusRefunded, to: consts.OrderStatusPaid},
+		{name: "支付后到达的关闭通知", status: consts.OrderStatusPaid, to: consts.OrderStatusClosed},
+		{name: "关闭后收到支付成功", status: consts.OrderStatusClosed, to: consts.OrderStatusPaid, effective: true},
+		{name: "关闭订单", status: consts.OrderStatusCreated, to: consts.OrderStatusClosed, effective: true},
+		{name: "退款", status: consts.OrderStatusPaid, to: consts.OrderStatusRefunded, effective: true},
+		{name: "未支付先到达退款通知", status: consts.OrderStatusCreated, to: consts.OrderStatusRefunded, errCode: gcode.CodeInvalidOperation},
+		{name: "关闭后到达退款通知", status: consts.OrderStatusClosed, to: consts.OrderStatusRefunded, errCode: gcode.CodeInvalidOperation},
+		{name: "支付金额不一致", status: consts.OrderStatusCreated, amount: 1, to: consts.OrderStatusPaid, anyErr: true},
+		{name: "重复通知的金额不一致", status: consts.OrderStatusPaid, amount: 1, to: consts.OrderStatusPaid, anyErr: true},
+		{name: "支付渠道不一致", status: consts.OrderStatusCreated, provider: consts.PaymentProviderAlipay, to: consts.OrderStatusPaid, anyErr: true},
+	}
+	for _, c := range cases {
+		order := &entity.PaymentOrders{
+			OutTradeNo: "T1",
+			Provider:   consts.PaymentProviderWechat,
+			AmountCent: 5000,
+			Status:     c.status,
+		}
+		event := &transition{OutTradeNo: "T1", Provider: c.provider, AmountCent: 5000 + c.amount}
+		if event.Provider == "" {
+			event.Provider = consts.PaymentProviderWechat
+		}
+
+		effective, err := resolve(order, event, c.to)
+		switch {
+		case c.errCode != nil:
+			if gerror.Code(err) != c.errCode {
+				t.Errorf("%s: 错误为 %v, 期望错误码 %v", c.name, err, c.errCode)
+			}
+		case c.anyErr:
+			if err == nil {
+				t.Errorf("%s: 应返回错误", c.name)
+			}
+		case err != nil:
+			t.Errorf("%s: 不应返回错误: %v", c.name, err)
+		}
+		if effective != c.effective {
+			t.Errorf("%s: 生效为 %v, 期望 %v", c.name, effective, c.effective)
+		}
+	}
+}
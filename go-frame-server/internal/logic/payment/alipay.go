@@ -0,0 +1,228 @@
+package payment
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	v1 "kgplatform-backend/api/alipay/v1"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/service"
+)
+
+// alipayProvider 支付宝渠道, 下单、查询和退款沿用已有的支付宝服务
+type alipayProvider struct {
+	publicKey *rsa.PublicKey
+}
+
+func newAlipay(ctx context.Context) (*alipayProvider, error) {
+	key := g.Cfg().MustGet(ctx, "alipay.alipayPublicKey").String()
+	if key == "" {
+		return nil, gerror.New("未配置支付宝公钥 alipay.alipayPublicKey")
+	}
+	publicKey, err := parsePublicKey(key)
+	if err != nil {
+		return nil, gerror.Wrap(err, "解析支付宝公钥失败")
+	}
+	return &alipayProvider{publicKey: publicKey}, nil
+}
+
+func (p *alipayProvider) Name() string {
+	return consts.PaymentProviderAlipay
+}
+
+func (p *alipayProvider) CreateOrder(ctx context.Context, in *CreateOrderInput) (*CreateOrderOutput, error) {
+	orderString, err := service.Alipay().CreatePay(ctx, &v1.CreatePayReq{
+		Subject:     in.Subject,
+		OutTradeNo:  in.OutTradeNo,
+		TotalAmount: float64(in.AmountCent) / 100,
+		Body:        in.Body,
+		PayType:     in.PayType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &CreateOrderOutput{
+		Provider:   p.Name(),
+		OutTradeNo: in.OutTradeNo,
+		PayData:    orderString,
+	}, nil
+}
+
+func (p *alipayProvider) QueryOrder(ctx context.Context, outTradeNo string) (*OrderStatus, error) {
+	res, err := service.Alipay().QueryOrder(ctx, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderStatus{
+		OutTradeNo:    res.OutTradeNo,
+		TransactionId: res.TradeNo,
+		Status:        alipayTradeStatus(res.TradeStatus),
+		AmountCent:    yuanToCent(res.TotalAmount),
+		Raw:           res.TradeStatus,
+	}, nil
+}
+
+func (p *alipayProvider) Refund(ctx context.Context, in *RefundInput) (*RefundOutput, error) {
+	res, err := service.Alipay().Refund(ctx, &v1.RefundReq{
+		OutTradeNo:   in.OutTradeNo,
+		RefundAmount: float64(in.RefundCent) / 100,
+		RefundReason: in.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 支付宝同步返回退款结果, 接口调用成功即退款成功
+	return &RefundOutput{
+		OutRefundNo: in.OutRefundNo,
+		RefundId:    res.TradeNo,
+		Status:      consts.RefundStatusSuccess,
+	}, nil
+}
+
+// VerifyNotify 校验支付宝异步通知的 RSA2 签名
+func (p *alipayProvider) VerifyNotify(ctx context.Context, r *http.Request) (*Notification, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, gerror.Wrap(err, "解析支付宝通知失败")
+	}
+	values := r.PostForm
+	if len(values) == 0 {
+		values = r.Form
+	}
+
+	sign, err := base64.StdEncoding.DecodeString(values.Get("sign"))
+	if err != nil {
+		return nil, gerror.New("支付宝通知签名格式错误")
+	}
+	digest := sha256.Sum256([]byte(alipaySignContent(values)))
+	if err = rsa.VerifyPKCS1v15(p.publicKey, crypto.SHA256, digest[:], sign); err != nil {
+		return nil, gerror.New("支付宝通知验签失败")
+	}
+
+	n := &Notification{
+		Provider:      p.Name(),
+		NotifyId:      values.Get("notify_id"),
+		OutTradeNo:    values.Get("out_trade_no"),
+		TransactionId: values.Get("trade_no"),
+		Status:        alipayTradeStatus(values.Get("trade_status")),
+		AmountCent:    yuanToCent(values.Get("total_amount")),
+		Raw:           values.Encode(),
+	}
+	if paidAt := values.Get("gmt_payment"); paidAt != "" {
+		n.PaidAt = gtime.NewFromStr(paidAt)
+	}
+	// 全额退款后支付宝会以原交易状态再次通知, 通过退款金额识别
+	if values.Get("refund_fee") != "" && yuanToCent(values.Get("refund_fee")) >= n.AmountCent {
+		n.Status = consts.TradeStatusRefunded
+	}
+	return n, nil
+}
+
+func (p *alipayProvider) AckNotify(r *ghttp.Request, err error) {
+	if err != nil {
+		r.Response.Write("fail")
+		return
+	}
+	// 返回success给支付宝,否则支付宝会持续通知
+	r.Response.Write("success")
+}
+
+// alipaySignContent 待验签内容: 除 sign 和 sign_type 外的非空参数按参数名排序后以 & 连接
+func alipaySignContent(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key == "sign" || key == "sign_type" || values.Get(key) == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// alipayTradeStatus 支付宝交易状态说明：
+// - WAIT_BUYER_PAY：等待买家付款
+// - TRADE_CLOSED：交易关闭或已退款
+// - TRADE_SUCCESS：支付成功
+// - TRADE_FINISHED：交易结束，不可退款
+func alipayTradeStatus(status string) string {
+	switch status {
+	case "TRADE_SUCCESS", "TRADE_FINISHED":
+		return consts.TradeStatusPaid
+	case "TRADE_CLOSED":
+		return consts.TradeStatusClosed
+	default:
+		return consts.TradeStatusPending
+	}
+}
+
+// parsePublicKey 解析 PEM 或不带头尾的 base64 公钥
+func parsePublicKey(key string) (*rsa.PublicKey, error) {
+	der, err := decodePEM(key, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+		if rsaPub, ok := pub.(*rsa.PublicKey); ok {
+			return rsaPub, nil
+		}
+		return nil, gerror.New("公钥不是 RSA 公钥")
+	}
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		if rsaPub, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return rsaPub, nil
+		}
+	}
+	return x509.ParsePKCS1PublicKey(der)
+}
+
+// parsePrivateKey 解析 PEM 或不带头尾的 base64 私钥, 支持 PKCS#8 和 PKCS#1
+func parsePrivateKey(key string) (*rsa.PrivateKey, error) {
+	der, err := decodePEM(key, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	if pk, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if rsaKey, ok := pk.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, gerror.New("私钥不是 RSA 私钥")
+	}
+	return x509.ParsePKCS1PrivateKey(der)
+}
+
+func decodePEM(key string, keyType string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasPrefix(key, "-----") {
+		key = fmt.Sprintf("-----BEGIN %s-----\n%s\n-----END %s-----", keyType, key, keyType)
+	}
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, gerror.New("密钥格式错误")
+	}
+	return block.Bytes, nil
+}
+
+func yuanToCent(yuan string) int64 {
+	return int64(math.Round(g.NewVar(yuan).Float64() * 100))
+}
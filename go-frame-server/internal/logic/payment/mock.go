@@ -0,0 +1,267 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/guid"
+
+	"kgplatform-backend/internal/consts"
+)
+
+const (
+	mockOrderKeyPrefix = "payment:mock:order:"
+	mockOrderTTL       = int64(7 * 24 * 3600)
+	mockSignHeader     = "X-Mock-Signature"
+)
+
+// mockProvider 本地模拟支付渠道, 订单保存在 Redis, 由模拟支付接口向本服务发送签名的支付通知
+// 用于在没有支付宝、微信支付环境时联调下单、通知、查询和退款流程
+type mockProvider struct {
+	secret    []byte
+	payUrl    string
+	notifyUrl string
+	client    *http.Client
+}
+
+// mockOrder 模拟渠道的订单
+type mockOrder struct {
+	OutTradeNo    string      `json:"outTradeNo"`
+	Subject       string      `json:"subject"`
+	AmountCent    int64       `json:"amountCent"`
+	Status        string      `json:"status"`
+	TransactionId string      `json:"transactionId"`
+	PaidAt        *gtime.Time `json:"paidAt"`
+}
+
+type MockPayInput struct {
+	OutTradeNo string
+	// Status 模拟的支付结果: paid-支付成功, closed-关闭
+	Status string
+	// Repeat 重复发送同一通知的次数, 用于模拟渠道重发
+	Repeat int
+}
+
+func mockEnabled(ctx context.Context) bool {
+	return g.Cfg().MustGet(ctx, "payment.mock.enabled", false).Bool()
+}
+
+func newMock(ctx context.Context) (*mockProvider, error) {
+	if !mockEnabled(ctx) {
+		return nil, gerror.New("模拟支付未启用, 需配置 payment.mock.enabled")
+	}
+	cfg := g.Cfg().MustGet(ctx, "payment.mock").MapStrVar()
+	p := &mockProvider{
+		secret:    []byte(cfg["secret"].String()),
+		payUrl:    strings.TrimRight(cfg["payUrl"].String(), "/"),
+		notifyUrl: cfg["notifyUrl"].String(),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+	if len(p.secret) == 0 || p.notifyUrl == "" {
+		return nil, gerror.New("模拟支付配置不完整, 需要 payment.mock.secret 和 notifyUrl")
+	}
+	return p, nil
+}
+
+func (p *mockProvider) Name() string {
+	return consts.PaymentProviderMock
+}
+
+func (p *mockProvider) CreateOrder(ctx context.Context, in *CreateOrderInput) (*CreateOrderOutput, error) {
+	order := &mockOrder{
+		OutTradeNo: in.OutTradeNo,
+		Subject:    in.Subject,
+		AmountCent: in.AmountCent,
+		Status:     consts.TradeStatusPending,
+	}
+	if err := p.save(ctx, order); err != nil {
+		return nil, err
+	}
+	return &CreateOrderOutput{
+		Provider:   p.Name(),
+		OutTradeNo: in.OutTradeNo,
+		PayData:    p.payUrl + "/" + in.OutTradeNo,
+	}, nil
+}
+
+func (p *mockProvider) QueryOrder(ctx context.Context, outTradeNo string) (*OrderStatus, error) {
+	order, err := p.load(ctx, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderStatus{
+		OutTradeNo:    order.OutTradeNo,
+		TransactionId: order.TransactionId,
+		Status:        order.Status,
+		AmountCent:    order.AmountCent,
+		PaidAt:        order.PaidAt,
+		Raw:           order.Status,
+	}, nil
+}
+
+func (p *mockProvider) Refund(ctx context.Context, in *RefundInput) (*RefundOutput, error) {
+	order, err := p.load(ctx, in.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != consts.TradeStatusPaid {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, "订单未支付, 不能退款")
+	}
+	if in.RefundCent >= order.AmountCent {
+		order.Status = consts.TradeStatusRefunded
+		if err = p.save(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	return &RefundOutput{
+		OutRefundNo: in.OutRefundNo,
+		RefundId:    "mock_refund_" + guid.S(),
+		Status:      consts.RefundStatusSuccess,
+	}, nil
+}
+
+// VerifyNotify 校验通知内容的 HMAC-SHA256 签名
+func (p *mockProvider) VerifyNotify(ctx context.Context, r *http.Request) (*Notification, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取模拟支付通知失败")
+	}
+	signature, err := hex.DecodeString(r.Header.Get(mockSignHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(body)) {
+		return nil, gerror.New("模拟支付通知验签失败")
+	}
+
+	var n *Notification
+	if err = json.Unmarshal(body, &n); err != nil || n == nil {
+		return nil, gerror.New("解析模拟支付通知失败")
+	}
+	n.Provider = p.Name()
+	n.Raw = string(body)
+	return n, nil
+}
+
+func (p *mockProvider) AckNotify(r *ghttp.Request, err error) {
+	if err != nil {
+		r.Response.Write("fail")
+		return
+	}
+	r.Response.Write("success")
+}
+
+// Pay 模拟用户完成支付或关闭订单, 并向通知地址发送支付通知
+func (p *mockProvider) Pay(ctx context.Context, in *MockPayInput) (*Notification, error) {
+	order, err := p.load(ctx, in.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != consts.TradeStatusPending && order.Status != in.Status {
+		return nil, gerror.NewCode(gcode.CodeInvalidOperation, fmt.Sprintf("订单状态为 %s, 不能模拟为 %s", order.Status, in.Status))
+	}
+
+	order.Status = in.Status
+	if in.Status == consts.TradeStatusPaid && order.TransactionId == "" {
+		order.TransactionId = "mock_" + guid.S()
+		order.PaidAt = gtime.Now()
+	}
+	if err = p.save(ctx, order); err != nil {
+		return nil, err
+	}
+
+	n := &Notification{
+		Provider:      p.Name(),
+		NotifyId:      "mock_notify_" + guid.S(),
+		OutTradeNo:    order.OutTradeNo,
+		TransactionId: order.TransactionId,
+		Status:        order.Status,
+		AmountCent:    order.AmountCent,
+		PaidAt:        order.PaidAt,
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+
+	repeat := max(in.Repeat, 1)
+	for i := 0; i < repeat; i++ {
+		if err = p.notify(ctx, body); err != nil {
+			return n, gerror.Wrapf(err, "第 %d 次发送模拟支付通知失败", i+1)
+		}
+	}
+	g.Log().Infof(ctx, "模拟支付订单 %s 状态为 %s, 已发送 %d 次通知", order.OutTradeNo, order.Status, repeat)
+	return n, nil
+}
+
+// notify 向通知地址发送签名的通知, 应答不是 success 时视为失败
+func (p *mockProvider) notify(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.notifyUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(mockSignHeader, hex.EncodeToString(p.sign(body)))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	ack, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(ack) != "success" {
+		return gerror.Newf("通知应答: %d %s", resp.StatusCode, ack)
+	}
+	return nil
+}
+
+func (p *mockProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func (p *mockProvider) save(ctx context.Context, order *mockOrder) error {
+	ttl := mockOrderTTL
+	_, err := g.Redis().Set(ctx, mockOrderKeyPrefix+order.OutTradeNo, gjson.MustEncodeString(order), gredis.SetOption{
+		TTLOption: gredis.TTLOption{EX: &ttl},
+	})
+	return err
+}
+
+func (p *mockProvider) load(ctx context.Context, outTradeNo string) (*mockOrder, error) {
+	value, err := g.Redis().Get(ctx, mockOrderKeyPrefix+outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if value.IsNil() {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "模拟支付订单不存在")
+	}
+	var order *mockOrder
+	if err = gjson.DecodeTo(value.Bytes(), &order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// MockPay 模拟支付, 仅在启用模拟渠道时可用
+func MockPay(ctx context.Context, in *MockPayInput) (*Notification, error) {
+	p, err := Get(ctx, consts.PaymentProviderMock)
+	if err != nil {
+		return nil, err
+	}
+	return p.(*mockProvider).Pay(ctx, in)
+}
@@ -0,0 +1,142 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+)
+
+// Provider 支付渠道, 金额统一以分为单位, 交易状态统一为 consts.TradeStatus*
+type Provider interface {
+	// Name 渠道名称
+	Name() string
+	// CreateOrder 创建支付订单, 返回前端拉起支付所需的数据
+	CreateOrder(ctx context.Context, in *CreateOrderInput) (*CreateOrderOutput, error)
+	// QueryOrder 按商户订单号查询交易
+	QueryOrder(ctx context.Context, outTradeNo string) (*OrderStatus, error)
+	// Refund 申请退款
+	Refund(ctx context.Context, in *RefundInput) (*RefundOutput, error)
+	// VerifyNotify 校验支付结果通知的签名并解析
+	VerifyNotify(ctx context.Context, r *http.Request) (*Notification, error)
+	// AckNotify 按渠道要求的格式回复通知, err 不为空时渠道会重试
+	AckNotify(r *ghttp.Request, err error)
+}
+
+type CreateOrderInput struct {
+	OutTradeNo string
+	Subject    string
+	Body       string
+	AmountCent int64
+	// PayType 支付场景: web-电脑网站, wap-手机网站, app-APP
+	PayType  string
+	ClientIp string
+	// ExpireAt 订单过期时间, 为空时使用渠道默认值
+	ExpireAt *gtime.Time
+}
+
+type CreateOrderOutput struct {
+	Provider   string `json:"provider"`
+	OutTradeNo string `json:"outTradeNo"`
+	// PayData 拉起支付的数据: 支付宝为表单或跳转地址, 微信为二维码链接、H5 跳转地址或 APP 调起参数
+	PayData string `json:"payData"`
+}
+
+type OrderStatus struct {
+	OutTradeNo    string
+	TransactionId string
+	Status        string
+	AmountCent    int64
+	PaidAt        *gtime.Time
+	// Raw 渠道返回的原始交易状态
+	Raw string
+}
+
+type RefundInput struct {
+	OutTradeNo  string
+	OutRefundNo string
+	RefundCent  int64
+	TotalCent   int64
+	Reason      string
+}
+
+type RefundOutput struct {
+	OutRefundNo string
+	RefundId    string
+	Status      string
+}
+
+// Notification 验签通过的支付结果通知
+type Notification struct {
+	Provider string `json:"provider"`
+	// NotifyId 通知ID, 同一通知重发时不变
+	NotifyId      string      `json:"notifyId"`
+	OutTradeNo    string      `json:"outTradeNo"`
+	TransactionId string      `json:"transactionId"`
+	Status        string      `json:"status"`
+	AmountCent    int64       `json:"amountCent"`
+	PaidAt        *gtime.Time `json:"paidAt"`
+	Raw           string      `json:"raw"`
+}
+
+var (
+	providersMu sync.Mutex
+	providers   = make(map[string]Provider)
+)
+
+// Get 获取支付渠道, 渠道按配置延迟初始化
+func Get(ctx context.Context, name string) (Provider, error) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if p, ok := providers[name]; ok {
+		return p, nil
+	}
+
+	var (
+		p   Provider
+		err error
+	)
+	switch name {
+	case consts.PaymentProviderAlipay:
+		p, err = newAlipay(ctx)
+	case consts.PaymentProviderWechat:
+		p, err = newWechat(ctx)
+	case consts.PaymentProviderMock:
+		p, err = newMock(ctx)
+	default:
+		return nil, gerror.Newf("不支持的支付渠道: %s", name)
+	}
+	if err != nil {
+		return nil, gerror.Wrapf(err, "初始化支付渠道失败: %s", name)
+	}
+	providers[name] = p
+	return p, nil
+}
+
+// Default 获取 payment.provider 配置的默认支付渠道
+func Default(ctx context.Context) (Provider, error) {
+	return Get(ctx, DefaultName(ctx))
+}
+
+// DefaultName 默认支付渠道名称
+func DefaultName(ctx context.Context) string {
+	return g.Cfg().MustGet(ctx, "payment.provider", consts.PaymentProviderAlipay).String()
+}
+
+// Enabled 已启用的支付渠道, 模拟渠道需在配置中显式开启
+func Enabled(ctx context.Context) []string {
+	names := []string{consts.PaymentProviderAlipay}
+	if g.Cfg().MustGet(ctx, "wechatpay.mchId").String() != "" {
+		names = append(names, consts.PaymentProviderWechat)
+	}
+	if mockEnabled(ctx) {
+		names = append(names, consts.PaymentProviderMock)
+	}
+	return names
+}
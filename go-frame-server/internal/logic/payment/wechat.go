@@ -0,0 +1,421 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/container/gvar"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/grand"
+
+	"kgplatform-backend/internal/consts"
+)
+
+const (
+	wechatBaseUrl = "https://api.mch.weixin.qq.com"
+	// 通知和应答的时间戳与本地时间相差超过该值时拒绝, 防止重放
+	wechatMaxClockSkew = 5 * time.Minute
+)
+
+// wechatProvider 微信支付 APIv3, 使用商户 API 私钥签名请求, 使用微信支付公钥验证应答和通知
+type wechatProvider struct {
+	appId       string
+	mchId       string
+	serialNo    string
+	apiV3Key    []byte
+	notifyUrl   string
+	publicKeyId string
+	privateKey  *rsa.PrivateKey
+	publicKey   *rsa.PublicKey
+	client      *http.Client
+}
+
+// wechatTransaction 微信支付交易, 查询订单的应答和支付通知解密后的内容
+type wechatTransaction struct {
+	OutTradeNo    string `json:"out_trade_no"`
+	TransactionId string `json:"transaction_id"`
+	TradeState    string `json:"trade_state"`
+	SuccessTime   string `json:"success_time"`
+	Amount        struct {
+		Total int64 `json:"total"`
+	} `json:"amount"`
+}
+
+func newWechat(ctx context.Context) (*wechatProvider, error) {
+	cfg := g.Cfg().MustGet(ctx, "wechatpay").MapStrVar()
+	p := &wechatProvider{
+		appId:       cfg["appId"].String(),
+		mchId:       cfg["mchId"].String(),
+		serialNo:    cfg["serialNo"].String(),
+		apiV3Key:    []byte(cfg["apiV3Key"].String()),
+		notifyUrl:   cfg["notifyUrl"].String(),
+		publicKeyId: cfg["publicKeyId"].String(),
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+	if p.appId == "" || p.mchId == "" || p.serialNo == "" {
+		return nil, gerror.New("微信支付配置不完整, 需要 wechatpay.appId, mchId 和 serialNo")
+	}
+	if len(p.apiV3Key) != 32 {
+		return nil, gerror.New("微信支付 APIv3 密钥必须为32字节")
+	}
+
+	var err error
+	if p.privateKey, err = parsePrivateKey(wechatKey(cfg, "privateKey")); err != nil {
+		return nil, gerror.Wrap(err, "解析微信支付商户私钥失败")
+	}
+	if p.publicKey, err = parsePublicKey(wechatKey(cfg, "publicKey")); err != nil {
+		return nil, gerror.Wrap(err, "解析微信支付公钥失败")
+	}
+	return p, nil
+}
+
+// wechatKey 读取密钥内容, 未直接配置时从 <name>Path 指定的文件读取
+func wechatKey(cfg map[string]*gvar.Var, name string) string {
+	if key := cfg[name].String(); key != "" {
+		return key
+	}
+	if path := cfg[name+"Path"].String(); path != "" {
+		return gfile.GetContents(path)
+	}
+	return ""
+}
+
+func (p *wechatProvider) Name() string {
+	return consts.PaymentProviderWechat
+}
+
+// CreateOrder 电脑网站使用 Native 支付返回二维码链接, 手机网站使用 H5 支付, APP 返回调起支付的参数
+func (p *wechatProvider) CreateOrder(ctx context.Context, in *CreateOrderInput) (*CreateOrderOutput, error) {
+	body := g.Map{
+		"appid":        p.appId,
+		"mchid":        p.mchId,
+		"description":  in.Subject,
+		"out_trade_no": in.OutTradeNo,
+		"notify_url":   p.notifyUrl,
+		"amount":       g.Map{"total": in.AmountCent, "currency": "CNY"},
+	}
+	if in.ExpireAt != nil {
+		body["time_expire"] = in.ExpireAt.Format("c")
+	}
+
+	var path string
+	switch in.PayType {
+	case "wap":
+		path = "/v3/pay/transactions/h5"
+		body["scene_info"] = g.Map{
+			"payer_client_ip": in.ClientIp,
+			"h5_info":         g.Map{"type": "Wap"},
+		}
+	case "app":
+		path = "/v3/pay/transactions/app"
+	default:
+		path = "/v3/pay/transactions/native"
+	}
+
+	var res struct {
+		CodeUrl  string `json:"code_url"`
+		H5Url    string `json:"h5_url"`
+		PrepayId string `json:"prepay_id"`
+	}
+	if err := p.do(ctx, http.MethodPost, path, body, &res); err != nil {
+		return nil, err
+	}
+
+	out := &CreateOrderOutput{Provider: p.Name(), OutTradeNo: in.OutTradeNo}
+	switch in.PayType {
+	case "wap":
+		out.PayData = res.H5Url
+	case "app":
+		payData, err := p.appPayData(res.PrepayId)
+		if err != nil {
+			return nil, err
+		}
+		out.PayData = payData
+	default:
+		out.PayData = res.CodeUrl
+	}
+	return out, nil
+}
+
+func (p *wechatProvider) QueryOrder(ctx context.Context, outTradeNo string) (*OrderStatus, error) {
+	path := fmt.Sprintf("/v3/pay/transactions/out-trade-no/%s?mchid=%s", url.PathEscape(outTradeNo), url.QueryEscape(p.mchId))
+	var res wechatTransaction
+	if err := p.do(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.toOrderStatus(), nil
+}
+
+func (p *wechatProvider) Refund(ctx context.Context, in *RefundInput) (*RefundOutput, error) {
+	body := g.Map{
+		"out_trade_no":  in.OutTradeNo,
+		"out_refund_no": in.OutRefundNo,
+		"reason":        in.Reason,
+		"amount": g.Map{
+			"refund":   in.RefundCent,
+			"total":    in.TotalCent,
+			"currency": "CNY",
+		},
+	}
+	var res struct {
+		RefundId    string `json:"refund_id"`
+		OutRefundNo string `json:"out_refund_no"`
+		Status      string `json:"status"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/v3/refund/domestic/refunds", body, &res); err != nil {
+		return nil, err
+	}
+
+	out := &RefundOutput{OutRefundNo: res.OutRefundNo, RefundId: res.RefundId}
+	switch res.Status {
+	case "SUCCESS":
+		out.Status = consts.RefundStatusSuccess
+	case "PROCESSING":
+		out.Status = consts.RefundStatusProcessing
+	default:
+		out.Status = consts.RefundStatusFailed
+	}
+	return out, nil
+}
+
+// VerifyNotify 验证通知签名并用 APIv3 密钥解密通知内容
+func (p *wechatProvider) VerifyNotify(ctx context.Context, r *http.Request) (*Notification, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取微信支付通知失败")
+	}
+	if err = p.verify(r.Header, body); err != nil {
+		return nil, err
+	}
+
+	var notify struct {
+		Id        string `json:"id"`
+		EventType string `json:"event_type"`
+		Resource  struct {
+			Algorithm      string `json:"algorithm"`
+			Ciphertext     string `json:"ciphertext"`
+			AssociatedData string `json:"associated_data"`
+			Nonce          string `json:"nonce"`
+		} `json:"resource"`
+	}
+	if err = json.Unmarshal(body, &notify); err != nil {
+		return nil, gerror.Wrap(err, "解析微信支付通知失败")
+	}
+	if notify.Resource.Algorithm != "AEAD_AES_256_GCM" {
+		return nil, gerror.Newf("不支持的通知加密算法: %s", notify.Resource.Algorithm)
+	}
+	plaintext, err := p.decrypt(notify.Resource.Ciphertext, notify.Resource.Nonce, notify.Resource.AssociatedData)
+	if err != nil {
+		return nil, err
+	}
+
+	var transaction wechatTransaction
+	if err = json.Unmarshal(plaintext, &transaction); err != nil {
+		return nil, gerror.Wrap(err, "解析微信支付通知内容失败")
+	}
+	status := transaction.toOrderStatus()
+	return &Notification{
+		Provider:      p.Name(),
+		NotifyId:      notify.Id,
+		OutTradeNo:    status.OutTradeNo,
+		TransactionId: status.TransactionId,
+		Status:        status.Status,
+		AmountCent:    status.AmountCent,
+		PaidAt:        status.PaidAt,
+		Raw:           string(plaintext),
+	}, nil
+}
+
+func (p *wechatProvider) AckNotify(r *ghttp.Request, err error) {
+	if err != nil {
+		r.Response.WriteHeader(http.StatusInternalServerError)
+		r.Response.WriteJson(g.Map{"code": "FAIL", "message": err.Error()})
+		return
+	}
+	r.Response.WriteJson(g.Map{"code": "SUCCESS", "message": "成功"})
+}
+
+// do 发送签名的 APIv3 请求并验证应答签名
+func (p *wechatProvider) do(ctx context.Context, method string, path string, body any, result any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	authorization, err := p.authorization(method, path, payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, wechatBaseUrl+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Wechatpay-Serial", p.publicKeyId)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return gerror.Wrap(err, "请求微信支付失败")
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return gerror.Wrap(err, "读取微信支付应答失败")
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		return gerror.Newf("微信支付返回错误: %d %s %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+	}
+	if err = p.verify(resp.Header, respBody); err != nil {
+		return err
+	}
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, result)
+}
+
+// authorization 请求签名: 请求方法\nURL\n时间戳\n随机串\n请求报文主体\n
+func (p *wechatProvider) authorization(method string, path string, body []byte) (string, error) {
+	nonce := grand.S(32)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := p.sign(fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n", method, path, timestamp, nonce, body))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		p.mchId, nonce, signature, timestamp, p.serialNo,
+	), nil
+}
+
+// verify 验证应答和通知签名: 时间戳\n随机串\n报文主体\n
+func (p *wechatProvider) verify(header http.Header, body []byte) error {
+	serial := header.Get("Wechatpay-Serial")
+	if p.publicKeyId != "" && serial != p.publicKeyId {
+		return gerror.Newf("微信支付公钥ID不匹配: %s", serial)
+	}
+	timestamp, err := strconv.ParseInt(header.Get("Wechatpay-Timestamp"), 10, 64)
+	if err != nil {
+		return gerror.New("微信支付签名时间戳错误")
+	}
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > wechatMaxClockSkew || skew < -wechatMaxClockSkew {
+		return gerror.New("微信支付签名已过期")
+	}
+	signature, err := base64.StdEncoding.DecodeString(header.Get("Wechatpay-Signature"))
+	if err != nil {
+		return gerror.New("微信支付签名格式错误")
+	}
+
+	message := fmt.Sprintf("%s\n%s\n%s\n", header.Get("Wechatpay-Timestamp"), header.Get("Wechatpay-Nonce"), body)
+	digest := sha256.Sum256([]byte(message))
+	if err = rsa.VerifyPKCS1v15(p.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return gerror.New("微信支付验签失败")
+	}
+	return nil
+}
+
+func (p *wechatProvider) sign(message string) (string, error) {
+	digest := sha256.Sum256([]byte(message))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", gerror.Wrap(err, "微信支付签名失败")
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// decrypt 使用 APIv3 密钥以 AEAD_AES_256_GCM 解密通知内容
+func (p *wechatProvider) decrypt(ciphertext string, nonce string, associatedData string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, gerror.New("微信支付通知密文格式错误")
+	}
+	block, err := aes.NewCipher(p.apiV3Key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, []byte(nonce), data, []byte(associatedData))
+	if err != nil {
+		return nil, gerror.New("微信支付通知解密失败")
+	}
+	return plaintext, nil
+}
+
+// appPayData APP 调起支付的参数, 签名内容为 应用ID\n时间戳\n随机串\n预支付交易会话ID\n
+func (p *wechatProvider) appPayData(prepayId string) (string, error) {
+	nonce := grand.S(32)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := p.sign(fmt.Sprintf("%s\n%s\n%s\n%s\n", p.appId, timestamp, nonce, prepayId))
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(g.MapStrStr{
+		"appid":     p.appId,
+		"partnerid": p.mchId,
+		"prepayid":  prepayId,
+		"package":   "Sign=WXPay",
+		"noncestr":  nonce,
+		"timestamp": timestamp,
+		"sign":      signature,
+	})
+	return string(data), err
+}
+
+// toOrderStatus 微信支付交易状态说明：
+// - SUCCESS：支付成功
+// - REFUND：转入退款, 部分退款后也是该状态, 按已支付处理, 订单是否全额退款由退款结果决定
+// - NOTPAY、USERPAYING：未支付、用户支付中
+// - CLOSED、REVOKED、PAYERROR：已关闭、已撤销、支付失败
+func (t *wechatTransaction) toOrderStatus() *OrderStatus {
+	status := &OrderStatus{
+		OutTradeNo:    t.OutTradeNo,
+		TransactionId: t.TransactionId,
+		AmountCent:    t.Amount.Total,
+		Raw:           t.TradeState,
+	}
+	switch t.TradeState {
+	case "SUCCESS", "REFUND":
+		status.Status = consts.TradeStatusPaid
+	case "CLOSED", "REVOKED", "PAYERROR":
+		status.Status = consts.TradeStatusClosed
+	default:
+		status.Status = consts.TradeStatusPending
+	}
+	if t.SuccessTime != "" {
+		status.PaidAt = gtime.NewFromStr(strings.TrimSpace(t.SuccessTime))
+	}
+	return status
+}
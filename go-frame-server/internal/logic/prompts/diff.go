@@ -0,0 +1,92 @@
+package prompts
+
+import "strings"
+
+// DiffLine 按行对比的结果
+type DiffLine struct {
+	Type    string `json:"type" dc:"equal-相同, add-新增, delete-删除"`
+	Content string `json:"content"`
+}
+
+// maxDiffCells 最长公共子序列表的最大单元格数, 超出时不同的部分按整段删除和新增输出
+const maxDiffCells = 1 << 21
+
+// DiffLines 基于最长公共子序列按行对比两段文本
+// 相同的首尾行直接输出, 只对中间不同的部分计算最长公共子序列
+func DiffLines(from string, to string) []DiffLine {
+	a := strings.Split(strings.ReplaceAll(from, "\r\n", "\n"), "\n")
+	b := strings.Split(strings.ReplaceAll(to, "\r\n", "\n"), "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		lines = append(lines, DiffLine{Type: "equal", Content: line})
+	}
+	lines = append(lines, diffLCS(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, DiffLine{Type: "equal", Content: line})
+	}
+	return lines
+}
+
+// diffLCS 按最长公共子序列对比两组行, 计算量超出 maxDiffCells 时整段删除后整段新增
+func diffLCS(a []string, b []string) []DiffLine {
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, DiffLine{Type: "delete", Content: line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{Type: "add", Content: line})
+		}
+		return lines
+	}
+
+	// lcs[i][j] 表示 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Type: "equal", Content: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Type: "delete", Content: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Type: "add", Content: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Type: "delete", Content: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Type: "add", Content: b[j]})
+	}
+	return lines
+}
@@ -0,0 +1,423 @@
+package prompts
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Prompts 提示词库，支持个人/团队/公开三种可见范围，每次修改生成新版本
+type Prompts struct{}
+
+func New() *Prompts {
+	return &Prompts{}
+}
+
+type CreatePromptInput struct {
+	UserId      int
+	Name        string
+	Description string
+	Scope       string
+	TeamId      int64
+	Content     string
+	ChangeNote  string
+}
+
+type CreatePromptOutput struct {
+	Prompt  *entity.Prompts
+	Version *entity.PromptVersions
+}
+
+type ListPromptInput struct {
+	UserId  int
+	Scope   string
+	TeamId  int64
+	Keyword string
+	Page    int
+	Size    int
+}
+
+type ListUsageInput struct {
+	UserId          int
+	PromptVersionId int
+	Page            int
+	Size            int
+}
+
+// VersionItem 版本信息及使用统计
+type VersionItem struct {
+	*entity.PromptVersions
+	Stats *UsageStats `json:"stats"`
+}
+
+// UsageStats 版本使用统计
+type UsageStats struct {
+	PromptVersionId int     `json:"promptVersionId"`
+	UsageCount      int     `json:"usageCount" dc:"使用次数"`
+	CompletedCount  int     `json:"completedCount" dc:"成功完成次数"`
+	FailedCount     int     `json:"failedCount" dc:"失败次数"`
+	TotalTriples    int     `json:"totalTriples" dc:"累计抽取三元组数量"`
+	AvgTriples      float64 `json:"avgTriples" dc:"每次成功抽取的平均三元组数量"`
+}
+
+// Create 创建提示词及其第一个版本
+func (p *Prompts) Create(ctx context.Context, in *CreatePromptInput) (*CreatePromptOutput, error) {
+	if err := p.checkScope(ctx, in.UserId, in.Scope, in.TeamId); err != nil {
+		return nil, err
+	}
+	if in.Scope != consts.PromptScopeTeam {
+		in.TeamId = 0
+	}
+
+	out := &CreatePromptOutput{
+		Prompt:  &entity.Prompts{},
+		Version: &entity.PromptVersions{},
+	}
+	err := dao.Prompts.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		data := g.Map{
+			"name":           in.Name,
+			"description":    in.Description,
+			"scope":          in.Scope,
+			"user_id":        in.UserId,
+			"latest_version": 1,
+			"created_at":     gtime.Now(),
+			"updated_at":     gtime.Now(),
+		}
+		if in.TeamId > 0 {
+			data["team_id"] = in.TeamId
+		}
+		promptId, err := dao.Prompts.Ctx(ctx).TX(tx).Data(data).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		versionId, err := dao.PromptVersions.Ctx(ctx).TX(tx).Data(g.Map{
+			"prompt_id":   promptId,
+			"version":     1,
+			"content":     in.Content,
+			"change_note": in.ChangeNote,
+			"created_by":  in.UserId,
+			"created_at":  gtime.Now(),
+		}).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		if err = dao.Prompts.Ctx(ctx).TX(tx).Where("id", promptId).Scan(out.Prompt); err != nil {
+			return err
+		}
+		return dao.PromptVersions.Ctx(ctx).TX(tx).Where("id", versionId).Scan(out.Version)
+	})
+	if err != nil {
+		g.Log().Errorf(ctx, "创建提示词失败: %v", err)
+		return nil, gerror.New("创建提示词失败")
+	}
+	return out, nil
+}
+
+// CreateVersion 为提示词创建新版本，历史版本保持不变
+func (p *Prompts) CreateVersion(ctx context.Context, userId int, promptId int, content string, changeNote string) (*entity.PromptVersions, error) {
+	prompt, err := p.getPrompt(ctx, promptId)
+	if err != nil {
+		return nil, err
+	}
+	if !p.canEdit(ctx, userId, prompt) {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "无权修改该提示词")
+	}
+
+	var version entity.PromptVersions
+	err = dao.Prompts.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 锁定提示词行，避免并发创建相同版本号
+		var locked entity.Prompts
+		if err := dao.Prompts.Ctx(ctx).TX(tx).Where("id", promptId).LockUpdate().Scan(&locked); err != nil {
+			return err
+		}
+
+		var latest *entity.PromptVersions
+		err := dao.PromptVersions.Ctx(ctx).TX(tx).
+			Where("prompt_id", promptId).
+			Where("version", locked.LatestVersion).
+			Scan(&latest)
+		if err != nil {
+			return err
+		}
+		if latest != nil && latest.Content == content {
+			return gerror.NewCode(gcode.CodeInvalidOperation, "内容与最新版本相同")
+		}
+
+		nextVersion := locked.LatestVersion + 1
+		versionId, err := dao.PromptVersions.Ctx(ctx).TX(tx).Data(g.Map{
+			"prompt_id":   promptId,
+			"version":     nextVersion,
+			"content":     content,
+			"change_note": changeNote,
+			"created_by":  userId,
+			"created_at":  gtime.Now(),
+		}).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		_, err = dao.Prompts.Ctx(ctx).TX(tx).Where("id", promptId).Update(g.Map{
+			"latest_version": nextVersion,
+			"updated_at":     gtime.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		return dao.PromptVersions.Ctx(ctx).TX(tx).Where("id", versionId).Scan(&version)
+	})
+	if err != nil {
+		if gerror.Code(err) == gcode.CodeInvalidOperation {
+			return nil, err
+		}
+		g.Log().Errorf(ctx, "创建提示词版本失败: %v", err)
+		return nil, gerror.New("创建提示词版本失败")
+	}
+	return &version, nil
+}
+
+// List 获取当前用户可见的提示词列表
+func (p *Prompts) List(ctx context.Context, in *ListPromptInput) ([]*entity.Prompts, int, error) {
+	m := dao.Prompts.Ctx(ctx)
+	visible := m.Builder().
+		Where("scope", consts.PromptScopePublic).
+		WhereOr("user_id", in.UserId)
+	if teamIds := p.userTeamIds(ctx, in.UserId); len(teamIds) > 0 {
+		visible = visible.WhereOr(m.Builder().
+			Where("scope", consts.PromptScopeTeam).
+			WhereIn("team_id", teamIds))
+	}
+	m = m.Where(visible)
+
+	if in.Scope != "" {
+		m = m.Where("scope", in.Scope)
+	}
+	if in.TeamId > 0 {
+		m = m.Where("team_id", in.TeamId)
+	}
+	if in.Keyword != "" {
+		m = m.WhereLike("name", "%"+in.Keyword+"%")
+	}
+
+	var (
+		list  []*entity.Prompts
+		total int
+	)
+	err := m.OrderDesc("updated_at").Page(in.Page, in.Size).ScanAndCount(&list, &total, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Get 获取提示词详情及所有版本的使用统计
+func (p *Prompts) Get(ctx context.Context, userId int, promptId int) (*entity.Prompts, []*VersionItem, error) {
+	prompt, err := p.getPrompt(ctx, promptId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !p.canView(ctx, userId, prompt) {
+		return nil, nil, gerror.NewCode(gcode.CodeNotAuthorized, "无权查看该提示词")
+	}
+
+	var versionList []*entity.PromptVersions
+	err = dao.PromptVersions.Ctx(ctx).Where("prompt_id", promptId).OrderDesc("version").Scan(&versionList)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	versionIds := make([]int, 0, len(versionList))
+	for _, version := range versionList {
+		versionIds = append(versionIds, version.Id)
+	}
+	statsMap, err := p.loadUsageStats(ctx, versionIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]*VersionItem, 0, len(versionList))
+	for _, version := range versionList {
+		stats, ok := statsMap[version.Id]
+		if !ok {
+			stats = &UsageStats{PromptVersionId: version.Id}
+		}
+		items = append(items, &VersionItem{
+			PromptVersions: version,
+			Stats:          stats,
+		})
+	}
+	return prompt, items, nil
+}
+
+// GetVersion 获取当前用户可使用的提示词版本
+func (p *Prompts) GetVersion(ctx context.Context, userId int, versionId int) (*entity.PromptVersions, error) {
+	var version entity.PromptVersions
+	err := dao.PromptVersions.Ctx(ctx).Where("id", versionId).Scan(&version)
+	if err != nil || version.Id == 0 {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "提示词版本不存在")
+	}
+	prompt, err := p.getPrompt(ctx, version.PromptId)
+	if err != nil {
+		return nil, err
+	}
+	if !p.canView(ctx, userId, prompt) {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "无权使用该提示词")
+	}
+	return &version, nil
+}
+
+// Diff 对比同一提示词两个版本的内容
+func (p *Prompts) Diff(ctx context.Context, userId int, fromVersionId int, toVersionId int) (*entity.PromptVersions, *entity.PromptVersions, []DiffLine, error) {
+	from, err := p.GetVersion(ctx, userId, fromVersionId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	to, err := p.GetVersion(ctx, userId, toVersionId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if from.PromptId != to.PromptId {
+		return nil, nil, nil, gerror.NewCode(gcode.CodeInvalidParameter, "只能对比同一提示词的版本")
+	}
+	return from, to, DiffLines(from.Content, to.Content), nil
+}
+
+// ListUsage 获取使用某个版本的抽取记录及统计
+func (p *Prompts) ListUsage(ctx context.Context, in *ListUsageInput) ([]*entity.PromptUsages, int, *UsageStats, error) {
+	if _, err := p.GetVersion(ctx, in.UserId, in.PromptVersionId); err != nil {
+		return nil, 0, nil, err
+	}
+
+	var (
+		list  []*entity.PromptUsages
+		total int
+	)
+	// 公开提示词的使用记录只展示自己的，统计包含所有用户
+	err := dao.PromptUsages.Ctx(ctx).
+		Where("prompt_version_id", in.PromptVersionId).
+		Where("user_id", in.UserId).
+		OrderDesc("id").
+		Page(in.Page, in.Size).
+		ScanAndCount(&list, &total, false)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	statsMap, err := p.loadUsageStats(ctx, []int{in.PromptVersionId})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	stats, ok := statsMap[in.PromptVersionId]
+	if !ok {
+		stats = &UsageStats{PromptVersionId: in.PromptVersionId}
+	}
+	return list, total, stats, nil
+}
+
+func (p *Prompts) getPrompt(ctx context.Context, promptId int) (*entity.Prompts, error) {
+	var prompt entity.Prompts
+	err := dao.Prompts.Ctx(ctx).Where("id", promptId).Scan(&prompt)
+	if err != nil || prompt.Id == 0 {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "提示词不存在")
+	}
+	return &prompt, nil
+}
+
+// loadUsageStats 按版本汇总使用统计
+func (p *Prompts) loadUsageStats(ctx context.Context, versionIds []int) (map[int]*UsageStats, error) {
+	statsMap := make(map[int]*UsageStats)
+	if len(versionIds) == 0 {
+		return statsMap, nil
+	}
+
+	var statsList []*UsageStats
+	err := dao.PromptUsages.Ctx(ctx).
+		Fields("prompt_version_id, "+
+			"COUNT(1) AS usage_count, "+
+			"COUNT(1) FILTER (WHERE status = ?) AS completed_count, "+
+			"COUNT(1) FILTER (WHERE status = ?) AS failed_count, "+
+			"COALESCE(SUM(triples_count), 0) AS total_triples",
+			consts.TaskStatusCompleted, consts.TaskStatusFailed).
+		WhereIn("prompt_version_id", versionIds).
+		Group("prompt_version_id").
+		Scan(&statsList)
+	if err != nil {
+		return nil, err
+	}
+	for _, stats := range statsList {
+		if stats.CompletedCount > 0 {
+			stats.AvgTriples = float64(stats.TotalTriples) / float64(stats.CompletedCount)
+		}
+		statsMap[stats.PromptVersionId] = stats
+	}
+	return statsMap, nil
+}
+
+// checkScope 校验可见范围，团队提示词要求用户为团队成员
+func (p *Prompts) checkScope(ctx context.Context, userId int, scope string, teamId int64) error {
+	switch scope {
+	case consts.PromptScopeUser, consts.PromptScopePublic:
+		return nil
+	case consts.PromptScopeTeam:
+		if teamId <= 0 {
+			return gerror.NewCode(gcode.CodeInvalidParameter, "请选择团队")
+		}
+		if !p.isTeamMember(ctx, userId, teamId) {
+			return gerror.NewCode(gcode.CodeNotAuthorized, "您不是该团队成员")
+		}
+		return nil
+	default:
+		return gerror.NewCode(gcode.CodeInvalidParameter, "无效的可见范围")
+	}
+}
+
+func (p *Prompts) canView(ctx context.Context, userId int, prompt *entity.Prompts) bool {
+	if prompt.Scope == consts.PromptScopePublic {
+		return true
+	}
+	return p.canEdit(ctx, userId, prompt)
+}
+
+// canEdit 创建者可编辑，团队提示词允许团队成员编辑
+func (p *Prompts) canEdit(ctx context.Context, userId int, prompt *entity.Prompts) bool {
+	if prompt.UserId == userId {
+		return true
+	}
+	return prompt.Scope == consts.PromptScopeTeam && p.isTeamMember(ctx, userId, prompt.TeamId)
+}
+
+func (p *Prompts) isTeamMember(ctx context.Context, userId int, teamId int64) bool {
+	count, err := dao.TeamMembers.Ctx(ctx).
+		Where("team_id", teamId).
+		Where("user_id", userId).
+		Where("status", "active").
+		Count()
+	if err != nil {
+		g.Log().Errorf(ctx, "查询团队成员失败: %v", err)
+		return false
+	}
+	return count > 0
+}
+
+func (p *Prompts) userTeamIds(ctx context.Context, userId int) []int64 {
+	values, err := dao.TeamMembers.Ctx(ctx).
+		Where("user_id", userId).
+		Where("status", "active").
+		Fields("team_id").
+		Array()
+	if err != nil {
+		g.Log().Errorf(ctx, "查询用户团队失败: %v", err)
+		return nil
+	}
+	teamIds := make([]int64, 0, len(values))
+	for _, value := range values {
+		teamIds = append(teamIds, value.Int64())
+	}
+	return teamIds
+}
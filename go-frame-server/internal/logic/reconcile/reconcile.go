@@ -0,0 +1,77 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/orders"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Reconcile 支付对账: 补处理丢失的支付通知、关闭过期订单, 并按天核对本地支付记录与渠道交易
+type Reconcile struct{}
+
+func New() *Reconcile {
+	return &Reconcile{}
+}
+
+// OrdersResult 一次订单对账的结果
+type OrdersResult struct {
+	Checked int `json:"checked" dc:"检查的订单数"`
+	Updated int `json:"updated" dc:"按渠道结果更新状态的订单数"`
+	Closed  int `json:"closed" dc:"过期关闭的订单数"`
+	Failed  int `json:"failed" dc:"查询渠道失败的订单数"`
+}
+
+// Orders 向渠道查询创建超过 payment.reconcile.delay 分钟仍未支付的订单, 并关闭已过期的订单
+// 关闭只在本地生效, 用户在关闭前已完成的支付仍会通过通知或下次查询补处理
+func (r *Reconcile) Orders(ctx context.Context) (*OrdersResult, error) {
+	delay := g.Cfg().MustGet(ctx, "payment.reconcile.delay", 10).Int()
+	batchSize := g.Cfg().MustGet(ctx, "payment.reconcile.batchSize", 200).Int()
+
+	var list []*entity.PaymentOrders
+	err := dao.PaymentOrders.Ctx(ctx).
+		Where("status", consts.OrderStatusCreated).
+		WhereLT("created_at", gtime.Now().Add(-time.Duration(delay)*time.Minute)).
+		OrderAsc("id").
+		Limit(batchSize).
+		Scan(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OrdersResult{}
+	o := orders.New()
+	for _, order := range list {
+		result.Checked++
+		synced, err := o.Sync(ctx, order.OutTradeNo)
+		if err != nil {
+			// 用户未打开支付页面时渠道查不到交易, 仍按过期时间关闭
+			g.Log().Warningf(ctx, "对账查询订单失败: %v, 订单: %s", err, order.OutTradeNo)
+			result.Failed++
+			synced = order
+		}
+		if synced.Status != consts.OrderStatusCreated {
+			result.Updated++
+			continue
+		}
+		if order.ExpireAt == nil || order.ExpireAt.After(gtime.Now()) {
+			continue
+		}
+		if _, err = o.Expire(ctx, order.OutTradeNo); err != nil {
+			g.Log().Errorf(ctx, "关闭过期订单失败: %v, 订单: %s", err, order.OutTradeNo)
+			continue
+		}
+		result.Closed++
+	}
+	if result.Checked > 0 {
+		g.Log().Infof(ctx, "支付订单对账完成: 检查 %d, 更新 %d, 关闭 %d, 查询失败 %d",
+			result.Checked, result.Updated, result.Closed, result.Failed)
+	}
+	return result, nil
+}
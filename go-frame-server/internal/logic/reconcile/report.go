@@ -0,0 +1,239 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/payment"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// localPayment 当日的本地支付记录及对应的支付订单
+type localPayment struct {
+	PaymentId     int     `orm:"payment_id"`
+	PaymentStatus string  `orm:"payment_status"`
+	PaymentAmount float64 `orm:"payment_amount"`
+	TransactionId string  `orm:"payment_transaction_id"`
+	Provider      string  `orm:"provider"`
+	OutTradeNo    string  `orm:"out_trade_no"`
+}
+
+// Report 生成指定日期的对账报告, 已存在时重新生成
+// 只核对通过支付订单产生的支付记录, 历史支付记录没有商户订单号, 无法向渠道查询
+func (r *Reconcile) Report(ctx context.Context, date *gtime.Time) (*entity.PaymentReconcileReports, error) {
+	start := date.StartOfDay()
+	end := start.AddDate(0, 0, 1)
+
+	var payments []*localPayment
+	err := g.DB().GetScan(ctx, &payments, `
+		select bp.id as payment_id, bp.payment_status, bp.payment_amount, bp.payment_transaction_id,
+		       po.provider, po.out_trade_no
+		from billing_payments bp
+		join payment_orders po on po.payment_id = bp.id
+		where bp.paid_at >= ? and bp.paid_at < ?
+		order by bp.id`, start, end)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询当日支付记录失败")
+	}
+
+	var (
+		items       []g.Map
+		amountTotal int64
+	)
+	for _, p := range payments {
+		local := &entity.PaymentReconcileItems{
+			Provider:        p.Provider,
+			OutTradeNo:      p.OutTradeNo,
+			PaymentId:       p.PaymentId,
+			LocalStatus:     p.PaymentStatus,
+			LocalAmountCent: int64(math.Round(p.PaymentAmount * 100)),
+		}
+		amountTotal += local.LocalAmountCent
+		if item := comparePayment(ctx, local, p.TransactionId); item != nil {
+			items = append(items, item)
+		}
+	}
+
+	unpaid, err := r.unpaidOrders(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, unpaid...)
+
+	status := consts.ReconcileMatched
+	if len(items) > 0 {
+		status = consts.ReconcileMismatched
+	}
+
+	var report *entity.PaymentReconcileReports
+	err = dao.PaymentReconcileReports.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		reportId, err := tx.GetValue(`
+			insert into payment_reconcile_reports (report_date, status, payment_count, payment_amount_cent, mismatch_count, created_at, updated_at)
+			values (?, ?, ?, ?, ?, now(), now())
+			on conflict (report_date) do update
+			set status = excluded.status, payment_count = excluded.payment_count,
+			    payment_amount_cent = excluded.payment_amount_cent, mismatch_count = excluded.mismatch_count, updated_at = now()
+			returning id`,
+			start.Format("Y-m-d"), status, len(payments), amountTotal, len(items))
+		if err != nil {
+			return err
+		}
+
+		if _, err = dao.PaymentReconcileItems.Ctx(ctx).TX(tx).Where("report_id", reportId.Int()).Delete(); err != nil {
+			return err
+		}
+		if len(items) > 0 {
+			for _, item := range items {
+				item["report_id"] = reportId.Int()
+			}
+			if _, err = dao.PaymentReconcileItems.Ctx(ctx).TX(tx).Data(items).Insert(); err != nil {
+				return err
+			}
+		}
+		return dao.PaymentReconcileReports.Ctx(ctx).TX(tx).Where("id", reportId.Int()).Scan(&report)
+	})
+	if err != nil {
+		return nil, gerror.Wrap(err, "保存对账报告失败")
+	}
+
+	if status == consts.ReconcileMismatched {
+		g.Log().Warningf(ctx, "%s 支付对账存在 %d 条差异", start.Format("Y-m-d"), len(items))
+	} else {
+		g.Log().Infof(ctx, "%s 支付对账一致, 共 %d 笔支付", start.Format("Y-m-d"), len(payments))
+	}
+	return report, nil
+}
+
+// comparePayment 向渠道查询交易并与本地支付记录比较, 一致时返回 nil
+func comparePayment(ctx context.Context, local *entity.PaymentReconcileItems, transactionId string) g.Map {
+	provider, err := payment.Get(ctx, local.Provider)
+	if err != nil {
+		return newItem(local, consts.MismatchQueryFailed, err.Error())
+	}
+	remote, err := provider.QueryOrder(ctx, local.OutTradeNo)
+	if err != nil {
+		return newItem(local, consts.MismatchQueryFailed, err.Error())
+	}
+	local.ProviderStatus = remote.Status
+	local.ProviderAmountCent = remote.AmountCent
+
+	switch {
+	case !statusMatched(local.LocalStatus, remote.Status):
+		return newItem(local, consts.MismatchStatus,
+			fmt.Sprintf("本地支付状态为 %s, 渠道交易状态为 %s", local.LocalStatus, remote.Status))
+	case local.LocalAmountCent != remote.AmountCent:
+		return newItem(local, consts.MismatchAmount,
+			fmt.Sprintf("本地金额 %d 分, 渠道金额 %d 分", local.LocalAmountCent, remote.AmountCent))
+	case transactionId != "" && remote.TransactionId != "" && transactionId != remote.TransactionId:
+		return newItem(local, consts.MismatchTransaction,
+			fmt.Sprintf("本地交易号 %s, 渠道交易号 %s", transactionId, remote.TransactionId))
+	}
+	return nil
+}
+
+// statusMatched 本地支付状态与渠道交易状态是否一致
+// 支付宝全额退款后交易状态为关闭, 微信退款后交易状态为 REFUND 并按已支付返回, 均与本地的已退款视为一致
+func statusMatched(local string, remote string) bool {
+	switch local {
+	case "paid":
+		return remote == consts.TradeStatusPaid
+	case "refunded":
+		return remote == consts.TradeStatusRefunded || remote == consts.TradeStatusClosed || remote == consts.TradeStatusPaid
+	default:
+		return false
+	}
+}
+
+// unpaidOrders 当日创建但本地未支付的订单中, 渠道已支付的订单
+// 查询失败的订单不计入差异, 多数是用户未打开支付页面, 渠道没有交易
+func (r *Reconcile) unpaidOrders(ctx context.Context, start *gtime.Time, end *gtime.Time) ([]g.Map, error) {
+	var list []*entity.PaymentOrders
+	err := dao.PaymentOrders.Ctx(ctx).
+		WhereIn("status", []string{consts.OrderStatusCreated, consts.OrderStatusClosed}).
+		WhereGTE("created_at", start).
+		WhereLT("created_at", end).
+		OrderAsc("id").
+		Scan(&list)
+	if err != nil {
+		return nil, gerror.Wrap(err, "查询当日未支付订单失败")
+	}
+
+	var items []g.Map
+	for _, order := range list {
+		provider, err := payment.Get(ctx, order.Provider)
+		if err != nil {
+			continue
+		}
+		remote, err := provider.QueryOrder(ctx, order.OutTradeNo)
+		if err != nil || remote.Status != consts.TradeStatusPaid {
+			continue
+		}
+		items = append(items, newItem(&entity.PaymentReconcileItems{
+			Provider:           order.Provider,
+			OutTradeNo:         order.OutTradeNo,
+			LocalStatus:        order.Status,
+			ProviderStatus:     remote.Status,
+			LocalAmountCent:    order.AmountCent,
+			ProviderAmountCent: remote.AmountCent,
+		}, consts.MismatchUnpaid, "渠道已支付, 本地订单未支付, 支付通知可能丢失"))
+	}
+	return items, nil
+}
+
+func newItem(item *entity.PaymentReconcileItems, mismatchType string, detail string) g.Map {
+	if len([]rune(detail)) > 500 {
+		detail = string([]rune(detail)[:500])
+	}
+	return g.Map{
+		"provider":             item.Provider,
+		"out_trade_no":         item.OutTradeNo,
+		"payment_id":           item.PaymentId,
+		"mismatch_type":        mismatchType,
+		"local_status":         item.LocalStatus,
+		"provider_status":      item.ProviderStatus,
+		"local_amount_cent":    item.LocalAmountCent,
+		"provider_amount_cent": item.ProviderAmountCent,
+		"detail":               detail,
+		"created_at":           gtime.Now(),
+	}
+}
+
+// ListReports 获取对账报告列表
+func (r *Reconcile) ListReports(ctx context.Context, page int, size int) ([]*entity.PaymentReconcileReports, int, error) {
+	var (
+		list  []*entity.PaymentReconcileReports
+		total int
+	)
+	err := dao.PaymentReconcileReports.Ctx(ctx).
+		OrderDesc("report_date").
+		Page(page, size).
+		ScanAndCount(&list, &total, false)
+	return list, total, err
+}
+
+// GetReport 获取对账报告及差异明细
+func (r *Reconcile) GetReport(ctx context.Context, reportId int) (*entity.PaymentReconcileReports, []*entity.PaymentReconcileItems, error) {
+	var report *entity.PaymentReconcileReports
+	if err := dao.PaymentReconcileReports.Ctx(ctx).Where("id", reportId).Scan(&report); err != nil {
+		return nil, nil, err
+	}
+	if report == nil {
+		return nil, nil, gerror.NewCode(gcode.CodeNotFound, "对账报告不存在")
+	}
+
+	var items []*entity.PaymentReconcileItems
+	err := dao.PaymentReconcileItems.Ctx(ctx).
+		Where("report_id", reportId).
+		OrderAsc("id").
+		Scan(&items)
+	return report, items, err
+}
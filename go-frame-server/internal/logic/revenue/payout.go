@@ -0,0 +1,195 @@
+package revenue
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// 生成结算批次时使用的事务级咨询锁, 避免并发生成的批次重复结算同一笔收入
+const payoutLockKey = 40001
+
+type CreateBatchInput struct {
+	AdminId int
+	// Period 结算截止月份, 该月及之前所有未结算的收入一并结算
+	Period string
+}
+
+// CreateBatch 生成结算批次
+// 只结算已结束的月份, 未结算余额不大于最低打款金额的创作者(包括退款导致余额为负的)顺延到下个批次
+func (r *Revenue) CreateBatch(ctx context.Context, in *CreateBatchInput) (*entity.CreatorPayoutBatches, error) {
+	periodEnd, err := gtime.StrToTimeFormat(in.Period, "Y-m")
+	if err != nil {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "结算月份格式错误")
+	}
+	if !periodEnd.Before(gtime.Now().StartOfMonth()) {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "只能结算已结束的月份")
+	}
+	minPayout := g.Cfg().MustGet(ctx, "revenue.minPayout", 0).Float64()
+
+	var batch *entity.CreatorPayoutBatches
+	err = dao.CreatorPayoutBatches.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock(?)", payoutLockKey); err != nil {
+			return err
+		}
+
+		var items []*entity.CreatorPayoutItems
+		err := dao.CreatorRevenueEntries.Ctx(ctx).TX(tx).
+			Fields("creator_id, COUNT(*) AS entry_count, SUM(net_amount) AS amount").
+			WhereNull("payout_batch_id").
+			WhereLTE("period", in.Period).
+			Group("creator_id").
+			Having("SUM(net_amount) > ?", minPayout).
+			OrderAsc("creator_id").
+			Scan(&items)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return gerror.NewCode(gcode.CodeNotFound, "没有需要结算的收入")
+		}
+
+		var (
+			now        = gtime.Now()
+			creatorIds = make([]int, 0, len(items))
+			total      float64
+		)
+		for _, item := range items {
+			creatorIds = append(creatorIds, item.CreatorId)
+			total += item.Amount
+		}
+		batchId, err := dao.CreatorPayoutBatches.Ctx(ctx).TX(tx).Data(g.Map{
+			"period":        in.Period,
+			"status":        consts.PayoutBatchPending,
+			"creator_count": len(items),
+			"total_amount":  total,
+			"created_by":    in.AdminId,
+			"created_at":    now,
+			"updated_at":    now,
+		}).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+
+		_, err = dao.CreatorRevenueEntries.Ctx(ctx).TX(tx).
+			WhereNull("payout_batch_id").
+			WhereLTE("period", in.Period).
+			WhereIn("creator_id", creatorIds).
+			Data("payout_batch_id", batchId).
+			Update()
+		if err != nil {
+			return err
+		}
+
+		data := make(g.List, 0, len(items))
+		for _, item := range items {
+			data = append(data, g.Map{
+				"batch_id":    batchId,
+				"creator_id":  item.CreatorId,
+				"entry_count": item.EntryCount,
+				"amount":      item.Amount,
+				"created_at":  now,
+			})
+		}
+		if _, err = dao.CreatorPayoutItems.Ctx(ctx).TX(tx).Data(data).Insert(); err != nil {
+			return err
+		}
+
+		return dao.CreatorPayoutBatches.Ctx(ctx).TX(tx).Where("id", batchId).Scan(&batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	g.Log().Infof(ctx, "管理员 %d 生成结算批次 %d, 截止 %s, 创作者 %d 人, 金额 %.2f",
+		in.AdminId, batch.Id, batch.Period, batch.CreatorCount, batch.TotalAmount)
+	return batch, nil
+}
+
+// ListBatches 结算批次列表
+func (r *Revenue) ListBatches(ctx context.Context, page int, size int) ([]*entity.CreatorPayoutBatches, int, error) {
+	var (
+		list  []*entity.CreatorPayoutBatches
+		total int
+	)
+	err := dao.CreatorPayoutBatches.Ctx(ctx).
+		OrderDesc("id").
+		Page(page, size).
+		ScanAndCount(&list, &total, false)
+	return list, total, err
+}
+
+// MarkBatchPaid 标记结算批次已打款
+func (r *Revenue) MarkBatchPaid(ctx context.Context, batchId int) error {
+	result, err := dao.CreatorPayoutBatches.Ctx(ctx).
+		Where("id", batchId).
+		Where("status", consts.PayoutBatchPending).
+		Data(g.Map{
+			"status":     consts.PayoutBatchPaid,
+			"paid_at":    gtime.Now(),
+			"updated_at": gtime.Now(),
+		}).
+		Update()
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return gerror.NewCode(gcode.CodeNotFound, "结算批次不存在或已打款")
+	}
+	return nil
+}
+
+// ExportBatch 导出结算批次的打款明细 CSV, 返回文件名和内容
+func (r *Revenue) ExportBatch(ctx context.Context, batchId int) (string, []byte, error) {
+	var batch *entity.CreatorPayoutBatches
+	if err := dao.CreatorPayoutBatches.Ctx(ctx).Where("id", batchId).Scan(&batch); err != nil {
+		return "", nil, err
+	}
+	if batch == nil {
+		return "", nil, gerror.NewCode(gcode.CodeNotFound, "结算批次不存在")
+	}
+
+	rows, err := dao.CreatorPayoutItems.Ctx(ctx).As("i").
+		LeftJoin(dao.Users.Table()+" u", "u.id = i.creator_id").
+		Fields("i.creator_id, u.username, u.phone, u.email, i.entry_count, i.amount").
+		Where("i.batch_id", batchId).
+		OrderAsc("i.creator_id").
+		All()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	// 写入 BOM, 便于 Excel 正确识别 UTF-8 编码
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"批次ID", "结算截止月份", "创作者ID", "用户名", "手机号", "邮箱", "收入记录数", "打款金额"})
+	for _, row := range rows {
+		_ = w.Write([]string{
+			fmt.Sprint(batch.Id),
+			batch.Period,
+			row["creator_id"].String(),
+			row["username"].String(),
+			row["phone"].String(),
+			row["email"].String(),
+			row["entry_count"].String(),
+			fmt.Sprintf("%.2f", row["amount"].Float64()),
+		})
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("payout_batch_%d_%s.csv", batch.Id, batch.Period), buf.Bytes(), nil
+}
@@ -0,0 +1,216 @@
+package revenue
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Revenue 创作者收入流水: 项目每笔成功的购买记一条销售, 退款记一条金额为负的冲销
+// 流水由定时任务根据 user_project_purchases 同步, 按支付记录和类型去重, 重复同步不会重复记账
+// 退款后再次购买会复用购买记录, 因此以支付记录而不是购买记录区分每一笔销售
+type Revenue struct{}
+
+func New() *Revenue {
+	return &Revenue{}
+}
+
+// Summary 收入汇总
+type Summary struct {
+	SaleCount        int     `json:"saleCount" dc:"销售笔数"`
+	SaleAmount       float64 `json:"saleAmount" dc:"销售金额"`
+	RefundCount      int     `json:"refundCount" dc:"退款笔数"`
+	RefundAmount     float64 `json:"refundAmount" dc:"退款金额, 负数"`
+	GrossAmount      float64 `json:"grossAmount" dc:"订单净额"`
+	CommissionAmount float64 `json:"commissionAmount" dc:"平台抽成"`
+	NetAmount        float64 `json:"netAmount" dc:"创作者收入"`
+}
+
+// Balance 创作者余额
+type Balance struct {
+	CreatorId int     `json:"creatorId" dc:"创作者ID"`
+	Earned    float64 `json:"earned" dc:"累计收入"`
+	PaidOut   float64 `json:"paidOut" dc:"已打款"`
+	Pending   float64 `json:"pending" dc:"已生成结算批次, 待打款"`
+	Unsettled float64 `json:"unsettled" dc:"未结算余额"`
+}
+
+type StatementInput struct {
+	CreatorId int
+	Period    string
+	Page      int
+	Size      int
+}
+
+type StatementOutput struct {
+	Period  string
+	Summary *Summary
+	Balance *Balance
+	Total   int
+	List    []*entity.CreatorRevenueEntries
+}
+
+// CommissionRate 平台抽成比例, 取值 [0, 1]
+func CommissionRate(ctx context.Context) (float64, error) {
+	rate := g.Cfg().MustGet(ctx, "revenue.commissionRate", 0.2).Float64()
+	if rate < 0 || rate > 1 {
+		return 0, gerror.Newf("平台抽成比例配置错误: %v", rate)
+	}
+	return rate, nil
+}
+
+// Sync 根据项目购买记录同步收入流水, 返回新增的销售和退款记录数
+// 销售按购买时记录的抽成比例记账, 早期未记录比例的购买按当前配置; 退款按原销售记录的金额和比例全额冲销
+func (r *Revenue) Sync(ctx context.Context) (sales int64, refunds int64, err error) {
+	rate, err := CommissionRate(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	entries := dao.CreatorRevenueEntries.Table()
+	result, err := g.DB().Exec(ctx,
+		`INSERT INTO `+entries+` (creator_id, project_id, purchase_id, payment_id, buyer_id, entry_type, purchase_type,
+			gross_amount, commission_rate, commission_amount, net_amount, period, occurred_at)
+		SELECT p.user_id, up.project_id, up.id, up.payment_id, up.user_id, ?, up.purchase_type,
+			up.purchase_price, r.rate, ROUND(up.purchase_price * r.rate, 2), up.purchase_price - ROUND(up.purchase_price * r.rate, 2),
+			to_char(COALESCE(bp.paid_at, up.updated_at), 'YYYY-MM'), COALESCE(bp.paid_at, up.updated_at)
+		FROM `+dao.UserProjectPurchases.Table()+` up
+		JOIN `+dao.Projects.Table()+` p ON p.id = up.project_id
+		LEFT JOIN `+dao.BillingPayments.Table()+` bp ON bp.id = up.payment_id
+		CROSS JOIN LATERAL (SELECT COALESCE(up.commission_rate, CAST(? AS numeric)) AS rate) r
+		WHERE up.status IN (?, ?) AND up.purchase_price > 0 AND up.payment_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM `+entries+` e WHERE e.payment_id = up.payment_id AND e.entry_type = ?)
+		ON CONFLICT (payment_id, entry_type) DO NOTHING`,
+		consts.RevenueEntrySale, rate,
+		consts.PurchaseStatusCompleted, consts.PurchaseStatusRefunded,
+		consts.RevenueEntrySale,
+	)
+	if err != nil {
+		return 0, 0, gerror.Wrap(err, "同步销售流水失败")
+	}
+	sales, _ = result.RowsAffected()
+
+	// 以支付记录的退款状态为准, 购买记录被再次购买覆盖后之前的退款仍能冲销
+	result, err = g.DB().Exec(ctx,
+		`INSERT INTO `+entries+` (creator_id, project_id, purchase_id, payment_id, buyer_id, entry_type, purchase_type,
+			gross_amount, commission_rate, commission_amount, net_amount, period, occurred_at)
+		SELECT e.creator_id, e.project_id, e.purchase_id, e.payment_id, e.buyer_id, ?, e.purchase_type,
+			-e.gross_amount, e.commission_rate, -e.commission_amount, -e.net_amount,
+			to_char(COALESCE(bp.refunded_at, bp.updated_at), 'YYYY-MM'), COALESCE(bp.refunded_at, bp.updated_at)
+		FROM `+entries+` e
+		JOIN `+dao.BillingPayments.Table()+` bp ON bp.id = e.payment_id
+		WHERE e.entry_type = ? AND bp.payment_status = 'refunded'
+			AND NOT EXISTS (SELECT 1 FROM `+entries+` x WHERE x.payment_id = e.payment_id AND x.entry_type = ?)
+		ON CONFLICT (payment_id, entry_type) DO NOTHING`,
+		consts.RevenueEntryRefund,
+		consts.RevenueEntrySale,
+		consts.RevenueEntryRefund,
+	)
+	if err != nil {
+		return sales, 0, gerror.Wrap(err, "同步退款流水失败")
+	}
+	refunds, _ = result.RowsAffected()
+
+	if sales > 0 || refunds > 0 {
+		g.Log().Infof(ctx, "已同步创作者收入流水, 销售 %d 条, 退款 %d 条", sales, refunds)
+	}
+	return sales, refunds, nil
+}
+
+// Statement 创作者月度对账单
+func (r *Revenue) Statement(ctx context.Context, in *StatementInput) (*StatementOutput, error) {
+	out := &StatementOutput{Period: in.Period}
+
+	var rows []struct {
+		EntryType        string
+		Count            int
+		GrossAmount      float64
+		CommissionAmount float64
+		NetAmount        float64
+	}
+	err := dao.CreatorRevenueEntries.Ctx(ctx).
+		Fields("entry_type, COUNT(*) AS count, SUM(gross_amount) AS gross_amount, SUM(commission_amount) AS commission_amount, SUM(net_amount) AS net_amount").
+		Where("creator_id", in.CreatorId).
+		Where("period", in.Period).
+		Group("entry_type").
+		Scan(&rows)
+	if err != nil {
+		return nil, err
+	}
+	out.Summary = &Summary{}
+	for _, row := range rows {
+		switch row.EntryType {
+		case consts.RevenueEntrySale:
+			out.Summary.SaleCount = row.Count
+			out.Summary.SaleAmount = row.GrossAmount
+		case consts.RevenueEntryRefund:
+			out.Summary.RefundCount = row.Count
+			out.Summary.RefundAmount = row.GrossAmount
+		}
+		out.Summary.GrossAmount += row.GrossAmount
+		out.Summary.CommissionAmount += row.CommissionAmount
+		out.Summary.NetAmount += row.NetAmount
+	}
+
+	err = dao.CreatorRevenueEntries.Ctx(ctx).
+		Where("creator_id", in.CreatorId).
+		Where("period", in.Period).
+		OrderDesc("occurred_at").
+		OrderDesc("id").
+		Page(in.Page, in.Size).
+		ScanAndCount(&out.List, &out.Total, false)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := r.balances(ctx, in.CreatorId, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	out.Balance = &Balance{CreatorId: in.CreatorId}
+	if len(balances) > 0 {
+		out.Balance = balances[0]
+	}
+	return out, nil
+}
+
+// Balances 各创作者的余额, 按未结算余额从高到低排序
+func (r *Revenue) Balances(ctx context.Context, page int, size int) ([]*Balance, int, error) {
+	total, err := dao.CreatorRevenueEntries.Ctx(ctx).Fields("COUNT(DISTINCT creator_id)").Value()
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := r.balances(ctx, 0, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total.Int(), nil
+}
+
+// balances 按创作者汇总收入流水, creatorId 为 0 时汇总所有创作者
+func (r *Revenue) balances(ctx context.Context, creatorId int, page int, size int) ([]*Balance, error) {
+	model := dao.CreatorRevenueEntries.Ctx(ctx).As("e").
+		LeftJoin(dao.CreatorPayoutBatches.Table()+" b", "b.id = e.payout_batch_id").
+		Fields(`e.creator_id,
+			COALESCE(SUM(e.net_amount), 0) AS earned,
+			COALESCE(SUM(CASE WHEN b.status = '`+consts.PayoutBatchPaid+`' THEN e.net_amount END), 0) AS paid_out,
+			COALESCE(SUM(CASE WHEN b.status = '`+consts.PayoutBatchPending+`' THEN e.net_amount END), 0) AS pending,
+			COALESCE(SUM(CASE WHEN e.payout_batch_id IS NULL THEN e.net_amount END), 0) AS unsettled`).
+		Group("e.creator_id").
+		Order("unsettled DESC, e.creator_id ASC").
+		Page(page, size)
+	if creatorId > 0 {
+		model = model.Where("e.creator_id", creatorId)
+	}
+
+	var list []*Balance
+	if err := model.Scan(&list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,33 @@
+package schedules
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/email"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// notifyFailure 运行失败时给计划创建者发送邮件, 发送失败只记录日志
+func (s *Schedules) notifyFailure(ctx context.Context, schedule *entity.PipelineSchedules, runId int, runErr error) {
+	var user *entity.Users
+	if err := dao.Users.Ctx(ctx).Where("id", schedule.UserId).Scan(&user); err != nil {
+		g.Log().Errorf(ctx, "获取用户信息失败: %v", err)
+		return
+	}
+	if user == nil || user.Email == "" {
+		return
+	}
+
+	subject := fmt.Sprintf("工作流定时运行失败（计划 #%d）", schedule.Id)
+	body := fmt.Sprintf(
+		"您的工作流定时运行失败。<br/>项目ID: %d<br/>工作流ID: %d<br/>运行ID: %d<br/>失败原因: %s<br/>本批素材将在下次运行时重新处理。",
+		schedule.ProjectId, schedule.PipelineId, runId, runErr.Error(),
+	)
+	if err := email.SendHTML(ctx, user.Email, subject, body); err != nil {
+		g.Log().Errorf(ctx, "发送运行失败通知邮件失败: %v, 计划ID: %d", err, schedule.Id)
+	}
+}
@@ -0,0 +1,249 @@
+package schedules
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/external/py_service"
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/tasks"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Run 执行一次运行计划: 对上次运行之后新增的素材依次执行 OCR、抽取和图谱化
+func (s *Schedules) Run(ctx context.Context, scheduleId int) error {
+	var schedule *entity.PipelineSchedules
+	if err := dao.PipelineSchedules.Ctx(ctx).Where("id", scheduleId).Scan(&schedule); err != nil {
+		return err
+	}
+	if schedule == nil || schedule.Status != consts.ScheduleStatusActive {
+		return nil
+	}
+
+	// 超过锁过期时间仍在运行的记录来自中断的运行(如服务重启), 标记为失败后不再阻塞计划
+	staleBefore := gtime.Now().Add(-lockTTL(ctx))
+	_, err := dao.PipelineScheduleRuns.Ctx(ctx).
+		Where("schedule_id", scheduleId).
+		Where("status", consts.ScheduleRunStatusRunning).
+		WhereLT("start_time", staleBefore).
+		Update(g.Map{
+			"status":        consts.ScheduleRunStatusFailed,
+			"error_message": "运行超时中断",
+			"finish_time":   gtime.Now(),
+		})
+	if err != nil {
+		return err
+	}
+
+	// 上一次运行尚未结束时跳过, 避免重复处理同一批素材
+	running, err := dao.PipelineScheduleRuns.Ctx(ctx).
+		Where("schedule_id", scheduleId).
+		Where("status", consts.ScheduleRunStatusRunning).
+		Count()
+	if err != nil {
+		return err
+	}
+	if running > 0 {
+		g.Log().Infof(ctx, "上一次运行尚未结束, 跳过本次运行, 计划ID: %d", scheduleId)
+		return nil
+	}
+
+	var materials []*entity.Materials
+	err = dao.Materials.Ctx(ctx).
+		Where("project_id", schedule.ProjectId).
+		WhereGT("id", schedule.LastMaterialId).
+		Where("enable", 1).
+		OrderAsc("id").
+		Scan(&materials)
+	if err != nil {
+		return err
+	}
+
+	now := gtime.Now()
+	if _, err = dao.PipelineSchedules.Ctx(ctx).Where("id", scheduleId).Update(g.Map{
+		"last_run_at": now,
+	}); err != nil {
+		return err
+	}
+
+	if len(materials) == 0 {
+		_, err = dao.PipelineScheduleRuns.Ctx(ctx).Data(g.Map{
+			"schedule_id": scheduleId,
+			"status":      consts.ScheduleRunStatusSkipped,
+			"start_time":  now,
+			"finish_time": now,
+			"created_at":  now,
+		}).Insert()
+		return err
+	}
+
+	materialIdList := make([]int, 0, len(materials))
+	for _, material := range materials {
+		materialIdList = append(materialIdList, material.Id)
+	}
+	runId, err := dao.PipelineScheduleRuns.Ctx(ctx).Data(g.Map{
+		"schedule_id":      scheduleId,
+		"status":           consts.ScheduleRunStatusRunning,
+		"material_id_list": materialIdList,
+		"start_time":       now,
+		"created_at":       now,
+	}).InsertAndGetId()
+	if err != nil {
+		return err
+	}
+
+	if err = s.runSteps(ctx, schedule, int(runId), materialIdList); err != nil {
+		g.Log().Errorf(ctx, "工作流定时运行失败: %v, 计划ID: %d, 运行ID: %d", err, scheduleId, runId)
+		_, updateErr := dao.PipelineScheduleRuns.Ctx(ctx).Where("id", runId).Update(g.Map{
+			"status":        consts.ScheduleRunStatusFailed,
+			"error_message": err.Error(),
+			"finish_time":   gtime.Now(),
+		})
+		if updateErr != nil {
+			g.Log().Errorf(ctx, "更新运行记录失败: %v", updateErr)
+		}
+		if schedule.NotifyOnFailure {
+			s.notifyFailure(ctx, schedule, int(runId), err)
+		}
+		return nil
+	}
+
+	// 全部步骤完成后才推进素材进度, 失败的批次会在下次运行时重新处理
+	return dao.PipelineSchedules.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		_, err := dao.PipelineScheduleRuns.Ctx(ctx).TX(tx).Where("id", runId).Update(g.Map{
+			"status":      consts.ScheduleRunStatusCompleted,
+			"finish_time": gtime.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = dao.PipelineSchedules.Ctx(ctx).TX(tx).Where("id", scheduleId).Update(g.Map{
+			"last_material_id": materialIdList[len(materialIdList)-1],
+			"updated_at":       gtime.Now(),
+		})
+		return err
+	})
+}
+
+// runSteps 依次执行 OCR、抽取和图谱化, 每一步结束后再进入下一步
+func (s *Schedules) runSteps(ctx context.Context, schedule *entity.PipelineSchedules, runId int, materialIdList []int) error {
+	var project *entity.Projects
+	if err := dao.Projects.Ctx(ctx).Where("id", schedule.ProjectId).Scan(&project); err != nil {
+		return err
+	}
+	if project == nil {
+		return gerror.New("项目不存在")
+	}
+
+	// 抽取参数取自项目最近一次使用的抽取配置
+	var extractConfig py_service.ExtractConfig
+	if project.ExtractConfig != nil {
+		if err := project.ExtractConfig.Scan(&extractConfig); err != nil {
+			return gerror.Wrap(err, "解析项目抽取配置失败")
+		}
+	}
+	if extractConfig.ModelId == 0 || extractConfig.Prompt == "" {
+		return gerror.New("项目未配置抽取参数, 请先手动执行一次抽取")
+	}
+
+	steps := []struct {
+		taskType string
+		column   string
+	}{
+		{consts.TaskTypeOCR, "ocr_task_id"},
+		{consts.TaskTypeExtract, "extract_task_id"},
+		{consts.TaskTypeGraph, "graph_task_id"},
+	}
+	for _, step := range steps {
+		task, err := s.createTask(ctx, schedule, project, step.taskType, materialIdList, extractConfig)
+		if err != nil {
+			return gerror.Wrapf(err, "创建%s任务失败", step.taskType)
+		}
+		if _, err = dao.PipelineScheduleRuns.Ctx(ctx).Where("id", runId).Update(g.Map{
+			step.column: task.Id,
+		}); err != nil {
+			return err
+		}
+
+		if err = s.waitTask(ctx, task.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTask 创建单个步骤的任务
+// 抽取任务创建时即写入计划ID和抽取配置, 完成后据此与项目已有的三元组合并
+func (s *Schedules) createTask(ctx context.Context, schedule *entity.PipelineSchedules, project *entity.Projects, taskType string, materialIdList []int, extractConfig py_service.ExtractConfig) (*entity.Tasks, error) {
+	if taskType == consts.TaskTypeExtract {
+		return py_service.CreateExtractTask(ctx, &py_service.ExtractTaskInput{
+			UserId:         project.UserId,
+			PipelineId:     schedule.PipelineId,
+			ProjectId:      schedule.ProjectId,
+			MaterialIdList: materialIdList,
+			Config:         extractConfig,
+			ScheduleId:     schedule.Id,
+		})
+	}
+
+	task, err := tasks.New().Create(ctx, &tasks.CreateTaskInput{
+		Type:           taskType,
+		PipelineId:     schedule.PipelineId,
+		ProjectId:      schedule.ProjectId,
+		MaterialIdList: materialIdList,
+		Status:         consts.TaskStatusPending,
+		UpdatedAt:      gtime.Now(),
+		CreatedAt:      gtime.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// OCR 与图谱化任务的计划ID仅用于追溯
+	if _, err = dao.Tasks.Ctx(ctx).Where("id", task.Id).Update(g.Map{
+		"schedule_id": schedule.Id,
+	}); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// waitTask 轮询任务状态直到结束
+func (s *Schedules) waitTask(ctx context.Context, taskId int) error {
+	interval := g.Cfg().MustGet(ctx, "schedule.pollInterval", 10).Int()
+	timeout := g.Cfg().MustGet(ctx, "schedule.stepTimeout", 7200).Int()
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+	for {
+		var task *entity.Tasks
+		if err := dao.Tasks.Ctx(ctx).Where("id", taskId).Scan(&task); err != nil {
+			return err
+		}
+		if task == nil {
+			return gerror.Newf("任务不存在: %d", taskId)
+		}
+		switch task.Status {
+		case consts.TaskStatusCompleted:
+			return nil
+		case consts.TaskStatusFailed:
+			if task.ErrorMessage != "" {
+				return gerror.Newf("%s任务失败: %s", task.Type, task.ErrorMessage)
+			}
+			return gerror.Newf("%s任务失败, 任务ID: %d", task.Type, taskId)
+		}
+
+		select {
+		case <-ctx.Done():
+			return gerror.Newf("等待%s任务超时, 任务ID: %d", task.Type, taskId)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package schedules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	cron "kgplatform-backend/internal/corn"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Schedules 工作流定时运行计划：按 cron 表达式对新增素材增量执行 OCR、抽取和图谱化
+type Schedules struct{}
+
+func New() *Schedules {
+	return &Schedules{}
+}
+
+type CreateScheduleInput struct {
+	UserId          int
+	PipelineId      int
+	CronExpr        string
+	NotifyOnFailure bool
+}
+
+type ListRunInput struct {
+	UserId     int
+	ScheduleId int
+	Page       int
+	Size       int
+}
+
+// cronName 运行计划在 gcron 中的任务名
+func cronName(scheduleId int) string {
+	return fmt.Sprintf("pipeline_schedule_%d", scheduleId)
+}
+
+// Create 创建运行计划, 只处理创建之后新增的素材
+func (s *Schedules) Create(ctx context.Context, in *CreateScheduleInput) (*entity.PipelineSchedules, error) {
+	var pipeline *entity.Pipelines
+	if err := dao.Pipelines.Ctx(ctx).Where("id", in.PipelineId).Scan(&pipeline); err != nil {
+		return nil, err
+	}
+	if pipeline == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "工作流不存在")
+	}
+	if err := s.checkProjectOwner(ctx, in.UserId, pipeline.ProjectId); err != nil {
+		return nil, err
+	}
+
+	lastMaterialId, err := dao.Materials.Ctx(ctx).Where("project_id", pipeline.ProjectId).Max("id")
+	if err != nil {
+		return nil, err
+	}
+
+	now := gtime.Now()
+	schedule := &entity.PipelineSchedules{
+		PipelineId:      pipeline.Id,
+		ProjectId:       pipeline.ProjectId,
+		UserId:          in.UserId,
+		CronExpr:        in.CronExpr,
+		Status:          consts.ScheduleStatusActive,
+		NotifyOnFailure: in.NotifyOnFailure,
+		LastMaterialId:  int(lastMaterialId),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	id, err := dao.PipelineSchedules.Ctx(ctx).Data(g.Map{
+		"pipeline_id":       schedule.PipelineId,
+		"project_id":        schedule.ProjectId,
+		"user_id":           schedule.UserId,
+		"cron_expr":         schedule.CronExpr,
+		"status":            schedule.Status,
+		"notify_on_failure": schedule.NotifyOnFailure,
+		"last_material_id":  schedule.LastMaterialId,
+		"created_at":        now,
+		"updated_at":        now,
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, err
+	}
+	schedule.Id = int(id)
+
+	// 写入后再注册, 避免注册的任务先于计划记录运行; 注册失败时删除计划, 同时校验了 cron 表达式
+	if err = s.register(ctx, schedule.Id, schedule.CronExpr); err != nil {
+		if _, deleteErr := dao.PipelineSchedules.Ctx(ctx).Where("id", schedule.Id).Delete(); deleteErr != nil {
+			g.Log().Errorf(ctx, "删除无效的运行计划失败: %v, 计划ID: %d", deleteErr, schedule.Id)
+		}
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, "cron表达式无效: %s", schedule.CronExpr)
+	}
+	return schedule, nil
+}
+
+// List 获取用户的运行计划, pipelineId 为 0 时返回全部
+func (s *Schedules) List(ctx context.Context, userId int, pipelineId int) ([]*entity.PipelineSchedules, error) {
+	m := dao.PipelineSchedules.Ctx(ctx).Where("user_id", userId)
+	if pipelineId > 0 {
+		m = m.Where("pipeline_id", pipelineId)
+	}
+	var list []*entity.PipelineSchedules
+	if err := m.OrderDesc("id").Scan(&list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Pause 暂停运行计划
+func (s *Schedules) Pause(ctx context.Context, userId int, scheduleId int) error {
+	schedule, err := s.getSchedule(ctx, userId, scheduleId)
+	if err != nil {
+		return err
+	}
+	if schedule.Status == consts.ScheduleStatusPaused {
+		return nil
+	}
+
+	_, err = dao.PipelineSchedules.Ctx(ctx).Where("id", scheduleId).Update(g.Map{
+		"status":     consts.ScheduleStatusPaused,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	gcron.Remove(cronName(scheduleId))
+	return nil
+}
+
+// Resume 恢复运行计划, 暂停期间新增的素材会在下次运行时处理
+func (s *Schedules) Resume(ctx context.Context, userId int, scheduleId int) error {
+	schedule, err := s.getSchedule(ctx, userId, scheduleId)
+	if err != nil {
+		return err
+	}
+	if schedule.Status == consts.ScheduleStatusActive {
+		return nil
+	}
+
+	_, err = dao.PipelineSchedules.Ctx(ctx).Where("id", scheduleId).Update(g.Map{
+		"status":     consts.ScheduleStatusActive,
+		"updated_at": gtime.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.register(ctx, scheduleId, schedule.CronExpr)
+}
+
+// Delete 删除运行计划及其运行记录
+func (s *Schedules) Delete(ctx context.Context, userId int, scheduleId int) error {
+	if _, err := s.getSchedule(ctx, userId, scheduleId); err != nil {
+		return err
+	}
+
+	gcron.Remove(cronName(scheduleId))
+	_, err := dao.PipelineSchedules.Ctx(ctx).Where("id", scheduleId).Delete()
+	return err
+}
+
+// ListRuns 获取运行计划的运行记录
+func (s *Schedules) ListRuns(ctx context.Context, in *ListRunInput) ([]*entity.PipelineScheduleRuns, int, error) {
+	if _, err := s.getSchedule(ctx, in.UserId, in.ScheduleId); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		list  []*entity.PipelineScheduleRuns
+		total int
+	)
+	err := dao.PipelineScheduleRuns.Ctx(ctx).
+		Where("schedule_id", in.ScheduleId).
+		OrderDesc("id").
+		Page(in.Page, in.Size).
+		ScanAndCount(&list, &total, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// StartAll 注册所有启用中的运行计划, 服务启动时调用
+func (s *Schedules) StartAll(ctx context.Context) error {
+	var list []*entity.PipelineSchedules
+	err := dao.PipelineSchedules.Ctx(ctx).Where("status", consts.ScheduleStatusActive).Scan(&list)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range list {
+		if err = s.register(ctx, schedule.Id, schedule.CronExpr); err != nil {
+			g.Log().Errorf(ctx, "注册工作流定时运行计划失败: %v, 计划ID: %d", err, schedule.Id)
+		}
+	}
+	g.Log().Infof(ctx, "已注册%d个工作流定时运行计划", len(list))
+	return nil
+}
+
+// register 将运行计划注册到 gcron, 已注册时先移除
+// 多实例部署时通过分布式锁保证同一计划只在一个实例上运行
+func (s *Schedules) register(ctx context.Context, scheduleId int, cronExpr string) error {
+	name := cronName(scheduleId)
+	gcron.Remove(name)
+	_, err := gcron.AddSingleton(ctx, cronExpr, func(ctx context.Context) {
+		unlock, ok, err := cron.TryLock(ctx, name, lockTTL(ctx))
+		if err != nil {
+			g.Log().Errorf(ctx, "获取工作流定时运行锁失败: %v, 计划ID: %d", err, scheduleId)
+			return
+		}
+		if !ok {
+			return
+		}
+		defer unlock()
+
+		if err := s.Run(ctx, scheduleId); err != nil {
+			g.Log().Errorf(ctx, "工作流定时运行失败: %v, 计划ID: %d", err, scheduleId)
+		}
+	}, name)
+	return err
+}
+
+// lockTTL 运行锁的过期时间, 三个步骤依次执行, 按最长等待时间计算
+func lockTTL(ctx context.Context) time.Duration {
+	stepTimeout := g.Cfg().MustGet(ctx, "schedule.stepTimeout", 7200).Int()
+	return time.Duration(stepTimeout*3) * time.Second
+}
+
+// getSchedule 获取运行计划并校验是否为本人创建
+func (s *Schedules) getSchedule(ctx context.Context, userId int, scheduleId int) (*entity.PipelineSchedules, error) {
+	var schedule *entity.PipelineSchedules
+	if err := dao.PipelineSchedules.Ctx(ctx).Where("id", scheduleId).Scan(&schedule); err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, gerror.NewCode(gcode.CodeNotFound, "运行计划不存在")
+	}
+	if schedule.UserId != userId {
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "无权操作该运行计划")
+	}
+	return schedule, nil
+}
+
+// checkProjectOwner 只有项目所有者可以为工作流创建运行计划
+func (s *Schedules) checkProjectOwner(ctx context.Context, userId int, projectId int) error {
+	var project *entity.Projects
+	if err := dao.Projects.Ctx(ctx).Where("id", projectId).Scan(&project); err != nil {
+		return err
+	}
+	if project == nil {
+		return gerror.NewCode(gcode.CodeNotFound, "项目不存在")
+	}
+	if project.UserId != userId {
+		return gerror.NewCode(gcode.CodeNotAuthorized, "无权操作该项目")
+	}
+	return nil
+}
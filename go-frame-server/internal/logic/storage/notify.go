@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/email"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// notifyWarning 存储用量达到提醒比例时给账户所有者发送邮件, 发送失败只记录日志
+func notifyWarning(ctx context.Context, acc *account, percent int) {
+	var user *entity.Users
+	if err := dao.Users.Ctx(ctx).Where("id", acc.ownerId).Scan(&user); err != nil {
+		g.Log().Errorf(ctx, "获取用户信息失败: %v", err)
+		return
+	}
+	if user == nil || user.Email == "" {
+		return
+	}
+
+	owner := "您的"
+	if acc.teamId > 0 {
+		owner = "您团队的"
+	}
+	subject := fmt.Sprintf("%s存储空间已使用 %d%%", owner, percent)
+	advice := "超出配额的部分将在月度账单中按超额费用计费。"
+	if acc.plan == consts.PlanFree {
+		advice = "免费版超出配额后将无法继续上传文件, 请删除不需要的文件或升级套餐。"
+	}
+	body := fmt.Sprintf("%s存储空间已使用 %d MB, 配额 %d MB, 已达到 %d%%。<br/>%s",
+		owner, acc.used, acc.quota, percent, advice)
+	if err := email.SendHTML(ctx, user.Email, subject, body); err != nil {
+		g.Log().Errorf(ctx, "发送存储提醒邮件失败: %v, 用户ID: %d", err, acc.ownerId)
+	}
+}
@@ -0,0 +1,596 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/guid"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/upload"
+	"kgplatform-backend/internal/logic/usage"
+	"kgplatform-backend/internal/model/do"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Storage 存储用量: 记录云存储中每个对象的归属和大小, 按未删除的对象统计个人和团队的存储用量
+// 免费版超出存储配额时拒绝写入, 付费套餐超出的部分在月度出账时按超额计费
+type Storage struct{}
+
+func New() *Storage {
+	return &Storage{}
+}
+
+const mb = 1 << 20
+
+// pendingPrefix 对象写入完成前 pending 记录使用的占位对象名前缀
+const pendingPrefix = "pending/"
+
+// 预留存储用量时使用的事务级咨询锁, 第二个键为存储账户, 同一账户的预留依次执行
+const reserveLockKey = 40002
+
+// CodeFreePlanStorageQuotaExceeded 免费版存储空间不足, 与字数配额的错误码 1002、1003 连续编号
+var CodeFreePlanStorageQuotaExceeded = gcode.New(1004, "FreePlanStorageQuotaExceeded", "免费版存储空间不足")
+
+// account 存储用量的归属: 团队成员的对象计入团队, 存储提醒发送给团队所有者
+type account struct {
+	ownerId int64
+	teamId  int64
+	plan    string
+	quota   int64 // 存储配额(MB)
+	used    int64 // 存储用量(MB)
+}
+
+// RecordInput 记录存储对象的参数
+type RecordInput struct {
+	UserId     int
+	ObjectName string
+	Size       int64
+	Source     string
+}
+
+// SaveData 校验存储配额后保存文本或 JSON 数据, 并计入用户的存储用量
+func (s *Storage) SaveData(ctx context.Context, in *upload.SaveDataInput, source string) (*upload.SaveDataOutput, error) {
+	return s.Write(ctx, &WriteInput{UserId: in.UserId, Size: int64(len(in.Content)), Source: source},
+		func(ctx context.Context) (*upload.SaveDataOutput, error) {
+			output, err := upload.NewUpload().SaveData(ctx, in)
+			if err == nil && output.FileSize <= 0 {
+				output.FileSize = int64(len(in.Content))
+			}
+			return output, err
+		})
+}
+
+// SaveObject 校验存储配额后保存二进制文件, 并计入用户的存储用量
+func (s *Storage) SaveObject(ctx context.Context, in *upload.SaveObjectInput, source string) (*upload.SaveDataOutput, error) {
+	return s.Write(ctx, &WriteInput{UserId: in.UserId, Size: int64(len(in.Content)), Source: source},
+		func(ctx context.Context) (*upload.SaveDataOutput, error) {
+			return upload.NewUpload().SaveObject(ctx, in)
+		})
+}
+
+// WriteInput 写入对象的参数, Size 为写入前预计的大小
+type WriteInput struct {
+	UserId int
+	Size   int64
+	Source string
+}
+
+// Write 写入对象并按实际大小计入存储用量, 分三步执行, 写入对象时不持有任何事务和锁:
+//  1. 在独立的短事务内校验配额并写入 pending 记录预留用量, 并发写入按预留的用量校验配额
+//  2. 在事务外写入对象, 并将对象名记录到 pending 记录
+//  3. 在调用方的事务内确认记录并按实际大小计入用量, 免费版超出配额时删除记录和已写入的对象
+//
+// 调用方事务回滚时记录保持 pending, 由 Purge 在 storage.pendingTimeout 后删除记录和对象
+func (s *Storage) Write(ctx context.Context, in *WriteInput, write func(ctx context.Context) (*upload.SaveDataOutput, error)) (*upload.SaveDataOutput, error) {
+	if in.UserId == 0 {
+		return write(ctx)
+	}
+	pendingId, err := reserve(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	output, err := write(ctx)
+	if err != nil {
+		if discardErr := discard(ctx, pendingId); discardErr != nil {
+			g.Log().Errorf(ctx, "删除预留的存储记录失败: %v, 记录ID: %d", discardErr, pendingId)
+		}
+		return nil, err
+	}
+	bind(ctx, pendingId, output)
+
+	var acc *account
+	err = dao.StorageObjects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var err error
+		acc, err = activate(ctx, tx, pendingId, &RecordInput{
+			UserId:     in.UserId,
+			ObjectName: output.FileName,
+			Size:       output.FileSize,
+			Source:     in.Source,
+		})
+		if err != nil {
+			return err
+		}
+		return checkQuota(acc, 0)
+	})
+	if err != nil {
+		if discardErr := discard(ctx, pendingId); discardErr != nil {
+			g.Log().Errorf(ctx, "删除未计入用量的对象失败: %v, 对象: %s", discardErr, output.FileName)
+		}
+		return nil, err
+	}
+	warn(ctx, acc)
+	return output, nil
+}
+
+// reserve 在独立于调用方事务的短事务内校验配额并写入 pending 记录, 按写入前预计的大小预留存储用量
+// 调用方事务可能已锁定用户的订阅, 这里只读取账户并以咨询锁串行同一账户的预留, 不更新订阅和团队上统计的用量
+func reserve(ctx context.Context, in *WriteInput) (int, error) {
+	ctx = gdb.WithoutTX(ctx, dao.StorageObjects.Group())
+	var pendingId int
+	err := dao.StorageObjects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		acc, err := readAccount(ctx, tx, in.UserId, false)
+		if err != nil {
+			return err
+		}
+		// 个人账户和团队账户分别以用户ID和团队ID的相反数区分
+		accountKey, where := int64(in.UserId), g.Map{"user_id": in.UserId, "team_id": 0}
+		if acc.teamId > 0 {
+			accountKey, where = -acc.teamId, g.Map{"team_id": acc.teamId}
+		}
+		if _, err = tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", reserveLockKey, accountKey); err != nil {
+			return err
+		}
+		// 按已写入和其他写入中预留的用量校验配额
+		if acc.used, err = usedMB(ctx, tx, where); err != nil {
+			return err
+		}
+		if err = checkQuota(acc, in.Size); err != nil {
+			return err
+		}
+		id, err := dao.StorageObjects.Ctx(ctx).TX(tx).Data(do.StorageObjects{
+			UserId:     in.UserId,
+			TeamId:     acc.teamId,
+			ObjectName: pendingPrefix + guid.S(),
+			SizeBytes:  max(in.Size, 0),
+			Source:     in.Source,
+			Status:     consts.StorageObjectPending,
+		}).InsertAndGetId()
+		if err != nil {
+			return gerror.Wrap(err, "预留存储用量失败")
+		}
+		pendingId = int(id)
+		return nil
+	})
+	return pendingId, err
+}
+
+// bind 将写入的对象名和实际大小记录到 pending 记录, 清理任务据此删除未确认的对象
+// 同名对象已有记录时只更新大小, 覆盖写入的对象仍由原记录跟踪
+func bind(ctx context.Context, pendingId int, output *upload.SaveDataOutput) {
+	ctx = gdb.WithoutTX(ctx, dao.StorageObjects.Group())
+	data := g.Map{
+		"size_bytes": output.FileSize,
+		"updated_at": gtime.Now(),
+	}
+	exists, err := dao.StorageObjects.Ctx(ctx).Where("object_name", output.FileName).Exist()
+	if err != nil {
+		g.Log().Errorf(ctx, "查询存储对象记录失败: %v, 对象: %s", err, output.FileName)
+	}
+	if err == nil && !exists {
+		data["object_name"] = output.FileName
+	}
+	_, err = dao.StorageObjects.Ctx(ctx).
+		Where("id", pendingId).
+		Where("status", consts.StorageObjectPending).
+		Data(data).
+		Update()
+	if err != nil {
+		g.Log().Errorf(ctx, "记录写入的存储对象失败: %v, 记录ID: %d, 对象: %s", err, pendingId, output.FileName)
+	}
+}
+
+// activate 在调用方的事务内将 pending 记录替换为按实际大小统计的对象记录, 返回更新后的账户
+func activate(ctx context.Context, tx gdb.TX, pendingId int, in *RecordInput) (*account, error) {
+	acc, err := loadAccount(ctx, tx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	result, err := dao.StorageObjects.Ctx(ctx).TX(tx).
+		Where("id", pendingId).
+		Where("status", consts.StorageObjectPending).
+		Delete()
+	if err != nil {
+		return nil, gerror.Wrap(err, "确认存储对象记录失败")
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, gerror.Newf("存储对象 %s 的预留记录已过期, 请重新上传", in.ObjectName)
+	}
+	return record(ctx, tx, acc, in)
+}
+
+// discard 在独立于调用方事务的事务内删除 pending 记录, 记录了对象名的同时从云存储中删除对象
+// pending 记录不计入订阅和团队上统计的用量, 删除后无需重新统计
+func discard(ctx context.Context, pendingId int) error {
+	ctx = gdb.WithoutTX(ctx, dao.StorageObjects.Group())
+	var object *entity.StorageObjects
+	err := dao.StorageObjects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		err := dao.StorageObjects.Ctx(ctx).TX(tx).
+			Where("id", pendingId).
+			Where("status", consts.StorageObjectPending).
+			LockUpdate().
+			Scan(&object)
+		if err != nil || object == nil {
+			return err
+		}
+		_, err = dao.StorageObjects.Ctx(ctx).TX(tx).Where("id", object.Id).Delete()
+		return gerror.Wrap(err, "删除预留的存储记录失败")
+	})
+	if err != nil || object == nil || strings.HasPrefix(object.ObjectName, pendingPrefix) {
+		return err
+	}
+	return upload.NewUpload().RemoveObject(ctx, object.ObjectName)
+}
+
+// Check 写入前校验存储配额, 免费版写入后超出配额时拒绝写入, 付费套餐不限制
+func (s *Storage) Check(ctx context.Context, userId int, size int64) error {
+	if userId == 0 {
+		return nil
+	}
+	return dao.StorageObjects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		acc, err := loadAccount(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+		return checkQuota(acc, size)
+	})
+}
+
+// checkQuota 校验账户写入 size 字节后是否超出免费版配额
+func checkQuota(acc *account, size int64) error {
+	if acc.plan != consts.PlanFree || acc.used*mb+size <= acc.quota*mb {
+		return nil
+	}
+	return gerror.NewCodef(CodeFreePlanStorageQuotaExceeded,
+		"免费版存储空间不足, 已使用 %d MB, 配额 %d MB, 请删除不需要的文件或升级套餐", acc.used, acc.quota)
+}
+
+// Record 记录对象的归属和大小并重新统计存储用量, 同名对象被覆盖时按新的大小统计, 不校验存储配额
+// 用量达到配额的 80% 和 100% 时邮件提醒
+func (s *Storage) Record(ctx context.Context, in *RecordInput) error {
+	if in.UserId == 0 || in.ObjectName == "" {
+		g.Log().Warningf(ctx, "存储对象 %s 未指定用户, 不计入存储用量", in.ObjectName)
+		return nil
+	}
+	var acc *account
+	err := dao.StorageObjects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var err error
+		if acc, err = loadAccount(ctx, tx, in.UserId); err != nil {
+			return err
+		}
+		acc, err = record(ctx, tx, acc, in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	warn(ctx, acc)
+	return nil
+}
+
+// record 在已锁定账户的事务内记录对象并重新统计存储用量, 返回更新后的账户
+func record(ctx context.Context, tx gdb.TX, acc *account, in *RecordInput) (*account, error) {
+	var prev *entity.StorageObjects
+	err := dao.StorageObjects.Ctx(ctx).TX(tx).Where("object_name", in.ObjectName).LockUpdate().Scan(&prev)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		insert into storage_objects (user_id, team_id, object_name, size_bytes, source, status, created_at, updated_at)
+		values (?, ?, ?, ?, ?, ?, now(), now())
+		on conflict (object_name) do update
+		set user_id = excluded.user_id, team_id = excluded.team_id, size_bytes = excluded.size_bytes,
+		    source = excluded.source, status = excluded.status, deleted_at = null, purged_at = null, updated_at = now()`,
+		in.UserId, acc.teamId, in.ObjectName, in.Size, in.Source, consts.StorageObjectActive)
+	if err != nil {
+		return nil, gerror.Wrap(err, "记录存储对象失败")
+	}
+	// 同名对象被覆盖时扣除原对象的用量, 被其他账户覆盖时原账户的用量同时重新统计
+	if prev != nil && prev.DeletedAt == nil {
+		if err = recordUsage(ctx, tx, prev, -1); err != nil {
+			return nil, err
+		}
+		if prev.UserId != in.UserId || int64(prev.TeamId) != acc.teamId {
+			if err = refresh(ctx, tx, prev.UserId, int64(prev.TeamId)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	err = recordUsage(ctx, tx, &entity.StorageObjects{
+		UserId:    in.UserId,
+		TeamId:    int(acc.teamId),
+		SizeBytes: in.Size,
+		Source:    in.Source,
+	}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err = refresh(ctx, tx, in.UserId, acc.teamId); err != nil {
+		return nil, err
+	}
+	return loadAccount(ctx, tx, in.UserId)
+}
+
+// Release 在事务内删除对象的记录并重新统计存储用量, 云存储中的对象由清理任务在事务提交后删除
+func Release(ctx context.Context, tx gdb.TX, objectName string) error {
+	if objectName == "" {
+		return nil
+	}
+	var object *entity.StorageObjects
+	err := dao.StorageObjects.Ctx(ctx).TX(tx).
+		Where("object_name", objectName).
+		WhereNull("deleted_at").
+		LockUpdate().
+		Scan(&object)
+	if err != nil || object == nil {
+		return err
+	}
+	_, err = dao.StorageObjects.Ctx(ctx).TX(tx).Where("id", object.Id).Data(g.Map{
+		"deleted_at": gtime.Now(),
+		"updated_at": gtime.Now(),
+	}).Update()
+	if err != nil {
+		return gerror.Wrap(err, "删除存储对象记录失败")
+	}
+	if err = recordUsage(ctx, tx, object, -1); err != nil {
+		return err
+	}
+	return refresh(ctx, tx, object.UserId, int64(object.TeamId))
+}
+
+// Remove 删除云存储中的对象并重新统计存储用量, 未记录的对象同样从云存储中删除
+func (s *Storage) Remove(ctx context.Context, objectName string) error {
+	if objectName == "" {
+		return nil
+	}
+	err := dao.StorageObjects.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		return Release(ctx, tx, objectName)
+	})
+	if err != nil {
+		return err
+	}
+	return purge(ctx, objectName)
+}
+
+// Purge 删除已释放但仍保留在云存储中的对象和超时未确认的写入, 返回删除的数量
+func (s *Storage) Purge(ctx context.Context) (int, error) {
+	batchSize := g.Cfg().MustGet(ctx, "storage.purgeBatchSize", 200).Int()
+	discarded, err := purgePending(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	names, err := dao.StorageObjects.Ctx(ctx).
+		Fields("object_name").
+		WhereNotNull("deleted_at").
+		WhereNull("purged_at").
+		OrderAsc("id").
+		Limit(batchSize).
+		Array()
+	if err != nil {
+		return discarded, err
+	}
+
+	purged := 0
+	for _, name := range names {
+		if err = purge(ctx, name.String()); err != nil {
+			g.Log().Errorf(ctx, "删除云存储对象失败: %v, 对象: %s", err, name.String())
+			continue
+		}
+		purged++
+	}
+	if purged > 0 {
+		g.Log().Infof(ctx, "清理已删除的存储对象 %d 个", purged)
+	}
+	return discarded + purged, nil
+}
+
+// purgePending 删除超过 storage.pendingTimeout 仍未确认的 pending 记录和已写入的对象
+// 调用方事务回滚、写入中断或确认失败后删除失败时会留下此类记录
+func purgePending(ctx context.Context, batchSize int) (int, error) {
+	timeout := g.Cfg().MustGet(ctx, "storage.pendingTimeout", 3600).Int()
+	ids, err := dao.StorageObjects.Ctx(ctx).
+		Fields("id").
+		Where("status", consts.StorageObjectPending).
+		WhereLT("updated_at", gtime.Now().Add(-time.Duration(timeout)*time.Second)).
+		OrderAsc("id").
+		Limit(batchSize).
+		Array()
+	if err != nil {
+		return 0, err
+	}
+
+	discarded := 0
+	for _, id := range ids {
+		if err = discard(ctx, id.Int()); err != nil {
+			g.Log().Errorf(ctx, "删除未确认的存储对象失败: %v, 记录ID: %d", err, id.Int())
+			continue
+		}
+		discarded++
+	}
+	if discarded > 0 {
+		g.Log().Infof(ctx, "清理未确认的存储对象 %d 个", discarded)
+	}
+	return discarded, nil
+}
+
+// purge 从云存储中删除已释放的对象, 已重新写入的同名对象和已删除的对象跳过, 未记录的对象直接删除
+func purge(ctx context.Context, objectName string) error {
+	var object *entity.StorageObjects
+	if err := dao.StorageObjects.Ctx(ctx).Where("object_name", objectName).Scan(&object); err != nil {
+		return err
+	}
+	if object != nil {
+		result, err := dao.StorageObjects.Ctx(ctx).
+			Where("id", object.Id).
+			WhereNotNull("deleted_at").
+			WhereNull("purged_at").
+			Data(g.Map{"purged_at": gtime.Now()}).
+			Update()
+		if err != nil {
+			return err
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return nil
+		}
+	}
+	return upload.NewUpload().RemoveObject(ctx, objectName)
+}
+
+// loadAccount 读取并锁定用户存储用量的归属账户, 同一账户的写入和删除串行统计
+func loadAccount(ctx context.Context, tx gdb.TX, userId int) (*account, error) {
+	return readAccount(ctx, tx, userId, true)
+}
+
+// readAccount 读取用户存储用量的归属账户, lock 为 true 时锁定订阅和团队
+func readAccount(ctx context.Context, tx gdb.TX, userId int, lock bool) (*account, error) {
+	var sub *entity.UserSubscriptions
+	m := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", userId)
+	if lock {
+		m = m.LockUpdate()
+	}
+	err := m.Scan(&sub)
+	if err != nil {
+		return nil, err
+	}
+	acc := &account{ownerId: int64(userId), plan: consts.PlanFree}
+	if sub == nil {
+		acc.quota = int64(billing.PlanQuota(ctx, acc.plan).Storage)
+		return acc, nil
+	}
+
+	if sub.TeamId > 0 {
+		var team *entity.Teams
+		m = dao.Teams.Ctx(ctx).TX(tx).Where("id", sub.TeamId)
+		if lock {
+			m = m.LockUpdate()
+		}
+		err = m.Scan(&team)
+		if err != nil {
+			return nil, err
+		}
+		if team != nil && team.Status == "active" {
+			acc.ownerId, acc.teamId, acc.plan = team.OwnerId, team.Id, consts.PlanTeam
+			acc.quota, acc.used = int64(team.TotalStorageQuota), int64(team.StorageUsed)
+			return acc, nil
+		}
+	}
+	if sub.SubscriptionStatus == "active" && sub.UserPlan != "" {
+		acc.plan = sub.UserPlan
+	}
+	acc.quota = int64(billing.PlanQuota(ctx, acc.plan).Storage)
+	acc.used = int64(sub.StorageUsed)
+	return acc, nil
+}
+
+// refresh 按已写入且未删除的对象重新统计存储用量, 团队同时统计成员的个人用量, 写入中预留的用量不计入
+func refresh(ctx context.Context, tx gdb.TX, userId int, teamId int64) error {
+	if teamId == 0 {
+		used, err := usedMB(ctx, tx, g.Map{"user_id": userId, "team_id": 0, "status": consts.StorageObjectActive})
+		if err != nil {
+			return err
+		}
+		_, err = dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", userId).Data(g.Map{
+			"storage_used": used,
+			"updated_at":   gtime.Now(),
+		}).Update()
+		return err
+	}
+
+	used, err := usedMB(ctx, tx, g.Map{"team_id": teamId, "status": consts.StorageObjectActive})
+	if err != nil {
+		return err
+	}
+	_, err = dao.Teams.Ctx(ctx).TX(tx).Where("id", teamId).Data(g.Map{
+		"storage_used": used,
+		"updated_at":   gtime.Now(),
+	}).Update()
+	if err != nil {
+		return err
+	}
+	personal, err := usedMB(ctx, tx, g.Map{"team_id": teamId, "user_id": userId, "status": consts.StorageObjectActive})
+	if err != nil {
+		return err
+	}
+	_, err = dao.TeamMembers.Ctx(ctx).TX(tx).
+		Where("team_id", teamId).
+		Where("user_id", userId).
+		Data(g.Map{"personal_storage_used": personal}).
+		Update()
+	return err
+}
+
+// recordUsage 记录写入(sign 为 1)或删除(sign 为 -1)对象的存储用量事件, 用量按实际大小折算为 MB
+func recordUsage(ctx context.Context, tx gdb.TX, object *entity.StorageObjects, sign float64) error {
+	return usage.Record(ctx, tx, &usage.Event{
+		UserId:       int64(object.UserId),
+		TeamId:       int64(object.TeamId),
+		ResourceType: billing.ResourceStorage,
+		Amount:       sign * float64(object.SizeBytes) / mb,
+		Source:       object.Source,
+	})
+}
+
+// usedMB 统计符合条件的未删除对象的总大小, 不足 1MB 的部分按 1MB 计
+func usedMB(ctx context.Context, tx gdb.TX, where g.Map) (int64, error) {
+	bytes, err := dao.StorageObjects.Ctx(ctx).TX(tx).Where(where).WhereNull("deleted_at").Sum("size_bytes")
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Ceil(bytes / mb)), nil
+}
+
+// storageWarnings 存储提醒的用量比例和对应的提醒标记, 从高到低排列
+var storageWarnings = []struct {
+	percent int
+	column  string
+}{
+	{100, "storage_warning_100_sent"},
+	{80, "storage_warning_80_sent"},
+}
+
+// warn 用量达到配额的 80% 和 100% 时各提醒一次, 同时达到时只发送 100% 的提醒; 用量回落后重置提醒标记
+func warn(ctx context.Context, acc *account) {
+	if acc.quota <= 0 {
+		return
+	}
+	percent := float64(acc.used) * 100 / float64(acc.quota)
+	notified := false
+	for _, level := range storageWarnings {
+		reached := percent >= float64(level.percent)
+		// 按原标记条件更新, 并发写入时只有一次更新成功
+		result, err := dao.UserSubscriptions.Ctx(ctx).
+			Where("user_id", acc.ownerId).
+			Where(level.column, !reached).
+			Data(g.Map{level.column: reached}).
+			Update()
+		if err != nil {
+			g.Log().Errorf(ctx, "更新存储提醒标记失败: %v, 用户ID: %d", err, acc.ownerId)
+			continue
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 && reached && !notified {
+			notified = true
+			go notifyWarning(gctx.NeverDone(ctx), acc, level.percent)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package traffic
+
+import (
+	"encoding/json"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// Meter 计量路由组内所有接口的响应流量, 需在登录校验之后注册
+// 流量计入请求用户的账户, 未登录的请求没有可计入的账户, 不计量; 不需要登录的接口均不返回项目数据
+func Meter(trafficType string) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		meter(r, trafficType)
+	}
+}
+
+// MeterRoutes 只计量指定接口的响应流量, routes 为请求方法和注册的路由到流量类型的映射, 如 GET /v1/projects/{id}
+func MeterRoutes(routes map[string]string) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		trafficType, ok := routes[r.Router.Method+" "+r.Router.Uri]
+		if !ok {
+			r.Middleware.Next()
+			return
+		}
+		meter(r, trafficType)
+	}
+}
+
+// meter 请求前校验流量配额, 请求完成后按响应大小记录流量, 失败的请求不计量
+func meter(r *ghttp.Request, trafficType string) {
+	var (
+		ctx    = r.Context()
+		t      = New()
+		userId = r.GetCtxVar("userID").Int64()
+	)
+	if userId == 0 {
+		r.Middleware.Next()
+		return
+	}
+	if err := t.Check(ctx, userId); err != nil {
+		r.SetError(err)
+		return
+	}
+
+	r.Middleware.Next()
+	if r.GetError() != nil {
+		return
+	}
+	// 项目相关接口的参数为 projectId, 项目详情的路由参数为 id
+	projectId := r.Get("projectId").Int()
+	if projectId == 0 {
+		projectId = r.GetRouter("id").Int()
+	}
+	t.record(ctx, &RecordInput{
+		UserId:      userId,
+		TrafficType: trafficType,
+		Endpoint:    r.Router.Uri,
+		ProjectId:   projectId,
+		Size:        responseSize(r),
+		Ip:          r.GetClientIp(),
+	})
+}
+
+// responseSize 响应的字节数
+// 接口返回的数据由外层的 MiddlewareHandlerResponse 在本中间件之后写入, 此时按其输出格式估算
+func responseSize(r *ghttp.Request) int64 {
+	if size := int64(r.Response.BufferLength()) + r.Response.BytesWritten(); size > 0 {
+		return size
+	}
+	res := r.GetHandlerResponse()
+	if res == nil {
+		return 0
+	}
+	content, err := json.Marshal(ghttp.DefaultHandlerResponse{
+		Code:    gcode.CodeOK.Code(),
+		Message: gcode.CodeOK.Message(),
+		Data:    res,
+	})
+	if err != nil {
+		return 0
+	}
+	return int64(len(content))
+}
@@ -0,0 +1,39 @@
+package traffic
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/email"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// notifyWarning 流量用量达到提醒比例时给账户所有者发送邮件, 发送失败只记录日志
+func notifyWarning(ctx context.Context, acc *account, percent int, hard bool) {
+	var user *entity.Users
+	if err := dao.Users.Ctx(ctx).Where("id", acc.ownerId).Scan(&user); err != nil {
+		g.Log().Errorf(ctx, "获取用户信息失败: %v", err)
+		return
+	}
+	if user == nil || user.Email == "" {
+		return
+	}
+
+	owner := "您的"
+	if acc.teamId > 0 {
+		owner = "您团队的"
+	}
+	subject := fmt.Sprintf("%s本月流量已使用 %d%%", owner, percent)
+	advice := "超出配额的部分将在月度账单中按超额费用计费。"
+	if hard {
+		advice = "流量用完后将无法继续查询图谱、读取项目数据、下载和导出文件, 请升级套餐或等待下月配额重置。"
+	}
+	body := fmt.Sprintf("%s本月流量已使用 %.2f GB, 配额 %.2f GB, 已达到 %d%%。<br/>%s",
+		owner, acc.used, acc.quota, percent, advice)
+	if err := email.SendHTML(ctx, user.Email, subject, body); err != nil {
+		g.Log().Errorf(ctx, "发送流量提醒邮件失败: %v, 用户ID: %d", err, acc.ownerId)
+	}
+}
@@ -0,0 +1,48 @@
+package traffic
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+)
+
+// Rollup 按明细重新汇总每小时的流量, 从最近一次汇总的前一小时开始, 覆盖跨小时写入的明细
+// 汇总结果按明细重新计算, 重复执行结果不变
+func (t *Traffic) Rollup(ctx context.Context) error {
+	last, err := dao.TrafficHourly.Ctx(ctx).OrderDesc("stat_hour").Value("stat_hour")
+	if err != nil {
+		return err
+	}
+	var start *gtime.Time
+	if !last.IsEmpty() {
+		start = last.GTime().Add(-time.Hour)
+	} else {
+		first, err := dao.TrafficLogs.Ctx(ctx).OrderAsc("created_at").Value("created_at")
+		if err != nil || first.IsEmpty() {
+			return err
+		}
+		start = first.GTime().StartOfHour()
+	}
+
+	result, err := dao.TrafficHourly.DB().Exec(ctx,
+		`INSERT INTO `+dao.TrafficHourly.Table()+` (stat_hour, user_id, team_id, traffic_type, endpoint, requests, data_size, updated_at)
+		SELECT date_trunc('hour', created_at), user_id, COALESCE(team_id, 0), traffic_type, COALESCE(endpoint, ''), COUNT(*), SUM(data_size), NOW()
+		FROM `+dao.TrafficLogs.Table()+`
+		WHERE created_at >= ?
+		GROUP BY 1, 2, 3, 4, 5
+		ON CONFLICT (stat_hour, user_id, team_id, traffic_type, endpoint) DO UPDATE
+		SET requests = EXCLUDED.requests, data_size = EXCLUDED.data_size, updated_at = EXCLUDED.updated_at`,
+		start,
+	)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		g.Log().Infof(ctx, "已汇总 %s 之后的每小时流量 %d 条", start.Format("Y-m-d H:i"), rows)
+	}
+	return nil
+}
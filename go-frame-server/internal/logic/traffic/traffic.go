@@ -0,0 +1,210 @@
+package traffic
+
+import (
+	"context"
+	"math"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/logic/usage"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Traffic 流量计量: 记录图谱查询、项目数据读取、文件下载和导出的响应流量, 计入个人或团队的月度流量用量
+// 硬限制的套餐流量用完后拒绝请求, 软限制的套餐超出的部分在月度出账时按超额计费
+type Traffic struct{}
+
+func New() *Traffic {
+	return &Traffic{}
+}
+
+const (
+	kb = 1 << 10
+	gb = 1 << 30
+)
+
+// CodeTrafficQuotaExceeded 流量配额已用完, 与存储空间不足的错误码 1004 连续编号
+var CodeTrafficQuotaExceeded = gcode.New(1005, "TrafficQuotaExceeded", "流量配额已用完")
+
+// account 流量用量的归属: 团队成员的流量计入团队, 流量提醒发送给团队所有者
+type account struct {
+	ownerId int64
+	teamId  int64
+	plan    string
+	quota   float64 // 流量配额(GB)
+	used    float64 // 流量用量(GB)
+}
+
+// RecordInput 记录流量的参数
+type RecordInput struct {
+	UserId      int64
+	TrafficType string
+	Endpoint    string
+	ProjectId   int
+	Size        int64
+	Ip          string
+}
+
+// Check 请求前校验流量配额, 硬限制的套餐用量达到配额后拒绝请求, 软限制的套餐不限制
+func (t *Traffic) Check(ctx context.Context, userId int64) error {
+	acc, err := loadAccount(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if acc.quota <= 0 || acc.used < acc.quota || !hardLimit(ctx, acc.plan) {
+		return nil
+	}
+	return gerror.NewCodef(CodeTrafficQuotaExceeded,
+		"本月流量已用完, 已使用 %.2f GB, 配额 %.2f GB, 请升级套餐或等待下月配额重置", acc.used, acc.quota)
+}
+
+// Record 写入流量明细并累加月度流量用量, 用量达到配额的 80% 和 100% 时邮件提醒
+func (t *Traffic) Record(ctx context.Context, in *RecordInput) error {
+	if in.UserId == 0 || in.Size <= 0 {
+		return nil
+	}
+	acc, err := loadAccount(ctx, in.UserId)
+	if err != nil {
+		return err
+	}
+
+	used := float64(in.Size) / gb
+	err = dao.TrafficLogs.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		data := g.Map{
+			"user_id":      in.UserId,
+			"traffic_type": in.TrafficType,
+			"data_size":    in.Size,
+			"traffic_kb":   math.Round(float64(in.Size)*1000/kb) / 1000,
+			"endpoint":     in.Endpoint,
+			"ip_address":   in.Ip,
+			"created_at":   gtime.Now(),
+		}
+		if acc.teamId > 0 {
+			data["team_id"] = acc.teamId
+		}
+		if _, err := dao.TrafficLogs.Ctx(ctx).TX(tx).Data(data).Insert(); err != nil {
+			return gerror.Wrap(err, "记录流量明细失败")
+		}
+		err := usage.Record(ctx, tx, &usage.Event{
+			UserId:       in.UserId,
+			TeamId:       acc.teamId,
+			ResourceType: billing.ResourceTraffic,
+			Amount:       used,
+			ProjectId:    in.ProjectId,
+			Source:       in.TrafficType,
+		})
+		if err != nil {
+			return err
+		}
+
+		if acc.teamId == 0 {
+			_, err = dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", in.UserId).Increment("traffic_used", used)
+			return err
+		}
+		if _, err = dao.Teams.Ctx(ctx).TX(tx).Where("id", acc.teamId).Increment("traffic_used", used); err != nil {
+			return err
+		}
+		_, err = dao.TeamMembers.Ctx(ctx).TX(tx).
+			Where("team_id", acc.teamId).
+			Where("user_id", in.UserId).
+			Increment("personal_traffic_used", used)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	acc.used += used
+	warn(ctx, acc)
+	return nil
+}
+
+// record 记录已完成的请求的流量, 响应已生成, 记录失败只记录日志, 不影响本次请求
+func (t *Traffic) record(ctx context.Context, in *RecordInput) {
+	if err := t.Record(ctx, in); err != nil {
+		g.Log().Errorf(ctx, "记录流量失败: %v, 用户ID: %d, 接口: %s", err, in.UserId, in.Endpoint)
+	}
+}
+
+// loadAccount 读取用户流量用量的归属账户
+func loadAccount(ctx context.Context, userId int64) (*account, error) {
+	var sub *entity.UserSubscriptions
+	if err := dao.UserSubscriptions.Ctx(ctx).Where("user_id", userId).Scan(&sub); err != nil {
+		return nil, err
+	}
+	acc := &account{ownerId: userId, plan: consts.PlanFree}
+	if sub == nil {
+		acc.quota = billing.PlanQuota(ctx, acc.plan).Traffic
+		return acc, nil
+	}
+
+	if sub.TeamId > 0 {
+		var team *entity.Teams
+		if err := dao.Teams.Ctx(ctx).Where("id", sub.TeamId).Scan(&team); err != nil {
+			return nil, err
+		}
+		if team != nil && team.Status == "active" {
+			acc.ownerId, acc.teamId, acc.plan = team.OwnerId, team.Id, consts.PlanTeam
+			acc.quota, acc.used = float64(team.TotalTrafficQuota), team.TrafficUsed
+			return acc, nil
+		}
+	}
+	if sub.SubscriptionStatus == "active" && sub.UserPlan != "" {
+		acc.plan = sub.UserPlan
+	}
+	acc.quota = billing.PlanQuota(ctx, acc.plan).Traffic
+	acc.used = sub.TrafficUsed
+	return acc, nil
+}
+
+// hardLimit 套餐的流量是否为硬限制, 未配置时按软限制处理
+func hardLimit(ctx context.Context, plan string) bool {
+	enforcement := g.Cfg().MustGet(ctx, "plans."+plan+".traffic_enforcement", consts.TrafficEnforceSoft).String()
+	return enforcement == consts.TrafficEnforceHard
+}
+
+// trafficWarnings 流量提醒的用量比例和对应的提醒标记, 从高到低排列
+var trafficWarnings = []struct {
+	percent int
+	column  string
+}{
+	{100, "traffic_warning_100_sent"},
+	{80, "traffic_warning_80_sent"},
+}
+
+// warn 用量达到配额的 80% 和 100% 时各提醒一次, 同时达到时只发送 100% 的提醒
+// 流量用量只在月度出账时清零, 提醒标记由出账时一并重置
+func warn(ctx context.Context, acc *account) {
+	if acc.quota <= 0 {
+		return
+	}
+	percent := acc.used * 100 / acc.quota
+	notified := false
+	for _, level := range trafficWarnings {
+		if percent < float64(level.percent) {
+			continue
+		}
+		// 按原标记条件更新, 并发请求时只有一次更新成功
+		result, err := dao.UserSubscriptions.Ctx(ctx).
+			Where("user_id", acc.ownerId).
+			Where(level.column, false).
+			Data(g.Map{level.column: true}).
+			Update()
+		if err != nil {
+			g.Log().Errorf(ctx, "更新流量提醒标记失败: %v, 用户ID: %d", err, acc.ownerId)
+			continue
+		}
+		if rows, _ := result.RowsAffected(); rows > 0 && !notified {
+			notified = true
+			go notifyWarning(gctx.NeverDone(ctx), acc, level.percent, hardLimit(ctx, acc.plan))
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package traffic
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/consts"
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/usage"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// 流量分析的时间粒度
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+)
+
+// trafficTypes 分析结果中始终返回的流量类型, 其他类型有流量时追加在后面
+var trafficTypes = []string{
+	consts.TrafficGraphQuery,
+	consts.TrafficApiRead,
+	consts.TrafficFileTransfer,
+	consts.TrafficExport,
+}
+
+// UsageInput 流量分析的查询条件, 日期包含起止两天
+type UsageInput struct {
+	UserId      int64
+	MemberId    int64 // 团队所有者和管理员查看指定成员的流量, 为0时查看整个团队
+	TrafficType string
+	Granularity string
+	StartDate   *gtime.Time
+	EndDate     *gtime.Time
+}
+
+// UsagePoint 一个时间段内的流量
+type UsagePoint struct {
+	Time     string `json:"time" dc:"时间段, 按小时为 YYYY-MM-DD HH:00, 按天为 YYYY-MM-DD"`
+	Bytes    int64  `json:"bytes" dc:"流量（字节）"`
+	Requests int    `json:"requests" dc:"请求数"`
+}
+
+// UsageSeries 一种流量类型的时间序列
+type UsageSeries struct {
+	TrafficType string        `json:"trafficType" dc:"流量类型"`
+	Bytes       int64         `json:"bytes" dc:"总流量（字节）"`
+	Requests    int           `json:"requests" dc:"总请求数"`
+	Points      []*UsagePoint `json:"points" dc:"按时间升序, 没有流量的时间段补0"`
+}
+
+// EndpointUsage 一个接口的流量
+type EndpointUsage struct {
+	Endpoint    string `json:"endpoint" dc:"接口路由"`
+	TrafficType string `json:"trafficType" dc:"流量类型"`
+	Bytes       int64  `json:"bytes" dc:"流量（字节）"`
+	Requests    int    `json:"requests" dc:"请求数"`
+}
+
+// Usage 流量分析结果
+type Usage struct {
+	Scope       string           `json:"scope" dc:"统计范围, personal-个人, team-整个团队, member-团队成员"`
+	Granularity string           `json:"granularity" dc:"时间粒度, hour-按小时, day-按天"`
+	StartDate   string           `json:"startDate" dc:"开始日期"`
+	EndDate     string           `json:"endDate" dc:"结束日期"`
+	Series      []*UsageSeries   `json:"series" dc:"各流量类型的时间序列"`
+	Endpoints   []*EndpointUsage `json:"endpoints" dc:"各接口的流量, 按流量降序"`
+}
+
+// Usage 按小时汇总的流量生成时间序列, 汇总任务每10分钟执行一次, 最近的流量可能尚未计入
+// 按小时最多查询7天, 按天最多查询92天
+func (t *Traffic) Usage(ctx context.Context, in *UsageInput) (*Usage, error) {
+	var (
+		layout  = "Y-m-d H:00"
+		maxDays = 7
+		span    = 0 // 未指定开始日期时查询的天数减1
+		end     = gtime.Now().StartOfDay()
+	)
+	if in.Granularity == GranularityDay {
+		layout, maxDays, span = "Y-m-d", 92, 6
+	}
+	if in.EndDate != nil {
+		end = in.EndDate.StartOfDay()
+	}
+	start := end.AddDate(0, 0, -span)
+	if in.StartDate != nil {
+		start = in.StartDate.StartOfDay()
+	}
+	if start.After(end) {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "开始日期不能晚于结束日期")
+	}
+	if end.Sub(start).Hours()/24 >= float64(maxDays) {
+		return nil, gerror.NewCodef(gcode.CodeInvalidParameter, "按%s查询最多%d天", granularityName(in.Granularity), maxDays)
+	}
+
+	scope, err := usage.ResolveScope(ctx, in.UserId, in.MemberId)
+	if err != nil {
+		return nil, err
+	}
+	until := end.AddDate(0, 0, 1)
+	model := dao.TrafficHourly.Ctx(ctx).
+		Where(scope.Where("")).
+		WhereGTE("stat_hour", start).
+		WhereLT("stat_hour", until)
+	if in.TrafficType != "" {
+		model = model.Where("traffic_type", in.TrafficType)
+	}
+	var rows []*entity.TrafficHourly
+	if err = model.OrderAsc("stat_hour").Scan(&rows); err != nil {
+		return nil, err
+	}
+
+	// 时间段, 没有流量的时间段补0
+	var times []string
+	for cur := start; cur.Before(until); {
+		times = append(times, cur.Format(layout))
+		if in.Granularity == GranularityDay {
+			cur = cur.AddDate(0, 0, 1)
+		} else {
+			cur = cur.Add(gtime.H)
+		}
+	}
+	types := slices.Clone(trafficTypes)
+	if in.TrafficType != "" {
+		types = []string{in.TrafficType}
+	}
+
+	var (
+		series    = make(map[string]map[string]*UsagePoint)
+		endpoints = make(map[string]*EndpointUsage)
+	)
+	for _, row := range rows {
+		if !slices.Contains(types, row.TrafficType) {
+			types = append(types, row.TrafficType)
+		}
+		if series[row.TrafficType] == nil {
+			series[row.TrafficType] = make(map[string]*UsagePoint)
+		}
+		key := row.StatHour.Format(layout)
+		point := series[row.TrafficType][key]
+		if point == nil {
+			point = &UsagePoint{Time: key}
+			series[row.TrafficType][key] = point
+		}
+		point.Bytes += row.DataSize
+		point.Requests += row.Requests
+
+		endpointKey := row.TrafficType + " " + row.Endpoint
+		endpoint := endpoints[endpointKey]
+		if endpoint == nil {
+			endpoint = &EndpointUsage{Endpoint: row.Endpoint, TrafficType: row.TrafficType}
+			endpoints[endpointKey] = endpoint
+		}
+		endpoint.Bytes += row.DataSize
+		endpoint.Requests += row.Requests
+	}
+
+	result := &Usage{
+		Scope:       scope.Name,
+		Granularity: in.Granularity,
+		StartDate:   start.Format("Y-m-d"),
+		EndDate:     end.Format("Y-m-d"),
+		Series:      make([]*UsageSeries, 0, len(types)),
+		Endpoints:   make([]*EndpointUsage, 0, len(endpoints)),
+	}
+	for _, trafficType := range types {
+		item := &UsageSeries{TrafficType: trafficType, Points: make([]*UsagePoint, 0, len(times))}
+		for _, key := range times {
+			point := series[trafficType][key]
+			if point == nil {
+				point = &UsagePoint{Time: key}
+			}
+			item.Bytes += point.Bytes
+			item.Requests += point.Requests
+			item.Points = append(item.Points, point)
+		}
+		result.Series = append(result.Series, item)
+	}
+	for _, endpoint := range endpoints {
+		result.Endpoints = append(result.Endpoints, endpoint)
+	}
+	sort.Slice(result.Endpoints, func(i, j int) bool {
+		if result.Endpoints[i].Bytes != result.Endpoints[j].Bytes {
+			return result.Endpoints[i].Bytes > result.Endpoints[j].Bytes
+		}
+		return result.Endpoints[i].Endpoint < result.Endpoints[j].Endpoint
+	})
+	return result, nil
+}
+
+func granularityName(granularity string) string {
+	if granularity == GranularityDay {
+		return "天"
+	}
+	return "小时"
+}
@@ -0,0 +1,125 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// SaveObjectInput 保存二进制文件的参数, SaveData 只支持文本和 JSON
+type SaveObjectInput struct {
+	FileName    string // 对象名, 需包含扩展名
+	Content     []byte
+	ContentType string
+	UserId      int
+}
+
+// SaveObject 将二进制内容保存到 upload.cloudStorage 配置的存储桶, 返回的 FileName 可用于 GenerateFileUrl
+func (u *Upload) SaveObject(ctx context.Context, in *SaveObjectInput) (*SaveDataOutput, error) {
+	if in.FileName == "" || len(in.Content) == 0 {
+		return nil, gerror.New("文件名和内容不能为空")
+	}
+	client, bucket, err := storageClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := in.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	info, err := client.PutObject(ctx, bucket, in.FileName,
+		bytes.NewReader(in.Content), int64(len(in.Content)),
+		minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return nil, gerror.Wrapf(err, "保存文件失败: %s", in.FileName)
+	}
+
+	g.Log().Infof(ctx, "用户 %d 保存文件 %s, 大小 %d", in.UserId, in.FileName, info.Size)
+	return &SaveDataOutput{
+		FileName: in.FileName,
+		FilePath: info.Key,
+		FileSize: info.Size,
+		FileType: path.Ext(in.FileName),
+		FileUrl:  u.GenerateFileUrl(ctx, in.FileName),
+	}, nil
+}
+
+// ReadObject 读取云存储中的对象, 返回内容和内容类型
+func (u *Upload) ReadObject(ctx context.Context, objectName string) ([]byte, string, error) {
+	client, bucket, err := storageClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	object, err := client.GetObject(ctx, bucket, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, "", gerror.Wrapf(err, "读取文件失败: %s", objectName)
+	}
+	defer object.Close()
+	info, err := object.Stat()
+	if err != nil {
+		return nil, "", gerror.Wrapf(err, "读取文件失败: %s", objectName)
+	}
+	content, err := io.ReadAll(object)
+	if err != nil {
+		return nil, "", gerror.Wrapf(err, "读取文件失败: %s", objectName)
+	}
+	return content, info.ContentType, nil
+}
+
+// RemoveObject 删除云存储中的对象, 对象不存在时不报错
+func (u *Upload) RemoveObject(ctx context.Context, objectName string) error {
+	client, bucket, err := storageClient(ctx)
+	if err != nil {
+		return err
+	}
+	if err = client.RemoveObject(ctx, bucket, objectName, minio.RemoveObjectOptions{}); err != nil {
+		return gerror.Wrapf(err, "删除文件失败: %s", objectName)
+	}
+	g.Log().Infof(ctx, "删除文件 %s", objectName)
+	return nil
+}
+
+// storageClients 按配置缓存的存储客户端, 客户端可并发使用, 配置变化时重新创建
+var storageClients struct {
+	sync.Mutex
+	key    string
+	client *minio.Client
+}
+
+// storageClient 返回 upload.cloudStorage 配置的存储客户端和存储桶
+func storageClient(ctx context.Context) (*minio.Client, string, error) {
+	cfg := g.Cfg().MustGet(ctx, "upload.cloudStorage").MapStrVar()
+	if !cfg["enabled"].Bool() {
+		return nil, "", gerror.New("未启用云存储")
+	}
+	bucket := cfg["bucket"].String()
+	key := strings.Join([]string{
+		cfg["endpoint"].String(), cfg["accessKey"].String(), cfg["secretKey"].String(),
+		cfg["region"].String(), cfg["useSSL"].String(),
+	}, "\n")
+
+	storageClients.Lock()
+	defer storageClients.Unlock()
+	if storageClients.client != nil && storageClients.key == key {
+		return storageClients.client, bucket, nil
+	}
+	client, err := minio.New(cfg["endpoint"].String(), &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg["accessKey"].String(), cfg["secretKey"].String(), ""),
+		Secure: cfg["useSSL"].Bool(),
+		Region: cfg["region"].String(),
+	})
+	if err != nil {
+		return nil, "", gerror.Wrap(err, "创建存储客户端失败")
+	}
+	storageClients.key, storageClients.client = key, client
+	return client, bucket, nil
+}
@@ -0,0 +1,324 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/billing"
+)
+
+// 用量分析的维度
+const (
+	DimensionProject = "project"
+	DimensionModel   = "model"
+	DimensionMember  = "member"
+)
+
+// resourceTypes 用量趋势中始终返回的资源类型
+var resourceTypes = []string{billing.ResourceWords, billing.ResourceStorage, billing.ResourceTraffic, billing.ResourceCu}
+
+// dimensionColumns 用量分析的维度对应的汇总字段
+var dimensionColumns = map[string]string{
+	DimensionProject: "project_id",
+	DimensionModel:   "model",
+	DimensionMember:  "user_id",
+}
+
+// QueryInput 用量查询条件, 日期包含起止两天, 未指定时查询最近30天
+type QueryInput struct {
+	UserId       int64
+	MemberId     int64 // 团队所有者和管理员查看指定成员的用量, 为0时查看整个团队
+	ResourceType string
+	StartDate    *gtime.Time
+	EndDate      *gtime.Time
+}
+
+// HistoryPoint 一天的用量
+type HistoryPoint struct {
+	Date           string  `json:"date" dc:"日期"`
+	Amount         float64 `json:"amount" dc:"用量"`
+	WeightedAmount float64 `json:"weightedAmount" dc:"按成本系数折算后的用量"`
+	Events         int     `json:"events" dc:"用量事件数"`
+}
+
+// HistorySeries 一种资源的每日用量
+type HistorySeries struct {
+	ResourceType   string          `json:"resourceType" dc:"资源类型"`
+	Unit           string          `json:"unit" dc:"用量单位"`
+	Amount         float64         `json:"amount" dc:"总用量"`
+	WeightedAmount float64         `json:"weightedAmount" dc:"按成本系数折算后的总用量"`
+	Points         []*HistoryPoint `json:"points" dc:"按日期升序, 没有用量的日期补0"`
+}
+
+// History 用量趋势
+type History struct {
+	Scope     string           `json:"scope" dc:"统计范围, personal-个人, team-整个团队, member-团队成员"`
+	StartDate string           `json:"startDate" dc:"开始日期"`
+	EndDate   string           `json:"endDate" dc:"结束日期"`
+	Series    []*HistorySeries `json:"series" dc:"各资源的每日用量"`
+}
+
+// BreakdownItem 一个项目、模型或成员的用量
+type BreakdownItem struct {
+	Key            string  `json:"key" dc:"项目ID、模型名称或成员的用户ID"`
+	Name           string  `json:"name" dc:"项目名称、模型名称或成员用户名"`
+	Amount         float64 `json:"amount" dc:"用量"`
+	WeightedAmount float64 `json:"weightedAmount" dc:"按成本系数折算后的用量"`
+	Events         int     `json:"events" dc:"用量事件数"`
+}
+
+// Breakdown 按维度分析的用量
+type Breakdown struct {
+	Scope        string           `json:"scope" dc:"统计范围, personal-个人, team-整个团队, member-团队成员"`
+	Dimension    string           `json:"dimension" dc:"维度, project-项目, model-模型, member-团队成员"`
+	ResourceType string           `json:"resourceType" dc:"资源类型"`
+	Unit         string           `json:"unit" dc:"用量单位"`
+	StartDate    string           `json:"startDate" dc:"开始日期"`
+	EndDate      string           `json:"endDate" dc:"结束日期"`
+	Items        []*BreakdownItem `json:"items" dc:"按折算后的用量降序"`
+}
+
+// History 按每日汇总的用量生成各资源的用量趋势, 最多查询366天
+// 汇总任务每10分钟执行一次, 最近的用量可能尚未计入; 存储用量为每日新增的净用量
+func (u *Usage) History(ctx context.Context, in *QueryInput) (*History, error) {
+	start, end, err := dateRange(in, 366)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := ResolveScope(ctx, in.UserId, in.MemberId)
+	if err != nil {
+		return nil, err
+	}
+
+	model := dao.UsageDaily.Ctx(ctx).
+		Fields("stat_date, resource_type, SUM(amount) AS amount, SUM(weighted_amount) AS weighted_amount, SUM(events) AS events").
+		Where(scope.Where("")).
+		WhereGTE("stat_date", start.Format("Y-m-d")).
+		WhereLTE("stat_date", end.Format("Y-m-d"))
+	if in.ResourceType != "" {
+		model = model.Where("resource_type", in.ResourceType)
+	}
+	rows, err := model.Group("stat_date, resource_type").All()
+	if err != nil {
+		return nil, err
+	}
+
+	types := slices.Clone(resourceTypes)
+	if in.ResourceType != "" {
+		types = []string{in.ResourceType}
+	}
+	points := make(map[string]*HistoryPoint)
+	for _, row := range rows {
+		date := row["stat_date"].GTime().Format("Y-m-d")
+		points[row["resource_type"].String()+" "+date] = &HistoryPoint{
+			Date:           date,
+			Amount:         row["amount"].Float64(),
+			WeightedAmount: row["weighted_amount"].Float64(),
+			Events:         row["events"].Int(),
+		}
+	}
+
+	history := &History{
+		Scope:     scope.Name,
+		StartDate: start.Format("Y-m-d"),
+		EndDate:   end.Format("Y-m-d"),
+		Series:    make([]*HistorySeries, 0, len(types)),
+	}
+	for _, resourceType := range types {
+		series := &HistorySeries{ResourceType: resourceType, Unit: units[resourceType]}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			date := day.Format("Y-m-d")
+			point := points[resourceType+" "+date]
+			if point == nil {
+				point = &HistoryPoint{Date: date}
+			}
+			series.Amount += point.Amount
+			series.WeightedAmount += point.WeightedAmount
+			series.Points = append(series.Points, point)
+		}
+		history.Series = append(history.Series, series)
+	}
+	return history, nil
+}
+
+// Breakdown 按项目、模型或团队成员汇总一种资源的用量, 最多查询366天
+func (u *Usage) Breakdown(ctx context.Context, in *QueryInput, dimension string) (*Breakdown, error) {
+	column, ok := dimensionColumns[dimension]
+	if !ok {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "分析维度错误")
+	}
+	if _, ok = units[in.ResourceType]; !ok {
+		return nil, gerror.NewCode(gcode.CodeInvalidParameter, "请选择资源类型")
+	}
+	start, end, err := dateRange(in, 366)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := ResolveScope(ctx, in.UserId, in.MemberId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dao.UsageDaily.Ctx(ctx).
+		Fields(column+" AS key, SUM(amount) AS amount, SUM(weighted_amount) AS weighted_amount, SUM(events) AS events").
+		Where(scope.Where("")).
+		Where("resource_type", in.ResourceType).
+		WhereGTE("stat_date", start.Format("Y-m-d")).
+		WhereLTE("stat_date", end.Format("Y-m-d")).
+		Group(column).
+		Order("weighted_amount DESC").
+		All()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row["key"].String())
+	}
+	names, err := dimensionNames(ctx, dimension, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &Breakdown{
+		Scope:        scope.Name,
+		Dimension:    dimension,
+		ResourceType: in.ResourceType,
+		Unit:         units[in.ResourceType],
+		StartDate:    start.Format("Y-m-d"),
+		EndDate:      end.Format("Y-m-d"),
+		Items:        make([]*BreakdownItem, 0, len(rows)),
+	}
+	for _, row := range rows {
+		key := row["key"].String()
+		breakdown.Items = append(breakdown.Items, &BreakdownItem{
+			Key:            key,
+			Name:           names[key],
+			Amount:         row["amount"].Float64(),
+			WeightedAmount: row["weighted_amount"].Float64(),
+			Events:         row["events"].Int(),
+		})
+	}
+	return breakdown, nil
+}
+
+// Export 导出用量事件明细 CSV, 返回文件名和内容, 最多导出92天
+func (u *Usage) Export(ctx context.Context, in *QueryInput) (string, []byte, error) {
+	start, end, err := dateRange(in, 92)
+	if err != nil {
+		return "", nil, err
+	}
+	scope, err := ResolveScope(ctx, in.UserId, in.MemberId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	model := dao.UsageEvents.Ctx(ctx).As("e").
+		LeftJoin(dao.Users.Table()+" u", "u.id = e.user_id").
+		LeftJoin(dao.Projects.Table()+" p", "p.id = e.project_id").
+		Fields("e.created_at, e.user_id, u.username, e.resource_type, e.amount, e.cost_multiplier, e.task_id, e.project_id, p.project_name, e.model, e.source").
+		Where(scope.Where("e.")).
+		WhereGTE("e.created_at", start).
+		WhereLT("e.created_at", end.AddDate(0, 0, 1))
+	if in.ResourceType != "" {
+		model = model.Where("e.resource_type", in.ResourceType)
+	}
+	rows, err := model.OrderAsc("e.id").All()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var buf bytes.Buffer
+	// 写入 BOM, 便于 Excel 正确识别 UTF-8 编码
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"时间", "成员ID", "用户名", "资源类型", "用量", "单位", "成本系数", "折算后用量", "任务ID", "项目ID", "项目名称", "模型", "来源"})
+	for _, row := range rows {
+		amount, multiplier := row["amount"].Float64(), row["cost_multiplier"].Float64()
+		_ = w.Write([]string{
+			row["created_at"].GTime().Format("Y-m-d H:i:s"),
+			row["user_id"].String(),
+			row["username"].String(),
+			row["resource_type"].String(),
+			formatAmount(amount),
+			units[row["resource_type"].String()],
+			formatAmount(multiplier),
+			formatAmount(amount * multiplier),
+			row["task_id"].String(),
+			row["project_id"].String(),
+			row["project_name"].String(),
+			row["model"].String(),
+			row["source"].String(),
+		})
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("usage_%s_%s.csv", start.Format("Ymd"), end.Format("Ymd")), buf.Bytes(), nil
+}
+
+// dateRange 查询的起止日期, 结束日期默认为今天, 开始日期默认为结束日期前29天
+func dateRange(in *QueryInput, maxDays int) (*gtime.Time, *gtime.Time, error) {
+	end := gtime.Now().StartOfDay()
+	if in.EndDate != nil {
+		end = in.EndDate.StartOfDay()
+	}
+	start := end.AddDate(0, 0, -29)
+	if in.StartDate != nil {
+		start = in.StartDate.StartOfDay()
+	}
+	if start.After(end) {
+		return nil, nil, gerror.NewCode(gcode.CodeInvalidParameter, "开始日期不能晚于结束日期")
+	}
+	if start.AddDate(0, 0, maxDays).Before(end.AddDate(0, 0, 1)) {
+		return nil, nil, gerror.NewCodef(gcode.CodeInvalidParameter, "最多查询%d天", maxDays)
+	}
+	return start, end, nil
+}
+
+// dimensionNames 项目名称和成员用户名, 没有关联项目和模型的用量显示为"其他"
+func dimensionNames(ctx context.Context, dimension string, keys []string) (map[string]string, error) {
+	names := make(map[string]string, len(keys))
+	for _, key := range keys {
+		names[key] = key
+		if key == "" || key == "0" {
+			names[key] = "其他"
+		}
+	}
+
+	var (
+		result gdb.Result
+		err    error
+	)
+	switch dimension {
+	case DimensionProject:
+		result, err = dao.Projects.Ctx(ctx).Fields("id, project_name AS name").WhereIn("id", keys).All()
+	case DimensionMember:
+		result, err = dao.Users.Ctx(ctx).Fields("id, username AS name").WhereIn("id", keys).All()
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range result {
+		names[row["id"].String()] = row["name"].String()
+	}
+	return names, nil
+}
+
+// formatAmount 用量保留6位小数, 与 usage_events.amount 的精度一致
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount*1e6)/1e6, 'f', -1, 64)
+}
@@ -0,0 +1,78 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gcode"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// 用量查询的统计范围
+const (
+	ScopePersonal = "personal"
+	ScopeTeam     = "team"
+	ScopeMember   = "member"
+)
+
+// Scope 用量查询的统计范围: 个人订阅查看自己的用量, 团队所有者和管理员可查看整个团队或指定成员, 其他成员只能查看自己
+type Scope struct {
+	Name   string
+	TeamId int64
+	UserId int64 // 查看整个团队时为0
+}
+
+// ResolveScope 按用户在团队中的角色确定统计范围, memberId 为要查看的团队成员, 为0时查看整个团队
+func ResolveScope(ctx context.Context, userId int64, memberId int64) (*Scope, error) {
+	var sub *entity.UserSubscriptions
+	if err := dao.UserSubscriptions.Ctx(ctx).Where("user_id", userId).Scan(&sub); err != nil {
+		return nil, err
+	}
+	var team *entity.Teams
+	if sub != nil && sub.TeamId > 0 {
+		if err := dao.Teams.Ctx(ctx).Where("id", sub.TeamId).Where("status", "active").Scan(&team); err != nil {
+			return nil, err
+		}
+	}
+	if team == nil {
+		if memberId > 0 && memberId != userId {
+			return nil, gerror.NewCode(gcode.CodeNotAuthorized, "仅团队所有者和管理员可查看成员的用量")
+		}
+		return &Scope{Name: ScopePersonal, UserId: userId}, nil
+	}
+
+	manager := team.OwnerId == userId
+	if !manager {
+		role, err := dao.TeamMembers.Ctx(ctx).
+			Where("team_id", team.Id).
+			Where("user_id", userId).
+			Where("status", "active").
+			Value("role")
+		if err != nil {
+			return nil, err
+		}
+		manager = role.String() == "admin"
+	}
+	switch {
+	case !manager && memberId > 0 && memberId != userId:
+		return nil, gerror.NewCode(gcode.CodeNotAuthorized, "仅团队所有者和管理员可查看成员的用量")
+	case !manager:
+		return &Scope{Name: ScopeMember, TeamId: team.Id, UserId: userId}, nil
+	case memberId > 0:
+		return &Scope{Name: ScopeMember, TeamId: team.Id, UserId: memberId}, nil
+	default:
+		return &Scope{Name: ScopeTeam, TeamId: team.Id}, nil
+	}
+}
+
+// Where 统计范围的查询条件, prefix 为联表查询时的表别名前缀, 如 "e."
+func (s *Scope) Where(prefix string) g.Map {
+	where := g.Map{prefix + "team_id": s.TeamId}
+	if s.UserId > 0 {
+		where[prefix+"user_id"] = s.UserId
+	}
+	return where
+}
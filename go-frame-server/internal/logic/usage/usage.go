@@ -0,0 +1,123 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/logic/billing"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Usage 用量历史: 字数、存储、算力和流量的每次用量追加写入用量事件, 由定时任务按天汇总
+// 用于查看用量趋势、按项目、模型和团队成员分析用量以及导出用量明细
+type Usage struct{}
+
+func New() *Usage {
+	return &Usage{}
+}
+
+// units 各资源用量的单位, 与用量计数器一致
+var units = map[string]string{
+	billing.ResourceWords:   "字",
+	billing.ResourceStorage: "MB",
+	billing.ResourceTraffic: "GB",
+	billing.ResourceCu:      "CU",
+}
+
+// Event 用量事件
+type Event struct {
+	UserId         int64
+	TeamId         int64 // 用量计入的团队, 个人用量为0
+	ResourceType   string
+	Amount         float64
+	CostMultiplier float64 // 为0时按1记录
+	TaskId         int
+	ProjectId      int
+	Model          string
+	Source         string
+}
+
+// Record 在事务内追加用量事件, 与用量计数器的更新一起提交
+func Record(ctx context.Context, tx gdb.TX, event *Event) error {
+	if event.UserId == 0 || event.Amount == 0 {
+		return nil
+	}
+	multiplier := event.CostMultiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	_, err := dao.UsageEvents.Ctx(ctx).TX(tx).Data(g.Map{
+		"user_id":         event.UserId,
+		"team_id":         event.TeamId,
+		"resource_type":   event.ResourceType,
+		"amount":          event.Amount,
+		"cost_multiplier": multiplier,
+		"task_id":         event.TaskId,
+		"project_id":      event.ProjectId,
+		"model":           event.Model,
+		"source":          event.Source,
+		"created_at":      gtime.Now(),
+	}).Insert()
+	if err != nil {
+		return gerror.Wrap(err, "记录用量事件失败")
+	}
+	return nil
+}
+
+// TeamOf 用户的用量计入的团队, 不在有效的团队中时返回0
+func TeamOf(ctx context.Context, tx gdb.TX, userId int64) (int64, error) {
+	var sub *entity.UserSubscriptions
+	if err := dao.UserSubscriptions.Ctx(ctx).TX(tx).Where("user_id", userId).Scan(&sub); err != nil {
+		return 0, err
+	}
+	if sub == nil || sub.TeamId == 0 {
+		return 0, nil
+	}
+	status, err := dao.Teams.Ctx(ctx).TX(tx).Where("id", sub.TeamId).Value("status")
+	if err != nil || status.String() != "active" {
+		return 0, err
+	}
+	return sub.TeamId, nil
+}
+
+// Rollup 按用量事件重新汇总每日用量, 从最近一次汇总的前一天开始, 覆盖跨天提交的事件
+// 汇总结果按明细重新计算, 重复执行结果不变
+func (u *Usage) Rollup(ctx context.Context) error {
+	last, err := dao.UsageDaily.Ctx(ctx).OrderDesc("stat_date").Value("stat_date")
+	if err != nil {
+		return err
+	}
+	var start *gtime.Time
+	if !last.IsEmpty() {
+		start = last.GTime().StartOfDay().AddDate(0, 0, -1)
+	} else {
+		first, err := dao.UsageEvents.Ctx(ctx).OrderAsc("created_at").Value("created_at")
+		if err != nil || first.IsEmpty() {
+			return err
+		}
+		start = first.GTime().StartOfDay()
+	}
+
+	result, err := dao.UsageDaily.DB().Exec(ctx,
+		`INSERT INTO `+dao.UsageDaily.Table()+` (stat_date, user_id, team_id, resource_type, project_id, model, amount, weighted_amount, events, updated_at)
+		SELECT created_at::date, user_id, team_id, resource_type, project_id, model, SUM(amount), SUM(amount * cost_multiplier), COUNT(*), NOW()
+		FROM `+dao.UsageEvents.Table()+`
+		WHERE created_at >= ?
+		GROUP BY 1, 2, 3, 4, 5, 6
+		ON CONFLICT (stat_date, user_id, team_id, resource_type, project_id, model) DO UPDATE
+		SET amount = EXCLUDED.amount, weighted_amount = EXCLUDED.weighted_amount, events = EXCLUDED.events, updated_at = EXCLUDED.updated_at`,
+		start,
+	)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows > 0 {
+		g.Log().Infof(ctx, "已汇总 %s 之后的每日用量 %d 条", start.Format("Y-m-d"), rows)
+	}
+	return nil
+}
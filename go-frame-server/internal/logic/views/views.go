@@ -0,0 +1,303 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/database/gredis"
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/util/guid"
+
+	"kgplatform-backend/internal/dao"
+	"kgplatform-backend/internal/model/entity"
+)
+
+// Redis 键
+const (
+	// 待写入数据库的浏览事件列表
+	eventListKey = "project:view:events"
+	// 各项目尚未写入数据库的浏览量, 有序集合, 成员为项目ID, 分数为待写入数量
+	pendingIndexKey = "project:view:pending"
+	// 访客去重标记前缀, 完整键为 前缀+项目ID:访客标识
+	dedupKeyPrefix = "project:view:seen:"
+	// 旧版按项目计数的键与浏览日志, 旧版写入逻辑仍在运行, 浏览已由 Record 计入, 只做清理
+	legacyCountPattern = "project:view:count:*"
+	legacyLogKey       = "project:view:logs"
+)
+
+// 原子地完成访客去重、写入浏览事件和累加待写入浏览量, 避免事件与计数不一致
+// KEYS[1] 去重标记, KEYS[2] 事件列表, KEYS[3] 待写入索引; ARGV[1] 去重窗口秒数, ARGV[2] 事件, ARGV[3] 项目ID
+const recordScript = `
+if not redis.call("SET", KEYS[1], 1, "EX", ARGV[1], "NX") then
+	return 0
+end
+redis.call("RPUSH", KEYS[2], ARGV[2])
+redis.call("ZINCRBY", KEYS[3], 1, ARGV[3])
+return 1`
+
+// 写入数据库后原子地移除已处理的事件并扣减待写入浏览量
+// KEYS[1] 事件列表, KEYS[2] 待写入索引; ARGV[1] 已处理的事件数, 之后依次为 项目ID, 数量
+const ackScript = `
+redis.call("LTRIM", KEYS[1], ARGV[1], -1)
+for i = 2, #ARGV, 2 do
+	redis.call("ZINCRBY", KEYS[2], -tonumber(ARGV[i + 1]), ARGV[i])
+end
+redis.call("ZREMRANGEBYSCORE", KEYS[2], "-inf", 0)
+return 1`
+
+// Views 项目浏览量统计: 访客去重后写入 Redis, 由定时任务批量写入数据库并按天汇总
+type Views struct{}
+
+func New() *Views {
+	return &Views{}
+}
+
+type RecordInput struct {
+	ProjectId int
+	UserId    int
+	Ip        string
+}
+
+// Event 浏览事件
+type Event struct {
+	EventId   string `json:"eventId"`
+	ProjectId int    `json:"projectId"`
+	UserId    int    `json:"userId"`
+	Viewer    string `json:"viewer"`
+	Ip        string `json:"ip"`
+	ViewedAt  string `json:"viewedAt"`
+}
+
+type TrendItem struct {
+	Date          string `json:"date" dc:"日期"`
+	Views         int    `json:"views" dc:"浏览量"`
+	UniqueViewers int    `json:"uniqueViewers" dc:"独立访客数"`
+}
+
+// Record 记录一次浏览, 同一访客在去重窗口内重复浏览只计一次
+// 返回是否计入浏览量
+func (v *Views) Record(ctx context.Context, in *RecordInput) (bool, error) {
+	viewer := viewerOf(in.UserId, in.Ip)
+	if viewer == "" {
+		return false, nil
+	}
+
+	window := g.Cfg().MustGet(ctx, "views.dedupWindow", 1800).Int64()
+	dedupKey := fmt.Sprintf("%s%d:%s", dedupKeyPrefix, in.ProjectId, viewer)
+	event := &Event{
+		EventId:   guid.S(),
+		ProjectId: in.ProjectId,
+		UserId:    in.UserId,
+		Viewer:    viewer,
+		Ip:        in.Ip,
+		ViewedAt:  gtime.Now().Format("c"),
+	}
+	recorded, err := g.Redis().Eval(ctx, recordScript, 3,
+		[]string{dedupKey, eventListKey, pendingIndexKey},
+		[]any{window, gjson.MustEncodeString(event), in.ProjectId},
+	)
+	if err != nil {
+		return false, err
+	}
+	return recorded.Int() == 1, nil
+}
+
+// Count 获取项目浏览量, 包含尚未写入数据库的部分
+func (v *Views) Count(ctx context.Context, projectId int) (int, error) {
+	count, err := dao.Projects.Ctx(ctx).Where("id", projectId).Value("view_count")
+	if err != nil {
+		return 0, err
+	}
+	pending, err := g.Redis().ZScore(ctx, pendingIndexKey, projectId)
+	if err != nil {
+		return 0, err
+	}
+	return count.Int() + int(pending), nil
+}
+
+// Trend 获取项目最近 days 天的每日浏览量, 没有浏览的日期补0
+func (v *Views) Trend(ctx context.Context, projectId int, days int) ([]*TrendItem, error) {
+	start := gtime.Now().AddDate(0, 0, 1-days).StartOfDay()
+
+	var list []*entity.ProjectViewDaily
+	err := dao.ProjectViewDaily.Ctx(ctx).
+		Where("project_id", projectId).
+		WhereGTE("view_date", start.Format("Y-m-d")).
+		OrderAsc("view_date").
+		Scan(&list)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]*entity.ProjectViewDaily, len(list))
+	for _, item := range list {
+		byDate[item.ViewDate.Format("Y-m-d")] = item
+	}
+
+	trend := make([]*TrendItem, 0, days)
+	for i := 0; i < days; i++ {
+		date := start.AddDate(0, 0, i).Format("Y-m-d")
+		item := &TrendItem{Date: date}
+		if daily, ok := byDate[date]; ok {
+			item.Views = daily.Views
+			item.UniqueViewers = daily.UniqueViewers
+		}
+		trend = append(trend, item)
+	}
+	return trend, nil
+}
+
+// Flush 将 Redis 中的浏览事件批量写入数据库
+// 事件按 event_id 去重写入, 同一批次重复执行不会重复计数
+func (v *Views) Flush(ctx context.Context) error {
+	batchSize := g.Cfg().MustGet(ctx, "views.flushBatchSize", 1000).Int()
+
+	total := 0
+	for {
+		values, err := g.Redis().LRange(ctx, eventListKey, 0, int64(batchSize-1))
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			break
+		}
+
+		events := make([]*Event, 0, len(values))
+		for _, value := range values {
+			var event *Event
+			if err := gjson.DecodeTo(value.Bytes(), &event); err != nil || event == nil || event.EventId == "" {
+				g.Log().Warningf(ctx, "忽略无法解析的浏览事件: %s", value.String())
+				continue
+			}
+			events = append(events, event)
+		}
+
+		if err = v.saveEvents(ctx, events); err != nil {
+			return err
+		}
+
+		// 按事件扣减待写入浏览量, 与事件列表的截断在同一脚本中完成
+		pending := make(map[int]int)
+		for _, event := range events {
+			pending[event.ProjectId]++
+		}
+		args := []any{len(values)}
+		for projectId, n := range pending {
+			args = append(args, projectId, n)
+		}
+		if _, err = g.Redis().Eval(ctx, ackScript, 2, []string{eventListKey, pendingIndexKey}, args); err != nil {
+			return err
+		}
+
+		total += len(events)
+		if len(values) < batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		g.Log().Infof(ctx, "已写入 %d 条浏览事件", total)
+	}
+
+	return v.discardLegacy(ctx)
+}
+
+// saveEvents 在一个事务中写入浏览事件、累加项目浏览量并重新汇总涉及日期的统计
+func (v *Views) saveEvents(ctx context.Context, events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var (
+		holders = make([]string, 0, len(events))
+		args    = make([]any, 0, len(events)*6)
+	)
+	for _, event := range events {
+		holders = append(holders, "(?, ?, ?, ?, ?, ?)")
+		args = append(args, event.EventId, event.ProjectId, event.UserId, event.Viewer, event.Ip, event.ViewedAt)
+	}
+	insertSql := fmt.Sprintf(
+		`INSERT INTO %s (event_id, project_id, user_id, viewer, ip, viewed_at) VALUES %s
+		ON CONFLICT (event_id) DO NOTHING
+		RETURNING project_id, viewed_at::date AS view_date`,
+		dao.ProjectViewEvents.Table(), strings.Join(holders, ","),
+	)
+
+	return dao.ProjectViewEvents.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		// 只有本次新写入的事件计入浏览量, 已写入过的事件会被忽略
+		inserted, err := tx.GetAll(insertSql, args...)
+		if err != nil {
+			return err
+		}
+
+		counts := make(map[int]int)
+		days := make(map[string]g.Map)
+		for _, row := range inserted {
+			projectId := row["project_id"].Int()
+			counts[projectId]++
+			date := row["view_date"].GTime().Format("Y-m-d")
+			days[fmt.Sprintf("%d:%s", projectId, date)] = g.Map{"project_id": projectId, "view_date": date}
+		}
+		for projectId, n := range counts {
+			_, err = dao.Projects.Ctx(ctx).TX(tx).Where("id", projectId).Increment("view_count", n)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 按明细重新汇总, 重复执行结果不变
+		for _, day := range days {
+			_, err = tx.Exec(
+				`INSERT INTO `+dao.ProjectViewDaily.Table()+` (project_id, view_date, views, unique_viewers, updated_at)
+				SELECT project_id, viewed_at::date, COUNT(*), COUNT(DISTINCT viewer), NOW()
+				FROM `+dao.ProjectViewEvents.Table()+`
+				WHERE project_id = ? AND viewed_at::date = ?
+				GROUP BY project_id, viewed_at::date
+				ON CONFLICT (project_id, view_date) DO UPDATE
+				SET views = EXCLUDED.views, unique_viewers = EXCLUDED.unique_viewers, updated_at = EXCLUDED.updated_at`,
+				day["project_id"], day["view_date"],
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// discardLegacy 清理旧版写入的浏览日志和计数, 使用 SCAN 遍历避免阻塞 Redis
+// 这些浏览已由 Record 计入, 再累加会重复计数
+func (v *Views) discardLegacy(ctx context.Context) error {
+	if _, err := g.Redis().Del(ctx, legacyLogKey); err != nil {
+		return err
+	}
+	var cursor uint64
+	for {
+		next, keys, err := g.Redis().Scan(ctx, cursor, gredis.ScanOption{Match: legacyCountPattern, Count: 100})
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if _, err = g.Redis().Del(ctx, keys...); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// viewerOf 访客标识, 登录用户按用户去重, 未登录按 IP 去重
+func viewerOf(userId int, ip string) string {
+	if userId > 0 {
+		return fmt.Sprintf("u:%d", userId)
+	}
+	if ip != "" {
+		return "ip:" + ip
+	}
+	return ""
+}
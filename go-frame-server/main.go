@@ -13,7 +13,6 @@ import (
 	"github.com/gogf/gf/v2/os/gctx"
 
 	"kgplatform-backend/internal/cmd"
-	corn "kgplatform-backend/internal/corn"
 )
 
 func main() {
@@ -35,11 +34,6 @@ func main() {
 
 	cmd.AsynQCmd.Run(gctx.GetInitCtx())
 	cmd.Main.Run(gctx.GetInitCtx())
-
-	err = corn.RegisterCronJobs(gctx.GetInitCtx())
-	if err != nil {
-		panic(fmt.Errorf("定时任务加载失败: %v", err))
-	}
 }
 
 func connDb() error {